@@ -31,6 +31,8 @@ func Build(ctx context.Context, _ eg.Op) error {
 		runtime.New(".egpyenv/bin/pip install --upgrade build twine"),
 		runtime.New(".egpyenv/bin/pip install ."),
 		runtime.New(".egpyenv/bin/python -m grpc_tools.protoc -I ../.proto --python_out=egpy --pyi_out=egpy --grpc_python_out=egpy/metrics ../.proto/eg.interp.events.proto"),
+		runtime.New(".egpyenv/bin/python -m grpc_tools.protoc -I ../.proto --python_out=egpy --pyi_out=egpy --grpc_python_out=egpy ../.proto/eg.interp.containers.proto ../.proto/eg.interp.exec.proto"),
+		runtime.New("sed -i 's/^from eg.interp import/from egpy.eg.interp import/' egpy/eg/interp/*_grpc.py"),
 		runtime.New(".egpyenv/bin/python -m build"),
 		runtime.New(".egpyenv/bin/python -m twine upload -u ${PYPI_USERNAME} -p ${PYPI_PASSWORD} dist/*"),
 	)
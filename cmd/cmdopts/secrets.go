@@ -0,0 +1,17 @@
+package cmdopts
+
+import "github.com/alecthomas/kong"
+
+// SecretURIExamples of the secret uri schemes, referenced within help text as ${vars_secret_uri_examples}.
+const SecretURIExamples = "Examples: chachasm://passphrase@/path/to/file, gcpsm://project-id/secret-name/version, awssm://secret-name?region=us-east-1, vault://mount/path#field, keyring://passphrase@/path/to/keyring.age#name"
+
+// SecretURIFragments describes the fragment and env parameters of secret uris, referenced within help text as ${vars_secret_uri_fragments}.
+const SecretURIFragments = "fragments select a key path from json/yaml secrets and env renames it, e.g. awssm://db?region=us-east-1&env=PGPASSWORD#password"
+
+// SecretVars returns the kong.Vars describing secret uris within help text.
+func SecretVars() kong.Vars {
+	return kong.Vars{
+		"vars_secret_uri_examples":  SecretURIExamples,
+		"vars_secret_uri_fragments": SecretURIFragments,
+	}
+}
@@ -0,0 +1,102 @@
+package cmdsecret
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/egdaemon/eg/cmd/cmdopts"
+	"github.com/egdaemon/eg/cmd/cmdssh"
+	"github.com/egdaemon/eg/internal/fsx"
+	"github.com/egdaemon/eg/internal/userx"
+	"github.com/egdaemon/eg/notary"
+	"github.com/egdaemon/eg/secrets"
+	"golang.org/x/crypto/ssh"
+)
+
+// auditor records changes to file backed secrets (chachasm, keyring, file) within the signed
+// audit log alongside the secret. once a secret has an audit log every change is recorded.
+type auditor struct {
+	Audit bool          `name:"audit" help:"record the change within the signed audit log alongside the secret, secrets with an existing audit log are always recorded"`
+	SSH   cmdssh.Signer `embed:"" prefix:"ssh-"`
+}
+
+func (t auditor) enabled(uri string) (bool, error) {
+	path, err := secrets.AuditPath(uri)
+	if err != nil && t.Audit {
+		return false, err
+	}
+
+	if err != nil {
+		return false, nil
+	}
+
+	return t.Audit || fsx.FileExists(path), nil
+}
+
+// change the secret recording the change from before to after within the audit log when enabled, only
+// the names of changed keys are recorded. the change is rolled back when it can't be recorded.
+func (t auditor) change(uri string, action string, before, after []byte, change func() error) error {
+	enabled, err := t.enabled(uri)
+	if err != nil {
+		return err
+	}
+
+	if !enabled {
+		return change()
+	}
+
+	signer, err := t.SSH.Load()
+	if err != nil {
+		return err
+	}
+
+	added, removed, changed, err := diffenv(before, after)
+	if err != nil {
+		return err
+	}
+
+	keys := slices.Concat(added, removed, changed)
+	slices.Sort(keys)
+
+	return secrets.Audit(uri, signer, secrets.AuditEntry{
+		Timestamp: time.Now(),
+		Actor:     userx.CurrentUserOrDefault(userx.Root()).Username,
+		Action:    action,
+		Keys:      keys,
+	}, change)
+}
+
+type CmdAudit struct {
+	URI            string   `arg:"" help:"File backed secret URI whose audit log to verify. Examples: chachasm:///path/to/file, keyring:///path/to/keyring.age"`
+	AuthorizedKeys []string `name:"authorized-keys" help:"authorized_keys files listing the keys permitted to change the secret" required:""`
+}
+
+// Run verifies the audit log of the secret and prints its entries.
+func (t CmdAudit) Run(gctx *cmdopts.Global) (err error) {
+	var (
+		keys []ssh.PublicKey
+	)
+
+	if keys, err = notary.AuthorizedKeys(t.AuthorizedKeys...); err != nil {
+		return fmt.Errorf("failed to read authorized keys: %w", err)
+	}
+
+	if len(keys) == 0 {
+		return fmt.Errorf("no authorized keys found: %s", strings.Join(t.AuthorizedKeys, ", "))
+	}
+
+	entries, verr := secrets.AuditVerify(t.URI, keys...)
+	for _, e := range entries {
+		pub, _, _, _, _ := ssh.ParseAuthorizedKey([]byte(e.PublicKey))
+		fmt.Fprintf(os.Stdout, "%s %s %s %s %s\n", e.Timestamp.Format(time.RFC3339), e.Action, e.Actor, ssh.FingerprintSHA256(pub), strings.Join(e.Keys, ","))
+	}
+
+	if verr != nil {
+		return fmt.Errorf("audit log verification failed: %w", verr)
+	}
+
+	return nil
+}
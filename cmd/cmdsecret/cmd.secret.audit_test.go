@@ -0,0 +1,150 @@
+package cmdsecret_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/egdaemon/eg/internal/sshx"
+	"github.com/egdaemon/eg/notary"
+	"github.com/egdaemon/eg/secrets"
+	"github.com/stretchr/testify/require"
+)
+
+// captureStdout runs fn returning what it wrote to stdout.
+func captureStdout(t *testing.T, fn func() error) (string, error) {
+	t.Helper()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := fn()
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	_, cerr := io.Copy(&buf, r)
+	require.NoError(t, cerr)
+
+	return buf.String(), err
+}
+
+func TestCmdKeysAndDiff(t *testing.T) {
+	tmp := t.TempDir()
+	before := "chachasm://p@" + filepath.Join(tmp, "before.chacha")
+	after := "chachasm://p@" + filepath.Join(tmp, "after.chacha")
+	require.NoError(t, secrets.Update(t.Context(), before, strings.NewReader("A=1\nB=2\nC=3")))
+	require.NoError(t, secrets.Update(t.Context(), after, strings.NewReader("A=1\nB=changed\nD=4")))
+
+	t.Run("keys are listed without values", func(t *testing.T) {
+		out, err := captureStdout(t, func() error {
+			return runSecretCLI(t, []string{"secret", "keys", after})
+		})
+		require.NoError(t, err)
+		require.Equal(t, "A\nB\nD\n", out)
+	})
+
+	t.Run("diff reports keys without values", func(t *testing.T) {
+		out, err := captureStdout(t, func() error {
+			return runSecretCLI(t, []string{"secret", "diff", before, after})
+		})
+		require.NoError(t, err)
+		require.Equal(t, "+ D\n- C\n~ B\n", out)
+		require.NotContains(t, out, "changed")
+	})
+}
+
+func TestCmdRotate(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "rotate.chacha")
+	require.NoError(t, secrets.Update(t.Context(), "chachasm://old@"+path, strings.NewReader("A=1")))
+
+	require.NoError(t, runSecretCLI(t, []string{"secret", "rotate", "chachasm://old@" + path, "--passphrase", "new"}))
+
+	_, err := io.ReadAll(secrets.Read(t.Context(), "chachasm://old@"+path))
+	require.ErrorContains(t, err, "decryption failed")
+
+	result, err := io.ReadAll(secrets.Read(t.Context(), "chachasm://new@"+path))
+	require.NoError(t, err)
+	require.Equal(t, "A=1", string(result))
+}
+
+func TestCmdAudit(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "audited.chacha")
+	keypath := filepath.Join(tmp, "id_ed25519")
+	uri := "chachasm://p@" + path
+
+	update := func(content string, args ...string) error {
+		input := filepath.Join(tmp, "input.env")
+		require.NoError(t, os.WriteFile(input, []byte(content), 0600))
+		return runSecretCLI(t, append([]string{"secret", "update", uri, "-i", input, "--ssh-path", keypath}, args...))
+	}
+
+	t.Run("changes are recorded once the audit log exists", func(t *testing.T) {
+		require.NoError(t, update("A=1\nB=2", "--audit"))
+		require.FileExists(t, path+secrets.AuditSuffix)
+		require.NoError(t, update("A=1\nB=3\nC=4"))
+		require.NoError(t, runSecretCLI(t, []string{"secret", "rotate", uri, "--passphrase", "rotated", "--ssh-path", keypath}))
+
+		keys, err := notary.AuthorizedKeys(keypath + ".pub")
+		require.NoError(t, err)
+
+		entries, err := secrets.AuditVerify("chachasm://"+path, keys...)
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+		require.Equal(t, []string{"A", "B"}, entries[0].Keys)
+		require.Equal(t, []string{"B", "C"}, entries[1].Keys)
+		require.Equal(t, secrets.AuditActionRotate, entries[2].Action)
+
+		encoded, err := os.ReadFile(path + secrets.AuditSuffix)
+		require.NoError(t, err)
+		require.NotContains(t, string(encoded), "B=3")
+
+		out, err := captureStdout(t, func() error {
+			return runSecretCLI(t, []string{"secret", "audit", "chachasm://" + path, "--authorized-keys", keypath + ".pub"})
+		})
+		require.NoError(t, err)
+		require.Len(t, strings.Split(strings.TrimSpace(out), "\n"), 3)
+	})
+
+	t.Run("failure without authorized keys", func(t *testing.T) {
+		_, err := captureStdout(t, func() error {
+			return runSecretCLI(t, []string{"secret", "audit", "chachasm://" + path})
+		})
+		require.ErrorContains(t, err, "--authorized-keys")
+	})
+
+	t.Run("secret is unchanged when the change can't be recorded", func(t *testing.T) {
+		original, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		// the parent of the key is a file preventing the signer from loading.
+		blocked := filepath.Join(t.TempDir(), "blocked")
+		require.NoError(t, os.WriteFile(blocked, nil, 0600))
+		input := filepath.Join(tmp, "input.env")
+		require.NoError(t, os.WriteFile(input, []byte("A=5"), 0600))
+
+		err = runSecretCLI(t, []string{"secret", "update", "chachasm://rotated@" + path, "-i", input, "--ssh-path", filepath.Join(blocked, "id_ed25519")})
+		require.ErrorContains(t, err, "failed to create parent directory")
+		current, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, original, current)
+	})
+
+	t.Run("failure unauthorized key", func(t *testing.T) {
+		other := filepath.Join(t.TempDir(), "other")
+		_, err := sshx.AutoCached(sshx.NewKeyGenSeeded("other"), other)
+		require.NoError(t, err)
+
+		_, err = captureStdout(t, func() error {
+			return runSecretCLI(t, []string{"secret", "audit", "chachasm://" + path, "--authorized-keys", other + ".pub"})
+		})
+		require.ErrorContains(t, err, "unauthorized key")
+	})
+}
@@ -14,7 +14,8 @@ import (
 )
 
 type CmdEdit struct {
-	URI string `arg:"" help:"Secret URI to edit. Example: chachasm://passphrase@/path/to/file"`
+	auditor
+	URI string `arg:"" help:"Secret URI to edit. Examples: chachasm://passphrase@/path/to/file, vault://mount/path#field, keyring://passphrase@/path/to/keyring.age#name"`
 }
 
 func (t CmdEdit) Run(gctx *cmdopts.Global) error {
@@ -56,7 +57,11 @@ func (t CmdEdit) Run(gctx *cmdopts.Global) error {
 		return nil
 	}
 
-	if err := secrets.Update(gctx.Context, t.URI, bytes.NewReader(newData)); err != nil {
+	update := func() error {
+		return secrets.Update(gctx.Context, t.URI, bytes.NewReader(newData))
+	}
+
+	if err := t.change(t.URI, secrets.AuditActionUpdate, oldData, newData, update); err != nil {
 		return fmt.Errorf("failed to update secret after edit: %w", err)
 	}
 
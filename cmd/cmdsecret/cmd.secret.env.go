@@ -11,7 +11,7 @@ import (
 )
 
 type CmdEnv struct {
-	URIs []string `name:"uri" short:"u" help:"Secret URIs containing KEY=VALUE environment variables. Examples: chachasm://passphrase@/path/to/file, gcpsm://project-id/secret-name/version, awssm://secret-name?region=us-east-1"`
+	URIs []string `name:"uri" short:"u" help:"Secret URIs containing KEY=VALUE environment variables. ${vars_secret_uri_examples}. ${vars_secret_uri_fragments}"`
 	Cmd  []string `arg:"" passthrough:"" optional:""`
 }
 
@@ -1,12 +1,14 @@
 package cmdsecret
 
 import (
+	"bytes"
 	"encoding/base64"
 	"fmt"
 	"io"
 	"os"
 
 	"github.com/egdaemon/eg/cmd/cmdopts"
+	"github.com/egdaemon/eg/internal/errorsx"
 	"github.com/egdaemon/eg/secrets"
 )
 
@@ -16,10 +18,14 @@ type SecretCmd struct {
 	Edit   CmdEdit   `cmd:"" help:"Interactively edit a secret using $EDITOR."`
 	B64    CmdB64    `cmd:"" name:"b64" help:"Base64 URL encode stdin and write the result to stdout or a file."`
 	Env    CmdEnv    `cmd:"" name:"env" help:"Run a command with environment variables loaded from secret URIs."`
+	Rotate CmdRotate `cmd:"" help:"Re-encrypt a passphrase protected secret with a new passphrase."`
+	Keys   CmdKeys   `cmd:"" help:"List the keys held by dotenv style secrets without their values."`
+	Diff   CmdDiff   `cmd:"" help:"List the keys added, removed and changed between two versions of a dotenv style secret."`
+	Audit  CmdAudit  `cmd:"" help:"Verify and print the signed audit log of a file backed secret."`
 }
 
 type CmdRead struct {
-	URIs   []string `arg:"" help:"List of secret URIs to download. Examples: chachasm://passphrase@/path/to/file, gcpsm://project-id/secret-name/version, awssm://secret-name?region=us-east-1"`
+	URIs   []string `arg:"" help:"List of secret URIs to download. ${vars_secret_uri_examples}"`
 	Output string   `name:"output" short:"o" help:"Write output to a file instead of stdout"`
 }
 
@@ -43,7 +49,8 @@ func (t CmdRead) Run(gctx *cmdopts.Global) error {
 }
 
 type CmdUpdate struct {
-	URI   string `arg:"" help:"Secret URI to update. Examples: chachasm://passphrase@/path/to/file, gcpsm://project-id/secret-name, awssm://secret-name?region=us-east-1"`
+	auditor
+	URI   string `arg:"" help:"Secret URI to update. ${vars_secret_uri_examples}"`
 	Input string `name:"input" short:"i" help:"Read content from a file instead of stdin"`
 }
 
@@ -59,7 +66,26 @@ func (t CmdUpdate) Run(gctx *cmdopts.Global) error {
 		in = f
 	}
 
-	if err := secrets.Update(gctx.Context, t.URI, in); err != nil {
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return fmt.Errorf("failed to read content: %w", err)
+	}
+
+	// the previous content is only required to record the keys that changed.
+	var previous []byte
+	if enabled, err := t.enabled(t.URI); err != nil {
+		return err
+	} else if enabled {
+		if previous, err = io.ReadAll(secrets.Read(gctx.Context, t.URI)); errorsx.Ignore(err, os.ErrNotExist) != nil {
+			return fmt.Errorf("failed to read secret [%s]: %w", t.URI, err)
+		}
+	}
+
+	update := func() error {
+		return secrets.Update(gctx.Context, t.URI, bytes.NewReader(data))
+	}
+
+	if err := t.change(t.URI, secrets.AuditActionUpdate, previous, data, update); err != nil {
 		return fmt.Errorf("failed to update secret [%s]: %w", t.URI, err)
 	}
 
@@ -0,0 +1,120 @@
+package cmdsecret
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/egdaemon/eg/cmd/cmdopts"
+	"github.com/egdaemon/eg/internal/envx"
+	"github.com/egdaemon/eg/secrets"
+)
+
+type CmdKeys struct {
+	URIs []string `arg:"" help:"Secret URIs containing KEY=VALUE environment variables. Examples: chachasm://passphrase@/path/to/file, vault://mount/path#field"`
+}
+
+// Run prints the names of the keys held by dotenv style secrets, values are never printed.
+func (t CmdKeys) Run(gctx *cmdopts.Global) error {
+	environ, err := envx.FromReader(secrets.NewReader(gctx.Context, t.URIs...))
+	if err != nil {
+		return fmt.Errorf("failed to read secrets: %w", err)
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(envmap(environ))) {
+		fmt.Println(k)
+	}
+
+	return nil
+}
+
+type CmdDiff struct {
+	Before string `arg:"" help:"Secret URI of the previous version. Examples: chachasm://passphrase@/path/to/old"`
+	After  string `arg:"" help:"Secret URI of the current version. Examples: chachasm://passphrase@/path/to/new"`
+}
+
+// Run prints the keys added (+), removed (-) and changed (~) between two versions of a dotenv style
+// secret, values are never printed.
+func (t CmdDiff) Run(gctx *cmdopts.Global) error {
+	before, err := io.ReadAll(secrets.Read(gctx.Context, t.Before))
+	if err != nil {
+		return fmt.Errorf("failed to read secret [%s]: %w", t.Before, err)
+	}
+
+	after, err := io.ReadAll(secrets.Read(gctx.Context, t.After))
+	if err != nil {
+		return fmt.Errorf("failed to read secret [%s]: %w", t.After, err)
+	}
+
+	added, removed, changed, err := diffenv(before, after)
+	if err != nil {
+		return err
+	}
+
+	for _, k := range added {
+		fmt.Println("+", k)
+	}
+
+	for _, k := range removed {
+		fmt.Println("-", k)
+	}
+
+	for _, k := range changed {
+		fmt.Println("~", k)
+	}
+
+	return nil
+}
+
+func envmap(environ []string) map[string]string {
+	m := make(map[string]string, len(environ))
+	for _, v := range environ {
+		if k, v, ok := strings.Cut(v, "="); ok {
+			m[k] = v
+		}
+	}
+
+	return m
+}
+
+// diffenv compares the keys of two dotenv style secrets, secrets without any keys
+// are compared as a whole and reported as a change to the unnamed key "*".
+func diffenv(before, after []byte) (added, removed, changed []string, err error) {
+	benv, err := envx.FromReader(bytes.NewReader(before))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	aenv, err := envx.FromReader(bytes.NewReader(after))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if len(benv) == 0 && len(aenv) == 0 {
+		if !bytes.Equal(before, after) {
+			changed = append(changed, "*")
+		}
+
+		return added, removed, changed, nil
+	}
+
+	bmap, amap := envmap(benv), envmap(aenv)
+	for _, k := range slices.Sorted(maps.Keys(amap)) {
+		if v, ok := bmap[k]; !ok {
+			added = append(added, k)
+		} else if v != amap[k] {
+			changed = append(changed, k)
+		}
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(bmap)) {
+		if _, ok := amap[k]; !ok {
+			removed = append(removed, k)
+		}
+	}
+
+	return added, removed, changed, nil
+}
@@ -0,0 +1,27 @@
+package cmdsecret
+
+import (
+	"fmt"
+
+	"github.com/egdaemon/eg/cmd/cmdopts"
+	"github.com/egdaemon/eg/secrets"
+)
+
+type CmdRotate struct {
+	auditor
+	URI        string `arg:"" help:"Passphrase protected secret URI to rotate, the URI provides the current passphrase. Examples: chachasm://passphrase@/path/to/file, keyring://passphrase@/path/to/keyring.age"`
+	Passphrase string `name:"passphrase" help:"new passphrase for the secret" env:"EG_SECRETS_PASSPHRASE" required:""`
+}
+
+// Run re-encrypts the secret with the new passphrase.
+func (t CmdRotate) Run(gctx *cmdopts.Global) error {
+	rotate := func() error {
+		return secrets.Rotate(gctx.Context, t.URI, t.Passphrase)
+	}
+
+	if err := t.change(t.URI, secrets.AuditActionRotate, nil, nil, rotate); err != nil {
+		return fmt.Errorf("failed to rotate secret: %w", err)
+	}
+
+	return nil
+}
@@ -25,6 +25,12 @@ func runSecretCLI(t *testing.T, args []string) error {
 
 	parser, err := kong.New(&cli,
 		kong.Name("eg"),
+		kong.Vars{
+			"vars_entropy_seed": "test-default-seed",
+			"vars_ssh_key_seed": "EGTEST_SSH_SEED",
+			"vars_ssh_key_path": filepath.Join(t.TempDir(), "id_ed25519"),
+		},
+		cmdopts.SecretVars(),
 		kong.Bind(&cli.Global),
 	)
 	require.NoError(t, err)
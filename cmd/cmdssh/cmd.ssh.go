@@ -14,11 +14,30 @@ type Cmd struct {
 	Key GenKey `cmd:"" name:"key" help:"generate a deterministic ssh key from a seed"`
 }
 
-type GenKey struct {
+// Signer loads the user's ssh key, generating it from the seed when it doesn't exist.
+// embed it within commands that sign on behalf of the user.
+type Signer struct {
 	Seed string `name:"seed" help:"seed for generating a deterministic key, useful for ci/cd" default:"${vars_entropy_seed}" env:"${vars_ssh_key_seed}"`
 	Path string `name:"path" help:"path for the generated key (private key and .pub file)" default:"${vars_ssh_key_path}"`
 }
 
+func (t Signer) Load() (ssh.Signer, error) {
+	if err := os.MkdirAll(filepath.Dir(t.Path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create parent directory: %w", err)
+	}
+
+	signer, err := sshx.AutoCached(sshx.NewKeyGenSeeded(t.Seed), t.Path)
+	if err != nil {
+		return nil, fmt.Errorf("ssh key generation failed: %w", err)
+	}
+
+	return signer, nil
+}
+
+type GenKey struct {
+	Signer `embed:""`
+}
+
 // Run generates a deterministic SSH key from the given seed and writes it to
 // the target path. The generated files are a private key (PEM format) and a
 // public key (.pub file).
@@ -26,14 +45,9 @@ type GenKey struct {
 // The key is stable: running the command again with the same seed produces the
 // same key, and if the file already exists it is loaded from disk unchanged.
 func (t GenKey) Run(gctx *cmdopts.Global) error {
-	if err := os.MkdirAll(filepath.Dir(t.Path), 0700); err != nil {
-		return fmt.Errorf("failed to create parent directory: %w", err)
-	}
-
-	kg := sshx.NewKeyGenSeeded(t.Seed)
-	signer, err := sshx.AutoCached(kg, t.Path)
+	signer, err := t.Load()
 	if err != nil {
-		return fmt.Errorf("ssh key generation failed: %w", err)
+		return err
 	}
 
 	pubkey := signer.PublicKey()
@@ -7,4 +7,5 @@ import (
 type actlcmd struct {
 	Authorize actl.AuthorizeAgent `cmd:"" help:"authorize agents"`
 	Bootstrap actl.Bootstrap      `cmd:"" help:"functions for bootstrapping the eg both locally and on workload runners"`
+	Queue     actl.Queue          `cmd:"" help:"inspect and control the workloads of the local runner"`
 }
@@ -0,0 +1,120 @@
+package actl
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/egdaemon/eg/cmd/cmdopts"
+	"github.com/egdaemon/eg/internal/bytesx"
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/sshx"
+	"github.com/egdaemon/eg/internal/userx"
+	"github.com/egdaemon/eg/notary"
+	"github.com/egdaemon/eg/runners"
+	"golang.org/x/crypto/ssh"
+)
+
+type Queue struct {
+	Status  QueueStatus  `cmd:"" help:"display the workloads of the local runner" default:"1"`
+	Cancel  QueueCancel  `cmd:"" help:"cancel a running workload"`
+	Requeue QueueRequeue `cmd:"" help:"requeue an archived workload. archived workloads are stripped of their environment, secrets and access token; requeued workloads run without them and fail to access private repositories"`
+}
+
+// queueauth identifies the account requests are issued against, requests are signed
+// with the notary key which the daemon authorizes.
+type queueauth struct {
+	Account string `name:"account" help:"account the runner is registered with" default:"${vars_account_id}"`
+}
+
+// client connected to the socket of the local runner daemon.
+func (t queueauth) client() (_ *runners.QueueClient, err error) {
+	var (
+		signer ssh.Signer
+	)
+
+	if signer, err = sshx.AutoCached(sshx.NewKeyGen(), notary.PrivateKeyPath()); err != nil {
+		return nil, errorsx.Wrap(err, "unable to retrieve notary key")
+	}
+
+	socket := userx.DefaultRuntimeDirectory("main.socket")
+	return runners.NewQueueClient(notary.NewHTTP(&http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+			},
+		},
+		Timeout: 30 * time.Second,
+	}, signer, t.Account)), nil
+}
+
+type QueueStatus struct {
+	queueauth
+}
+
+func (t QueueStatus) Run(gctx *cmdopts.Global) (err error) {
+	c, err := t.client()
+	if err != nil {
+		return err
+	}
+
+	s, err := c.Status(gctx.Context)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "id\tstate\tmodified\trepository\tcommit\tcores\tmemory")
+	for _, group := range []struct {
+		state     string
+		workloads []runners.Workload
+	}{
+		{state: "running", workloads: s.Running},
+		{state: "queued", workloads: s.Queued},
+		{state: "archived", workloads: s.Archived},
+	} {
+		for _, w := range group.workloads {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n", w.ID, group.state, w.Modified.Format(time.RFC3339), w.Enqueued.GetVcsUri(), w.Enqueued.GetVcsCommit(), w.Enqueued.GetCores(), bytesx.Unit(w.Enqueued.GetMemory()))
+		}
+	}
+
+	if err = tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nreserved cores %d/%d memory %s/%s vram %s/%s\n", s.Reserved.Cores, s.Limit.Cores, bytesx.Unit(s.Reserved.Memory), bytesx.Unit(s.Limit.Memory), bytesx.Unit(s.Reserved.Vram), bytesx.Unit(s.Limit.Vram))
+
+	return nil
+}
+
+type QueueCancel struct {
+	queueauth
+	ID string `arg:"" name:"id" help:"id of the running workload"`
+}
+
+func (t QueueCancel) Run(gctx *cmdopts.Global) (err error) {
+	c, err := t.client()
+	if err != nil {
+		return err
+	}
+
+	return c.Cancel(gctx.Context, t.ID)
+}
+
+type QueueRequeue struct {
+	queueauth
+	ID string `arg:"" name:"id" help:"id of the archived workload"`
+}
+
+func (t QueueRequeue) Run(gctx *cmdopts.Global) (err error) {
+	c, err := t.client()
+	if err != nil {
+		return err
+	}
+
+	return c.Requeue(gctx.Context, t.ID)
+}
@@ -0,0 +1,209 @@
+package analyticscmds
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/egdaemon/eg/cmd/cmdopts"
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/stringsx"
+	"github.com/egdaemon/eg/interp/analytics"
+
+	_ "github.com/duckdb/duckdb-go/v2"
+)
+
+type Cmd struct {
+	Runs      runs      `cmd:"" help:"list the runs whose analytics have been archived"`
+	Query     query     `cmd:"" help:"query the analytics of a run, lists the available tables when no query is provided"`
+	Slowest   slowest   `cmd:"" help:"slowest operations of a run"`
+	Trends    trends    `cmd:"" help:"compare operation durations across the archived runs, ordered by the operations that slowed down the most"`
+	Coverage  coverage  `cmd:"" help:"coverage recorded by a run per path"`
+	Uncovered uncovered `cmd:"" help:"uncovered lines recorded by a run per path, requires line level coverage"`
+	Tests     tests     `cmd:"" help:"test results recorded by a run, failures first followed by the slowest tests"`
+	Metric    metric    `cmd:"" help:"series of a custom metric recorded by a run"`
+}
+
+type output struct {
+	Format string `name:"format" help:"output format (table,csv,json)" enum:"table,csv,json" default:"table"`
+}
+
+func (t output) render(ctx context.Context, do func(ctx context.Context) (analytics.Table, error)) error {
+	table, err := do(ctx)
+	if err != nil {
+		return err
+	}
+
+	return analytics.Render(os.Stdout, t.Format, table)
+}
+
+// run identifies the analytics of a single run.
+type run struct {
+	Run string `arg:"" name:"run" help:"archived run id, runtime directory or path to an analytics database"`
+}
+
+func (t run) open() (*sql.DB, error) {
+	path, err := analytics.Resolve(t.Run)
+	if err != nil {
+		return nil, err
+	}
+
+	return analytics.Open(path)
+}
+
+type runs struct {
+	output
+}
+
+func (t runs) Run(gctx *cmdopts.Global) (err error) {
+	return t.render(gctx.Context, func(ctx context.Context) (table analytics.Table, err error) {
+		ids, err := analytics.Runs()
+		if err != nil {
+			return table, err
+		}
+
+		table.Columns = []string{"run", "path"}
+		for _, id := range ids {
+			table.Rows = append(table.Rows, []any{id, analytics.Directory(fmt.Sprintf("%s.db", id))})
+		}
+
+		return table, nil
+	})
+}
+
+type query struct {
+	output
+	run
+	SQL string `arg:"" name:"sql" help:"query to execute against the run's analytics" optional:""`
+}
+
+func (t query) Run(gctx *cmdopts.Global) (err error) {
+	db, err := t.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return t.render(gctx.Context, func(ctx context.Context) (analytics.Table, error) {
+		if stringsx.Blank(t.SQL) {
+			return analytics.Tables(ctx, db)
+		}
+
+		return analytics.Query(ctx, db, t.SQL)
+	})
+}
+
+type slowest struct {
+	output
+	run
+	Limit int `name:"limit" help:"maximum number of operations to display" default:"10"`
+}
+
+func (t slowest) Run(gctx *cmdopts.Global) (err error) {
+	db, err := t.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return t.render(gctx.Context, func(ctx context.Context) (analytics.Table, error) {
+		return analytics.Slowest(ctx, db, t.Limit)
+	})
+}
+
+type coverage struct {
+	output
+	run
+}
+
+func (t coverage) Run(gctx *cmdopts.Global) (err error) {
+	db, err := t.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return t.render(gctx.Context, func(ctx context.Context) (analytics.Table, error) {
+		return analytics.Coverage(ctx, db)
+	})
+}
+
+type uncovered struct {
+	output
+	run
+	Limit int `name:"limit" help:"maximum number of paths to display" default:"20"`
+}
+
+func (t uncovered) Run(gctx *cmdopts.Global) (err error) {
+	db, err := t.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return t.render(gctx.Context, func(ctx context.Context) (analytics.Table, error) {
+		return analytics.Uncovered(ctx, db, t.Limit)
+	})
+}
+
+type tests struct {
+	output
+	run
+	Limit int `name:"limit" help:"maximum number of tests to display" default:"20"`
+}
+
+func (t tests) Run(gctx *cmdopts.Global) (err error) {
+	db, err := t.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return t.render(gctx.Context, func(ctx context.Context) (analytics.Table, error) {
+		return analytics.Tests(ctx, db, t.Limit)
+	})
+}
+
+type metric struct {
+	output
+	run
+	Name string `arg:"" name:"name" help:"name of the metric"`
+}
+
+func (t metric) Run(gctx *cmdopts.Global) (err error) {
+	db, err := t.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return t.render(gctx.Context, func(ctx context.Context) (analytics.Table, error) {
+		return analytics.Metric(ctx, db, t.Name)
+	})
+}
+
+type trends struct {
+	output
+	Since time.Duration `name:"since" help:"only include runs within the duration" default:"168h"`
+	Limit int           `name:"limit" help:"maximum number of operations to display" default:"20"`
+}
+
+func (t trends) Run(gctx *cmdopts.Global) (err error) {
+	since := time.Now().Add(-t.Since)
+	ids, err := analytics.Runs()
+	if err != nil {
+		return err
+	}
+
+	db, err := analytics.Attach(gctx.Context, analytics.Since(since, ids...)...)
+	if err != nil {
+		return errorsx.Wrap(err, "unable to load archived runs")
+	}
+	defer db.Close()
+
+	return t.render(gctx.Context, func(ctx context.Context) (analytics.Table, error) {
+		return analytics.Trends(ctx, db, since, t.Limit)
+	})
+}
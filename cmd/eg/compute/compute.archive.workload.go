@@ -64,7 +64,7 @@ func (t archive) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig) (err error)
 
 	log.Println("cacheid", ws.CachedID)
 
-	if err = compile.EnsureRequiredPackages(gctx.Context, filepath.Join(ws.Root, ws.TransDir)); err != nil {
+	if err = compile.EnsureRequiredPackages(gctx.Context, ws); err != nil {
 		return err
 	}
 
@@ -26,10 +26,12 @@ import (
 	"github.com/egdaemon/eg/internal/grpcx"
 	"github.com/egdaemon/eg/internal/md5x"
 	"github.com/egdaemon/eg/internal/podmanx"
+	"github.com/egdaemon/eg/internal/redactx"
 	"github.com/egdaemon/eg/internal/runtimex"
 	"github.com/egdaemon/eg/internal/stringsx"
 	"github.com/egdaemon/eg/internal/wasix"
 	"github.com/egdaemon/eg/interp"
+	"github.com/egdaemon/eg/interp/analytics"
 	"github.com/egdaemon/eg/interp/c8sproxy"
 	"github.com/egdaemon/eg/interp/events"
 	"github.com/egdaemon/eg/interp/execproxy"
@@ -51,7 +53,8 @@ type baremetal struct {
 	GitRemote       string   `name:"git-remote" help:"name of the git remote to use" default:"${vars_git_default_remote_name}"`
 	GitReference    string   `name:"git-ref" help:"name of the branch or commit to checkout" default:"${vars_git_head_reference}"`
 	Environment     []string `name:"env" short:"e" help:"define environment variables and their values to be included"`
-	Secrets         []string `name:"secret" help:"List of secret URIs to use. Examples: chachasm://passphrase@/path/to/file, gcpsm://project-id/secret-name/version, awssm://secret-name?region=us-east-1"`
+	Secrets         []string `name:"secret" help:"List of secret URIs to use. ${vars_secret_uri_examples}. ${vars_secret_uri_fragments}"`
+	Redact          bool     `name:"redact" help:"mask the values of secrets, including their base64 and url encoded forms, within the workload's output"`
 	Clone           bool     `name:"git-clone" help:"allow cloning via git"`
 	InvalidateCache bool     `name:"invalidate-cache" help:"removes workload build cache"`
 	Podman          bool     `name:"podman" help:"enable/disable podman" hidden:"true" negatable:"" default:"true"`
@@ -124,7 +127,7 @@ func (t baremetal) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig, hotswapbin
 		return err
 	}
 
-	if err = compile.EnsureRequiredPackages(ctx, filepath.Join(ws.Root, ws.TransDir)); err != nil {
+	if err = compile.EnsureRequiredPackages(ctx, ws); err != nil {
 		return err
 	}
 
@@ -173,7 +176,12 @@ func (t baremetal) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig, hotswapbin
 	}
 	defer control.Close()
 
-	if db, err = sql.Open("duckdb", filepath.Join(ws.RuntimeDir, "analytics.db")); err != nil {
+	// archived once the database is closed.
+	defer func() {
+		errorsx.Log(analytics.Archive(filepath.Join(ws.RuntimeDir, analytics.Database), uid.String()))
+	}()
+
+	if db, err = sql.Open("duckdb", filepath.Join(ws.RuntimeDir, analytics.Database)); err != nil {
 		return errorsx.Wrap(err, "unable to create analytics.db")
 	}
 	defer db.Close()
@@ -182,9 +190,15 @@ func (t baremetal) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig, hotswapbin
 		return errorsx.Wrap(err, "unable to prepare analytics.db")
 	}
 
+	secretenv, err := envx.FromReader(secrets.NewReader(ctx, t.Secrets...))
+	if err != nil {
+		return errorsx.Wrap(err, "unable to read secrets")
+	}
+
 	gitenv := errorsx.Zero(gitx.LocalEnv(repo, t.GitRemote, t.GitReference))
 	cmdenvb := envx.Build().
-		FromReader(secrets.NewReader(ctx, t.Secrets...)).
+		FromEnviron(secretenv...).
+		FromEnviron(redactx.Environ(t.Redact, secretenv...)...).
 		FromEnv(t.Environment...).
 		FromEnv(
 			"PATH",
@@ -257,6 +271,8 @@ func (t baremetal) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig, hotswapbin
 		return err
 	}
 
+	redact := redactx.FromEnviron(cmdenv...)
+
 	// periodic sampling of system metrics
 	go runners.BackgroundSystemLoad(ctx, db)
 
@@ -267,6 +283,12 @@ func (t baremetal) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig, hotswapbin
 		errorsx.Log(runners.SampleSystemLoad(fctx, db))
 	}()
 
+	defer func() {
+		fctx, done := context.WithTimeout(ctx, 10*time.Second)
+		defer done()
+		errorsx.Log(runners.RecordRedactions(fctx, db, redact))
+	}()
+
 	srv := grpc.NewServer(
 		grpc.Creds(insecure.NewCredentials()), // this is a local socket
 		grpc.ChainUnaryInterceptor(
@@ -283,6 +305,7 @@ func (t baremetal) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig, hotswapbin
 				FromEnviron(gitenv...).
 				FromEnviron(os.Environ()...).Environ(),
 		),
+		execproxy.ExecProxyOptionRedact(redact),
 	).Bind(srv)
 
 	canonicaluri := errorsx.Zero(gitx.CanonicalURI(repo, t.GitRemote))
@@ -312,6 +335,7 @@ func (t baremetal) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig, hotswapbin
 			ragent.Options()...,
 		),
 		c8sproxy.ServiceProxyOptionBaremetal,
+		c8sproxy.ServiceProxyOptionRedact(redact),
 	).Bind(srv)
 
 	go func() {
@@ -331,6 +355,7 @@ func (t baremetal) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig, hotswapbin
 			cc,
 			m.Path,
 			interp.OptionEnviron(cmdenv...),
+			interp.OptionRedact(redact),
 		)
 
 		if err != nil {
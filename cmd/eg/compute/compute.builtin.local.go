@@ -101,7 +101,7 @@ func (t builtinLocal) Run(gctx *cmdopts.Global, hotswapbin *cmdopts.HotswapPath)
 		return errorsx.Wrap(err, "unable to transpile")
 	}
 
-	if err = compile.EnsureRequiredPackages(gctx.Context, filepath.Join(ws.Root, ws.TransDir)); err != nil {
+	if err = compile.EnsureRequiredPackages(gctx.Context, ws); err != nil {
 		return errorsx.Wrap(err, "transpiled failed to ensure required packages")
 	}
 
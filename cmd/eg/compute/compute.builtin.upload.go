@@ -106,7 +106,7 @@ func (t builtinUpload) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig) (err e
 
 	log.Println("cacheid", ws.CachedID)
 
-	if err = compile.EnsureRequiredPackages(gctx.Context, filepath.Join(ws.Root, ws.TransDir)); err != nil {
+	if err = compile.EnsureRequiredPackages(gctx.Context, ws); err != nil {
 		return err
 	}
 
@@ -182,8 +182,13 @@ func (t builtinUpload) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig) (err e
 	// if its the node we upload to it'll cost more due to having to
 	// push the archive to another node that matches the requirements.
 	// in theory we could use redirects to handle that but it'd still take a performance hit.
+	entrypoint, err := workspaces.PathEntry(ws, entry.Path)
+	if err != nil {
+		return err
+	}
+
 	mimetype, buf, err := runners.NewEnqueueUpload(&runners.Enqueued{
-		Entry:       filepath.Join(ws.Module, filepath.Base(entry.Path)),
+		Entry:       entrypoint,
 		Ttl:         uint64(t.RuntimeResources.TTL.Milliseconds()),
 		Cores:       t.RuntimeResources.Cores,
 		Memory:      uint64(t.RuntimeResources.Memory),
@@ -4,16 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strconv"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/egdaemon/eg"
 	"github.com/egdaemon/eg/cmd/cmdopts"
+	"github.com/egdaemon/eg/cmd/cmdssh"
 	"github.com/egdaemon/eg/compile"
 	"github.com/egdaemon/eg/internal/contextx"
 	"github.com/egdaemon/eg/internal/debugx"
@@ -21,40 +24,53 @@ import (
 	"github.com/egdaemon/eg/internal/errorsx"
 	"github.com/egdaemon/eg/internal/gitx"
 	"github.com/egdaemon/eg/internal/md5x"
+	"github.com/egdaemon/eg/internal/netpolicy"
 	"github.com/egdaemon/eg/internal/podmanx"
+	"github.com/egdaemon/eg/internal/redactx"
 	"github.com/egdaemon/eg/internal/stringsx"
 	"github.com/egdaemon/eg/internal/userx"
 	"github.com/egdaemon/eg/internal/wasix"
+	"github.com/egdaemon/eg/interp/analytics"
 	"github.com/egdaemon/eg/interp/c8sproxy"
+	"github.com/egdaemon/eg/interp/provenance"
 	"github.com/egdaemon/eg/runners"
 	"github.com/egdaemon/eg/secrets"
 	"github.com/egdaemon/eg/transpile"
 	"github.com/egdaemon/eg/workspaces"
 	"github.com/go-git/go-git/v6"
 	"github.com/gofrs/uuid/v5"
+	"golang.org/x/crypto/ssh"
 )
 
 type local struct {
 	cmdopts.RuntimeResources
-	Dir              string   `name:"directory" help:"root directory of the repository" default:"${vars_eg_root_directory}"`
-	ModuleDir        string   `name:"moduledir" help:"must be a subdirectory in the provided directory" default:"${vars_workload_directory}" hidden:"true"`
-	Debug            bool     `name:"debug" help:"keep workspace around to debug issues, requires manual cleanup"`
-	Privileged       bool     `name:"privileged" help:"run the initial container in privileged mode"`
-	Dirty            bool     `name:"dirty" help:"include user directories and environment variables" hidden:"true"`
-	Wayland          bool     `name:"wayland" help:"bind-mount the host wayland display socket into the container"`
-	GPU              bool     `name:"gpu" help:"enable gpu support" hidden:"true"`
-	GCPAuto          bool     `name:"gcp-auto" help:"use the default well known path for gcp's application default credentials"`
-	GCP              string   `name:"gcp" help:"path to gcp's application default credentials"`
-	InvalidateCache  bool     `name:"invalidate-cache" help:"removes workload build cache"`
-	EnvironmentPaths []string `name:"envpath" help:"environment files to pass to the module" default:""`
-	Environment      []string `name:"env" short:"e" help:"define environment variables and their values to be included"`
-	GitRemote        string   `name:"git-remote" help:"name of the git remote to use" default:"${vars_git_default_remote_name}"`
-	GitReference     string   `name:"git-ref" help:"name of the branch or commit to checkout" default:"${vars_git_head_reference}"`
-	Ports            []int    `name:"ports" help:"list of ports to publish to the host system" hidden:"true"`
-	ContainerArgs    []string `name:"cargs" help:"list of command line arguments to pass to the root container" hidden:"true"`
-	Secrets          []string `name:"secret" help:"List of secret URIs to use. Examples: chachasm://passphrase@/path/to/file, gcpsm://project-id/secret-name/version, awssm://secret-name?region=us-east-1"`
-	Profile          string   `name:"profile" help:"enable profiling of module runs (cpu,heap,mem,allocs,block)" enum:"cpu,heap,mem,allocs,block," default:""`
-	Name             string   `arg:"" name:"module" help:"name of the workload to run, i.e. the folder name within workload directory" default:"" predictor:"eg.workload"`
+	Dir              string        `name:"directory" help:"root directory of the repository" default:"${vars_eg_root_directory}"`
+	ModuleDir        string        `name:"moduledir" help:"must be a subdirectory in the provided directory" default:"${vars_workload_directory}" hidden:"true"`
+	Debug            bool          `name:"debug" help:"keep workspace around to debug issues, requires manual cleanup"`
+	Privileged       bool          `name:"privileged" help:"run the initial container in privileged mode"`
+	Dirty            bool          `name:"dirty" help:"include user directories and environment variables" hidden:"true"`
+	Wayland          bool          `name:"wayland" help:"bind-mount the host wayland display socket into the container"`
+	GPU              bool          `name:"gpu" help:"enable gpu support" hidden:"true"`
+	GCPAuto          bool          `name:"gcp-auto" help:"use the default well known path for gcp's application default credentials"`
+	GCP              string        `name:"gcp" help:"path to gcp's application default credentials"`
+	InvalidateCache  bool          `name:"invalidate-cache" help:"removes workload build cache"`
+	EnvironmentPaths []string      `name:"envpath" help:"environment files to pass to the module" default:""`
+	Environment      []string      `name:"env" short:"e" help:"define environment variables and their values to be included"`
+	GitRemote        string        `name:"git-remote" help:"name of the git remote to use" default:"${vars_git_default_remote_name}"`
+	GitReference     string        `name:"git-ref" help:"name of the branch or commit to checkout" default:"${vars_git_head_reference}"`
+	Ports            []int         `name:"ports" help:"list of ports to publish to the host system" hidden:"true"`
+	ContainerArgs    []string      `name:"cargs" help:"list of command line arguments to pass to the root container" hidden:"true"`
+	Secrets          []string      `name:"secret" help:"List of secret URIs to use. ${vars_secret_uri_examples}. ${vars_secret_uri_fragments}"`
+	Redact           bool          `name:"redact" help:"mask the values of secrets, including their base64 and url encoded forms, within the workload's output"`
+	NetworkPolicy    string        `name:"network-policy" help:"restrict the workload's egress: open, deny, or a comma separated allow list of hosts (*.example.com for subdomains), CIDRs and @registries for well known package registries" default:""`
+	Provenance       bool          `name:"provenance" help:"attest successful runs, writing an SBOM and provenance signed with your ssh key into the runtime directory. see eg provenance"`
+	SSH              cmdssh.Signer `embed:"" prefix:"provenance-ssh-"`
+	Profile          string        `name:"profile" help:"enable profiling of module runs (cpu,heap,mem,allocs,block)" enum:"cpu,heap,mem,allocs,block," default:""`
+	Plan             bool          `name:"plan" help:"print the operations the workload would perform without executing any commands or containers"`
+	PlanFormat       string        `name:"plan-format" help:"output format of the plan (text,json,dot)" enum:"text,json,dot" default:"text"`
+	PlanOutput       string        `name:"plan-output" help:"write the plan to the specified path instead of stdout" default:""`
+	TUI              bool          `name:"tui" help:"render the operations of the run and their output as they execute"`
+	Name             string        `arg:"" name:"module" help:"name of the workload to run, i.e. the folder name within workload directory" default:"" predictor:"eg.workload"`
 }
 
 func (t local) Run(gctx *cmdopts.Global, hotswapbin *cmdopts.HotswapPath) (err error) {
@@ -80,11 +96,6 @@ func (t local) Run(gctx *cmdopts.Global, hotswapbin *cmdopts.HotswapPath) (err e
 	contextx.WaitGroupAdd(gctx.Context, 1)
 	go contextx.WaitGroupDone(gctx.Context)
 
-	ctx, err := podmanx.WithClient(gctx.Context)
-	if err != nil {
-		return errorsx.Wrap(err, "unable to connect to podman")
-	}
-
 	if ws, err = workspaces.NewLocal(
 		gctx.Context,
 		uuid.Must(uuid.NewV7()),
@@ -115,9 +126,24 @@ func (t local) Run(gctx *cmdopts.Global, hotswapbin *cmdopts.HotswapPath) (err e
 
 	canonicaluri := errorsx.Zero(gitx.CanonicalURI(repo, t.GitRemote))
 
+	policy, err := netpolicy.Parse(t.NetworkPolicy)
+	if err != nil {
+		return err
+	}
+
+	if policy.Enforced() && len(t.Ports) > 0 {
+		return errors.New("ports can't be published when the network policy restricts egress")
+	}
+
+	secretenv, err := envx.FromReader(secrets.NewReader(gctx.Context, t.Secrets...))
+	if err != nil {
+		return errorsx.Wrap(err, "unable to read secrets")
+	}
+
 	envb := envx.Build().
 		Var("GH_TOKEN", githubToken(gctx.Context, canonicaluri)).
-		FromReader(secrets.NewReader(gctx.Context, t.Secrets...)).
+		FromEnviron(secretenv...).
+		FromEnviron(redactx.Environ(t.Redact, secretenv...)...).
 		FromPath(t.EnvironmentPaths...).
 		FromEnv(os.Environ()...).
 		FromEnviron(envx.AutoEnviron(t.Environment...)...).
@@ -136,7 +162,9 @@ func (t local) Run(gctx *cmdopts.Global, hotswapbin *cmdopts.HotswapPath) (err e
 		Var(eg.EnvComputeTTL, t.RuntimeResources.TTL.String()).
 		Var(eg.EnvComputeGPU, strconv.FormatBool(t.GPU)).
 		Var(eg.EnvUnsafeGitCloneEnabled, strconv.FormatBool(false)). // hack to disable cloning
-		Var(eg.EnvComputeProfileMode, t.Profile)
+		Var(eg.EnvComputeProfileMode, t.Profile).
+		Var(eg.EnvComputeEventLog, strconv.FormatBool(t.TUI)).
+		Var(eg.EnvComputeNetworkPolicy, policy.String())
 
 	if t.Dirty {
 		mounthome = runners.AgentOptionAutoMountHome(homedir)
@@ -170,7 +198,7 @@ func (t local) Run(gctx *cmdopts.Global, hotswapbin *cmdopts.HotswapPath) (err e
 		return errorsx.Wrap(err, "unable to transpile")
 	}
 
-	if err = compile.EnsureRequiredPackages(gctx.Context, filepath.Join(ws.Root, ws.TransDir)); err != nil {
+	if err = compile.EnsureRequiredPackages(gctx.Context, ws); err != nil {
 		return err
 	}
 
@@ -186,6 +214,15 @@ func (t local) Run(gctx *cmdopts.Global, hotswapbin *cmdopts.HotswapPath) (err e
 	debugx.Println("modules", modules)
 	debugx.Println("runtime resources", spew.Sdump(t.RuntimeResources))
 
+	if t.Plan {
+		return t.plan(gctx.Context, ws, uid.String(), errorsx.Must(envb.Environ()), modules...)
+	}
+
+	ctx, err := podmanx.WithClient(gctx.Context)
+	if err != nil {
+		return errorsx.Wrap(err, "unable to connect to podman")
+	}
+
 	if err = runners.BuildRootContainerPath(gctx.Context, t.Dir, filepath.Join(ws.RuntimeDir, "Containerfile"), "--platform", platform); err != nil {
 		return err
 	}
@@ -198,6 +235,10 @@ func (t local) Run(gctx *cmdopts.Global, hotswapbin *cmdopts.HotswapPath) (err e
 		privileged = runners.AgentOptionCommandLine("--privileged")
 	}
 
+	if err = runners.NetworkPolicyServe(gctx.Context, ws.RuntimeDir, policy); err != nil {
+		return err
+	}
+
 	ragent := runners.NewRunner(
 		gctx.Context,
 		ws,
@@ -221,36 +262,82 @@ func (t local) Run(gctx *cmdopts.Global, hotswapbin *cmdopts.HotswapPath) (err e
 		runners.AgentOptionCores(t.RuntimeResources.Cores),
 		runners.AgentOptionMemory(uint64(t.RuntimeResources.Memory)),
 		runners.AgentOptionPlatform(platform),
+		runners.AgentOptionNetworkPolicy(policy),
 		gnupghome, // must come after the runtime directory mount to ensure correct mounting order.
 		gcpcreds,  // must come after the mount directory to ensure correct mounting order.
 		gpu,       // enable gpu support
 		wayland,   // must come after the runtime directory mount to ensure correct mounting order.
 	)
 
-	for _, m := range modules {
-		options := append(
-			ragent.Options(),
-			runners.AgentOptionVolumeSpecs(
-				runners.AgentMountReadOnly(
-					filepath.Join(ws.Root, ws.BuildDir, ws.Module, eg.ModuleDir),
-					eg.DefaultMountRoot(eg.RuntimeDirectory, ws.Module, eg.ModuleDir),
-				),
-				runners.AgentMountReadOnly(m.Path, eg.ModuleMount()),
-			)...)
-
-		prepcmd := func(cmd *exec.Cmd) *exec.Cmd {
-			cmd.Dir = ws.Root
-			cmd.Stdout = log.Writer()
-			cmd.Stderr = log.Writer()
-			cmd.Stdin = os.Stdin
-			return cmd
+	run := func(ctx context.Context, stdin io.Reader, output io.Writer) error {
+		for _, m := range modules {
+			options := append(
+				ragent.Options(),
+				runners.AgentOptionVolumeSpecs(
+					runners.AgentMountReadOnly(
+						filepath.Join(ws.Root, ws.BuildDir, ws.Module, eg.ModuleDir),
+						eg.DefaultMountRoot(eg.RuntimeDirectory, ws.Module, eg.ModuleDir),
+					),
+					runners.AgentMountReadOnly(m.Path, eg.ModuleMount()),
+				)...)
+
+			prepcmd := func(cmd *exec.Cmd) *exec.Cmd {
+				cmd.Dir = ws.Root
+				cmd.Stdout = output
+				cmd.Stderr = output
+				cmd.Stdin = stdin
+				return cmd
+			}
+
+			// TODO REVISIT using t.ws.RuntimeDir as moduledir.
+			if err := c8sproxy.PodmanModule(ctx, prepcmd, eg.WorkingDirectory, fmt.Sprintf("eg-%s", uid.String()), ws.RuntimeDir, options...); err != nil {
+				return errorsx.Wrap(err, "module execution failed")
+			}
 		}
 
-		// TODO REVISIT using t.ws.RuntimeDir as moduledir.
-		if err := c8sproxy.PodmanModule(ctx, prepcmd, eg.WorkingDirectory, fmt.Sprintf("eg-%s", uid.String()), ws.RuntimeDir, options...); err != nil {
-			return errorsx.Wrap(err, "module execution failed")
-		}
+		return nil
+	}
+
+	// retain the analytics and provenance of the run for comparison with subsequent runs.
+	defer func() {
+		errorsx.Log(analytics.Archive(filepath.Join(ws.RuntimeDir, analytics.Database), uid.String()))
+		errorsx.Log(provenance.Archive(ws.RuntimeDir, uid.String()))
+	}()
+
+	if t.Provenance {
+		run = t.attest(ws, provenance.Run{
+			ID:         uid.String(),
+			Repository: canonicaluri,
+			Commit:     envx.NewEnvironFromStrings(errorsx.Zero(envb.Environ())...).String("", eg.EnvGitHeadCommit),
+		}, run)
+	}
+
+	if t.TUI {
+		return t.tui(ctx, ws, uid, run)
 	}
 
-	return nil
+	return run(ctx, os.Stdin, log.Writer())
+}
+
+// attest successful runs, signing the provenance with the user's key.
+func (t local) attest(ws workspaces.Context, r provenance.Run, run func(ctx context.Context, stdin io.Reader, output io.Writer) error) func(ctx context.Context, stdin io.Reader, output io.Writer) error {
+	return func(ctx context.Context, stdin io.Reader, output io.Writer) (err error) {
+		signer, err := t.SSH.Load()
+		if err != nil {
+			return err
+		}
+
+		r.Started = time.Now()
+		if err = run(ctx, stdin, output); err != nil {
+			return err
+		}
+		r.Finished = time.Now()
+
+		if err = runners.Attest(ctx, ws.RuntimeDir, signer, r); err != nil {
+			return errorsx.Wrap(err, "unable to attest run")
+		}
+
+		log.Println("provenance signed by", ssh.FingerprintSHA256(signer.PublicKey()), filepath.Join(ws.RuntimeDir, provenance.Statement))
+		return nil
+	}
 }
@@ -0,0 +1,117 @@
+package compute
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/egdaemon/eg"
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/stringsx"
+	"github.com/egdaemon/eg/interp"
+	"github.com/egdaemon/eg/interp/c8s"
+	"github.com/egdaemon/eg/interp/plan"
+	"github.com/egdaemon/eg/transpile"
+	"github.com/egdaemon/eg/workspaces"
+	"github.com/gofrs/uuid/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// plan runs the modules with every command, container and module dispatch stubbed out
+// and renders the resulting operation tree.
+func (t local) plan(ctx context.Context, ws workspaces.Context, runid string, environ []string, modules ...transpile.Compiled) (err error) {
+	var (
+		control net.Listener
+		cc      *grpc.ClientConn
+		out     io.Writer = os.Stdout
+		aid               = uuid.Nil.String()
+		r                 = plan.NewRecorder()
+	)
+
+	if stringsx.Present(t.PlanOutput) {
+		dst, err := os.Create(t.PlanOutput)
+		if err != nil {
+			return errorsx.Wrap(err, "unable to open plan output")
+		}
+		defer dst.Close()
+		out = dst
+	}
+
+	cspath := filepath.Join(ws.RuntimeDir, eg.SocketControl)
+	if control, err = net.Listen("unix", cspath); err != nil {
+		return errorsx.Wrapf(err, "unable to create socket %s", cspath)
+	}
+	defer control.Close()
+
+	srv := grpc.NewServer(
+		grpc.Creds(insecure.NewCredentials()), // this is a local socket
+	)
+	defer srv.GracefulStop()
+
+	// nested modules are planned in process, rooted at the operation that dispatched them.
+	dispatch := func(dctx context.Context, path []string, req *c8s.ModuleRequest) error {
+		return interp.Remote(
+			dctx,
+			ws,
+			aid,
+			runid,
+			cc,
+			filepath.Join(ws.Root, ws.BuildDir, req.Module),
+			interp.OptionAnalysing(true),
+			interp.OptionEnviron(append(append(environ, containerEnviron(req.Options...)...), fmt.Sprintf("%s=%s", eg.EnvComputeOperationPath, strings.Join(path, "/")))...),
+		)
+	}
+
+	plan.NewServiceEvents(r).Bind(srv)
+	plan.NewServiceExec(r).Bind(srv)
+	plan.NewServiceContainers(r, dispatch).Bind(srv)
+
+	go func() {
+		errorsx.Log(errorsx.Wrap(srv.Serve(control), "unable to serve control socket"))
+	}()
+
+	if cc, err = grpc.DialContext(ctx, fmt.Sprintf("unix://%s", cspath), grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock()); err != nil {
+		return errorsx.Wrap(err, "failed to dial control service")
+	}
+	defer cc.Close()
+
+	for _, m := range modules {
+		err := interp.Remote(
+			ctx,
+			ws,
+			aid,
+			runid,
+			cc,
+			m.Path,
+			interp.OptionAnalysing(true),
+			interp.OptionEnviron(environ...),
+		)
+
+		if err != nil {
+			return errorsx.Wrap(err, "failed to plan module")
+		}
+	}
+
+	return plan.Render(out, t.PlanFormat, r.Tree())
+}
+
+// environment variables assigned by the container options of a nested module, i.e. the
+// python operations the module executes.
+func containerEnviron(options ...string) (environ []string) {
+	for i := 0; i+1 < len(options); i++ {
+		if options[i] != "-e" && options[i] != "--env" {
+			continue
+		}
+
+		if v := options[i+1]; strings.Contains(v, "=") {
+			environ = append(environ, v)
+		}
+	}
+
+	return environ
+}
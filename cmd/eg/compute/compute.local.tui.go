@@ -0,0 +1,91 @@
+package compute
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/stringsx"
+	"github.com/egdaemon/eg/interp/events"
+	"github.com/egdaemon/eg/interp/watch"
+	"github.com/egdaemon/eg/workspaces"
+	"github.com/gofrs/uuid/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// tui executes the run while rendering its operations from the run's event log.
+// the output of the root container is attributed to the run itself.
+func (t local) tui(ctx context.Context, ws workspaces.Context, uid uuid.UUID, run func(ctx context.Context, stdin io.Reader, output io.Writer) error) (err error) {
+	var (
+		control net.Listener
+		cc      *grpc.ClientConn
+		stream  events.Agent_WatchClient
+		final   tea.Model
+		failed  = make(chan error, 1)
+	)
+
+	ctx, done := context.WithCancel(ctx)
+	defer done()
+
+	aspath := filepath.Join(ws.RuntimeDir, "watch.socket")
+	if control, err = net.Listen("unix", aspath); err != nil {
+		return errorsx.Wrapf(err, "unable to create socket %s", aspath)
+	}
+	defer control.Close()
+
+	srv := grpc.NewServer(
+		grpc.Creds(insecure.NewCredentials()), // this is a local socket
+	)
+	defer srv.GracefulStop()
+
+	events.NewServiceAgent(ws.RuntimeDir).Bind(srv)
+
+	go func() {
+		errorsx.Log(errorsx.Wrap(srv.Serve(control), "unable to serve watch socket"))
+	}()
+
+	if cc, err = grpc.DialContext(ctx, fmt.Sprintf("unix://%s", aspath), grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock()); err != nil {
+		return errorsx.Wrap(err, "failed to dial watch service")
+	}
+	defer cc.Close()
+
+	if stream, err = events.NewAgentClient(cc).Watch(ctx, &events.RunWatchRequest{Run: &events.RunMetadata{Id: uid.Bytes()}}); err != nil {
+		return errorsx.Wrap(err, "unable to watch run")
+	}
+
+	p := tea.NewProgram(watch.New(stringsx.DefaultIfBlank(t.Name, "run")), tea.WithContext(ctx))
+
+	go func() {
+		for {
+			m, err := stream.Recv()
+			if err != nil {
+				return
+			}
+
+			p.Send(m)
+		}
+	}()
+
+	go func() {
+		cause := run(ctx, nil, watch.Writer(p))
+		p.Send(watch.Done(cause))
+		failed <- cause
+	}()
+
+	if final, err = p.Run(); err != nil {
+		done()
+		return errorsx.Compact(errorsx.Wrap(err, "terminal ui failed"), <-failed)
+	}
+
+	// the user exited before the run concluded.
+	if m, ok := final.(watch.Model); ok && m.Interrupted() {
+		done()
+	}
+
+	return <-failed
+}
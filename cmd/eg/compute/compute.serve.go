@@ -20,6 +20,7 @@ import (
 	"github.com/egdaemon/eg/internal/gitx"
 	"github.com/egdaemon/eg/internal/md5x"
 	"github.com/egdaemon/eg/internal/podmanx"
+	"github.com/egdaemon/eg/internal/redactx"
 	"github.com/egdaemon/eg/internal/stringsx"
 	"github.com/egdaemon/eg/internal/userx"
 	"github.com/egdaemon/eg/internal/wasix"
@@ -42,7 +43,8 @@ type serve struct {
 	Wayland          bool     `name:"wayland" help:"bind-mount the host wayland display socket into the container"`
 	EnvironmentPaths []string `name:"envpath" help:"environment files to pass to the module" default:""`
 	Environment      []string `name:"env" short:"e" help:"define environment variables and their values to be included"`
-	Secrets          []string `name:"secret" help:"List of secret URIs to use. Examples: chachasm://passphrase@/path/to/file, gcpsm://project-id/secret-name/version, awssm://secret-name?region=us-east-1"`
+	Secrets          []string `name:"secret" help:"List of secret URIs to use. ${vars_secret_uri_examples}. ${vars_secret_uri_fragments}"`
+	Redact           bool     `name:"redact" help:"mask the values of secrets, including their base64 and url encoded forms, within the workload's output"`
 	GitRemote        string   `name:"git-remote" help:"name of the git remote to use" default:"${vars_git_default_remote_name}"`
 	GitReference     string   `name:"git-ref" help:"name of the branch or commit to checkout" default:"${vars_git_head_reference}"`
 	Ports            []int    `name:"ports" help:"list of ports to publish to the host system"`
@@ -97,10 +99,16 @@ func (t serve) Run(gctx *cmdopts.Global, hotswapbin *cmdopts.HotswapPath) (err e
 		return errorsx.Wrapf(err, "unable to open git repository: %s", ws.WorkingDir)
 	}
 
+	secretenv, err := envx.FromReader(secrets.NewReader(gctx.Context, t.Secrets...))
+	if err != nil {
+		return errorsx.Wrap(err, "unable to read secrets")
+	}
+
 	log.Println("loading environment file", t.datadir(".eg.env"))
 	envb := envx.Build().
 		FromEnv(os.Environ()...).
-		FromReader(secrets.NewReader(gctx.Context, t.Secrets...)).
+		FromEnviron(secretenv...).
+		FromEnviron(redactx.Environ(t.Redact, secretenv...)...).
 		FromPath(t.EnvironmentPaths...).
 		FromPath(t.datadir(".eg.env")).
 		FromEnv(t.Environment...).
@@ -137,7 +145,7 @@ func (t serve) Run(gctx *cmdopts.Global, hotswapbin *cmdopts.HotswapPath) (err e
 		return err
 	}
 
-	if err = compile.EnsureRequiredPackages(gctx.Context, filepath.Join(ws.Root, ws.TransDir)); err != nil {
+	if err = compile.EnsureRequiredPackages(gctx.Context, ws); err != nil {
 		return err
 	}
 
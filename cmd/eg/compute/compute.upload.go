@@ -24,6 +24,8 @@ import (
 	"github.com/egdaemon/eg/internal/httpx"
 	"github.com/egdaemon/eg/internal/iox"
 	"github.com/egdaemon/eg/internal/md5x"
+	"github.com/egdaemon/eg/internal/netpolicy"
+	"github.com/egdaemon/eg/internal/redactx"
 	"github.com/egdaemon/eg/internal/slicesx"
 	"github.com/egdaemon/eg/internal/sshx"
 	"github.com/egdaemon/eg/internal/stringsx"
@@ -52,7 +54,9 @@ type upload struct {
 	GitRemote        string   `name:"git-remote" help:"name of the git remote to use" default:"${vars_git_default_remote_name}"`
 	GitReference     string   `name:"git-ref" help:"name of the branch or commit to checkout" default:"${vars_git_default_reference}"`
 	GitClone         string   `name:"git-clone-uri" help:"clone uri"`
-	Secrets          []string `name:"secret" help:"List of secret URIs to use. Examples: chachasm://passphrase@/path/to/file, gcpsm://project-id/secret-name/version, awssm://secret-name?region=us-east-1"`
+	Secrets          []string `name:"secret" help:"List of secret URIs to use. ${vars_secret_uri_examples}. ${vars_secret_uri_fragments}"`
+	Redact           bool     `name:"redact" help:"mask the values of secrets, including their base64 and url encoded forms, within the workload's output"`
+	NetworkPolicy    string   `name:"network-policy" help:"restrict the workload's egress: open, deny, or a comma separated allow list of hosts (*.example.com for subdomains), CIDRs and @registries for well known package registries" default:""`
 }
 
 func (t upload) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig) (err error) {
@@ -87,7 +91,7 @@ func (t upload) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig) (err error) {
 
 	log.Println("cacheid", ws.CachedID)
 
-	if err = compile.EnsureRequiredPackages(gctx.Context, filepath.Join(ws.Root, ws.TransDir)); err != nil {
+	if err = compile.EnsureRequiredPackages(gctx.Context, ws); err != nil {
 		return err
 	}
 
@@ -124,10 +128,22 @@ func (t upload) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig) (err error) {
 
 	t.GitClone = stringsx.First(t.GitClone, errorsx.Zero(gitx.QuirkCloneURI(repo, t.GitRemote)))
 
+	policy, err := netpolicy.Parse(t.NetworkPolicy)
+	if err != nil {
+		return err
+	}
+
+	secretenv, err := envx.FromReader(secrets.NewReader(gctx.Context, t.Secrets...))
+	if err != nil {
+		return errorsx.Wrap(err, "unable to read secrets")
+	}
+
 	envb := envx.Build().
 		Var(eg.EnvComputeArch, t.Arch).
 		Var(eg.EnvComputeOS, t.OS).
-		FromReader(secrets.NewReader(gctx.Context, t.Secrets...)).
+		Var(eg.EnvComputeNetworkPolicy, policy.String()).
+		FromEnviron(secretenv...).
+		FromEnviron(redactx.Environ(t.Redact, secretenv...)...).
 		FromEnviron(envx.Dirty(t.Dirty)...).
 		FromPath(t.EnvironmentPaths...).
 		FromEnviron(envx.AutoEnviron(t.Environment...)...).
@@ -174,8 +190,13 @@ func (t upload) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig) (err error) {
 	// if its the node we upload to it'll cost more due to having to
 	// push the archive to another node that matches the requirements.
 	// in theory we could use redirects to handle that but it'd still take a performance hit.
+	entrypoint, err := workspaces.PathEntry(ws, entry.Path)
+	if err != nil {
+		return err
+	}
+
 	mimetype, buf, err := runners.NewEnqueueUpload(&runners.Enqueued{
-		Entry:       filepath.Join(ws.Module, filepath.Base(entry.Path)),
+		Entry:       entrypoint,
 		Ttl:         uint64(t.RuntimeResources.TTL.Milliseconds()),
 		Cores:       t.RuntimeResources.Cores,
 		Memory:      uint64(t.RuntimeResources.Memory),
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/egdaemon/eg"
@@ -21,6 +22,7 @@ import (
 	"github.com/egdaemon/eg/internal/runtimex"
 	"github.com/egdaemon/eg/internal/sshx"
 	"github.com/egdaemon/eg/internal/userx"
+	"github.com/egdaemon/eg/notary"
 	"github.com/egdaemon/eg/runners"
 	"golang.org/x/crypto/ssh"
 	"golang.org/x/oauth2"
@@ -30,16 +32,17 @@ import (
 
 type daemon struct {
 	cmdopts.RuntimeResources
-	AccountID     string   `name:"account" help:"account to register runner with" default:"${vars_account_id}" required:"true"`
-	MachineID     string   `name:"machine" help:"unique id for this particular machine" default:"${vars_machine_id}" required:"true"`
-	Seed          string   `name:"seed" help:"seed for generating ssh credentials in a consistent manner" default:"${vars_entropy_seed}"`
-	SSHKeyPath    string   `name:"sshkeypath" help:"path to ssh key to use" default:"${vars_ssh_key_path}"`
-	SSHAgentPath  string   `name:"sshagentpath" help:"ssh agent socket path" default:"${vars_runtime_directory}/ssh.agent.socket"`
-	SSHKnownHosts string   `name:"sshknownhostspath" help:"ssh known hosts path" default:"${vars_ssh_known_hosts_path}"`
-	Autodownload  bool     `name:"autodownload" help:"enable/disable the basic download scheduler" default:"true"`
-	CacheDir      string   `name:"directory" help:"local cache directory" default:"${vars_cache_directory}"`
-	MountDirs     []string `name:"mounts" short:"m" help:"folders to mount using podman mount specs" default:""`
-	EnvVars       []string `name:"env" short:"e" help:"environment variables to import"`
+	AccountID     string        `name:"account" help:"account to register runner with" default:"${vars_account_id}" required:"true"`
+	MachineID     string        `name:"machine" help:"unique id for this particular machine" default:"${vars_machine_id}" required:"true"`
+	Seed          string        `name:"seed" help:"seed for generating ssh credentials in a consistent manner" default:"${vars_entropy_seed}"`
+	SSHKeyPath    string        `name:"sshkeypath" help:"path to ssh key to use" default:"${vars_ssh_key_path}"`
+	SSHAgentPath  string        `name:"sshagentpath" help:"ssh agent socket path" default:"${vars_runtime_directory}/ssh.agent.socket"`
+	SSHKnownHosts string        `name:"sshknownhostspath" help:"ssh known hosts path" default:"${vars_ssh_known_hosts_path}"`
+	Autodownload  bool          `name:"autodownload" help:"enable/disable the basic download scheduler" default:"true"`
+	CacheDir      string        `name:"directory" help:"local cache directory" default:"${vars_cache_directory}"`
+	MountDirs     []string      `name:"mounts" short:"m" help:"folders to mount using podman mount specs" default:""`
+	EnvVars       []string      `name:"env" short:"e" help:"environment variables to import"`
+	CancelGrace   time.Duration `name:"cancel-grace" help:"duration cancelled workloads are given to run their cleanup operations before being terminated" default:"1m"`
 }
 
 func (t daemon) signer(keygen cmdopts.KeyGenSeeded) (ssh.Signer, error) {
@@ -62,6 +65,7 @@ func (t daemon) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig, keygen cmdopt
 	rm := runners.NewResourceManager(runners.NewRuntimeResources())
 	rundirs := runners.DefaultSpoolDirs()
 	compiledirs := runners.NewSpoolDir(userx.DefaultCacheDirectory("compilespool"))
+	cancellations := runners.NewCancellations(t.CancelGrace)
 
 	// we want to set the umask to 0002 to ensure that the cache (and other) directory are readable by the group.
 	runtimex.Umask(0002)
@@ -92,7 +96,19 @@ func (t daemon) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig, keygen cmdopt
 		tokensrc,
 	)
 
-	if err = daemons.HTTP(gctx, httpl, rm, compiledirs); err != nil {
+	local, err := notary.AuthorizedKeys(notary.PublicKeyPath())
+	if err != nil {
+		return errorsx.Wrap(err, "unable to load authorized keys")
+	}
+
+	// the runner only serves its own account, every authorized key is bound to it.
+	authorized := []notary.AuthorizedKey{notary.Bind(signer.PublicKey(), t.AccountID)}
+	for _, k := range local {
+		authorized = append(authorized, notary.Bind(k, t.AccountID))
+	}
+
+	verifier := notary.NewVerifier(authorized)
+	if err = daemons.HTTP(gctx, httpl, verifier, t.AccountID, rm, rundirs, compiledirs, cancellations); err != nil {
 		return err
 	}
 	defer httpl.Close()
@@ -101,7 +117,7 @@ func (t daemon) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig, keygen cmdopt
 		return err
 	}
 
-	if err = daemons.Agent(gctx, grpcl); err != nil {
+	if err = daemons.Agent(gctx, grpcl, cancellations); err != nil {
 		return errorsx.Wrap(err, "unable to initialize daemon")
 	}
 
@@ -164,5 +180,7 @@ func (t daemon) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig, keygen cmdopt
 		),
 		runners.QueueOptionLogVerbosity(gctx.Verbosity),
 		runners.QueueOptionGPU(t.RuntimeResources.Vram > 0),
+		runners.QueueOptionCancellations(cancellations),
+		runners.QueueOptionSigner(signer),
 	)
 }
@@ -2,6 +2,7 @@ package daemons
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
@@ -17,8 +18,11 @@ import (
 	"github.com/egdaemon/eg/interp/events"
 	"github.com/egdaemon/eg/runners"
 	"github.com/egdaemon/eg/workspaces"
+	"github.com/gofrs/uuid/v5"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
 func DefaultAgentSocketPath() string {
@@ -89,13 +93,22 @@ func AutoRunnerClient(global *cmdopts.Global, ws workspaces.Context, uid string,
 }
 
 // local agent for managing jobs
-func Agent(global *cmdopts.Global, grpcl net.Listener) (err error) {
+func Agent(global *cmdopts.Global, grpcl net.Listener, cancellations *runners.Cancellations) (err error) {
 	srv := grpc.NewServer(
 		grpc.Creds(insecure.NewCredentials()), // this is a local socket
 	)
 
 	events.NewServiceAgent(
 		errorsx.Must(filepath.Abs(runners.DefaultManagerDirectory())),
+		events.AgentServiceOptionCancel(func(ctx context.Context, id uuid.UUID) error {
+			if err := cancellations.Cancel(id.String()); errors.Is(err, runners.ErrWorkloadNotRunning) {
+				return status.Error(codes.NotFound, err.Error())
+			} else if err != nil {
+				return status.Error(codes.Internal, err.Error())
+			}
+
+			return nil
+		}),
 	).Bind(srv)
 
 	global.Cleanup.Add(1)
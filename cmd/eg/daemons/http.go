@@ -5,36 +5,46 @@ import (
 	"net"
 	"net/http"
 
-	"github.com/egdaemon/eg"
 	"github.com/egdaemon/eg/cmd/cmdopts"
 	"github.com/egdaemon/eg/internal/envx"
 	"github.com/egdaemon/eg/internal/httpx"
+	"github.com/egdaemon/eg/notary"
 	"github.com/egdaemon/eg/runners"
 	"github.com/gorilla/mux"
 	"github.com/justinas/alice"
 )
 
-func HTTP(global *cmdopts.Global, httpl net.Listener, rm *runners.ResourceManager, compiledirs runners.SpoolDirs) (err error) {
+func HTTP(global *cmdopts.Global, httpl net.Listener, v notary.Verifier, account string, rm *runners.ResourceManager, rundirs runners.SpoolDirs, compiledirs runners.SpoolDirs, cancellations *runners.Cancellations) (err error) {
 	httpmux := mux.NewRouter()
 	httpmux.NotFoundHandler = alice.New(httpx.RouteInvoked).ThenFunc(httpx.NotFound)
 
 	httpmux.HandleFunc("/healthz", httpx.Healthz(envx.Int(http.StatusOK, cmdopts.EnvHealthzCode))).Methods("GET")
 
-	// gates the runner's push HTTP surface (POST /b/upload, POST /c/enqueue) as a
-	// stopgap ahead of real request authentication -- see the accompanying plan doc.
-	apigate := httpx.GatedResponse(envx.Boolean(false, eg.EnvComputeAPIEnabled), http.StatusForbidden)
+	// the runner's push HTTP surface (POST /b/upload, POST /c/enqueue, /q) requires requests
+	// signed by an authorized key carrying a compute token for the runner's account.
+	// see notary.Verifier for the details.
+	readable := v.Middleware(account, notary.ComputeRead)
+	modifiable := v.Middleware(account, notary.ComputeModify)
 
 	// POST /b/upload accepts a pre-built kernel archive + environment file
 	// pushed to this runner and enqueues them directly. See http.upload.go
 	// for the (independently testable) handler implementation.
-	httpmux.Handle("/b/upload", alice.New(httpx.RouteInvoked, apigate).Then(NewUploadHandler())).Methods(http.MethodPost)
+	httpmux.Handle("/b/upload", alice.New(httpx.RouteInvoked, modifiable).Then(NewUploadHandler())).Methods(http.MethodPost)
 
 	// POST /c/enqueue pushes a source-ref submission (instead of a pre-built
 	// archive) to this runner: the runner clones and compiles it itself,
 	// asynchronously, after deciding synchronously whether to admit it based
 	// on current load. See http.enqueue.go for the (independently testable)
 	// handler implementation.
-	httpmux.Handle("/c/enqueue", alice.New(httpx.RouteInvoked, apigate).Then(NewEnqueueHandler(compiledirs, rm))).Methods(http.MethodPost)
+	httpmux.Handle("/c/enqueue", alice.New(httpx.RouteInvoked, modifiable).Then(NewEnqueueHandler(compiledirs, rm))).Methods(http.MethodPost)
+
+	// GET /q lists the workloads of the run spool along with the reserved resources,
+	// POST /q/{id}/cancel cancels a running workload and POST /q/{id}/requeue
+	// requeues an archived workload. See http.queue.go and runners.QueueClient.
+	queue := NewQueueHandler(rundirs, rm, cancellations)
+	httpmux.Handle("/q", alice.New(httpx.RouteInvoked, readable).ThenFunc(queue.Status)).Methods(http.MethodGet)
+	httpmux.Handle("/q/{id}/cancel", alice.New(httpx.RouteInvoked, modifiable).ThenFunc(queue.Cancel)).Methods(http.MethodPost)
+	httpmux.Handle("/q/{id}/requeue", alice.New(httpx.RouteInvoked, modifiable).ThenFunc(queue.Requeue)).Methods(http.MethodPost)
 
 	global.Cleanup.Go(func() {
 		defer global.Shutdown(nil)
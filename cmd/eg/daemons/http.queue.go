@@ -0,0 +1,87 @@
+package daemons
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+	"net/http"
+
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/httpx"
+	"github.com/egdaemon/eg/runners"
+	"github.com/gofrs/uuid/v5"
+	"github.com/gorilla/mux"
+)
+
+// NewQueueHandler constructs the handlers for interrogating and controlling
+// the run spool. see runners.QueueClient.
+func NewQueueHandler(dirs runners.SpoolDirs, rm *runners.ResourceManager, cancellations *runners.Cancellations) *QueueHandler {
+	return &QueueHandler{
+		Dirs:          dirs,
+		RM:            rm,
+		Cancellations: cancellations,
+	}
+}
+
+// QueueHandler implements GET /q, POST /q/{id}/cancel and POST /q/{id}/requeue.
+// Dirs/RM/Cancellations are exported so tests can point this at an isolated
+// spool instead of the process defaults.
+type QueueHandler struct {
+	Dirs          runners.SpoolDirs
+	RM            *runners.ResourceManager
+	Cancellations *runners.Cancellations
+}
+
+// Status lists the queued, running, and archived workloads along with the reserved resources.
+func (t *QueueHandler) Status(w http.ResponseWriter, r *http.Request) {
+	s, err := runners.NewQueueStatus(t.Dirs, t.RM)
+	if err != nil {
+		log.Println(errorsx.Wrap(err, "unable to determine queue status"))
+		errorsx.Log(httpx.WriteEmptyJSON(w, http.StatusInternalServerError))
+		return
+	}
+
+	errorsx.Log(errorsx.Wrap(httpx.WriteJSON(w, httpx.GetBuffer(r), s), "unable to write response"))
+}
+
+// Cancel a running workload.
+func (t *QueueHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	if err := t.Cancellations.Cancel(id); errors.Is(err, runners.ErrWorkloadNotRunning) {
+		errorsx.Log(httpx.WriteEmptyJSON(w, http.StatusNotFound))
+		return
+	} else if err != nil {
+		log.Println(errorsx.Wrapf(err, "unable to cancel workload: %s", id))
+		errorsx.Log(httpx.WriteEmptyJSON(w, http.StatusInternalServerError))
+		return
+	}
+
+	log.Println("cancelled workload", id)
+	errorsx.Log(httpx.WriteEmptyJSON(w, http.StatusAccepted))
+}
+
+// Requeue an archived workload.
+func (t *QueueHandler) Requeue(w http.ResponseWriter, r *http.Request) {
+	uid, err := uuid.FromString(mux.Vars(r)["id"])
+	if err != nil {
+		log.Println(errorsx.Wrap(err, "invalid requeue request, malformed id"))
+		errorsx.Log(httpx.WriteEmptyJSON(w, http.StatusBadRequest))
+		return
+	}
+
+	if err = t.Dirs.Requeue(uid); errors.Is(err, fs.ErrExist) {
+		errorsx.Log(httpx.WriteEmptyJSON(w, http.StatusConflict))
+		return
+	} else if errors.Is(err, fs.ErrNotExist) {
+		errorsx.Log(httpx.WriteEmptyJSON(w, http.StatusNotFound))
+		return
+	} else if err != nil {
+		log.Println(errorsx.Wrapf(err, "unable to requeue workload: %s", uid))
+		errorsx.Log(httpx.WriteEmptyJSON(w, http.StatusInternalServerError))
+		return
+	}
+
+	log.Println("requeued workload", uid)
+	errorsx.Log(httpx.WriteEmptyJSON(w, http.StatusAccepted))
+}
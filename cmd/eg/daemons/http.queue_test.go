@@ -0,0 +1,78 @@
+package daemons_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/egdaemon/eg/cmd/eg/daemons"
+	"github.com/egdaemon/eg/runners"
+	"github.com/gofrs/uuid/v5"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueueHandler(t *testing.T) {
+	t.Run("status lists the spooled workloads without credentials", func(t *testing.T) {
+		h := daemons.NewQueueHandler(
+			runners.NewSpoolDir(t.TempDir()),
+			runners.NewResourceManager(runners.RuntimeResources{Cores: 10, Memory: 10, Vram: 10}),
+			runners.NewCancellations(time.Minute),
+		)
+
+		uid := uuid.Must(uuid.NewV7())
+		encoded, err := json.Marshal(runners.EnqueuedDequeueResponse{
+			Enqueued:    &runners.Enqueued{Id: uid.String(), VcsUri: "https://example.com/repo.git", Cores: 1},
+			AccessToken: "secret",
+		})
+		require.NoError(t, err)
+		require.NoError(t, h.Dirs.Download(uid, "metadata.json", bytes.NewReader(encoded)))
+		require.NoError(t, h.Dirs.Enqueue(uid))
+
+		w := httptest.NewRecorder()
+		h.Status(w, httptest.NewRequest(http.MethodGet, "/q", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		require.NotContains(t, w.Body.String(), "secret")
+
+		var s runners.QueueStatus
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &s))
+		require.Len(t, s.Queued, 1)
+		require.Empty(t, s.Running)
+		require.Equal(t, uid.String(), s.Queued[0].ID)
+		require.Equal(t, "https://example.com/repo.git", s.Queued[0].Enqueued.VcsUri)
+		require.Equal(t, uint64(10), s.Limit.Cores)
+	})
+
+	t.Run("cancelling a workload that isn't running", func(t *testing.T) {
+		h := daemons.NewQueueHandler(runners.NewSpoolDir(t.TempDir()), runners.NewResourceManager(runners.RuntimeResources{}), runners.NewCancellations(time.Minute))
+
+		w := httptest.NewRecorder()
+		h.Cancel(w, mux.SetURLVars(httptest.NewRequest(http.MethodPost, "/q/unknown/cancel", nil), map[string]string{"id": "unknown"}))
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("requeue", func(t *testing.T) {
+		h := daemons.NewQueueHandler(runners.NewSpoolDir(t.TempDir()), runners.NewResourceManager(runners.RuntimeResources{}), runners.NewCancellations(time.Minute))
+		requeue := func(id string) int {
+			w := httptest.NewRecorder()
+			h.Requeue(w, mux.SetURLVars(httptest.NewRequest(http.MethodPost, "/q/"+id+"/requeue", nil), map[string]string{"id": id}))
+			return w.Code
+		}
+
+		require.Equal(t, http.StatusBadRequest, requeue("malformed"))
+		require.Equal(t, http.StatusNotFound, requeue(uuid.Must(uuid.NewV7()).String()))
+
+		uid := uuid.Must(uuid.NewV7())
+		require.NoError(t, h.Dirs.Download(uid, "metadata.json", bytes.NewReader([]byte("{}"))))
+		require.NoError(t, h.Dirs.Enqueue(uid))
+		_, err := h.Dirs.Dequeue()
+		require.NoError(t, err)
+		require.NoError(t, h.Dirs.Completed(uid))
+
+		require.Equal(t, http.StatusAccepted, requeue(uid.String()))
+		require.Equal(t, http.StatusConflict, requeue(uid.String()))
+	})
+}
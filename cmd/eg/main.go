@@ -24,8 +24,10 @@ import (
 	"github.com/egdaemon/eg/cmd/cmdsecret"
 	"github.com/egdaemon/eg/cmd/cmdssh"
 	"github.com/egdaemon/eg/cmd/eg/accountcmds"
+	"github.com/egdaemon/eg/cmd/eg/analyticscmds"
 	"github.com/egdaemon/eg/cmd/eg/compute"
 	"github.com/egdaemon/eg/cmd/eg/daemons"
+	"github.com/egdaemon/eg/cmd/eg/provenancecmds"
 	"github.com/egdaemon/eg/internal/bytesx"
 	"github.com/egdaemon/eg/internal/contextx"
 	"github.com/egdaemon/eg/internal/envx"
@@ -79,6 +81,8 @@ func main() {
 		cmdopts.TLSConfig
 		Version            cmdopts.Version              `cmd:"" help:"display versioning information"`
 		Compute            compute.Cmd                  `cmd:"" help:"commands for running compute workloads"`
+		Analytics          analyticscmds.Cmd            `cmd:"" help:"query the analytics recorded by runs"`
+		Provenance         provenancecmds.Cmd           `cmd:"" help:"inspect and verify the signed provenance and SBOM of runs"`
 		Module             module                       `cmd:"" help:"executes a compiled module directly" hidden:"true"`
 		Wasi               wasiCmd                      `cmd:"" help:"run a standalone wasi module" hidden:"true"`
 		Daemon             daemon                       `cmd:"" help:"run in daemon mode letting the control plane push jobs to machines" hidden:"true"`
@@ -162,6 +166,7 @@ func main() {
 			"vars_git_head_reference":      envx.String("HEAD", "GITHUB_SHA"),      // github actions environment variable
 			"vars_workload_directory":      eg.DefaultModuleDirectory(),
 		},
+		cmdopts.SecretVars(),
 		kong.UsageOnError(),
 		kong.Bind(
 			&shellcli.Global,
@@ -0,0 +1,155 @@
+package provenancecmds
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/egdaemon/eg/cmd/cmdopts"
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/interp/provenance"
+	"github.com/egdaemon/eg/notary"
+	"golang.org/x/crypto/ssh"
+)
+
+type Cmd struct {
+	Show   show   `cmd:"" help:"display the provenance statement of a run"`
+	SBOM   sbom   `cmd:"" name:"sbom" help:"display the SBOM of a run"`
+	Verify verify `cmd:"" help:"verify the signature of a run's provenance and optionally the digests of its artifacts"`
+}
+
+// run identifies the provenance of a single run.
+type run struct {
+	Run string `arg:"" name:"run" help:"archived run id or runtime directory"`
+}
+
+func (t run) resolve(name string) (string, error) {
+	return provenance.Resolve(t.Run, name)
+}
+
+// trusted keys for verifying the signature of the provenance, defaults to the key runs are signed with.
+type trusted struct {
+	AuthorizedKeys []string `name:"authorized-keys" help:"authorized keys files containing the keys trusted to sign provenance" default:"${vars_ssh_key_path}.pub"`
+}
+
+func (t trusted) keys() (keys []ssh.PublicKey, err error) {
+	if keys, err = notary.AuthorizedKeys(t.AuthorizedKeys...); err != nil {
+		return nil, err
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no keys found within %v", t.AuthorizedKeys)
+	}
+
+	return keys, nil
+}
+
+func (t run) statement(trust trusted) (payload []byte, signer ssh.PublicKey, err error) {
+	keys, err := trust.keys()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	path, err := t.resolve(provenance.Statement)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	envelope, err := provenance.ReadEnvelope(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return provenance.Verify(envelope, keys...)
+}
+
+func indent(encoded []byte) error {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, encoded, "", "  "); err != nil {
+		return errorsx.Wrap(err, "invalid json")
+	}
+
+	_, err := fmt.Fprintln(os.Stdout, buf.String())
+	return err
+}
+
+type show struct {
+	run
+	trusted
+}
+
+func (t show) Run(gctx *cmdopts.Global) (err error) {
+	payload, _, err := t.statement(t.trusted)
+	if err != nil {
+		return err
+	}
+
+	return indent(payload)
+}
+
+type sbom struct {
+	run
+}
+
+func (t sbom) Run(gctx *cmdopts.Global) (err error) {
+	path, err := t.resolve(provenance.SBOM)
+	if err != nil {
+		return err
+	}
+
+	encoded, err := os.ReadFile(path)
+	if err != nil {
+		return errorsx.Wrap(err, "unable to read sbom")
+	}
+
+	return indent(encoded)
+}
+
+type verify struct {
+	run
+	trusted
+	Artifacts []string `name:"artifact" help:"artifacts to verify against the subjects of the provenance"`
+}
+
+func (t verify) Run(gctx *cmdopts.Global) (err error) {
+	var (
+		subject struct {
+			Subject []provenance.Subject `json:"subject"`
+		}
+	)
+
+	payload, signer, err := t.statement(t.trusted)
+	if err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(payload, &subject); err != nil {
+		return errorsx.Wrap(err, "invalid provenance statement")
+	}
+
+	for _, path := range t.Artifacts {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return errorsx.Wrapf(err, "unable to read artifact %s", path)
+		}
+
+		if !matches(subject.Subject, provenance.NewSubject(filepath.Base(path), content)) {
+			return fmt.Errorf("artifact %s does not match the subjects of the provenance", path)
+		}
+	}
+
+	fmt.Println("provenance verified, signed by", ssh.FingerprintSHA256(signer))
+	return nil
+}
+
+func matches(subjects []provenance.Subject, artifact provenance.Subject) bool {
+	for _, s := range subjects {
+		if s.Name == artifact.Name && s.Digest["sha256"] == artifact.Digest["sha256"] {
+			return true
+		}
+	}
+
+	return false
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/rand"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -29,7 +30,9 @@ import (
 	"github.com/egdaemon/eg/internal/execx"
 	"github.com/egdaemon/eg/internal/fsx"
 	"github.com/egdaemon/eg/internal/gitx"
+	"github.com/egdaemon/eg/internal/netpolicy"
 	"github.com/egdaemon/eg/internal/podmanx"
+	"github.com/egdaemon/eg/internal/redactx"
 	"github.com/egdaemon/eg/internal/runtimex"
 	"github.com/egdaemon/eg/internal/stringsx"
 	"github.com/egdaemon/eg/internal/wasix"
@@ -38,6 +41,7 @@ import (
 	"github.com/egdaemon/eg/interp/c8sproxy"
 	"github.com/egdaemon/eg/interp/events"
 	"github.com/egdaemon/eg/interp/execproxy"
+	"github.com/egdaemon/eg/interp/provenance"
 	"github.com/egdaemon/eg/interp/runtime/wasi/ffiwasinet"
 	"github.com/egdaemon/eg/runners"
 	"github.com/egdaemon/eg/runtime/wasi/egunsafe/egworkloads"
@@ -238,6 +242,75 @@ func recordDiscoveredWorkloads(ctx context.Context, db *sql.DB, dir string, envi
 	}
 }
 
+// records the material of the run's provenance within the analytics.
+func recordProvenance[T any](db *sql.DB, name string) func(ctx context.Context, v T) {
+	return func(ctx context.Context, v T) {
+		m, err := provenance.Metric(name, v)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		errorsx.Log(errorsx.Wrap(events.RecordMetric(ctx, db, m), "unable to record provenance"))
+	}
+}
+
+// dispatches the material of the run's provenance to the root module.
+func dispatchProvenance[T any](d events.EventsClient, name string) func(ctx context.Context, v T) {
+	return func(ctx context.Context, v T) {
+		m, err := provenance.Metric(name, v)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		_, err = d.Dispatch(ctx, events.NewDispatch(m))
+		errorsx.Log(errorsx.Wrap(err, "unable to dispatch provenance"))
+	}
+}
+
+// serves the proxy enforcing the network policy on the loopback interface, permitted connections
+// are tunneled through the proxy served by the host (see runners.NetworkPolicyServe). permitted
+// connections are recorded as a metric so runs can show what they accessed, rejected connections
+// are dispatched as network violation events.
+func networkpolicy(ctx context.Context, d events.EventsServer, p netpolicy.Policy) (addr string, err error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", errorsx.Wrap(err, "unable to create network policy proxy")
+	}
+
+	observe := func(ctx context.Context, c netpolicy.Connection) {
+		if !c.Allowed {
+			log.Println("network policy violation", net.JoinHostPort(c.Host, strconv.Itoa(c.Port)))
+			_, err := d.Dispatch(ctx, events.NewDispatch(events.NewNetworkViolation(&events.NetworkViolation{Host: c.Host, Port: uint32(c.Port)})))
+			errorsx.Log(errorsx.Wrap(err, "unable to record network policy violation"))
+			return
+		}
+
+		encoded, err := json.Marshal(c)
+		if err != nil {
+			log.Println("unable to encode network connection", err)
+			return
+		}
+
+		_, err = d.Dispatch(ctx, events.NewDispatch(events.NewMetric(netpolicy.MetricName, encoded)))
+		errorsx.Log(errorsx.Wrap(err, "unable to record network connection"))
+	}
+
+	proxy := netpolicy.NewProxy(
+		p,
+		netpolicy.ProxyOptionPermit(netpolicy.ControlPlane()...),
+		netpolicy.ProxyOptionDialer(netpolicy.TunnelDialer(eg.DefaultRuntimeDirectory(eg.SocketNetwork))),
+		netpolicy.ProxyOptionObserve(observe),
+	)
+
+	go func() {
+		errorsx.Log(errorsx.Wrap(netpolicy.Serve(ctx, l, proxy), "network policy proxy failed"))
+	}()
+
+	return l.Addr().String(), nil
+}
+
 func (t module) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig) (err error) {
 	var (
 		ws      workspaces.Context
@@ -247,6 +320,7 @@ func (t module) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig) (err error) {
 		hostnet = envx.Toggle(runners.AgentOptionCommandLine("--network", "host"), runners.AgentOptionNoop, envx.Boolean(false, eg.EnvExperimentalDisableHostNetwork)) // ipv4 group bullshit. pretty sure its a podman 4 issue that was resolved in podman 5. this is 'safe' to do because we are already in a container.
 		cc      grpc.ClientConnInterface
 		cmdenv  []string
+		redact  = redactx.FromEnviron(os.Environ()...)
 	)
 
 	// ensure when we run modules our umask is set to allow git clones to work properly
@@ -284,6 +358,7 @@ func (t module) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig) (err error) {
 			control   net.Listener
 			db        *sql.DB
 			vmemlimit int64
+			policy    netpolicy.Policy
 		)
 
 		// automatically detect the correct number of max procs for the module
@@ -327,6 +402,62 @@ func (t module) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig) (err error) {
 
 		recordDiscoveredWorkloads(gctx.Context, db, eg.DefaultModuleDirectory(t.Dir), os.Environ()...)
 
+		var (
+			dispatchopts []events.DispatchOption
+			execopts     = []execproxy.ExecProxyOption{
+				execproxy.ExecProxyOptionRedact(redact),
+				execproxy.ExecProxyOptionProvenance(recordProvenance[provenance.Command](db, provenance.MetricCommand)),
+			}
+		)
+
+		// the run is being watched, record events and command output to the event log.
+		if envx.Boolean(false, eg.EnvComputeEventLog) {
+			var (
+				elog *events.Log
+				ecc  *grpc.ClientConn
+			)
+
+			if elog, err = events.NewLogEnsureDir(events.NewLogDirFromRunID(t.RuntimeDir, uid)); err != nil {
+				return errorsx.Wrap(err, "unable to create event log")
+			}
+
+			if ecc, err = grpc.DialContext(gctx.Context, fmt.Sprintf("unix://%s", cspath), grpc.WithTransportCredentials(insecure.NewCredentials())); err != nil {
+				return errorsx.Wrapf(err, "unable to dial %s", cspath)
+			}
+			defer ecc.Close()
+
+			dispatchopts = append(dispatchopts, events.DispatchOptionLog(elog))
+			execopts = append(execopts, execproxy.ExecProxyOptionEvents(events.NewEventsClient(ecc)))
+		}
+
+		dispatch := events.NewServiceDispatch(db, dispatchopts...)
+
+		if policy, err = netpolicy.FromEnviron(os.Environ()...); err != nil {
+			return errorsx.Wrap(err, "invalid network policy")
+		}
+
+		// the root container has no network when egress is restricted, workloads reach the permitted
+		// hosts through the proxy served on the loopback interface. nested containers use the host
+		// network of the nested podman which is the root container's network, sharing the proxy.
+		if policy.Enforced() {
+			var addr string
+			if addr, err = networkpolicy(gctx.Context, dispatch, policy); err != nil {
+				return err
+			}
+
+			proxyenv := make([]runners.AgentOption, 0, 8)
+			for _, v := range netpolicy.Environ(addr) {
+				k, v, _ := strings.Cut(v, "=")
+				errorsx.Log(errorsx.Wrapf(os.Setenv(k, v), "unable to set %s", k))
+				proxyenv = append(proxyenv, runners.AgentOptionEnv(k, v))
+			}
+
+			hostnet = runners.AgentOptionCompose(
+				runners.AgentOptionCommandLine("--network", "host"),
+				runners.AgentOptionCompose(proxyenv...),
+			)
+		}
+
 		cmdenvb = cmdenvb.Var(
 			eg.EnvComputeModuleSocket, eg.DefaultMountRoot(eg.RuntimeDirectory, filepath.Base(cspath)),
 		).FromEnviron(
@@ -345,6 +476,22 @@ func (t module) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig) (err error) {
 			defer done()
 			errorsx.Log(runners.SampleSystemLoad(fctx, db))
 		}()
+
+		defer func() {
+			fctx, done := context.WithTimeout(context.Background(), 10*time.Second)
+			defer done()
+			errorsx.Log(runners.RecordRedactions(fctx, db, redact))
+		}()
+		// toolchains available to the workload are materials of the run's provenance.
+		defer func() {
+			fctx, done := context.WithTimeout(context.Background(), time.Minute)
+			defer done()
+			record := recordProvenance[provenance.Toolchain](db, provenance.MetricToolchain)
+			for _, tc := range provenance.Toolchains(fctx) {
+				record(fctx, tc)
+			}
+		}()
+
 		srv := grpc.NewServer(
 			grpc.Creds(insecure.NewCredentials()), // this is a local socket
 			grpc.ChainUnaryInterceptor(
@@ -353,8 +500,8 @@ func (t module) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig) (err error) {
 		)
 		defer srv.GracefulStop()
 
-		events.NewServiceDispatch(db).Bind(srv)
-		execproxy.NewExecProxy(t.Dir, cmdenv).Bind(srv)
+		dispatch.Bind(srv)
+		execproxy.NewExecProxy(t.Dir, cmdenv, execopts...).Bind(srv)
 
 		gpu, err := runners.AgentOptionGPU(envx.Boolean(false, eg.EnvComputeGPU))
 		if err != nil {
@@ -386,6 +533,9 @@ func (t module) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig) (err error) {
 			c8sproxy.ServiceProxyOptionContainerOptions(
 				ragent.Options()...,
 			),
+			c8sproxy.ServiceProxyOptionRedact(redact),
+			c8sproxy.ServiceProxyOptionNetworkPolicy(policy),
+			c8sproxy.ServiceProxyOptionProvenance(recordProvenance[provenance.Container](db, provenance.MetricContainer)),
 		).Bind(srv)
 
 		go func() {
@@ -434,7 +584,36 @@ func (t module) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig) (err error) {
 			return errorsx.Wrap(err, "failed to generate module command environment")
 		}
 
-		execproxy.NewExecProxy(t.Dir, cmdenv).Bind(srv)
+		var (
+			execopts = []execproxy.ExecProxyOption{execproxy.ExecProxyOptionRedact(redact)}
+		)
+
+		// nested module output is also redacted by the root module, the count is only reported.
+		defer func() {
+			if n := redact.Count(); n > 0 {
+				log.Println("redacted", n, "occurrences of secrets from the output")
+			}
+		}()
+
+		var (
+			ecc    *grpc.ClientConn
+			cspath = filepath.Join(t.RuntimeDir, eg.SocketControl)
+		)
+
+		if ecc, err = grpc.DialContext(gctx.Context, fmt.Sprintf("unix://%s", cspath), grpc.WithTransportCredentials(insecure.NewCredentials())); err != nil {
+			return errorsx.Wrapf(err, "unable to dial %s", cspath)
+		}
+		defer ecc.Close()
+
+		// commands are recorded by the root module as materials of the run's provenance.
+		execopts = append(execopts, execproxy.ExecProxyOptionProvenance(dispatchProvenance[provenance.Command](events.NewEventsClient(ecc), provenance.MetricCommand)))
+
+		// the run is being watched, command output is dispatched to the root module's control socket.
+		if envx.Boolean(false, eg.EnvComputeEventLog) {
+			execopts = append(execopts, execproxy.ExecProxyOptionEvents(events.NewEventsClient(ecc)))
+		}
+
+		execproxy.NewExecProxy(t.Dir, cmdenv, execopts...).Bind(srv)
 		go func() {
 			errorsx.Log(errorsx.Wrap(srv.Serve(control), "unable to serve control socket"))
 		}()
@@ -472,6 +651,7 @@ func (t module) Run(gctx *cmdopts.Global, tlsc *cmdopts.TLSConfig) (err error) {
 		cc,
 		t.Module,
 		interp.OptionEnviron(cmdenv...),
+		interp.OptionRedact(redact),
 	)
 }
 
@@ -0,0 +1,7 @@
+from egpy import eg, shell
+
+from helpers import greeting
+
+if __name__ == "__main__":
+    ctx = eg.background()
+    eg.perform(ctx, shell.op(shell.new(f"echo {greeting()}")))
@@ -7,8 +7,10 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/egdaemon/eg"
 	"github.com/egdaemon/eg/internal/debugx"
 	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/modgraph"
 	"github.com/egdaemon/eg/internal/tracex"
 	"github.com/egdaemon/eg/transpile"
 	"github.com/egdaemon/eg/workspaces"
@@ -46,28 +48,84 @@ func InitGolangTidy(ctx context.Context, dir string) error {
 	return nil
 }
 
-func EnsureRequiredPackages(ctx context.Context, dir string, packages ...string) error {
-	defaultPackages := []string{
-		"get",
+// EnsureRequiredPackages adds the packages required by the runtime to the transpiled module.
+func EnsureRequiredPackages(ctx context.Context, ws workspaces.Context, packages ...string) (err error) {
+	var (
+		key    string
+		dir    = filepath.Join(ws.Root, ws.TransDir)
+		store  = modgraph.NewStore(workspaces.PathGraph(ws))
+		cached required
+	)
+
+	// python modules have no go dependencies.
+	if transpile.Python(dir) {
+		return nil
+	}
+
+	packages = append([]string{
 		"google.golang.org/genproto@latest",
 		"github.com/egdaemon/eg/runtime/autowasinet",
 		"github.com/egdaemon/eg/interp/events",
+	}, packages...)
+
+	// the go.mod and go.sum resulting from a previous build with the same go.mod and go.sum are reused.
+	if store.Enabled() {
+		var (
+			env     string
+			current required
+			found   bool
+		)
+
+		if env, err = goenv(ctx, dir); err != nil {
+			return err
+		}
+
+		if current, err = readrequired(dir); err != nil {
+			return errorsx.Wrapf(err, "unable to read required packages: %s", dir)
+		}
+
+		key = requiredkey(env, current, packages...)
+		if found, err = store.Read(key, &cached); err != nil {
+			return err
+		}
+
+		if found {
+			tracex.Println("reusing required packages", key)
+			return errorsx.Wrapf(writerequired(dir, cached), "unable to restore required packages: %s", dir)
+		}
 	}
 
-	cmd := exec.CommandContext(ctx, "go", append(defaultPackages, packages...)...)
+	cmd := exec.CommandContext(ctx, "go", append([]string{"get"}, packages...)...)
 	cmd.Dir = dir
 	cmd.Stderr = os.Stderr
 	cmd.Stdout = os.Stdout
 
-	if err := cmd.Run(); err != nil {
+	if err = cmd.Run(); err != nil {
 		return errorsx.Wrapf(err, "unable to download default packages: %s - %s", cmd.Dir, cmd.String())
 	}
 
+	if store.Enabled() {
+		if cached, err = readrequired(dir); err != nil {
+			return errorsx.Wrapf(err, "unable to read required packages: %s", dir)
+		}
+
+		errorsx.Log(errorsx.Wrapf(store.Write(key, cached), "unable to retain required packages: %s", dir))
+	}
+
 	return nil
 }
 
+// FromTranspiled compiles the transpiled roots into the build directory. compiled go modules
+// are retained within the build graph of the workspace, roots whose inputs are unchanged reuse
+// the retained module instead of being compiled again.
 func FromTranspiled(ctx context.Context, ws workspaces.Context, m ...transpile.Compiled) (modules []transpile.Compiled, err error) {
+	var (
+		g   *modgraph.Graph
+		env string
+	)
+
 	modules = make([]transpile.Compiled, 0, len(m))
+	store := modgraph.NewStore(workspaces.PathGraph(ws))
 
 	for _, root := range m {
 		var (
@@ -79,7 +137,12 @@ func FromTranspiled(ctx context.Context, ws workspaces.Context, m ...transpile.C
 		}
 
 		path = workspaces.TrimRoot(path, filepath.Base(ws.GenModDir))
-		path = workspaces.ReplaceExt(path, ".wasm")
+		interpreted := filepath.Ext(path) == ".py"
+		if interpreted {
+			path = filepath.Join(workspaces.ReplaceExt(path, ".wasm.d"), eg.ModulePython)
+		} else {
+			path = workspaces.ReplaceExt(path, ".wasm")
+		}
 		path = filepath.Join(ws.Root, ws.BuildDir, path)
 
 		if !root.Generated {
@@ -91,14 +154,57 @@ func FromTranspiled(ctx context.Context, ws workspaces.Context, m ...transpile.C
 			continue
 		}
 
+		if interpreted {
+			tracex.Println("packaging module", root.Path)
+			if err = Python(ctx, filepath.Join(ws.Root, ws.TransDir), path); err != nil {
+				return modules, err
+			}
+			continue
+		}
+
 		mpath := strings.TrimPrefix(strings.TrimPrefix(root.Path, ws.TransDir), "/")
 
 		// fsx.PrintDir(os.DirFS(filepath.Join(ws.Root, ws.TransDir)))
 
+		var (
+			key      string
+			restored bool
+		)
+
+		// scanned lazily after the required packages are ensured, the go.mod and go.sum are part of the keys.
+		if store.Enabled() && g == nil {
+			if g, err = scan(ctx, filepath.Join(ws.Root, ws.TransDir)); err != nil {
+				return modules, err
+			}
+
+			if env, err = goenv(ctx, filepath.Join(ws.Root, ws.TransDir)); err != nil {
+				return modules, err
+			}
+		}
+
+		if g != nil {
+			if key, err = compilekey(*g, env, filepath.Join(ws.Root, root.Path)); err != nil {
+				return modules, err
+			}
+
+			if restored, err = store.Restore(ctx, key, moduleWasm, path); err != nil {
+				return modules, err
+			}
+		}
+
+		if restored {
+			tracex.Println("reusing compiled module", root.Path, key)
+			continue
+		}
+
 		tracex.Println("compiling module", root.Path, mpath)
 		if err = Run(ctx, filepath.Join(ws.Root, ws.TransDir), mpath, path); err != nil {
 			return modules, err
 		}
+
+		if g != nil {
+			errorsx.Log(errorsx.Wrapf(store.Retain(ctx, key, moduleWasm, path), "unable to retain compiled module: %s", root.Path))
+		}
 	}
 
 	return modules, errorsx.Wrap(err, "compilation failed")
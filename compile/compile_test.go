@@ -1,6 +1,8 @@
 package compile_test
 
 import (
+	"archive/zip"
+	"bytes"
 	"crypto/md5"
 	"io/fs"
 	"os"
@@ -10,6 +12,7 @@ import (
 	"github.com/egdaemon/eg"
 	"github.com/egdaemon/eg/compile"
 	"github.com/egdaemon/eg/internal/fsx"
+	"github.com/egdaemon/eg/internal/md5x"
 	"github.com/egdaemon/eg/internal/testx"
 	"github.com/egdaemon/eg/internal/wasix"
 	"github.com/egdaemon/eg/transpile"
@@ -31,7 +34,7 @@ func TestFromTranspiled(t *testing.T) {
 		roots, err := transpile.Autodetect(transpile.New(srcdir, ws)).Run(ctx)
 		require.NoError(t, err)
 
-		err = compile.EnsureRequiredPackages(ctx, filepath.Join(ws.Root, ws.TransDir))
+		err = compile.EnsureRequiredPackages(ctx, ws)
 		require.NoError(t, err)
 		modules, err := compile.FromTranspiled(ctx, ws, roots...)
 		require.NoError(t, err)
@@ -51,7 +54,7 @@ func TestFromTranspiled(t *testing.T) {
 
 		roots, err := transpile.Autodetect(transpile.New(srcdir, ws)).Run(ctx)
 		require.NoError(t, err)
-		err = compile.EnsureRequiredPackages(ctx, filepath.Join(ws.Root, ws.TransDir))
+		err = compile.EnsureRequiredPackages(ctx, ws)
 		require.NoError(t, err)
 
 		modules, err := compile.FromTranspiled(ctx, ws, roots...)
@@ -62,6 +65,126 @@ func TestFromTranspiled(t *testing.T) {
 		require.Equal(t, "6d5e29ce-6e99-d52f-f8c6-4ab44bee50b1", testx.ReadMD5(filepath.Join(tmpdir, ws.TransDir, "m1", "m1.go")), testx.ReadString(filepath.Join(tmpdir, ws.TransDir, "m1", "m1.go")))
 		require.Equal(t, "8d6b4444-b948-e467-8435-24d7c4fea235", testx.ReadMD5(filepath.Join(tmpdir, ws.TransDir, "m1", "m2", "m2.go")))
 	})
+
+	t.Run("should reuse unchanged packages between cache ids", func(t *testing.T) {
+		ctx := t.Context()
+		srcdir := t.TempDir()
+		tmpdir := t.TempDir()
+
+		require.NoError(t, fsx.CloneTree(ctx, srcdir, filepath.Join("example.2", eg.DefaultModuleDirectory()), os.DirFS(testx.Fixture())))
+
+		ws1, err := workspaces.New(ctx, md5.New(), tmpdir, tmpdir, "")
+		require.NoError(t, err)
+		roots1, err := transpile.Autodetect(transpile.New(srcdir, ws1)).Run(ctx)
+		require.NoError(t, err)
+
+		initial, err := os.ReadDir(workspaces.PathGraph(ws1))
+		require.NoError(t, err)
+		require.Len(t, initial, 3)
+
+		m2, err := os.OpenFile(filepath.Join(srcdir, "m1", "m2", "m2.go"), os.O_APPEND|os.O_WRONLY, 0600)
+		require.NoError(t, err)
+		_, err = m2.WriteString("\n// modified\n")
+		require.NoError(t, err)
+		require.NoError(t, m2.Close())
+
+		ws2, err := workspaces.New(ctx, md5x.Digest("modified"), tmpdir, tmpdir, "")
+		require.NoError(t, err)
+		require.NotEqual(t, ws1.TransDir, ws2.TransDir)
+		roots2, err := transpile.Autodetect(transpile.New(srcdir, ws2)).Run(ctx)
+		require.NoError(t, err)
+		require.Len(t, roots2, len(roots1))
+
+		// only the modified package is transpiled again.
+		updated, err := os.ReadDir(workspaces.PathGraph(ws2))
+		require.NoError(t, err)
+		require.Len(t, updated, len(initial)+1)
+
+		for idx, root := range roots2 {
+			require.Equal(t, roots1[idx].Generated, root.Generated)
+			require.Equal(t, testx.ReadMD5(filepath.Join(tmpdir, roots1[idx].Path)), testx.ReadMD5(filepath.Join(tmpdir, root.Path)))
+		}
+		require.Equal(t, "6d5e29ce-6e99-d52f-f8c6-4ab44bee50b1", testx.ReadMD5(filepath.Join(tmpdir, ws2.TransDir, "m1", "m1.go")))
+		require.Equal(t, "8d6b4444-b948-e467-8435-24d7c4fea235", testx.ReadMD5(filepath.Join(tmpdir, ws1.TransDir, "m1", "m2", "m2.go")))
+		require.NotEqual(t, testx.ReadMD5(filepath.Join(tmpdir, ws1.TransDir, "m1", "m2", "m2.go")), testx.ReadMD5(filepath.Join(tmpdir, ws2.TransDir, "m1", "m2", "m2.go")))
+	})
+
+	t.Run("should only rebuild roots whose inputs changed", func(t *testing.T) {
+		ctx := t.Context()
+		srcdir := t.TempDir()
+		tmpdir := t.TempDir()
+
+		require.NoError(t, fsx.CloneTree(ctx, srcdir, filepath.Join("example.2", eg.DefaultModuleDirectory()), os.DirFS(testx.Fixture())))
+
+		build := func(ws workspaces.Context) {
+			roots, err := transpile.Autodetect(transpile.New(srcdir, ws)).Run(ctx)
+			require.NoError(t, err)
+			require.NoError(t, compile.EnsureRequiredPackages(ctx, ws))
+			_, err = compile.FromTranspiled(ctx, ws, roots...)
+			require.NoError(t, err)
+		}
+
+		// compiled modules restored from the build graph are hard links to the retained module.
+		restored := func(ws workspaces.Context, path string) bool {
+			compiled, err := os.Stat(filepath.Join(ws.Root, ws.BuildDir, path))
+			require.NoError(t, err)
+			retained, err := filepath.Glob(filepath.Join(workspaces.PathGraph(ws), "*", "module.wasm"))
+			require.NoError(t, err)
+			for _, r := range retained {
+				if info, err := os.Stat(r); err == nil && os.SameFile(compiled, info) {
+					return true
+				}
+			}
+			return false
+		}
+
+		ws1, err := workspaces.New(ctx, md5.New(), tmpdir, tmpdir, "")
+		require.NoError(t, err)
+		build(ws1)
+
+		// only the main function changes, the generated modules replace it and are unaffected.
+		original, err := os.ReadFile(filepath.Join(srcdir, "main.go"))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(srcdir, "main.go"), bytes.Replace(original, []byte("ubuntu:plucky"), []byte("ubuntu:noble"), 1), 0600))
+
+		ws2, err := workspaces.New(ctx, md5x.Digest("modified"), tmpdir, tmpdir, "")
+		require.NoError(t, err)
+		build(ws2)
+
+		require.Equal(t, testx.ReadString(filepath.Join(tmpdir, ws1.TransDir, "go.mod")), testx.ReadString(filepath.Join(tmpdir, ws2.TransDir, "go.mod")))
+		require.False(t, restored(ws2, "main.wasm"))
+		require.True(t, restored(ws2, filepath.Join("main.wasm.d", "module.24.3.wasm")))
+		require.True(t, restored(ws2, filepath.Join("main.wasm.d", "module.41.3.wasm")))
+	})
+
+	t.Run("should package python modules", func(t *testing.T) {
+		ctx := t.Context()
+		srcdir := t.TempDir()
+		tmpdir := t.TempDir()
+		ws, err := workspaces.New(ctx, md5.New(), tmpdir, tmpdir, "")
+		require.NoError(t, err)
+
+		require.NoError(t, fsx.CloneTree(ctx, srcdir, filepath.Join("example.python", eg.DefaultModuleDirectory()), os.DirFS(testx.Fixture())))
+
+		roots, err := transpile.Autodetect(transpile.New(srcdir, ws)).Run(ctx)
+		require.NoError(t, err)
+		err = compile.EnsureRequiredPackages(ctx, ws)
+		require.NoError(t, err)
+
+		modules, err := compile.FromTranspiled(ctx, ws, roots...)
+		require.NoError(t, err)
+		require.Len(t, modules, 1)
+		require.Equal(t, filepath.Join(tmpdir, ws.BuildDir, "main.wasm.d", eg.ModulePython), modules[0].Path)
+
+		archive, err := zip.OpenReader(modules[0].Path)
+		require.NoError(t, err)
+		defer archive.Close()
+
+		for _, name := range []string{"__main__.py", "main.py", "helpers/__init__.py", "egpy/__init__.py", "egpy/eg/__init__.py"} {
+			_, err = fs.Stat(archive, name)
+			require.NoError(t, err, name)
+		}
+	})
 }
 
 func TestWasixWarmCache(t *testing.T) {
@@ -79,7 +202,7 @@ func TestWasixWarmCache(t *testing.T) {
 
 		roots, err := transpile.Autodetect(transpile.New(srcdir, ws)).Run(ctx)
 		require.NoError(t, err)
-		err = compile.EnsureRequiredPackages(ctx, filepath.Join(ws.Root, ws.TransDir))
+		err = compile.EnsureRequiredPackages(ctx, ws)
 		require.NoError(t, err)
 
 		_, err = compile.FromTranspiled(ctx, ws, roots...)
@@ -0,0 +1,86 @@
+package compile
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/egdaemon/eg/cmd/cmdopts"
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/md5x"
+	"github.com/egdaemon/eg/internal/modgraph"
+)
+
+// name of the compiled module within the build graph.
+const moduleWasm = "module.wasm"
+
+func scan(ctx context.Context, dir string) (*modgraph.Graph, error) {
+	g, err := modgraph.Scan(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &g, nil
+}
+
+// goenv reports the version and flags of the toolchain compiling the modules, modules
+// compiled by a different toolchain or with different flags are never reused.
+func goenv(ctx context.Context, dir string) (string, error) {
+	cmd := exec.CommandContext(ctx, "go", "env", "GOVERSION", "GOFLAGS")
+	cmd.Env = append(os.Environ(), "GOOS=wasip1", "GOARCH=wasm")
+	cmd.Dir = dir
+
+	encoded, err := cmd.Output()
+	if err != nil {
+		return "", errorsx.Wrapf(err, "unable to determine the go environment: %s", dir)
+	}
+
+	return string(encoded), nil
+}
+
+// compilekey identifies the compiled module of a transpiled root, covering the root,
+// the module local packages it depends on, the required modules and the toolchain.
+func compilekey(g modgraph.Graph, env string, path string) (string, error) {
+	key, err := g.File(path)
+	if err != nil {
+		return "", err
+	}
+
+	return md5x.FormatHex(md5x.Digest(cmdopts.BuildInfoSafe(), "compile", "wasip1", "wasm", env, key)), nil
+}
+
+// required go.mod and go.sum resulting from ensuring the required packages.
+type required struct {
+	GoMod string `json:"gomod"`
+	GoSum string `json:"gosum"`
+}
+
+// readrequired reads the go.mod and go.sum within the directory, the go.sum is optional.
+func readrequired(dir string) (r required, err error) {
+	gomod, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return r, errorsx.WithStack(err)
+	}
+
+	gosum, err := os.ReadFile(filepath.Join(dir, "go.sum"))
+	if errorsx.Ignore(err, os.ErrNotExist) != nil {
+		return r, errorsx.WithStack(err)
+	}
+
+	return required{GoMod: string(gomod), GoSum: string(gosum)}, nil
+}
+
+// writerequired replaces the go.mod and go.sum within the directory.
+func writerequired(dir string, r required) error {
+	return errorsx.Compact(
+		os.WriteFile(filepath.Join(dir, "go.mod"), []byte(r.GoMod), 0600),
+		os.WriteFile(filepath.Join(dir, "go.sum"), []byte(r.GoSum), 0600),
+	)
+}
+
+// requiredkey identifies the result of ensuring the packages for the go.mod and go.sum.
+func requiredkey(env string, r required, packages ...string) string {
+	return md5x.FormatHex(md5x.Digest(cmdopts.BuildInfoSafe(), "required", env, r.GoMod, r.GoSum, strings.Join(packages, " ")))
+}
@@ -0,0 +1,95 @@
+package compile
+
+import (
+	"archive/zip"
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/egdaemon/eg/egpylib"
+	"github.com/egdaemon/eg/internal/debugx"
+	"github.com/egdaemon/eg/internal/errorsx"
+)
+
+// executes the module's entrypoint when the archive is run by the interpreter.
+const pythonMain = `import runpy
+
+runpy.run_module("main", run_name="__main__", alter_sys=True)
+`
+
+// Python packages the python module at dir alongside the egpy sdk as a zip application,
+// allowing it to be mounted and executed the same way as wasm modules.
+func Python(ctx context.Context, dir string, output string) (err error) {
+	debugx.Println("packaging initiated", dir, "->", output)
+	defer debugx.Println("packaging completed", dir, "->", output)
+
+	if err = os.MkdirAll(filepath.Dir(output), 0700); err != nil {
+		return errorsx.Wrapf(err, "unable to create module directory: %s", filepath.Dir(output))
+	}
+
+	dst, err := os.OpenFile(output, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return errorsx.Wrapf(err, "unable to create module: %s", output)
+	}
+	defer dst.Close()
+
+	archive := zip.NewWriter(dst)
+
+	if err = pyarchive(ctx, archive, os.DirFS(dir), "."); err != nil {
+		return errorsx.Wrapf(err, "unable to package module: %s", dir)
+	}
+
+	if err = pyarchive(ctx, archive, egpylib.SDK, "egpy"); err != nil {
+		return errorsx.Wrap(err, "unable to package egpy")
+	}
+
+	w, err := archive.Create("__main__.py")
+	if err != nil {
+		return errorsx.Wrap(err, "unable to package entrypoint")
+	}
+
+	if _, err = io.WriteString(w, pythonMain); err != nil {
+		return errorsx.Wrap(err, "unable to package entrypoint")
+	}
+
+	return errorsx.Compact(archive.Close(), dst.Close())
+}
+
+// copies the python sources into the archive, bytecode caches and hidden
+// directories (i.e. virtual environments, generated go modules) are ignored.
+func pyarchive(ctx context.Context, archive *zip.Writer, tree fs.FS, root string) error {
+	return fs.WalkDir(tree, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if path != root && (d.Name() == "__pycache__" || strings.HasPrefix(d.Name(), ".")) {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		src, err := tree.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+
+		dst, err := archive.Create(filepath.ToSlash(path))
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(dst, src)
+		return err
+	})
+}
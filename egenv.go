@@ -90,8 +90,11 @@ const (
 	EnvComputeGPU                = "EG_COMPUTE_GPU"                             // enable gpu support for the compute workload, propagated to nested module containers.
 	EnvComputeModuleSocket       = "EG_COMPUTE_MODULE_SOCKET"                   // socket providing functionality that is scoped to an individual module. primarily command execution.
 	EnvComputeDefaultGroup       = "EG_COMPUTE_DEFAULT_GROUP"                   // override the group assigned to the user. mainly used by baremetal.
-	EnvComputeAPIEnabled         = "EG_COMPUTE_API_ENABLED"                     // gates the runner's push HTTP surface (POST /b/upload, POST /c/enqueue); default-disabled stopgap ahead of real request authentication.
 	EnvComputeProfileMode        = "EG_COMPUTE_PROFILE_MODE"                    // profile mode (cpu,heap,mem,allocs,block) for module runs.
+	EnvComputeOperationPath      = "EG_COMPUTE_OPERATION_PATH"                  // slash separated path of the operation a nested module was dispatched from.
+	EnvComputeEventLog           = "EG_COMPUTE_EVENT_LOG"                       // records the run's events, including command output, to the event log allowing the run to be watched.
+	EnvComputeRedact             = "EG_COMPUTE_REDACT"                          // comma separated names of environment variables (sourced from secrets) whose values are redacted from the workload's logs.
+	EnvComputeNetworkPolicy      = "EG_COMPUTE_NETWORK_POLICY"                  // network policy restricting the workload's egress (open, deny, or a comma separated allow list of hosts and CIDRs).
 )
 
 const (
@@ -150,9 +153,12 @@ const (
 	WorkspaceDirectory = ".eg.workspace" // persistent shared directory for the duration of a single workload.
 	ModuleDir          = "main.wasm.d"
 	ModuleBin          = ".eg.module.wasm"
+	ModulePython       = "main.pyz" // python modules are packaged as a zip application within the module directory.
 	BinaryBin          = "egbin"
 	EnvironFile        = "environ.env"
+	CancelFile         = "cancelled" // created within the runtime directory once the workload has been cancelled.
 	SocketControl      = "control.socket"
+	SocketNetwork      = "network.socket" // egress proxy served by the host enforcing the workload's network policy.
 )
 
 // generate unique module socket
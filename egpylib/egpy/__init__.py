@@ -1,3 +1,7 @@
 from . import eg
 from . import metrics
 from . import envx
+from . import shell
+from . import egenv
+from . import egtests
+from . import egcoverage
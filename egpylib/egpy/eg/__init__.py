@@ -1,24 +1,96 @@
 import os
 import time
+import secrets
+import uuid
 import grpc
 from . import interp
-from uuid_extensions import uuid7str
+
 
 def defaultRunnerRuntimeDir() -> str:
-	return os.environ.get("EG_RUNTIME_DIRECTORY", os.path.join("/", "opt", "eg.runtime"))
+    return os.environ.get(
+        "EG_COMPUTE_RUNTIME_DIRECTORY",
+        os.environ.get("EG_RUNTIME_DIRECTORY", os.path.join("/", "eg.mnt", ".eg.runtime")),
+    )
+
 
 def defaultRunnerSocketPath() -> str:
-	return os.path.join(defaultRunnerRuntimeDir(), "control.socket")
+    return os.path.join(defaultRunnerRuntimeDir(), "control.socket")
+
+
+def defaultModuleSocketPath() -> str:
+    return os.environ.get("EG_COMPUTE_MODULE_SOCKET", defaultRunnerSocketPath())
+
 
 def autoclient():
-	return grpc.insecure_channel(f"unix://{defaultRunnerSocketPath()}")
+    '''
+    client for the control socket of the root module, provides events and containers.
+    '''
+    return grpc.insecure_channel(f"unix://{defaultRunnerSocketPath()}")
+
+
+def moduleclient():
+    '''
+    client for the socket scoped to the current module, provides command execution.
+    '''
+    return grpc.insecure_channel(f"unix://{defaultModuleSocketPath()}")
+
+
+def uuid7str() -> str:
+    ms = time.time_ns() // 1000000
+    rand = int.from_bytes(secrets.token_bytes(10), "big")
+    hi = (ms & 0xFFFFFFFFFFFF) << 16 | 0x7 << 12 | (rand >> 62) & 0xFFF
+    lo = 0x2 << 62 | rand & 0x3FFFFFFFFFFFFFFF
+    return str(uuid.UUID(int=hi << 64 | lo))
+
 
 def metric(name: str, fields: bytes):
-		return interp.events_pb2.Message(
-			id=uuid7str(),
-			ts=time.time_ns() // 1000000,
-			metric=interp.events_pb2.Metric(name=name, fieldsJSON=fields)
-		)
+    return interp.events_pb2.Message(
+        id=uuid7str(),
+        ts=time.time_ns() // 1000000,
+        metric=interp.events_pb2.Metric(name=name, fieldsJSON=fields),
+    )
+
+
+def op(o: interp.events_pb2.Op) -> interp.events_pb2.Message:
+    return interp.events_pb2.Message(
+        id=uuid7str(),
+        ts=time.time_ns() // 1000000,
+        op=o,
+    )
+
+
+def coverage(c: interp.events_pb2.Coverage) -> interp.events_pb2.Message:
+    return interp.events_pb2.Message(
+        id=uuid7str(),
+        ts=time.time_ns() // 1000000,
+        coverage=c,
+    )
+
+
+def test(r: interp.events_pb2.TestResult) -> interp.events_pb2.Message:
+    return interp.events_pb2.Message(
+        id=uuid7str(),
+        ts=time.time_ns() // 1000000,
+        test=r,
+    )
+
 
 def dispatch(*arg: interp.events_pb2.Message) -> interp.events_pb2.DispatchRequest:
-	return interp.events_pb2.DispatchRequest(messages=arg)
+    return interp.events_pb2.DispatchRequest(messages=arg)
+
+
+from .context import Context, background
+from .graph import operation
+from .cancel import Cancelled, cancelled, on_cancel
+from .ops import (
+    Op,
+    perform,
+    sequential,
+    parallel,
+    when,
+    Container,
+    container,
+    build,
+    module,
+    exec,
+)
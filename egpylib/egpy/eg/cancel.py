@@ -0,0 +1,70 @@
+import os
+import typing
+import threading
+import egpy.eg as eg
+
+# created by the runner when the workload is cancelled.
+CancelFile = "cancelled"
+
+_lock = threading.Lock()
+_operations: typing.List[typing.Callable] = []
+
+
+class Cancelled(Exception):
+    '''
+    raised for operations interrupted by the cancellation of the workload.
+    '''
+
+
+def on_cancel(*operations: typing.Callable):
+    '''
+    registers operations to execute when the workload is cancelled. cancelling
+    a workload interrupts the operations in flight, the registered operations are then
+    executed in order and given a grace period to complete before the workload is terminated.
+    i.e.) eg.on_cancel(shell.op(shell.new("podman stop --all")))
+    '''
+    with _lock:
+        _operations.extend(operations)
+
+
+def cancelled() -> bool:
+    '''
+    reports if the workload has been cancelled.
+    '''
+    return os.path.exists(os.path.join(eg.defaultRunnerRuntimeDir(), CancelFile))
+
+
+def interrupted(cause: BaseException) -> BaseException:
+    '''
+    ensures failures of operations interrupted by the cancellation of the
+    workload are reported as cancelled.
+    '''
+    if isinstance(cause, (Cancelled, KeyboardInterrupt)) or not cancelled():
+        return cause
+
+    err = Cancelled(f"workload cancelled: {cause}")
+    err.__cause__ = cause
+    return err
+
+
+def cleanup(ctx, cause: BaseException):
+    '''
+    executes the registered cancellation operations, each operation is
+    executed at most once regardless of failures.
+    '''
+    from .ops import Op
+    from . import graph
+
+    if not cancelled():
+        return
+
+    with _lock:
+        operations = list(_operations)
+        _operations.clear()
+
+    for fn in operations:
+        ref = Op(fn)
+        try:
+            graph.trace(ctx, fn, ref.id(), lambda c: fn(c, ref))
+        except Exception as e:
+            cause.add_note(f"cancellation operation failed: {e}")
@@ -0,0 +1,54 @@
+import os
+import time
+import typing
+
+
+class Context(object):
+    '''
+    carries the deadline and the path of the executing operation between operations,
+    the python equivalent of go's context.Context within eg modules.
+    '''
+
+    def __init__(
+        self,
+        deadline: typing.Optional[float] = None,
+        path: typing.Sequence[str] = (),
+        dispatched: bool = False,
+    ):
+        self.deadline = deadline
+        self.path = tuple(path)
+        self.dispatched = dispatched
+
+    def with_timeout(self, seconds: float) -> "Context":
+        '''
+        derive a context that expires after the given number of seconds, the
+        earliest deadline wins.
+        '''
+        deadline = time.monotonic() + seconds
+        if self.deadline is not None:
+            deadline = min(deadline, self.deadline)
+        return Context(deadline, self.path, self.dispatched)
+
+    def with_operation(self, id: str) -> "Context":
+        return Context(self.deadline, self.path + (id,), self.dispatched)
+
+    def remaining(self) -> typing.Optional[float]:
+        '''
+        seconds remaining until the deadline, None when the context has no deadline.
+        '''
+        if self.deadline is None:
+            return None
+        return max(0.0, self.deadline - time.monotonic())
+
+    def err(self) -> typing.Optional[BaseException]:
+        if self.deadline is not None and time.monotonic() >= self.deadline:
+            return TimeoutError("context deadline exceeded")
+        return None
+
+
+def background() -> Context:
+    '''
+    root context for a module. nested modules are rooted at the operation that dispatched them.
+    '''
+    path = os.environ.get("EG_COMPUTE_OPERATION_PATH", "")
+    return Context(path=[p for p in path.split("/") if p])
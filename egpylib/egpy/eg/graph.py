@@ -0,0 +1,158 @@
+import time
+import typing
+import contextlib
+import logging
+import grpc
+import egpy.eg as eg
+from egpy import envx
+from .context import Context
+from .interp.events_pb2 import Op
+
+# metadata key used by the control socket to attribute requests to operations.
+MetadataOperationPath = "eg.operation.path"
+
+
+def internal(fn: typing.Callable) -> bool:
+    '''
+    operations implemented by the sdk (sequential, parallel, etc) are not traced.
+    '''
+    module = getattr(fn, "__module__", "") or ""
+    return module == "egpy.eg" or module.startswith("egpy.eg.")
+
+
+def state(cause: typing.Optional[BaseException]) -> int:
+    from .cancel import Cancelled
+
+    if cause is None:
+        return Op.Completed
+    if isinstance(cause, TimeoutError):
+        return Op.TimedOut
+    if isinstance(cause, grpc.RpcError) and cause.code() == grpc.StatusCode.DEADLINE_EXCEEDED:
+        return Op.TimedOut
+    if isinstance(cause, (Cancelled, KeyboardInterrupt)):
+        return Op.Cancelled
+    return Op.Error
+
+
+def info(
+    fn: typing.Callable,
+    id: str,
+    ts: float,
+    cause: typing.Optional[BaseException],
+    path: typing.Sequence[str],
+) -> typing.Optional[Op]:
+    if internal(fn):
+        return None
+
+    code = getattr(fn, "__code__", None)
+    return Op(
+        state=state(cause),
+        milliseconds=int((time.monotonic() - ts) * 1000),
+        name=f"{getattr(fn, '__module__', '')}.{getattr(fn, '__qualname__', repr(fn))}",
+        module=code.co_filename if code is not None else "",
+        op=id,
+        path=path,
+    )
+
+
+def trace(ctx: Context, fn: typing.Callable, id: str, do: typing.Callable[[Context], typing.Any]):
+    '''
+    executes the operation recording its outcome, child operations are
+    rooted at the operation.
+    '''
+    if internal(fn):
+        # nothing to trace
+        return do(ctx)
+
+    current = ctx.path
+    ts = time.monotonic()
+    if envx.boolean(False, "EG_COMPUTE_EVENT_LOG"):
+        evt = info(fn, id, ts, None, current)
+        if evt is not None:
+            evt.state = Op.Initiated
+            evt.milliseconds = 0
+            record(evt)
+
+    try:
+        result = do(ctx.with_operation(id))
+    except BaseException as cause:
+        record(info(fn, id, ts, cause, current))
+        raise
+
+    record(info(fn, id, ts, None, current))
+    return result
+
+
+@contextlib.contextmanager
+def operation(ctx: Context, name: str, module: str = "") -> typing.Iterator[Context]:
+    '''
+    records the enclosed block as an operation, allowing work performed outside
+    of perform (i.e. test suites, scripts) to be observed alongside the operations.
+    i.e.)
+    with eg.operation(ctx, "migrations") as octx:
+        shell.run(octx, shell.new("make migrate"))
+    '''
+    id = f"op{eg.uuid7str()}"
+    current = ctx.path
+    ts = time.monotonic()
+
+    def evt(cause: typing.Optional[BaseException]) -> Op:
+        return Op(
+            state=state(cause),
+            milliseconds=int((time.monotonic() - ts) * 1000),
+            name=name,
+            module=module,
+            op=id,
+            path=current,
+        )
+
+    if envx.boolean(False, "EG_COMPUTE_EVENT_LOG"):
+        initiated = evt(None)
+        initiated.state = Op.Initiated
+        initiated.milliseconds = 0
+        record(initiated)
+
+    try:
+        yield ctx.with_operation(id)
+    except BaseException as cause:
+        record(evt(cause))
+        raise
+
+    record(evt(None))
+
+
+def skipped(ctx: Context, fn: typing.Callable, id: str):
+    '''
+    records the operation without executing it, used for conditional operations
+    whose condition was not met.
+    '''
+    evt = info(fn, id, time.monotonic(), None, ctx.path)
+    if evt is None:
+        return
+
+    evt.state = Op.Skipped
+    record(evt)
+
+
+def outgoing(ctx: Context) -> typing.Sequence[typing.Tuple[str, str]]:
+    '''
+    metadata allowing the control socket to attribute requests to the operation that issued them.
+    '''
+    if len(ctx.path) == 0:
+        return ()
+
+    return ((MetadataOperationPath, "/".join(ctx.path)),)
+
+
+def record(op: typing.Optional[Op]):
+    from egpy.metrics.eg_interp_events_pb2_grpc import EventsStub
+
+    if op is None:
+        return
+
+    # operations are recorded even when they were cancelled or timed out.
+    try:
+        with eg.autoclient() as c:
+            EventsStub(c).Dispatch(eg.dispatch(eg.op(op)), timeout=10)
+    except Exception as e:
+        logging.error(f"unable to record graph metric {e}")
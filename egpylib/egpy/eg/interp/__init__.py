@@ -1 +1,3 @@
 from . import events_pb2
+from . import containers_pb2
+from . import exec_pb2
@@ -0,0 +1,70 @@
+from google.protobuf.internal import containers as _containers
+from google.protobuf import descriptor as _descriptor
+from google.protobuf import message as _message
+from typing import ClassVar as _ClassVar, Iterable as _Iterable, Optional as _Optional
+
+DESCRIPTOR: _descriptor.FileDescriptor
+
+class PullRequest(_message.Message):
+    __slots__ = ("name", "dir", "options")
+    NAME_FIELD_NUMBER: _ClassVar[int]
+    DIR_FIELD_NUMBER: _ClassVar[int]
+    OPTIONS_FIELD_NUMBER: _ClassVar[int]
+    name: str
+    dir: str
+    options: _containers.RepeatedScalarFieldContainer[str]
+    def __init__(self, name: _Optional[str] = ..., dir: _Optional[str] = ..., options: _Optional[_Iterable[str]] = ...) -> None: ...
+
+class PullResponse(_message.Message):
+    __slots__ = ()
+    def __init__(self) -> None: ...
+
+class BuildRequest(_message.Message):
+    __slots__ = ("name", "directory", "definition", "options")
+    NAME_FIELD_NUMBER: _ClassVar[int]
+    DIRECTORY_FIELD_NUMBER: _ClassVar[int]
+    DEFINITION_FIELD_NUMBER: _ClassVar[int]
+    OPTIONS_FIELD_NUMBER: _ClassVar[int]
+    name: str
+    directory: str
+    definition: str
+    options: _containers.RepeatedScalarFieldContainer[str]
+    def __init__(self, name: _Optional[str] = ..., directory: _Optional[str] = ..., definition: _Optional[str] = ..., options: _Optional[_Iterable[str]] = ...) -> None: ...
+
+class BuildResponse(_message.Message):
+    __slots__ = ()
+    def __init__(self) -> None: ...
+
+class RunRequest(_message.Message):
+    __slots__ = ("image", "name", "command", "options")
+    IMAGE_FIELD_NUMBER: _ClassVar[int]
+    NAME_FIELD_NUMBER: _ClassVar[int]
+    COMMAND_FIELD_NUMBER: _ClassVar[int]
+    OPTIONS_FIELD_NUMBER: _ClassVar[int]
+    image: str
+    name: str
+    command: _containers.RepeatedScalarFieldContainer[str]
+    options: _containers.RepeatedScalarFieldContainer[str]
+    def __init__(self, image: _Optional[str] = ..., name: _Optional[str] = ..., command: _Optional[_Iterable[str]] = ..., options: _Optional[_Iterable[str]] = ...) -> None: ...
+
+class RunResponse(_message.Message):
+    __slots__ = ()
+    def __init__(self) -> None: ...
+
+class ModuleRequest(_message.Message):
+    __slots__ = ("image", "name", "mdir", "options", "module")
+    IMAGE_FIELD_NUMBER: _ClassVar[int]
+    NAME_FIELD_NUMBER: _ClassVar[int]
+    MDIR_FIELD_NUMBER: _ClassVar[int]
+    OPTIONS_FIELD_NUMBER: _ClassVar[int]
+    MODULE_FIELD_NUMBER: _ClassVar[int]
+    image: str
+    name: str
+    mdir: str
+    options: _containers.RepeatedScalarFieldContainer[str]
+    module: str
+    def __init__(self, image: _Optional[str] = ..., name: _Optional[str] = ..., mdir: _Optional[str] = ..., options: _Optional[_Iterable[str]] = ..., module: _Optional[str] = ...) -> None: ...
+
+class ModuleResponse(_message.Message):
+    __slots__ = ()
+    def __init__(self) -> None: ...
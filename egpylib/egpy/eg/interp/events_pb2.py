@@ -24,7 +24,7 @@ _sym_db = _symbol_database.Default()
 
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x16\x65g.interp.events.proto\x12\x10\x65g.interp.events\"\x19\n\x0bRunMetadata\x12\n\n\x02id\x18\x01 \x01(\x0c\"R\n\tLogHeader\x12\r\n\x05Major\x18\x01 \x01(\x05\x12\r\n\x05Minor\x18\x02 \x01(\x05\x12\r\n\x05Patch\x18\x03 \x01(\x05\x12\x0b\n\x03sts\x18\x04 \x01(\x03\x12\x0b\n\x03\x65ts\x18\x05 \x01(\x03\"\x0b\n\tHeartbeat\"\xb3\x01\n\x04Task\x12\n\n\x02id\x18\x01 \x01(\t\x12\x0b\n\x03pid\x18\x02 \x01(\t\x12\x13\n\x0b\x64\x65scription\x18\x03 \x01(\t\x12+\n\x05state\x18\x04 \x01(\x0e\x32\x1c.eg.interp.events.Task.State\x12\x10\n\x08\x64\x65\x61\x64line\x18\x05 \x01(\x03\">\n\x05State\x12\x0b\n\x07Pending\x10\x00\x12\r\n\tInitiated\x10\x01\x12\r\n\tCompleted\x10\x02\x12\n\n\x05\x45rror\x10\xe8\x07\"2\n\x06Metric\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x13\n\nfieldsJSON\x18\xe8\x07 \x01(\x0cJ\x05\x08\x02\x10\xe8\x07\"\xe1\x01\n\x07Message\x12\n\n\x02id\x18\x01 \x01(\t\x12\n\n\x02ts\x18\x02 \x01(\x03\x12/\n\x08preamble\x18\x64 \x01(\x0b\x32\x1b.eg.interp.events.LogHeaderH\x00\x12\x30\n\theartbeat\x18\x65 \x01(\x0b\x32\x1b.eg.interp.events.HeartbeatH\x00\x12&\n\x04task\x18\x66 \x01(\x0b\x32\x16.eg.interp.events.TaskH\x00\x12*\n\x06metric\x18g \x01(\x0b\x32\x18.eg.interp.events.MetricH\x00\x42\x07\n\x05\x45vent\"\xc4\x01\n\x0eRunUploadChunk\x12\x0c\n\x04\x64\x61ta\x18\x01 \x01(\x0c\x12\x10\n\x08\x63hecksum\x18\x02 \x01(\x0c\x12\x0e\n\x04none\x18\x03 \x01(\x08H\x00\x12=\n\x08metadata\x18\x04 \x01(\x0b\x32).eg.interp.events.RunUploadChunk.MetadataH\x00\x1a+\n\x08Metadata\x12\r\n\x05\x62ytes\x18\x01 \x01(\x04\x12\x10\n\x08\x63hecksum\x18\x02 \x01(\x0c\x42\x16\n\x14initialChunkMetadata\"?\n\x11RunUploadResponse\x12*\n\x03run\x18\x01 \x01(\x0b\x32\x1d.eg.interp.events.RunMetadata\";\n\rRunLogRequest\x12*\n\x03run\x18\x01 \x01(\x0b\x32\x1d.eg.interp.events.RunMetadata\"!\n\x0eRunLogResponse\x12\x0f\n\x07\x63ontent\x18\x01 \x01(\x0c\"\x14\n\x12RunInitiateRequest\"\x13\n\x11RunInitiateResult\"\x12\n\x10RunCancelRequest\"\x13\n\x11RunCancelResponse\"=\n\x0fRunWatchRequest\x12*\n\x03run\x18\x01 \x01(\x0b\x32\x1d.eg.interp.events.RunMetadata\">\n\x0f\x44ispatchRequest\x12+\n\x08messages\x18\x01 \x03(\x0b\x32\x19.eg.interp.events.Message\"\x12\n\x10\x44ispatchResponse2\xcb\x02\n\x05\x41gent\x12S\n\x06Upload\x12 .eg.interp.events.RunUploadChunk\x1a#.eg.interp.events.RunUploadResponse\"\x00(\x01\x12S\n\x06\x43\x61ncel\x12\".eg.interp.events.RunCancelRequest\x1a#.eg.interp.events.RunCancelResponse\"\x00\x12M\n\x04Logs\x12\x1f.eg.interp.events.RunLogRequest\x1a .eg.interp.events.RunLogResponse\"\x00\x30\x01\x12I\n\x05Watch\x12!.eg.interp.events.RunWatchRequest\x1a\x19.eg.interp.events.Message\"\x00\x30\x01\x32]\n\x06\x45vents\x12S\n\x08\x44ispatch\x12!.eg.interp.events.DispatchRequest\x1a\".eg.interp.events.DispatchResponse\"\x00\x32]\n\x06Runner\x12S\n\x08\x44ispatch\x12!.eg.interp.events.DispatchRequest\x1a\".eg.interp.events.DispatchResponse\"\x00\x62\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x16\x65g.interp.events.proto\x12\x10\x65g.interp.events\"\x1d\n\x0bRunMetadata\x12\x0e\n\x02id\x18\x01 \x01(\x0cR\x02id\"q\n\tLogHeader\x12\x14\n\x05Major\x18\x01 \x01(\x05R\x05Major\x12\x14\n\x05Minor\x18\x02 \x01(\x05R\x05Minor\x12\x14\n\x05Patch\x18\x03 \x01(\x05R\x05Patch\x12\x10\n\x03sts\x18\x04 \x01(\x03R\x03sts\x12\x10\n\x03\x65ts\x18\x05 \x01(\x03R\x03\x65ts\"\x0b\n\tHeartbeat\"\x8e\x02\n\x02Op\x12\x30\n\x05state\x18\x01 \x01(\x0e\x32\x1a.eg.interp.events.Op.StateR\x05state\x12!\n\x0cmilliseconds\x18\x02 \x01(\x03R\x0bmillisecond\x12\x12\n\x04name\x18\x03 \x01(\tR\x04name\x12\x16\n\x06module\x18\x04 \x01(\tR\x06module\x12\x0e\n\x02op\x18\x05 \x01(\tR\x02op\x12\x13\n\x04path\x18\xe8\x07 \x03(\tR\x04path\"[\n\x05State\x12\r\n\tInitiated\x10\x00\x12\r\n\tCompleted\x10\x01\x12\r\n\tCancelled\x10\x02\x12\x0b\n\x07Skipped\x10\x03\x12\x0c\n\x08TimedOut\x10\x04\x12\n\n\x05\x45rror\x10\xe8\x07J\x05\x08\x06\x10\xe8\x07\"D\n\x06Metric\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x1f\n\nfieldsJSON\x18\xe8\x07 \x01(\x0cR\nfieldsJSONJ\x05\x08\x02\x10\xe8\x07\"\x90\x01\n\x08\x43overage\x12\x12\n\x04path\x18\x01 \x01(\tR\x04path\x12\x1e\n\nstatements\x18\x02 \x01(\x02R\nstatements\x12\x1a\n\x08\x62ranches\x18\x03 \x01(\x02R\x08\x62ranches\x12\x34\n\x05lines\x18\x04 \x03(\x0b\x32\x1e.eg.interp.events.CoverageLineR\x05lines\"u\n\x0c\x43overageLine\x12\x12\n\x04line\x18\x01 \x01(\rR\x04line\x12\x12\n\x04hits\x18\x02 \x01(\x04R\x04hits\x12\x1a\n\x08\x62ranches\x18\x03 \x01(\rR\x08\x62ranches\x12!\n\x0c\x62ranches_hit\x18\x04 \x01(\rR\x0b\x62ranchesHit\"L\n\x06Output\x12\x12\n\x04path\x18\x01 \x03(\tR\x04path\x12\x16\n\x06stdout\x18\x02 \x01(\x0cR\x06stdout\x12\x16\n\x06stderr\x18\x03 \x01(\x0cR\x06stderr\"\x89\x02\n\nTestResult\x12;\n\x06status\x18\x01 \x01(\x0e\x32#.eg.interp.events.TestResult.StatusR\x06status\x12\x14\n\x05suite\x18\x02 \x01(\tR\x05suite\x12\x12\n\x04name\x18\x03 \x01(\tR\x04name\x12\"\n\x0cmilliseconds\x18\x04 \x01(\x03R\x0cmilliseconds\x12\x1c\n\tframework\x18\x05 \x01(\tR\tframework\x12\x18\n\x07message\x18\x06 \x01(\tR\x07message\"8\n\x06Status\x12\n\n\x06Passed\x10\x00\x12\n\n\x06\x46\x61iled\x10\x01\x12\x0b\n\x07Skipped\x10\x02\x12\t\n\x05\x45rror\x10\x03\":\n\x10NetworkViolation\x12\x12\n\x04host\x18\x01 \x01(\tR\x04host\x12\x12\n\x04port\x18\x02 \x01(\rR\x04port\"\xe8\x03\n\x07Message\x12\x0e\n\x02id\x18\x01 \x01(\tR\x02id\x12\x0e\n\x02ts\x18\x02 \x01(\x03R\x02ts\x12\x39\n\x08preamble\x18\x64 \x01(\x0b\x32\x1b.eg.interp.events.LogHeaderH\x00R\x08preamble\x12;\n\theartbeat\x18\x65 \x01(\x0b\x32\x1b.eg.interp.events.HeartbeatH\x00R\theartbeat\x12&\n\x02op\x18\x66 \x01(\x0b\x32\x14.eg.interp.events.OpH\x00R\x02op\x12\x32\n\x06metric\x18g \x01(\x0b\x32\x18.eg.interp.events.MetricH\x00R\x06metric\x12\x38\n\x08\x63overage\x18h \x01(\x0b\x32\x1a.eg.interp.events.CoverageH\x00R\x08\x63overage\x12\x32\n\x06output\x18i \x01(\x0b\x32\x18.eg.interp.events.OutputH\x00R\x06output\x12\x32\n\x04test\x18j \x01(\x0b\x32\x1c.eg.interp.events.TestResultH\x00R\x04test\x12>\n\x07network\x18k \x01(\x0b\x32\".eg.interp.events.NetworkViolationH\x00R\x07networkB\x07\n\x05\x45vent\"\xf5\x01\n\x0eRunUploadChunk\x12\x12\n\x04\x64\x61ta\x18\x01 \x01(\x0cR\x04\x64\x61ta\x12\x1a\n\x08\x63hecksum\x18\x02 \x01(\x0cR\x08\x63hecksum\x12\x14\n\x04none\x18\x03 \x01(\x08H\x00R\x04none\x12G\n\x08metadata\x18\x04 \x01(\x0b\x32).eg.interp.events.RunUploadChunk.MetadataH\x00R\x08metadata\x1a<\n\x08Metadata\x12\x14\n\x05\x62ytes\x18\x01 \x01(\x04R\x05\x62ytes\x12\x1a\n\x08\x63hecksum\x18\x02 \x01(\x0cR\x08\x63hecksumB\x16\n\x14initialChunkMetadata\"D\n\x11RunUploadResponse\x12/\n\x03run\x18\x01 \x01(\x0b\x32\x1d.eg.interp.events.RunMetadataR\x03run\"@\n\rRunLogRequest\x12/\n\x03run\x18\x01 \x01(\x0b\x32\x1d.eg.interp.events.RunMetadataR\x03run\"*\n\x0eRunLogResponse\x12\x18\n\x07\x63ontent\x18\x01 \x01(\x0cR\x07\x63ontent\"\x14\n\x12RunInitiateRequest\"\x13\n\x11RunInitiateResult\"C\n\x10RunCancelRequest\x12/\n\x03run\x18\x01 \x01(\x0b\x32\x1d.eg.interp.events.RunMetadataR\x03run\"\x13\n\x11RunCancelResponse\"B\n\x0fRunWatchRequest\x12/\n\x03run\x18\x01 \x01(\x0b\x32\x1d.eg.interp.events.RunMetadataR\x03run\"H\n\x0f\x44ispatchRequest\x12\x35\n\x08messages\x18\x01 \x03(\x0b\x32\x19.eg.interp.events.MessageR\x08messages\"\x12\n\x10\x44ispatchResponse2\xcb\x02\n\x05\x41gent\x12S\n\x06Upload\x12 .eg.interp.events.RunUploadChunk\x1a#.eg.interp.events.RunUploadResponse\"\x00(\x01\x12S\n\x06\x43\x61ncel\x12\".eg.interp.events.RunCancelRequest\x1a#.eg.interp.events.RunCancelResponse\"\x00\x12M\n\x04Logs\x12\x1f.eg.interp.events.RunLogRequest\x1a .eg.interp.events.RunLogResponse\"\x00\x30\x01\x12I\n\x05Watch\x12!.eg.interp.events.RunWatchRequest\x1a\x19.eg.interp.events.Message\"\x00\x30\x01\x32]\n\x06\x45vents\x12S\n\x08\x44ispatch\x12!.eg.interp.events.DispatchRequest\x1a\".eg.interp.events.DispatchResponse\"\x00\x32]\n\x06Runner\x12S\n\x08\x44ispatch\x12!.eg.interp.events.DispatchRequest\x1a\".eg.interp.events.DispatchResponse\"\x00\x62\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -32,47 +32,59 @@ _builder.BuildTopDescriptorsAndMessages(DESCRIPTOR, 'eg.interp.events_pb2', _glo
 if not _descriptor._USE_C_DESCRIPTORS:
   DESCRIPTOR._loaded_options = None
   _globals['_RUNMETADATA']._serialized_start=44
-  _globals['_RUNMETADATA']._serialized_end=69
-  _globals['_LOGHEADER']._serialized_start=71
-  _globals['_LOGHEADER']._serialized_end=153
-  _globals['_HEARTBEAT']._serialized_start=155
-  _globals['_HEARTBEAT']._serialized_end=166
-  _globals['_TASK']._serialized_start=169
-  _globals['_TASK']._serialized_end=348
-  _globals['_TASK_STATE']._serialized_start=286
-  _globals['_TASK_STATE']._serialized_end=348
-  _globals['_METRIC']._serialized_start=350
-  _globals['_METRIC']._serialized_end=400
-  _globals['_MESSAGE']._serialized_start=403
-  _globals['_MESSAGE']._serialized_end=628
-  _globals['_RUNUPLOADCHUNK']._serialized_start=631
-  _globals['_RUNUPLOADCHUNK']._serialized_end=827
-  _globals['_RUNUPLOADCHUNK_METADATA']._serialized_start=760
-  _globals['_RUNUPLOADCHUNK_METADATA']._serialized_end=803
-  _globals['_RUNUPLOADRESPONSE']._serialized_start=829
-  _globals['_RUNUPLOADRESPONSE']._serialized_end=892
-  _globals['_RUNLOGREQUEST']._serialized_start=894
-  _globals['_RUNLOGREQUEST']._serialized_end=953
-  _globals['_RUNLOGRESPONSE']._serialized_start=955
-  _globals['_RUNLOGRESPONSE']._serialized_end=988
-  _globals['_RUNINITIATEREQUEST']._serialized_start=990
-  _globals['_RUNINITIATEREQUEST']._serialized_end=1010
-  _globals['_RUNINITIATERESULT']._serialized_start=1012
-  _globals['_RUNINITIATERESULT']._serialized_end=1031
-  _globals['_RUNCANCELREQUEST']._serialized_start=1033
-  _globals['_RUNCANCELREQUEST']._serialized_end=1051
-  _globals['_RUNCANCELRESPONSE']._serialized_start=1053
-  _globals['_RUNCANCELRESPONSE']._serialized_end=1072
-  _globals['_RUNWATCHREQUEST']._serialized_start=1074
-  _globals['_RUNWATCHREQUEST']._serialized_end=1135
-  _globals['_DISPATCHREQUEST']._serialized_start=1137
-  _globals['_DISPATCHREQUEST']._serialized_end=1199
-  _globals['_DISPATCHRESPONSE']._serialized_start=1201
-  _globals['_DISPATCHRESPONSE']._serialized_end=1219
-  _globals['_AGENT']._serialized_start=1222
-  _globals['_AGENT']._serialized_end=1553
-  _globals['_EVENTS']._serialized_start=1555
-  _globals['_EVENTS']._serialized_end=1648
-  _globals['_RUNNER']._serialized_start=1650
-  _globals['_RUNNER']._serialized_end=1743
+  _globals['_RUNMETADATA']._serialized_end=73
+  _globals['_LOGHEADER']._serialized_start=75
+  _globals['_LOGHEADER']._serialized_end=188
+  _globals['_HEARTBEAT']._serialized_start=190
+  _globals['_HEARTBEAT']._serialized_end=201
+  _globals['_OP']._serialized_start=204
+  _globals['_OP']._serialized_end=474
+  _globals['_OP_STATE']._serialized_start=376
+  _globals['_OP_STATE']._serialized_end=467
+  _globals['_METRIC']._serialized_start=476
+  _globals['_METRIC']._serialized_end=544
+  _globals['_COVERAGE']._serialized_start=547
+  _globals['_COVERAGE']._serialized_end=691
+  _globals['_COVERAGELINE']._serialized_start=693
+  _globals['_COVERAGELINE']._serialized_end=810
+  _globals['_OUTPUT']._serialized_start=812
+  _globals['_OUTPUT']._serialized_end=888
+  _globals['_TESTRESULT']._serialized_start=891
+  _globals['_TESTRESULT']._serialized_end=1156
+  _globals['_TESTRESULT_STATUS']._serialized_start=1100
+  _globals['_TESTRESULT_STATUS']._serialized_end=1156
+  _globals['_NETWORKVIOLATION']._serialized_start=1158
+  _globals['_NETWORKVIOLATION']._serialized_end=1216
+  _globals['_MESSAGE']._serialized_start=1219
+  _globals['_MESSAGE']._serialized_end=1707
+  _globals['_RUNUPLOADCHUNK']._serialized_start=1710
+  _globals['_RUNUPLOADCHUNK']._serialized_end=1955
+  _globals['_RUNUPLOADCHUNK_METADATA']._serialized_start=1871
+  _globals['_RUNUPLOADCHUNK_METADATA']._serialized_end=1931
+  _globals['_RUNUPLOADRESPONSE']._serialized_start=1957
+  _globals['_RUNUPLOADRESPONSE']._serialized_end=2025
+  _globals['_RUNLOGREQUEST']._serialized_start=2027
+  _globals['_RUNLOGREQUEST']._serialized_end=2091
+  _globals['_RUNLOGRESPONSE']._serialized_start=2093
+  _globals['_RUNLOGRESPONSE']._serialized_end=2135
+  _globals['_RUNINITIATEREQUEST']._serialized_start=2137
+  _globals['_RUNINITIATEREQUEST']._serialized_end=2157
+  _globals['_RUNINITIATERESULT']._serialized_start=2159
+  _globals['_RUNINITIATERESULT']._serialized_end=2178
+  _globals['_RUNCANCELREQUEST']._serialized_start=2180
+  _globals['_RUNCANCELREQUEST']._serialized_end=2247
+  _globals['_RUNCANCELRESPONSE']._serialized_start=2249
+  _globals['_RUNCANCELRESPONSE']._serialized_end=2268
+  _globals['_RUNWATCHREQUEST']._serialized_start=2270
+  _globals['_RUNWATCHREQUEST']._serialized_end=2336
+  _globals['_DISPATCHREQUEST']._serialized_start=2338
+  _globals['_DISPATCHREQUEST']._serialized_end=2410
+  _globals['_DISPATCHRESPONSE']._serialized_start=2412
+  _globals['_DISPATCHRESPONSE']._serialized_end=2430
+  _globals['_AGENT']._serialized_start=2433
+  _globals['_AGENT']._serialized_end=2764
+  _globals['_EVENTS']._serialized_start=2766
+  _globals['_EVENTS']._serialized_end=2859
+  _globals['_RUNNER']._serialized_start=2861
+  _globals['_RUNNER']._serialized_end=2954
 # @@protoc_insertion_point(module_scope)
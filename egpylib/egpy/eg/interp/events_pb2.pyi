@@ -30,29 +30,35 @@ class Heartbeat(_message.Message):
     __slots__ = ()
     def __init__(self) -> None: ...
 
-class Task(_message.Message):
-    __slots__ = ("id", "pid", "description", "state", "deadline")
+class Op(_message.Message):
+    __slots__ = ("state", "milliseconds", "name", "module", "op", "path")
     class State(int, metaclass=_enum_type_wrapper.EnumTypeWrapper):
         __slots__ = ()
-        Pending: _ClassVar[Task.State]
-        Initiated: _ClassVar[Task.State]
-        Completed: _ClassVar[Task.State]
-        Error: _ClassVar[Task.State]
-    Pending: Task.State
-    Initiated: Task.State
-    Completed: Task.State
-    Error: Task.State
-    ID_FIELD_NUMBER: _ClassVar[int]
-    PID_FIELD_NUMBER: _ClassVar[int]
-    DESCRIPTION_FIELD_NUMBER: _ClassVar[int]
+        Initiated: _ClassVar[Op.State]
+        Completed: _ClassVar[Op.State]
+        Cancelled: _ClassVar[Op.State]
+        Skipped: _ClassVar[Op.State]
+        TimedOut: _ClassVar[Op.State]
+        Error: _ClassVar[Op.State]
+    Initiated: Op.State
+    Completed: Op.State
+    Cancelled: Op.State
+    Skipped: Op.State
+    TimedOut: Op.State
+    Error: Op.State
     STATE_FIELD_NUMBER: _ClassVar[int]
-    DEADLINE_FIELD_NUMBER: _ClassVar[int]
-    id: str
-    pid: str
-    description: str
-    state: Task.State
-    deadline: int
-    def __init__(self, id: _Optional[str] = ..., pid: _Optional[str] = ..., description: _Optional[str] = ..., state: _Optional[_Union[Task.State, str]] = ..., deadline: _Optional[int] = ...) -> None: ...
+    MILLISECONDS_FIELD_NUMBER: _ClassVar[int]
+    NAME_FIELD_NUMBER: _ClassVar[int]
+    MODULE_FIELD_NUMBER: _ClassVar[int]
+    OP_FIELD_NUMBER: _ClassVar[int]
+    PATH_FIELD_NUMBER: _ClassVar[int]
+    state: Op.State
+    milliseconds: int
+    name: str
+    module: str
+    op: str
+    path: _containers.RepeatedScalarFieldContainer[str]
+    def __init__(self, state: _Optional[_Union[Op.State, str]] = ..., milliseconds: _Optional[int] = ..., name: _Optional[str] = ..., module: _Optional[str] = ..., op: _Optional[str] = ..., path: _Optional[_Iterable[str]] = ...) -> None: ...
 
 class Metric(_message.Message):
     __slots__ = ("name", "fieldsJSON")
@@ -62,21 +68,97 @@ class Metric(_message.Message):
     fieldsJSON: bytes
     def __init__(self, name: _Optional[str] = ..., fieldsJSON: _Optional[bytes] = ...) -> None: ...
 
+class Coverage(_message.Message):
+    __slots__ = ("path", "statements", "branches", "lines")
+    PATH_FIELD_NUMBER: _ClassVar[int]
+    STATEMENTS_FIELD_NUMBER: _ClassVar[int]
+    BRANCHES_FIELD_NUMBER: _ClassVar[int]
+    LINES_FIELD_NUMBER: _ClassVar[int]
+    path: str
+    statements: float
+    branches: float
+    lines: _containers.RepeatedCompositeFieldContainer[CoverageLine]
+    def __init__(self, path: _Optional[str] = ..., statements: _Optional[float] = ..., branches: _Optional[float] = ..., lines: _Optional[_Iterable[_Union[CoverageLine, _Mapping]]] = ...) -> None: ...
+
+class CoverageLine(_message.Message):
+    __slots__ = ("line", "hits", "branches", "branches_hit")
+    LINE_FIELD_NUMBER: _ClassVar[int]
+    HITS_FIELD_NUMBER: _ClassVar[int]
+    BRANCHES_FIELD_NUMBER: _ClassVar[int]
+    BRANCHES_HIT_FIELD_NUMBER: _ClassVar[int]
+    line: int
+    hits: int
+    branches: int
+    branches_hit: int
+    def __init__(self, line: _Optional[int] = ..., hits: _Optional[int] = ..., branches: _Optional[int] = ..., branches_hit: _Optional[int] = ...) -> None: ...
+
+class Output(_message.Message):
+    __slots__ = ("path", "stdout", "stderr")
+    PATH_FIELD_NUMBER: _ClassVar[int]
+    STDOUT_FIELD_NUMBER: _ClassVar[int]
+    STDERR_FIELD_NUMBER: _ClassVar[int]
+    path: _containers.RepeatedScalarFieldContainer[str]
+    stdout: bytes
+    stderr: bytes
+    def __init__(self, path: _Optional[_Iterable[str]] = ..., stdout: _Optional[bytes] = ..., stderr: _Optional[bytes] = ...) -> None: ...
+
+class TestResult(_message.Message):
+    __slots__ = ("status", "suite", "name", "milliseconds", "framework", "message")
+    class Status(int, metaclass=_enum_type_wrapper.EnumTypeWrapper):
+        __slots__ = ()
+        Passed: _ClassVar[TestResult.Status]
+        Failed: _ClassVar[TestResult.Status]
+        Skipped: _ClassVar[TestResult.Status]
+        Error: _ClassVar[TestResult.Status]
+    Passed: TestResult.Status
+    Failed: TestResult.Status
+    Skipped: TestResult.Status
+    Error: TestResult.Status
+    STATUS_FIELD_NUMBER: _ClassVar[int]
+    SUITE_FIELD_NUMBER: _ClassVar[int]
+    NAME_FIELD_NUMBER: _ClassVar[int]
+    MILLISECONDS_FIELD_NUMBER: _ClassVar[int]
+    FRAMEWORK_FIELD_NUMBER: _ClassVar[int]
+    MESSAGE_FIELD_NUMBER: _ClassVar[int]
+    status: TestResult.Status
+    suite: str
+    name: str
+    milliseconds: int
+    framework: str
+    message: str
+    def __init__(self, status: _Optional[_Union[TestResult.Status, str]] = ..., suite: _Optional[str] = ..., name: _Optional[str] = ..., milliseconds: _Optional[int] = ..., framework: _Optional[str] = ..., message: _Optional[str] = ...) -> None: ...
+
+class NetworkViolation(_message.Message):
+    __slots__ = ("host", "port")
+    HOST_FIELD_NUMBER: _ClassVar[int]
+    PORT_FIELD_NUMBER: _ClassVar[int]
+    host: str
+    port: int
+    def __init__(self, host: _Optional[str] = ..., port: _Optional[int] = ...) -> None: ...
+
 class Message(_message.Message):
-    __slots__ = ("id", "ts", "preamble", "heartbeat", "task", "metric")
+    __slots__ = ("id", "ts", "preamble", "heartbeat", "op", "metric", "coverage", "output", "test", "network")
     ID_FIELD_NUMBER: _ClassVar[int]
     TS_FIELD_NUMBER: _ClassVar[int]
     PREAMBLE_FIELD_NUMBER: _ClassVar[int]
     HEARTBEAT_FIELD_NUMBER: _ClassVar[int]
-    TASK_FIELD_NUMBER: _ClassVar[int]
+    OP_FIELD_NUMBER: _ClassVar[int]
     METRIC_FIELD_NUMBER: _ClassVar[int]
+    COVERAGE_FIELD_NUMBER: _ClassVar[int]
+    OUTPUT_FIELD_NUMBER: _ClassVar[int]
+    TEST_FIELD_NUMBER: _ClassVar[int]
+    NETWORK_FIELD_NUMBER: _ClassVar[int]
     id: str
     ts: int
     preamble: LogHeader
     heartbeat: Heartbeat
-    task: Task
+    op: Op
     metric: Metric
-    def __init__(self, id: _Optional[str] = ..., ts: _Optional[int] = ..., preamble: _Optional[_Union[LogHeader, _Mapping]] = ..., heartbeat: _Optional[_Union[Heartbeat, _Mapping]] = ..., task: _Optional[_Union[Task, _Mapping]] = ..., metric: _Optional[_Union[Metric, _Mapping]] = ...) -> None: ...
+    coverage: Coverage
+    output: Output
+    test: TestResult
+    network: NetworkViolation
+    def __init__(self, id: _Optional[str] = ..., ts: _Optional[int] = ..., preamble: _Optional[_Union[LogHeader, _Mapping]] = ..., heartbeat: _Optional[_Union[Heartbeat, _Mapping]] = ..., op: _Optional[_Union[Op, _Mapping]] = ..., metric: _Optional[_Union[Metric, _Mapping]] = ..., coverage: _Optional[_Union[Coverage, _Mapping]] = ..., output: _Optional[_Union[Output, _Mapping]] = ..., test: _Optional[_Union[TestResult, _Mapping]] = ..., network: _Optional[_Union[NetworkViolation, _Mapping]] = ...) -> None: ...
 
 class RunUploadChunk(_message.Message):
     __slots__ = ("data", "checksum", "none", "metadata")
@@ -124,8 +206,10 @@ class RunInitiateResult(_message.Message):
     def __init__(self) -> None: ...
 
 class RunCancelRequest(_message.Message):
-    __slots__ = ()
-    def __init__(self) -> None: ...
+    __slots__ = ("run",)
+    RUN_FIELD_NUMBER: _ClassVar[int]
+    run: RunMetadata
+    def __init__(self, run: _Optional[_Union[RunMetadata, _Mapping]] = ...) -> None: ...
 
 class RunCancelResponse(_message.Message):
     __slots__ = ()
@@ -0,0 +1,334 @@
+import os
+import sys
+import uuid
+import atexit
+import typing
+import hashlib
+import logging
+import threading
+import collections
+import concurrent.futures
+import egpy.eg as eg
+from . import graph
+from .context import Context
+from .cancel import interrupted, cleanup
+
+OpFn = typing.Callable[[Context, "Op"], typing.Any]
+
+# location of the packaged module relative to the build directory, see compile.Python.
+Module = os.path.join("main.wasm.d", "main.pyz")
+
+
+class Op(object):
+    '''
+    reference to an executing operation.
+    '''
+
+    def __init__(self, fn: OpFn):
+        self.fn = fn
+
+    def id(self) -> str:
+        return f"ref{id(self.fn):x}"
+
+
+def _run(ctx: Context, fn: OpFn):
+    ref = Op(fn)
+
+    def do(ctx: Context):
+        try:
+            return fn(ctx, ref)
+        except BaseException as cause:
+            err = interrupted(cause)
+            if err is cause:
+                raise
+            raise err from cause
+
+    return graph.trace(ctx, fn, ref.id(), do)
+
+
+def _deadline(ctx: Context):
+    err = ctx.err()
+    if err is not None:
+        raise err
+
+
+class _modules(object):
+    '''
+    nested modules execute the same program as the root module. while composing
+    the operations every module is registered by the location it was declared at,
+    the operations of the module being executed replace the program's operations.
+    '''
+
+    def __init__(self, target: str):
+        self.lock = threading.Lock()
+        self.target = target
+        self.sites: typing.Counter[str] = collections.Counter()
+        self.operations: typing.Dict[str, typing.Sequence[OpFn]] = {}
+        self.dispatched = False
+
+    def register(self, site: str, operations: typing.Sequence[OpFn]) -> str:
+        with self.lock:
+            self.sites[site] += 1
+            key = f"{site}#{self.sites[site]}"
+            self.operations[key] = operations
+            return key
+
+    def claim(self) -> typing.Optional[typing.Sequence[OpFn]]:
+        with self.lock:
+            if self.dispatched or self.target not in self.operations:
+                return None
+            self.dispatched = True
+            return self.operations[self.target]
+
+    def verify(self):
+        if self.dispatched:
+            return
+        logging.error(f"unable to locate module {self.target}, modules must be declared deterministically")
+        os._exit(1)
+
+
+_registry = _modules(os.environ.get("EG_COMPUTE_PYTHON_MODULE", ""))
+if _registry.target:
+    atexit.register(_registry.verify)
+
+
+def perform(ctx: Context, *operations: OpFn):
+    '''
+    execute the provided operations in sequential order. when the workload is cancelled
+    the operations registered with on_cancel are executed before returning.
+    '''
+    if _registry.target and not ctx.dispatched:
+        # within a nested module only the operations of the module are executed.
+        operations = _registry.claim()
+        if operations is None:
+            return
+        ctx = Context(ctx.deadline, ctx.path, dispatched=True)
+
+    for fn in operations:
+        try:
+            _deadline(ctx)
+            _run(ctx, fn)
+        except BaseException as cause:
+            cleanup(ctx, cause)
+            raise
+
+
+def sequential(*operations: OpFn) -> OpFn:
+    '''
+    execute the operations in order, the first failure stops execution.
+    '''
+
+    def op(ctx: Context, o: Op):
+        for fn in operations:
+            _deadline(ctx)
+            _run(ctx, fn)
+
+    return op
+
+
+def parallel(*operations: OpFn, limit: int = 0) -> OpFn:
+    '''
+    execute the operations in parallel with at most limit operations executing at a time.
+    a limit less than 1 is unbounded. every operation is executed, failures are
+    raised once all the operations have completed.
+    '''
+
+    def op(ctx: Context, o: Op):
+        workers = limit if limit > 0 else max(len(operations), 1)
+        with concurrent.futures.ThreadPoolExecutor(max_workers=workers) as pool:
+            futures = [pool.submit(_run, ctx, fn) for fn in operations]
+            errs = [f.exception() for f in futures]
+
+        errs = [e for e in errs if e is not None]
+        if len(errs) == 1:
+            raise errs[0]
+        if len(errs) > 1:
+            raise BaseExceptionGroup("parallel operations failed", errs)
+
+    return op
+
+
+def when(b: typing.Union[bool, typing.Callable[[Context], bool]], o: OpFn) -> OpFn:
+    '''
+    make an operation conditional on a boolean or a function of the context.
+    '''
+
+    def op(ctx: Context, i: Op):
+        if not (b(ctx) if callable(b) else b):
+            graph.skipped(ctx, o, Op(o).id())
+            return
+        return _run(ctx, o)
+
+    return op
+
+
+class Container(object):
+    '''
+    container to execute modules and commands within, see container().
+    '''
+
+    def __init__(self, name: str):
+        self.name = name
+        self.definition = ""
+        self.pull = ""
+        self.cmd: typing.List[str] = []
+        self.options: typing.List[str] = []
+        self._built = {"lock": threading.Lock(), "done": False}
+
+    def clone(self) -> "Container":
+        dup = Container(self.name)
+        dup.definition = self.definition
+        dup.pull = self.pull
+        dup.cmd = list(self.cmd)
+        dup.options = list(self.options)
+        dup._built = self._built
+        return dup
+
+    def build_from_file(self, path: str) -> "Container":
+        '''
+        specifies the location of the container file on disk.
+        '''
+        dup = self.clone()
+        dup.definition = path
+        return dup
+
+    def pull_from(self, uri: str) -> "Container":
+        '''
+        pull the container from a remote repository.
+        '''
+        dup = self.clone()
+        dup.pull = uri
+        return dup
+
+    def command(self, cmd: str) -> "Container":
+        '''
+        the command to execute.
+        '''
+        dup = self.clone()
+        dup.cmd = cmd.split(" ")
+        return dup
+
+    def option_literal(self, *args: str) -> "Container":
+        dup = self.clone()
+        dup.options.extend(args)
+        return dup
+
+    def option_working_directory(self, dir: str) -> "Container":
+        return self.option_literal("-w", dir)
+
+    def option_env_var(self, k: str) -> "Container":
+        return self.option_literal("-e", k)
+
+    def option_env(self, k: str, v: str) -> "Container":
+        return self.option_literal("-e", f"{k}={v}")
+
+    def compile_with(self, ctx: Context):
+        '''
+        pulls and builds the container, the container is built at most once.
+        '''
+        from .interp.containers_pb2 import PullRequest, BuildRequest
+
+        with self._built["lock"]:
+            if self._built["done"]:
+                return
+
+            with eg.autoclient() as c:
+                proxy = _containers(c)
+                if self.pull:
+                    proxy.Pull(
+                        PullRequest(name=self.pull, dir=os.getcwd(), options=self.options),
+                        timeout=ctx.remaining(),
+                        metadata=graph.outgoing(ctx),
+                    )
+
+                if self.definition:
+                    proxy.Build(
+                        BuildRequest(name=self.name, definition=self.definition, options=self.options),
+                        timeout=ctx.remaining(),
+                        metadata=graph.outgoing(ctx),
+                    )
+
+            self._built["done"] = True
+
+    def run_with(self, ctx: Context):
+        from .interp.containers_pb2 import RunRequest
+
+        with eg.autoclient() as c:
+            _containers(c).Run(
+                RunRequest(image=self.name, name=_cname(self.name, "".join(self.cmd)), command=self.cmd, options=self.options),
+                timeout=ctx.remaining(),
+                metadata=graph.outgoing(ctx),
+            )
+
+    def module_with(self, ctx: Context, key: str):
+        from .interp.containers_pb2 import ModuleRequest
+
+        level = int(os.environ.get("EG_COMPUTE_MODULE_LEVEL", "-1")) + 1
+        options = self.options + [
+            "-e", f"EG_COMPUTE_MODULE_LEVEL={level}",
+            "-e", f"EG_COMPUTE_PYTHON_MODULE={key}",
+            "-e", f"EG_COMPUTE_OPERATION_PATH={'/'.join(ctx.path)}",
+        ]
+
+        with eg.autoclient() as c:
+            _containers(c).Module(
+                ModuleRequest(
+                    image=self.name,
+                    name=_cname(self.name, Module + key),
+                    mdir=eg.defaultRunnerRuntimeDir(),
+                    module=Module,
+                    options=options,
+                ),
+                timeout=ctx.remaining(),
+                metadata=graph.outgoing(ctx),
+            )
+
+
+def _containers(c):
+    from .interp.containers_pb2_grpc import ProxyStub
+
+    return ProxyStub(c)
+
+
+def _cname(name: str, id: str) -> str:
+    digest = hashlib.md5(f"{id}{os.environ.get('EG_COMPUTE_RUN_ID', '')}".encode("utf-8")).digest()
+    return f"{name}.{uuid.UUID(bytes=digest)}"
+
+
+def container(name: str) -> Container:
+    '''
+    run the operations within the specified container.
+    '''
+    return Container(name)
+
+
+def build(r: Container) -> OpFn:
+    def op(ctx: Context, o: Op):
+        r.compile_with(ctx)
+
+    return op
+
+
+def module(ctx: Context, r: Container, *operations: OpFn) -> OpFn:
+    '''
+    executes a set of operations within the provided container. the container
+    must be built beforehand, see build().
+    '''
+    caller = sys._getframe(1)
+    key = _registry.register(f"{caller.f_globals.get('__name__', '')}:{caller.f_lineno}", operations)
+
+    def op(ctx: Context, o: Op):
+        r.module_with(ctx, key)
+
+    return op
+
+
+def exec(ctx: Context, r: Container) -> OpFn:
+    '''
+    executes the container's command.
+    '''
+
+    def op(ctx: Context, o: Op):
+        r.run_with(ctx)
+
+    return op
@@ -0,0 +1,108 @@
+'''
+reports line level coverage of the workload, allowing coverage to be tracked
+over time and runs to be gated on the coverage of the lines they change.
+'''
+import os
+import typing
+import logging
+import egpy.eg as eg
+from egpy.eg.interp.events_pb2 import Coverage, CoverageLine
+
+# maximum number of coverage lines dispatched at once, keeps line level
+# coverage well within the message size limits of the control socket.
+maxlines = 32 * 1024
+
+
+def lines(*ls: CoverageLine) -> float:
+    '''
+    percentage of the executable lines hit.
+    '''
+    if len(ls) == 0:
+        return 0.0
+
+    return (len([l for l in ls if l.hits > 0]) / len(ls)) * 100.0
+
+
+def branches(*ls: CoverageLine) -> float:
+    '''
+    percentage of the branches hit.
+    '''
+    total = sum(l.branches for l in ls)
+    if total == 0:
+        return 0.0
+
+    return (sum(l.branches_hit for l in ls) / total) * 100.0
+
+
+def report(*batch: Coverage, timeout: float = 30.0):
+    '''
+    report the coverage of the provided files, large reports are dispatched in chunks.
+    '''
+    from egpy.metrics.eg_interp_events_pb2_grpc import EventsStub
+
+    if len(batch) == 0:
+        return
+
+    with eg.autoclient() as c:
+        d = EventsStub(c)
+
+        def dispatch(reps: typing.Sequence[Coverage]):
+            try:
+                d.Dispatch(eg.dispatch(*[eg.coverage(rep) for rep in reps]), timeout=timeout)
+            except Exception as e:
+                raise RuntimeError("unable to report coverage") from e
+
+        offset, n = 0, 0
+        for idx, rep in enumerate(batch):
+            if n > 0 and n + len(rep.lines) > maxlines:
+                dispatch(batch[offset:idx])
+                offset, n = idx, 0
+            n += len(rep.lines)
+
+        dispatch(batch[offset:])
+
+
+def coveragepy(cov, root: str) -> typing.Iterator[Coverage]:
+    '''
+    converts the data measured by coverage.py into coverage reports. only files within
+    the root directory are reported, paths are relative to the root.
+    coverage.py does not count executions, hit lines are reported with a single hit.
+    '''
+    root = os.path.abspath(root)
+    data = cov.get_data()
+
+    for path in sorted(data.measured_files()):
+        abspath = os.path.abspath(path)
+        if not abspath.startswith(root + os.sep):
+            continue
+
+        try:
+            _, statements, _, missing, _ = cov.analysis2(abspath)
+        except Exception as e:
+            logging.debug(f"unable to analyze coverage {abspath} {e}")
+            continue
+
+        stats = _branches(cov, abspath) if data.has_arcs() else {}
+        missed = set(missing)
+        ls = []
+        for n in statements:
+            total, taken = stats.get(n, (0, 0))
+            ls.append(CoverageLine(line=n, hits=0 if n in missed else 1, branches=total, branches_hit=taken))
+
+        yield Coverage(
+            path=os.path.relpath(abspath, root),
+            statements=lines(*ls),
+            branches=branches(*ls),
+            lines=ls,
+        )
+
+
+def _branches(cov, path: str) -> typing.Dict[int, typing.Tuple[int, int]]:
+    # coverage.py only exposes branch statistics through its private analysis, the possible
+    # arcs are unavailable from the public api. when it changes the branches are reported as
+    # uncovered rather than failing the report, warn so the missing statistics are noticed.
+    try:
+        return cov._analyze(path).branch_stats()
+    except Exception as e:
+        logging.warning(f"unable to analyze branches, branch coverage unavailable {path} {e}")
+        return {}
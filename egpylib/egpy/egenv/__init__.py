@@ -0,0 +1,121 @@
+import os
+import tempfile
+from egpy import envx
+
+# root of the directories accessible by egd, see eg.DefaultWorkloadDirectory.
+_workload = os.path.join("/", "workload")
+
+
+def ttl() -> float:
+    '''
+    provides the TTL specified by the runtime in seconds. used for setting context durations.
+    defaults to an hour.
+    i.e.) ctx = eg.background().with_timeout(egenv.ttl())
+    '''
+    return envx.duration(3600.0, "EG_COMPUTE_TTL")
+
+
+def run_id() -> str:
+    '''
+    read the run ID from the environment.
+    '''
+    return envx.string("00000000-0000-0000-0000-000000000000", "EG_COMPUTE_RUN_ID")
+
+
+def workload_directory(*paths: str) -> str:
+    '''
+    returns the absolute path to the workload directory of the module. this directory is the
+    root directory of the workload.
+    i.e.) workload_directory("foo", "bar") -> "/{eg.workload}/foo/bar"
+    '''
+    return os.path.join(envx.string(_workload, "EG_COMPUTE_WORKLOAD_DIRECTORY"), *paths)
+
+
+def cache_directory(*paths: str) -> str:
+    '''
+    returns the absolute path to the cache directory, when arguments are provided they are
+    joined with the cache directory.
+
+    files stored in the cache directory are maintained between runs on a best effort basis.
+    files prefixed with .eg are reserved for system use.
+    i.e.) cache_directory("foo", "bar") -> "/{eg.cache}/foo/bar"
+    '''
+    return os.path.join(envx.string(tempfile.gettempdir(), "EG_COMPUTE_CACHE_DIRECTORY", "CACHE_DIRECTORY"), *paths)
+
+
+def runtime_directory(*paths: str) -> str:
+    '''
+    returns the absolute path to the runtime directory, when arguments are provided they are
+    joined with the runtime directory.
+
+    files stored in the runtime directory are maintained for the duration of a workload. every module
+    will be able to read the data stored in the runtime folder.
+    i.e.) runtime_directory("foo", "bar") -> "/{eg.runtime}/foo/bar"
+    '''
+    return os.path.join(envx.string(tempfile.gettempdir(), "EG_COMPUTE_RUNTIME_DIRECTORY"), *paths)
+
+
+def workspace_directory(*paths: str) -> str:
+    '''
+    returns the absolute path to the workspace directory, when arguments are provided they are
+    joined with the workspace directory.
+
+    experimental directory - intended to be a directory for maintaining data for the lifetime of the workload.
+    i.e.) workspace_directory("foo", "bar") -> "/{eg.workspace}/foo/bar"
+    '''
+    return os.path.join(
+        envx.string(os.path.join(_workload, ".eg.workspace"), "EG_COMPUTE_WORKSPACE_DIRECTORY"), *paths
+    )
+
+
+def working_directory(*paths: str) -> str:
+    '''
+    returns the absolute path to the working directory of the module. this directory is the
+    initial working directory of the workload and is used for cloning git repositories etc.
+    '''
+    return os.path.join(envx.string(os.path.join(_workload, "eg"), "EG_COMPUTE_WORKING_DIRECTORY"), *paths)
+
+
+def ephemeral_directory(*paths: str) -> str:
+    '''
+    returns the absolute path to the ephemeral directory, when arguments are provided they are
+    joined with the ephemeral directory.
+
+    files stored in the ephemeral directory are maintained for the duration of a single module's execution
+    and is unique to that module.
+    i.e.) ephemeral_directory("foo", "bar") -> "/{eg.ephemeral}/foo/bar"
+    '''
+    # TMPDIR is read on every call, matching egenv.EphemeralDirectory, tempfile caches the first value it resolves.
+    return os.path.join(envx.string(tempfile.gettempdir(), "TMPDIR"), *paths)
+
+
+def boolean(fallback: bool, *keys: str) -> bool:
+    '''
+    extract a boolean formatted environment variable from the given keys
+    returns the first valid result if none of the keys exist then the fallback is returned.
+    '''
+    return envx.boolean(fallback, *keys)
+
+
+def string(fallback: str, *keys: str) -> str:
+    '''
+    extract a string formatted environment variable from the given keys
+    returns the first valid result if none of the keys exist then the fallback is returned.
+    '''
+    return envx.string(fallback, *keys)
+
+
+def integer(fallback: int, *keys: str) -> int:
+    '''
+    retrieve an integer from the environment, checks each key in order
+    first to parse successfully is returned.
+    '''
+    return envx.integer(fallback, *keys)
+
+
+def duration(fallback: float, *keys: str) -> float:
+    '''
+    extract a go formatted duration (i.e. 1h30m) in seconds from the given keys
+    returns the first valid result if none of the keys exist then the fallback is returned.
+    '''
+    return envx.duration(fallback, *keys)
@@ -0,0 +1,41 @@
+'''
+reports the results of individual tests, allowing flaky and slow tests to be tracked over time.
+'''
+import typing
+import egpy.eg as eg
+from egpy.eg.interp.events_pb2 import TestResult
+
+Passed = TestResult.Passed
+Failed = TestResult.Failed
+Skipped = TestResult.Skipped
+Error = TestResult.Error
+
+
+def report(*results: TestResult, timeout: float = 30.0):
+    '''
+    report the test results.
+    '''
+    from egpy.metrics.eg_interp_events_pb2_grpc import EventsStub
+
+    if len(results) == 0:
+        return
+
+    with eg.autoclient() as c:
+        try:
+            EventsStub(c).Dispatch(eg.dispatch(*[eg.test(r) for r in results]), timeout=timeout)
+        except Exception as e:
+            raise RuntimeError("unable to report test results") from e
+
+
+def record(results: typing.Iterable[TestResult], size: int = 128):
+    '''
+    report the test results in batches.
+    '''
+    batch = []
+    for r in results:
+        batch.append(r)
+        if len(batch) == size:
+            report(*batch)
+            batch = []
+
+    report(*batch)
@@ -1,7 +1,51 @@
 import os
+import re
 import typing
 import logging
-import distutils
+
+# units of go formatted durations (i.e. 1h30m) in seconds.
+_units = {
+    "ns": 1e-9,
+    "us": 1e-6,
+    "µs": 1e-6,
+    "ms": 1e-3,
+    "s": 1.0,
+    "m": 60.0,
+    "h": 3600.0,
+}
+_duration = re.compile(r"(\d+(?:\.\d*)?|\.\d+)(ns|us|µs|ms|s|m|h)")
+
+
+def strtobool(v: str) -> bool:
+    '''
+    parses a boolean using the same rules as go's strconv.ParseBool.
+    '''
+    if v in ("1", "t", "T", "TRUE", "true", "True"):
+        return True
+    if v in ("0", "f", "F", "FALSE", "false", "False"):
+        return False
+    raise ValueError(f"invalid boolean {v}")
+
+
+def parseduration(v: str) -> float:
+    '''
+    parses a go formatted duration (i.e. 1h30m, 500ms) returning the number of seconds.
+    '''
+    sign, v = (-1.0, v[1:]) if v.startswith("-") else (1.0, v.lstrip("+"))
+    if v == "0":
+        return 0.0
+
+    total, offset = 0.0, 0
+    for m in _duration.finditer(v):
+        if m.start() != offset:
+            break
+        total += float(m.group(1)) * _units[m.group(2)]
+        offset = m.end()
+
+    if offset == 0 or offset != len(v):
+        raise ValueError(f"invalid duration {v}")
+
+    return sign * total
 
 
 def boolean(fallback: bool, *s: typing.List[str]) -> bool:
@@ -10,7 +54,7 @@ def boolean(fallback: bool, *s: typing.List[str]) -> bool:
             v = os.environ.get(x)
             if v is None:
                 continue
-            return distutils.util.strtobool(v) == 0
+            return strtobool(v)
         except Exception as e:
             logging.error(
                 "unable to parse boolean environment variable {} -> {}".format(x, e)
@@ -33,3 +77,34 @@ def string(fallback: str, *s: typing.List[str]) -> str:
                 )
             )
     return fallback
+
+
+def integer(fallback: int, *s: typing.List[str]) -> int:
+    for x in s:
+        try:
+            v = os.environ.get(x)
+            if v is None:
+                continue
+            return int(v)
+        except Exception as e:
+            logging.error(
+                "unable to parse integer environment variable {} -> {}".format(x, e)
+            )
+    return fallback
+
+
+def duration(fallback: float, *s: typing.List[str]) -> float:
+    '''
+    retrieve a go formatted duration in seconds from the environment.
+    '''
+    for x in s:
+        try:
+            v = os.environ.get(x)
+            if v is None:
+                continue
+            return parseduration(v)
+        except Exception as e:
+            logging.error(
+                "unable to parse duration environment variable {} -> {}".format(x, e)
+            )
+    return fallback
@@ -0,0 +1,120 @@
+'''
+pytest plugin reporting test timings and coverage into the run's events. the plugin is
+registered automatically when egpy is installed and is only active within eg workloads.
+disable it with -p no:egpy.
+'''
+import os
+import time
+import logging
+import pytest
+import egpy.eg as eg
+from egpy import egenv, egtests, egcoverage
+from egpy.eg.interp.events_pb2 import TestResult
+
+
+def pytest_addoption(parser):
+    group = parser.getgroup("egpy")
+    group.addoption(
+        "--no-eg-coverage",
+        action="store_true",
+        default=False,
+        help="do not measure and report coverage to the eg runtime.",
+    )
+
+
+def pytest_configure(config):
+    if not os.path.exists(eg.defaultRunnerSocketPath()):
+        return
+
+    config.pluginmanager.register(Reporter(config), "egpy.reporter")
+
+
+class Reporter(object):
+    def __init__(self, config):
+        self.config = config
+        self.results = {}
+        self.cov = None
+        self.owned = False
+
+        if config.getoption("no_eg_coverage"):
+            return
+
+        self.cov = _pytestcov(config)
+        if self.cov is not None:
+            return
+
+        try:
+            import coverage
+        except ImportError:
+            logging.debug("coverage.py is not installed, coverage will not be reported")
+            return
+
+        self.cov = coverage.Coverage(data_file=None, branch=True)
+        self.cov.start()
+        self.owned = True
+
+    def pytest_runtest_logreport(self, report):
+        suite, _, name = report.nodeid.rpartition("::")
+        r = self.results.get(report.nodeid)
+        if r is None:
+            r = TestResult(status=TestResult.Passed, suite=suite, name=name or report.nodeid, framework="pytest")
+            self.results[report.nodeid] = r
+
+        r.milliseconds += int(report.duration * 1000)
+
+        if report.skipped:
+            r.status = TestResult.Skipped
+            r.message = _skipreason(report)
+        elif report.failed and report.when == "call":
+            r.status = TestResult.Failed
+            r.message = report.longreprtext
+        elif report.failed and r.status == TestResult.Passed:
+            # failures during setup and teardown are errors of the test harness.
+            r.status = TestResult.Error
+            r.message = report.longreprtext
+
+    @pytest.hookimpl(trylast=True)
+    def pytest_sessionfinish(self, session, exitstatus):
+        ts = time.monotonic()
+        try:
+            egtests.record(self.results.values())
+        except Exception as e:
+            logging.warning(f"unable to report test results {e}")
+
+        if self.cov is None:
+            return
+
+        if self.owned:
+            self.cov.stop()
+
+        try:
+            egcoverage.report(*egcoverage.coveragepy(self.cov, _root(session.config)))
+        except Exception as e:
+            logging.warning(f"unable to report coverage {e}")
+
+        logging.debug(f"reporting completed {time.monotonic() - ts}")
+
+
+def _pytestcov(config):
+    # reuse the measurements of pytest-cov when it is active.
+    plugin = config.pluginmanager.getplugin("_cov")
+    controller = getattr(plugin, "cov_controller", None)
+    return getattr(controller, "cov", None)
+
+
+def _skipreason(report) -> str:
+    if isinstance(report.longrepr, tuple) and len(report.longrepr) == 3:
+        return str(report.longrepr[2]).removeprefix("Skipped: ")
+    return getattr(report, "wasxfail", "") or report.longreprtext
+
+
+def _root(config) -> str:
+    '''
+    coverage paths are relative to the working directory of the workload, allowing them
+    to be matched against the changes of the run. falls back to the pytest root directory.
+    '''
+    root = str(config.rootpath)
+    wdir = egenv.working_directory()
+    if root == wdir or root.startswith(wdir + os.sep):
+        return wdir
+    return root
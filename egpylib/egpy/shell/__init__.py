@@ -0,0 +1,227 @@
+import os
+import sys
+import time
+import typing
+import logging
+import egpy.eg as eg
+from egpy.eg import graph
+from egpy.eg.interp.exec_pb2 import ExecRequest
+
+DefaultTimeout = 5 * 60
+DefaultUsername = "egd"
+
+
+class Command(object):
+    '''
+    shell command executed within the workload environment, commands are immutable
+    every option returns a modified copy allowing commands to be used as templates.
+    i.e.)
+    runtime = shell.runtime().environ("FOO", "BAR")
+    shell.run(ctx, runtime.new("ls -lha"), runtime.new("echo hello world"))
+    '''
+
+    def __init__(self, cmd: str):
+        self._cmd = cmd
+        self._user = DefaultUsername
+        self._group = os.environ.get("EG_COMPUTE_DEFAULT_GROUP", DefaultUsername)
+        self._directory = ""
+        self._environ: typing.List[str] = []
+        self._timeout = DefaultTimeout
+        self._attempts = 1
+        self._lenient = False
+
+    def _clone(self) -> "Command":
+        dup = Command(self._cmd)
+        dup.__dict__.update(self.__dict__)
+        dup._environ = list(self._environ)
+        return dup
+
+    def new(self, cmd: str) -> "Command":
+        '''
+        clone the current command configuration and replace the command that will be executed.
+        '''
+        dup = self._clone()
+        dup._cmd = cmd
+        return dup
+
+    def attempts(self, n: int) -> "Command":
+        '''
+        number of attempts to make before giving up.
+        '''
+        dup = self._clone()
+        dup._attempts = n
+        return dup
+
+    def directory(self, d: str) -> "Command":
+        '''
+        directory to run the command in. must be a relative path.
+        '''
+        dup = self._clone()
+        dup._directory = d
+        return dup
+
+    def lenient(self, b: bool) -> "Command":
+        '''
+        failures are logged and ignored.
+        '''
+        dup = self._clone()
+        dup._lenient = b
+        return dup
+
+    def timeout(self, seconds: float) -> "Command":
+        '''
+        maximum duration in seconds for a command to run. default is 5 minutes.
+        '''
+        dup = self._clone()
+        dup._timeout = seconds
+        return dup
+
+    def environ_from(self, *environ: str) -> "Command":
+        '''
+        append a set of environment variables in the form KEY=VALUE to the environment.
+        '''
+        dup = self._clone()
+        dup._environ.extend(environ)
+        return dup
+
+    def environ(self, k: str, v: typing.Any) -> "Command":
+        '''
+        append a specific key/value environment variable.
+        '''
+        return self.environ_from(f"{k}={v}")
+
+    def user(self, u: str) -> "Command":
+        '''
+        user to run the command as.
+        '''
+        dup = self._clone()
+        dup._user = u
+        return dup
+
+    def group(self, g: str) -> "Command":
+        '''
+        group to run the command as.
+        '''
+        dup = self._clone()
+        dup._group = g
+        return dup
+
+    def run_as(self, u: str) -> "Command":
+        '''
+        convience function that sets both user and group to the provided value.
+        '''
+        return self.user(u).group(u)
+
+    def privileged(self) -> "Command":
+        '''
+        specialized for run_as("root") which runs the command as root.
+        '''
+        return self.run_as("root")
+
+    def request(self) -> ExecRequest:
+        return ExecRequest(
+            dir=self._directory,
+            cmd="sudo",
+            arguments=["-E", "-H", "-u", self._user, "-g", self._group, "bash", "-c", self._cmd],
+            environment=self._environ,
+        )
+
+
+def new(cmd: str) -> Command:
+    '''
+    create a new command with reasonable defaults.
+    defaults:
+        timeout: 5 minutes.
+    '''
+    return Command(cmd)
+
+
+def runtime() -> Command:
+    '''
+    creates a command with no specified command to run, used as a template.
+    '''
+    return new("")
+
+
+def env() -> Command:
+    '''
+    creates a runtime command pre-populated with the current process environment.
+    '''
+    return runtime().environ_from(*[f"{k}={v}" for k, v in os.environ.items()])
+
+
+def _timeout(ctx: eg.Context, cmd: Command) -> float:
+    remaining = ctx.remaining()
+    if remaining is None:
+        return cmd._timeout
+    return min(remaining, cmd._timeout)
+
+
+def _attempt(ctx: eg.Context, cmd: Command, do: typing.Callable[[eg.Context, Command], typing.Any]):
+    for i in range(max(cmd._attempts, 1)):
+        try:
+            return do(ctx, cmd)
+        except Exception as e:
+            if i + 1 >= max(cmd._attempts, 1):
+                raise
+            logging.warning(f"command failed, retrying: {cmd._cmd} {e}")
+            time.sleep(0.2)
+
+
+def _exec(ctx: eg.Context, cmd: Command):
+    from egpy.eg.interp.exec_pb2_grpc import ProxyStub
+
+    with eg.moduleclient() as c:
+        ProxyStub(c).Exec(cmd.request(), timeout=_timeout(ctx, cmd), metadata=graph.outgoing(ctx))
+
+
+def run(ctx: eg.Context, *cmds: Command):
+    '''
+    run the provided commands in order.
+    '''
+    for cmd in cmds:
+        try:
+            _attempt(ctx, cmd, _exec)
+        except Exception as e:
+            if cmd._lenient:
+                logging.warning(f"command failed, but lenient mode enable, ignoring {e}")
+                continue
+            raise RuntimeError(f"shell command failed: {cmd._cmd}") from e
+
+
+def op(*cmds: Command) -> eg.ops.OpFn:
+    '''
+    convience function for running a set of commands as an operation.
+    '''
+
+    def op(ctx: eg.Context, o: eg.Op):
+        run(ctx, *cmds)
+
+    return op
+
+
+def output(ctx: eg.Context, cmd: Command) -> bytes:
+    '''
+    runs the command and returns its standard output. standard error is written to the terminal.
+    i.e.) version = shell.output(ctx, runtime.new("git describe --tags"))
+    '''
+    from egpy.eg.interp.exec_pb2_grpc import ProxyStub
+
+    def do(ctx: eg.Context, cmd: Command) -> bytes:
+        buf = bytearray()
+        exit = 0
+        with eg.moduleclient() as c:
+            for chunk in ProxyStub(c).Output(cmd.request(), timeout=_timeout(ctx, cmd), metadata=graph.outgoing(ctx)):
+                buf.extend(chunk.stdout)
+                if chunk.stderr:
+                    sys.stderr.buffer.write(chunk.stderr)
+                exit = chunk.exit
+
+        if exit != 0:
+            raise RuntimeError(f"exit status {exit}")
+        return bytes(buf)
+
+    try:
+        return _attempt(ctx, cmd, do)
+    except Exception as e:
+        raise RuntimeError(f"shell command failed: {cmd._cmd}") from e
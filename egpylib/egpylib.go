@@ -0,0 +1,7 @@
+// Package egpylib embeds the egpy sdk, python modules are packaged alongside it.
+package egpylib
+
+import "embed"
+
+//go:embed all:egpy
+var SDK embed.FS
@@ -0,0 +1,49 @@
+import shutil
+import tempfile
+import threading
+import concurrent.futures
+import grpc
+import pytest
+from egpy.metrics.eg_interp_events_pb2_grpc import EventsServicer, add_EventsServicer_to_server
+from egpy.eg.interp import events_pb2
+
+pytest_plugins = ["pytester"]
+
+
+class FakeEvents(EventsServicer):
+    '''
+    records the messages dispatched to the control socket.
+    '''
+
+    def __init__(self):
+        self.lock = threading.Lock()
+        self.messages = []
+
+    def Dispatch(self, request, context):
+        with self.lock:
+            self.messages.extend(request.messages)
+        return events_pb2.DispatchResponse()
+
+    def of(self, kind: str):
+        with self.lock:
+            return [getattr(m, kind) for m in self.messages if m.WhichOneof("Event") == kind]
+
+
+@pytest.fixture
+def runtimedir(monkeypatch):
+    # unix socket paths are limited in length, avoid the deeply nested pytest directories.
+    dir = tempfile.mkdtemp(prefix="egpy")
+    monkeypatch.setenv("EG_COMPUTE_RUNTIME_DIRECTORY", dir)
+    yield dir
+    shutil.rmtree(dir, ignore_errors=True)
+
+
+@pytest.fixture
+def events(runtimedir):
+    fake = FakeEvents()
+    server = grpc.server(concurrent.futures.ThreadPoolExecutor(max_workers=4))
+    add_EventsServicer_to_server(fake, server)
+    server.add_insecure_port(f"unix://{runtimedir}/control.socket")
+    server.start()
+    yield fake
+    server.stop(None)
@@ -0,0 +1,47 @@
+import pytest
+from egpy import egcoverage
+from egpy.eg.interp.events_pb2 import Coverage, CoverageLine
+
+
+def test_percentages():
+    lines = [
+        CoverageLine(line=1, hits=1, branches=2, branches_hit=1),
+        CoverageLine(line=2, hits=0),
+        CoverageLine(line=3, hits=4, branches=2, branches_hit=2),
+        CoverageLine(line=4, hits=1),
+    ]
+    assert egcoverage.lines(*lines) == 75.0
+    assert egcoverage.branches(*lines) == 75.0
+    assert egcoverage.lines() == 0.0
+    assert egcoverage.branches(CoverageLine(line=1, hits=1)) == 0.0
+
+
+def test_report_chunks_large_reports(events, monkeypatch):
+    monkeypatch.setattr(egcoverage, "maxlines", 4)
+    batch = [
+        Coverage(path=f"file{i}.py", statements=100.0, lines=[CoverageLine(line=n, hits=1) for n in range(3)])
+        for i in range(3)
+    ]
+    egcoverage.report(*batch)
+
+    assert [c.path for c in events.of("coverage")] == ["file0.py", "file1.py", "file2.py"]
+
+
+def test_coveragepy(tmp_path):
+    coverage = pytest.importorskip("coverage")
+    src = tmp_path / "example.py"
+    src.write_text("def example(v):\n    if v:\n        return 1\n    return 2\n")
+
+    cov = coverage.Coverage(data_file=None, branch=True)
+    cov.start()
+    ns = {}
+    exec(compile(src.read_text(), str(src), "exec"), ns)
+    ns["example"](True)
+    cov.stop()
+
+    reports = list(egcoverage.coveragepy(cov, str(tmp_path)))
+    assert len(reports) == 1
+    assert reports[0].path == "example.py"
+    assert {l.line: l.hits for l in reports[0].lines} == {1: 1, 2: 1, 3: 1, 4: 0}
+    assert reports[0].statements == 75.0
+    assert reports[0].branches == 50.0
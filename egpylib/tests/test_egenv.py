@@ -0,0 +1,42 @@
+from egpy import egenv, envx
+
+
+def test_ttl_defaults_to_an_hour(monkeypatch):
+    monkeypatch.delenv("EG_COMPUTE_TTL", raising=False)
+    assert egenv.ttl() == 3600.0
+
+
+def test_ttl_parses_go_durations(monkeypatch):
+    monkeypatch.setenv("EG_COMPUTE_TTL", "1h30m0s")
+    assert egenv.ttl() == 5400.0
+
+
+def test_run_id(monkeypatch):
+    monkeypatch.delenv("EG_COMPUTE_RUN_ID", raising=False)
+    assert egenv.run_id() == "00000000-0000-0000-0000-000000000000"
+    monkeypatch.setenv("EG_COMPUTE_RUN_ID", "0192f1a4-0000-7000-8000-000000000000")
+    assert egenv.run_id() == "0192f1a4-0000-7000-8000-000000000000"
+
+
+def test_directories(monkeypatch):
+    monkeypatch.setenv("EG_COMPUTE_CACHE_DIRECTORY", "/workload/.eg.cache")
+    monkeypatch.setenv("EG_COMPUTE_RUNTIME_DIRECTORY", "/eg.mnt/.eg.runtime")
+    monkeypatch.delenv("EG_COMPUTE_WORKSPACE_DIRECTORY", raising=False)
+    monkeypatch.delenv("EG_COMPUTE_WORKING_DIRECTORY", raising=False)
+    assert egenv.cache_directory("foo", "bar") == "/workload/.eg.cache/foo/bar"
+    assert egenv.runtime_directory("foo") == "/eg.mnt/.eg.runtime/foo"
+    assert egenv.workspace_directory("foo") == "/workload/.eg.workspace/foo"
+    assert egenv.working_directory() == "/workload/eg"
+
+
+def test_ephemeral_directory(monkeypatch):
+    monkeypatch.setenv("TMPDIR", "/eg.mnt/.eg.ephemeral")
+    assert egenv.ephemeral_directory("foo", "bar") == "/eg.mnt/.eg.ephemeral/foo/bar"
+
+
+def test_invalid_values_fallback(monkeypatch):
+    monkeypatch.setenv("EXAMPLE_DURATION", "10")
+    monkeypatch.setenv("EXAMPLE_BOOLEAN", "yes")
+    assert envx.duration(1.0, "EXAMPLE_DURATION") == 1.0
+    assert envx.boolean(False, "EXAMPLE_BOOLEAN") is False
+    assert envx.boolean(True, "MISSING") is True
@@ -0,0 +1,15 @@
+from egpy import egtests
+from egpy.eg.interp.events_pb2 import TestResult
+
+
+def test_record_batches_results(events):
+    results = [TestResult(status=egtests.Passed, suite="suite", name=f"test{i}", framework="pytest") for i in range(5)]
+    egtests.record(results, size=2)
+
+    assert [r.name for r in events.of("test")] == [f"test{i}" for i in range(5)]
+    assert len(events.messages) == 5
+
+
+def test_report_nothing(events):
+    egtests.report()
+    assert events.messages == []
@@ -0,0 +1,79 @@
+import pytest
+from egpy import eg
+from egpy.eg.interp.events_pb2 import Op
+
+
+def succeed(ctx: eg.Context, o: eg.Op):
+    pass
+
+
+def fail(ctx: eg.Context, o: eg.Op):
+    raise ValueError("example failure")
+
+
+def test_perform_records_operations(events, monkeypatch):
+    monkeypatch.setenv("EG_COMPUTE_OPERATION_PATH", "root")
+    eg.perform(eg.background(), succeed, eg.sequential(succeed), eg.when(False, fail))
+
+    ops = events.of("op")
+    assert [(o.name, o.state) for o in ops] == [
+        ("test_graph.succeed", Op.Completed),
+        ("test_graph.succeed", Op.Completed),
+        ("test_graph.fail", Op.Skipped),
+    ]
+    # sdk operations are not traced, children are rooted at the traced operation.
+    assert all(list(o.path) == ["root"] for o in ops)
+
+
+def test_perform_records_failures(events):
+    with pytest.raises(ValueError):
+        eg.perform(eg.background(), succeed, fail, succeed)
+
+    assert [o.state for o in events.of("op")] == [Op.Completed, Op.Error]
+
+
+def test_parallel_failures(events):
+    with pytest.raises(ExceptionGroup):
+        eg.perform(eg.background(), eg.parallel(fail, fail, succeed))
+
+    assert sorted(o.state for o in events.of("op")) == [Op.Completed, Op.Error, Op.Error]
+
+
+def test_initiated_events(events, monkeypatch):
+    monkeypatch.setenv("EG_COMPUTE_EVENT_LOG", "true")
+    eg.perform(eg.background(), succeed)
+
+    assert [o.state for o in events.of("op")] == [Op.Initiated, Op.Completed]
+
+
+def test_operation(events):
+    ctx = eg.background()
+    with eg.operation(ctx, "example", module="example.py") as octx:
+        assert len(octx.path) == 1
+
+    with pytest.raises(TimeoutError):
+        with eg.operation(ctx, "timeout"):
+            raise TimeoutError()
+
+    ops = events.of("op")
+    assert [(o.name, o.module, o.state) for o in ops] == [
+        ("example", "example.py", Op.Completed),
+        ("timeout", "", Op.TimedOut),
+    ]
+    assert ops[0].op != ops[1].op
+
+
+def test_cancelled_operations(events, runtimedir):
+    cleanup = []
+
+    def cancelled(ctx: eg.Context, o: eg.Op):
+        open(f"{runtimedir}/cancelled", "w").close()
+        raise RuntimeError("interrupted")
+
+    eg.on_cancel(lambda ctx, o: cleanup.append(True))
+    with pytest.raises(eg.Cancelled):
+        eg.perform(eg.background(), cancelled)
+
+    assert cleanup == [True]
+    assert eg.cancelled()
+    assert Op.Cancelled in [o.state for o in events.of("op")]
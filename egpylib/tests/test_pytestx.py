@@ -0,0 +1,55 @@
+import os
+from egpy.eg.interp.events_pb2 import TestResult
+
+EGPYLIB = os.path.dirname(os.path.dirname(os.path.abspath(__file__)))
+
+
+def test_reports_results_and_coverage(pytester, events, monkeypatch):
+    monkeypatch.setenv("PYTHONPATH", EGPYLIB)
+    monkeypatch.setenv("EG_COMPUTE_WORKING_DIRECTORY", str(pytester.path))
+    pytester.makepyfile(
+        example="def add(a, b):\n    return a + b\n\n\ndef unused():\n    return None\n",
+        test_example="""
+import pytest
+import example
+
+
+def test_add():
+    assert example.add(1, 2) == 3
+
+
+def test_failure():
+    assert example.add(1, 2) == 4
+
+
+@pytest.mark.skip(reason="not yet")
+def test_skipped():
+    pass
+""",
+    )
+
+    result = pytester.runpytest_subprocess("-p", "no:egpy", "-p", "egpy.pytestx")
+    result.assert_outcomes(passed=1, failed=1, skipped=1)
+
+    tests = {t.name: t for t in events.of("test")}
+    assert {n: t.status for n, t in tests.items()} == {
+        "test_add": TestResult.Passed,
+        "test_failure": TestResult.Failed,
+        "test_skipped": TestResult.Skipped,
+    }
+    assert tests["test_add"].suite == "test_example.py"
+    assert tests["test_add"].framework == "pytest"
+    assert tests["test_skipped"].message == "not yet"
+    assert "assert 3 == 4" in tests["test_failure"].message
+
+    coverage = {c.path: c for c in events.of("coverage")}
+    assert "example.py" in coverage
+    assert {l.line: l.hits for l in coverage["example.py"].lines} == {1: 1, 2: 1, 5: 1, 6: 0}
+
+
+def test_inactive_outside_workloads(pytester, runtimedir, monkeypatch):
+    monkeypatch.setenv("PYTHONPATH", EGPYLIB)
+    pytester.makepyfile("def test_example():\n    pass\n")
+
+    result = pytester.runpytest_subprocess("-p", "no:egpy", "-p", "egpy.pytestx")
+    result.assert_outcomes(passed=1)
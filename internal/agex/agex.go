@@ -0,0 +1,80 @@
+// Package agex passphrase (scrypt) encryption using the age v1 file format,
+// see https://age-encryption.org/v1. files produced are readable by `age -d` and vice versa.
+package agex
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"filippo.io/age"
+	"github.com/egdaemon/eg/internal/errorsx"
+)
+
+const (
+	// scrypt work factor, matches the memory cost of the argon2 parameters used by chachasm.
+	workfactor = 16
+	// maximum work factor accepted when decrypting, prevents denial of service by crafted files.
+	maxworkfactor = 22
+)
+
+const (
+	ErrIncorrectPassphrase = errorsx.String("incorrect passphrase")
+	ErrMalformed           = errorsx.String("malformed age file")
+)
+
+// Encrypt the plaintext with the passphrase.
+func Encrypt(passphrase string, plaintext []byte) (_ []byte, err error) {
+	var (
+		buf bytes.Buffer
+	)
+
+	if passphrase == "" {
+		return nil, errorsx.New("passphrase required for encryption")
+	}
+
+	r, err := age.NewScryptRecipient(passphrase)
+	if err != nil {
+		return nil, errorsx.WithStack(err)
+	}
+	r.SetWorkFactor(workfactor)
+
+	w, err := age.Encrypt(&buf, r)
+	if err != nil {
+		return nil, errorsx.Wrap(err, "unable to encrypt")
+	}
+
+	if _, err = w.Write(plaintext); err != nil {
+		return nil, errorsx.Wrap(err, "unable to encrypt")
+	}
+
+	if err = w.Close(); err != nil {
+		return nil, errorsx.Wrap(err, "unable to encrypt")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Decrypt the ciphertext with the passphrase.
+func Decrypt(passphrase string, ciphertext []byte) (_ []byte, err error) {
+	id, err := age.NewScryptIdentity(passphrase)
+	if err != nil {
+		return nil, errorsx.WithStack(err)
+	}
+	id.SetMaxWorkFactor(maxworkfactor)
+
+	r, err := age.Decrypt(bytes.NewReader(ciphertext), id)
+	if errors.Is(err, age.ErrIncorrectIdentity) {
+		return nil, ErrIncorrectPassphrase
+	} else if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+
+	plaintext, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+
+	return plaintext, nil
+}
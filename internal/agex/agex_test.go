@@ -0,0 +1,60 @@
+package agex_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/egdaemon/eg/internal/agex"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	large := make([]byte, 3*64*1024+17)
+	_, err := rand.Read(large)
+	require.NoError(t, err)
+
+	for _, plaintext := range [][]byte{[]byte(""), []byte("hello world"), large, large[:2*64*1024]} {
+		encrypted, err := agex.Encrypt("passphrase", plaintext)
+		require.NoError(t, err)
+		require.True(t, bytes.HasPrefix(encrypted, []byte("age-encryption.org/v1\n-> scrypt ")))
+
+		decrypted, err := agex.Decrypt("passphrase", encrypted)
+		require.NoError(t, err)
+		require.Equal(t, len(plaintext), len(decrypted))
+		require.True(t, bytes.Equal(plaintext, decrypted))
+	}
+}
+
+func TestDecrypt(t *testing.T) {
+	encrypted, err := agex.Encrypt("passphrase", []byte("hello world"))
+	require.NoError(t, err)
+
+	t.Run("incorrect passphrase", func(t *testing.T) {
+		_, err := agex.Decrypt("incorrect", encrypted)
+		require.ErrorIs(t, err, agex.ErrIncorrectPassphrase)
+	})
+
+	t.Run("tampered payload", func(t *testing.T) {
+		tampered := bytes.Clone(encrypted)
+		tampered[len(tampered)-1] ^= 1
+		_, err := agex.Decrypt("passphrase", tampered)
+		require.ErrorIs(t, err, agex.ErrMalformed)
+	})
+
+	t.Run("tampered header", func(t *testing.T) {
+		tampered := bytes.Replace(encrypted, []byte(" 16\n"), []byte(" 15\n"), 1)
+		_, err := agex.Decrypt("passphrase", tampered)
+		require.Error(t, err)
+	})
+
+	t.Run("not an age file", func(t *testing.T) {
+		_, err := agex.Decrypt("passphrase", []byte("hello world"))
+		require.ErrorIs(t, err, agex.ErrMalformed)
+	})
+}
+
+func TestEncryptRequiresPassphrase(t *testing.T) {
+	_, err := agex.Encrypt("", []byte("hello world"))
+	require.Error(t, err)
+}
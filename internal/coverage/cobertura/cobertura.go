@@ -0,0 +1,150 @@
+// Package cobertura parses cobertura xml coverage reports, as produced by coverage.py (coverage xml),
+// istanbul, gcovr and the cobertura maven/gradle plugins.
+package cobertura
+
+import (
+	"cmp"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/egdaemon/eg/internal/coverage"
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/interp/events"
+)
+
+type class struct {
+	Filename string `xml:"filename,attr"`
+	Lines    []line `xml:"lines>line"`
+}
+
+type line struct {
+	Number    uint32 `xml:"number,attr"`
+	Hits      int64  `xml:"hits,attr"`
+	Branch    bool   `xml:"branch,attr"`
+	Condition string `xml:"condition-coverage,attr"` // i.e. 50% (1/2)
+}
+
+// conditions parses the branches hit and total from the condition coverage.
+func (t line) conditions() (hit, total uint32, err error) {
+	if !t.Branch || t.Condition == "" {
+		return 0, 0, nil
+	}
+
+	_, fraction, ok := strings.Cut(t.Condition, "(")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid condition coverage %s", t.Condition)
+	}
+
+	if _, err = fmt.Sscanf(fraction, "%d/%d)", &hit, &total); err != nil {
+		return 0, 0, errorsx.Wrapf(err, "invalid condition coverage %s", t.Condition)
+	}
+
+	return hit, total, nil
+}
+
+// Parse the cobertura report. classes are merged by source file, relative file names
+// are resolved against the first source of the report.
+func Parse(ctx context.Context, src io.Reader) iter.Seq2[*coverage.Report, error] {
+	return func(yield func(*coverage.Report, error) bool) {
+		var (
+			source string
+			files  = make(map[string]map[uint32]*events.CoverageLine)
+		)
+
+		decoder := xml.NewDecoder(src)
+		for {
+			tok, err := decoder.Token()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				yield(nil, errorsx.Wrap(err, "failed to read cobertura"))
+				return
+			}
+
+			el, ok := tok.(xml.StartElement)
+			if !ok {
+				continue
+			}
+
+			switch el.Name.Local {
+			case "source":
+				var s string
+				if err = decoder.DecodeElement(&s, &el); err != nil {
+					yield(nil, errorsx.Wrap(err, "invalid source"))
+					return
+				}
+
+				if source == "" {
+					source = strings.TrimSpace(s)
+				}
+			case "class":
+				var c class
+				if err = decoder.DecodeElement(&c, &el); err != nil {
+					yield(nil, errorsx.Wrap(err, "invalid class"))
+					return
+				}
+
+				lines, ok := files[c.Filename]
+				if !ok {
+					lines = make(map[uint32]*events.CoverageLine)
+					files[c.Filename] = lines
+				}
+
+				for _, l := range c.Lines {
+					hit, total, err := l.conditions()
+					if err != nil {
+						yield(nil, err)
+						return
+					}
+
+					cl, ok := lines[l.Number]
+					if !ok {
+						cl = &events.CoverageLine{Line: l.Number}
+						lines[l.Number] = cl
+					}
+
+					cl.Hits = max(cl.Hits, uint64(max(l.Hits, 0)))
+					cl.Branches = max(cl.Branches, total)
+					cl.BranchesHit = max(cl.BranchesHit, hit)
+				}
+			}
+
+			if err = ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(files)) {
+			lines := slices.SortedFunc(maps.Values(files[name]), func(a, b *events.CoverageLine) int { return cmp.Compare(a.Line, b.Line) })
+			path := name
+			if source != "" && !filepath.IsAbs(name) {
+				path = filepath.Join(source, name)
+			}
+
+			ok := yield(&coverage.Report{
+				Path:       path,
+				Statements: coverage.Lines(lines...),
+				Branches:   coverage.Branches(lines...),
+				Lines:      lines,
+			}, nil)
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// Coverage from the cobertura reports within the directory, i.e. coverage.xml and cobertura*.xml files.
+func Coverage(ctx context.Context, dir string) iter.Seq2[*coverage.Report, error] {
+	return coverage.Walk(ctx, dir, func(name string) bool {
+		return name == "coverage.xml" || (strings.HasPrefix(name, "cobertura") && strings.HasSuffix(name, ".xml"))
+	}, Parse)
+}
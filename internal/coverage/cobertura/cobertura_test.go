@@ -0,0 +1,76 @@
+package cobertura_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/egdaemon/eg/internal/coverage"
+	"github.com/egdaemon/eg/internal/coverage/cobertura"
+	"github.com/egdaemon/eg/internal/testx"
+	"github.com/egdaemon/eg/interp/events"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	ctx, done := testx.Context(t)
+	defer done()
+
+	var (
+		reports []*coverage.Report
+	)
+
+	for rep, err := range cobertura.Parse(ctx, testx.Read(".fixtures", "coverage.py.xml")) {
+		require.NoError(t, err)
+		reports = append(reports, rep)
+	}
+
+	require.Len(t, reports, 2)
+	require.Equal(t, "/workload/src/app/__init__.py", reports[0].Path)
+	require.Equal(t, float32(100), reports[0].Statements)
+
+	calc := reports[1]
+	require.Equal(t, "/workload/src/app/calc.py", calc.Path)
+	require.Equal(t, float32(62.5), calc.Statements)
+	require.Equal(t, float32(50), calc.Branches)
+	require.Len(t, calc.Lines, 8)
+	require.Equal(t, &events.CoverageLine{Line: 5, Hits: 3, Branches: 2, BranchesHit: 1}, calc.Lines[2])
+	require.Equal(t, uint64(0), calc.Lines[4].Hits)
+}
+
+func TestParseMergesClasses(t *testing.T) {
+	ctx, done := testx.Context(t)
+	defer done()
+
+	const report = `<coverage><packages><package name="com.example"><classes>
+<class name="com.example.Foo" filename="com/example/Foo.java"><lines><line number="3" hits="0"/></lines></class>
+<class name="com.example.Foo$Inner" filename="com/example/Foo.java"><lines><line number="3" hits="2"/><line number="9" hits="1"/></lines></class>
+</classes></package></packages></coverage>`
+
+	var (
+		reports []*coverage.Report
+	)
+
+	for rep, err := range cobertura.Parse(ctx, strings.NewReader(report)) {
+		require.NoError(t, err)
+		reports = append(reports, rep)
+	}
+
+	require.Len(t, reports, 1)
+	require.Equal(t, "com/example/Foo.java", reports[0].Path)
+	require.Equal(t, []*events.CoverageLine{{Line: 3, Hits: 2}, {Line: 9, Hits: 1}}, reports[0].Lines)
+}
+
+func TestParseInvalid(t *testing.T) {
+	ctx, done := testx.Context(t)
+	defer done()
+
+	var (
+		failed error
+	)
+
+	for _, err := range cobertura.Parse(ctx, strings.NewReader(`<coverage><class filename="a.py"><lines><line number="1" hits="1" branch="true" condition-coverage="50%"/></lines></class></coverage>`)) {
+		failed = err
+	}
+
+	require.ErrorContains(t, failed, "invalid condition coverage")
+}
@@ -1,7 +1,93 @@
 package coverage
 
 import (
+	"context"
+	"io"
+	"io/fs"
+	"iter"
+	"os"
+	"path/filepath"
+
+	"github.com/egdaemon/eg/internal/errorsx"
 	"github.com/egdaemon/eg/interp/events"
 )
 
 type Report = events.Coverage
+
+// Parser of an individual coverage file.
+type Parser func(ctx context.Context, src io.Reader) iter.Seq2[*Report, error]
+
+// Walk the directory parsing the files matching the predicate.
+func Walk(ctx context.Context, dir string, match func(name string) bool, parse Parser) iter.Seq2[*Report, error] {
+	return func(yield func(*Report, error) bool) {
+		var (
+			stopped bool
+		)
+
+		err := fs.WalkDir(os.DirFS(dir), ".", func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return errorsx.Wrapf(err, "failed: %s", filepath.Join(dir, path))
+			}
+
+			if d.IsDir() || !match(d.Name()) {
+				return nil
+			}
+
+			src, err := os.Open(filepath.Join(dir, path))
+			if err != nil {
+				return errorsx.Wrapf(err, "unable to open coverage file: %s", path)
+			}
+			defer src.Close()
+
+			for rep, err := range parse(ctx, src) {
+				if !yield(rep, errorsx.Wrapf(err, "invalid coverage file: %s", path)) {
+					stopped = true
+					return fs.SkipAll
+				}
+			}
+
+			return nil
+		})
+
+		if err != nil && !stopped {
+			yield(nil, err)
+		}
+	}
+}
+
+// Lines is the percentage of the executable lines hit.
+func Lines(lines ...*events.CoverageLine) float32 {
+	var (
+		hit int
+	)
+
+	if len(lines) == 0 {
+		return 0
+	}
+
+	for _, l := range lines {
+		if l.Hits > 0 {
+			hit++
+		}
+	}
+
+	return (float32(hit) / float32(len(lines))) * 100.0
+}
+
+// Branches is the percentage of the branches hit.
+func Branches(lines ...*events.CoverageLine) float32 {
+	var (
+		total, hit uint32
+	)
+
+	for _, l := range lines {
+		total += l.Branches
+		hit += l.BranchesHit
+	}
+
+	if total == 0 {
+		return 0
+	}
+
+	return (float32(hit) / float32(total)) * 100.0
+}
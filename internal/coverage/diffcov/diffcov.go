@@ -0,0 +1,253 @@
+// Package diffcov intersects line level coverage with the lines changed between two commits.
+package diffcov
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/egdaemon/eg/internal/coverage"
+	"github.com/egdaemon/eg/internal/errorsx"
+)
+
+// MetricName of the custom metric recording the diff coverage of a run.
+const MetricName = "eg.coverage.diff"
+
+// Changes lines added or modified within each path of the head commit, paths are relative to the repository.
+type Changes map[string][]uint32
+
+// ParseUnified parses the output of `git diff --unified=0`.
+func ParseUnified(r io.Reader) (changes Changes, err error) {
+	var (
+		current string
+	)
+
+	changes = make(Changes)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, "+++ ") {
+			current = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+			// deleted files have no lines in the head commit.
+			if current == "/dev/null" {
+				current = ""
+			}
+			continue
+		}
+
+		if !strings.HasPrefix(line, "@@ ") || current == "" {
+			continue
+		}
+
+		start, count, err := hunk(line)
+		if err != nil {
+			return nil, errorsx.Wrapf(err, "invalid hunk %s", line)
+		}
+
+		for n := start; n < start+count; n++ {
+			changes[current] = append(changes[current], n)
+		}
+	}
+
+	return changes, errorsx.Wrap(scanner.Err(), "unable to read diff")
+}
+
+// hunk parses the head range of a hunk header: @@ -l[,s] +l[,s] @@
+func hunk(header string) (start, count uint32, err error) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0, fmt.Errorf("missing head range")
+	}
+
+	lstr, cstr, ok := strings.Cut(strings.TrimPrefix(fields[2], "+"), ",")
+	l, err := strconv.ParseUint(lstr, 10, 32)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if !ok {
+		return uint32(l), 1, nil
+	}
+
+	c, err := strconv.ParseUint(cstr, 10, 32)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return uint32(l), uint32(c), nil
+}
+
+// File diff coverage of an individual path.
+type File struct {
+	Path      string   `json:"path"`
+	Changed   int      `json:"changed"` // changed lines containing executable code.
+	Covered   int      `json:"covered"`
+	Uncovered []uint32 `json:"uncovered,omitempty"`
+}
+
+// Report diff coverage of the changes.
+type Report struct {
+	Changed int    `json:"changed"`
+	Covered int    `json:"covered"`
+	Files   []File `json:"files,omitempty"`
+}
+
+// Percentage of the changed executable lines covered, changes without executable lines are fully covered.
+func (t Report) Percentage() float32 {
+	if t.Changed == 0 {
+		return 100.0
+	}
+
+	return (float32(t.Covered) / float32(t.Changed)) * 100.0
+}
+
+// Compute intersects the changes with the line level coverage reports. changed lines absent from
+// the reports aren't executable and are ignored. coverage of a path reported multiple times
+// (i.e. by separate test binaries) is merged.
+func Compute(ctx context.Context, changes Changes, reports iter.Seq2[*coverage.Report, error]) (r Report, err error) {
+	hits, err := collect(ctx, reports, func(reported string) (string, bool) {
+		return resolve(changes, reported)
+	})
+	if err != nil {
+		return r, err
+	}
+
+	return summarize(changes, hits), nil
+}
+
+// Everything treats every executable line of the reports as changed. used when the changes
+// between the commits are unknown so the coverage gate fails closed.
+func Everything(ctx context.Context, reports iter.Seq2[*coverage.Report, error]) (r Report, err error) {
+	hits, err := collect(ctx, reports, func(reported string) (string, bool) {
+		return path.Clean(strings.TrimPrefix(reported, "file://")), true
+	})
+	if err != nil {
+		return r, err
+	}
+
+	changes := make(Changes, len(hits))
+	for p, lines := range hits {
+		changes[p] = slices.Sorted(maps.Keys(lines))
+	}
+
+	return summarize(changes, hits), nil
+}
+
+// collect the hits of each line within the reports by the path selected for the reported path.
+func collect(ctx context.Context, reports iter.Seq2[*coverage.Report, error], selected func(reported string) (string, bool)) (map[string]map[uint32]uint64, error) {
+	hits := make(map[string]map[uint32]uint64)
+
+	for rep, err := range reports {
+		if err != nil {
+			return nil, err
+		}
+
+		changed, ok := selected(rep.Path)
+		if !ok {
+			continue
+		}
+
+		lines, ok := hits[changed]
+		if !ok {
+			lines = make(map[uint32]uint64, len(rep.Lines))
+			hits[changed] = lines
+		}
+
+		for _, l := range rep.Lines {
+			lines[l.Line] = max(lines[l.Line], l.Hits)
+		}
+
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return hits, nil
+}
+
+func summarize(changes Changes, hits map[string]map[uint32]uint64) (r Report) {
+	for p, changed := range changes {
+		lines, ok := hits[p]
+		if !ok {
+			continue
+		}
+
+		f := File{Path: p}
+		for _, n := range changed {
+			h, executable := lines[n]
+			if !executable {
+				continue
+			}
+
+			f.Changed++
+			if h > 0 {
+				f.Covered++
+			} else {
+				f.Uncovered = append(f.Uncovered, n)
+			}
+		}
+
+		if f.Changed == 0 {
+			continue
+		}
+
+		r.Changed += f.Changed
+		r.Covered += f.Covered
+		r.Files = append(r.Files, f)
+	}
+
+	slices.SortFunc(r.Files, func(a, b File) int { return strings.Compare(a.Path, b.Path) })
+	return r
+}
+
+// resolve the changed path of the coverage report. coverage tools report paths relative
+// to different roots (go reports import paths, lcov typically absolute paths) so
+// the longest changed path the reported path ends with is selected.
+func resolve(changes Changes, reported string) (resolved string, ok bool) {
+	reported = path.Clean(strings.TrimPrefix(reported, "file://"))
+	for p := range changes {
+		if reported != p && !strings.HasSuffix(reported, "/"+p) {
+			continue
+		}
+
+		if len(p) > len(resolved) {
+			resolved, ok = p, true
+		}
+	}
+
+	return resolved, ok
+}
+
+// Ranges compacts the lines into ranges, i.e. 1-3,7,9-10
+func Ranges(lines ...uint32) string {
+	var (
+		ranges []string
+	)
+
+	for i := 0; i < len(lines); {
+		j := i
+		for j+1 < len(lines) && lines[j+1] == lines[j]+1 {
+			j++
+		}
+
+		if i == j {
+			ranges = append(ranges, strconv.FormatUint(uint64(lines[i]), 10))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", lines[i], lines[j]))
+		}
+
+		i = j + 1
+	}
+
+	return strings.Join(ranges, ",")
+}
@@ -0,0 +1,134 @@
+package diffcov_test
+
+import (
+	"iter"
+	"strings"
+	"testing"
+
+	"github.com/egdaemon/eg/internal/coverage"
+	"github.com/egdaemon/eg/internal/coverage/diffcov"
+	"github.com/egdaemon/eg/internal/testx"
+	"github.com/egdaemon/eg/interp/events"
+	"github.com/stretchr/testify/require"
+)
+
+const diff = `diff --git a/pkg/a.go b/pkg/a.go
+index 3b18e51..a9a5f2c 100644
+--- a/pkg/a.go
++++ b/pkg/a.go
+@@ -3,0 +4,3 @@ func a() {
++	x := 1
++	y := 2
++	return x + y
+@@ -10 +13 @@ func b() {
+-	return 0
++	return 1
+@@ -20,2 +23,0 @@ func c() {
+-	removed()
+-	removed()
+diff --git a/removed.go b/removed.go
+deleted file mode 100644
+--- a/removed.go
++++ /dev/null
+@@ -1,2 +0,0 @@
+-package main
+-
+diff --git a/README.md b/README.md
+--- a/README.md
++++ b/README.md
+@@ -1 +1,2 @@
+-# eg
++# eg
++documentation
+`
+
+func reports(reps ...*coverage.Report) iter.Seq2[*coverage.Report, error] {
+	return func(yield func(*coverage.Report, error) bool) {
+		for _, rep := range reps {
+			if !yield(rep, nil) {
+				return
+			}
+		}
+	}
+}
+
+func lines(hits map[uint32]uint64) (l []*events.CoverageLine) {
+	for n, h := range hits {
+		l = append(l, &events.CoverageLine{Line: n, Hits: h})
+	}
+	return l
+}
+
+func TestParseUnified(t *testing.T) {
+	changes, err := diffcov.ParseUnified(strings.NewReader(diff))
+	require.NoError(t, err)
+	require.Equal(t, diffcov.Changes{
+		"pkg/a.go":  {4, 5, 6, 13},
+		"README.md": {1, 2},
+	}, changes)
+}
+
+func TestCompute(t *testing.T) {
+	ctx, done := testx.Context(t)
+	defer done()
+
+	changes, err := diffcov.ParseUnified(strings.NewReader(diff))
+	require.NoError(t, err)
+
+	t.Run("import paths and merged reports", func(t *testing.T) {
+		r, err := diffcov.Compute(ctx, changes, reports(
+			&coverage.Report{Path: "github.com/example/repo/pkg/a.go", Lines: lines(map[uint32]uint64{4: 1, 5: 0, 6: 0, 13: 0, 30: 0})},
+			&coverage.Report{Path: "github.com/example/repo/pkg/a.go", Lines: lines(map[uint32]uint64{6: 3})},
+			&coverage.Report{Path: "github.com/example/repo/pkg/b.go", Lines: lines(map[uint32]uint64{4: 0})},
+		))
+		require.NoError(t, err)
+		require.Equal(t, diffcov.Report{
+			Changed: 4,
+			Covered: 2,
+			Files:   []diffcov.File{{Path: "pkg/a.go", Changed: 4, Covered: 2, Uncovered: []uint32{5, 13}}},
+		}, r)
+		require.Equal(t, float32(50), r.Percentage())
+	})
+
+	t.Run("absolute paths", func(t *testing.T) {
+		r, err := diffcov.Compute(ctx, changes, reports(
+			&coverage.Report{Path: "/workload/pkg/a.go", Lines: lines(map[uint32]uint64{4: 1, 5: 1})},
+		))
+		require.NoError(t, err)
+		require.Equal(t, float32(100), r.Percentage())
+		require.Equal(t, 2, r.Changed)
+	})
+
+	t.Run("no executable changes", func(t *testing.T) {
+		r, err := diffcov.Compute(ctx, changes, reports())
+		require.NoError(t, err)
+		require.Equal(t, float32(100), r.Percentage())
+		require.Empty(t, r.Files)
+	})
+}
+
+func TestEverything(t *testing.T) {
+	ctx, done := testx.Context(t)
+	defer done()
+
+	r, err := diffcov.Everything(ctx, reports(
+		&coverage.Report{Path: "pkg/a.go", Lines: lines(map[uint32]uint64{4: 1, 5: 0})},
+		&coverage.Report{Path: "pkg/a.go", Lines: lines(map[uint32]uint64{5: 2, 9: 0})},
+		&coverage.Report{Path: "pkg/b.go", Lines: lines(map[uint32]uint64{1: 1})},
+	))
+	require.NoError(t, err)
+	require.Equal(t, diffcov.Report{
+		Changed: 4,
+		Covered: 3,
+		Files: []diffcov.File{
+			{Path: "pkg/a.go", Changed: 3, Covered: 2, Uncovered: []uint32{9}},
+			{Path: "pkg/b.go", Changed: 1, Covered: 1},
+		},
+	}, r)
+}
+
+func TestRanges(t *testing.T) {
+	require.Equal(t, "", diffcov.Ranges())
+	require.Equal(t, "7", diffcov.Ranges(7))
+	require.Equal(t, "1-3,7,9-10", diffcov.Ranges(1, 2, 3, 7, 9, 10))
+}
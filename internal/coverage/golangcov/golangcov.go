@@ -4,15 +4,18 @@ package golangcov
 // https://cs.opensource.google/go/go/+/refs/tags/go1.23.5:src/cmd/cover/func.go
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"io/fs"
 	"iter"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/egdaemon/eg/internal/coverage"
 	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/interp/events"
 	"golang.org/x/tools/cover"
 )
 
@@ -36,6 +39,7 @@ func Coverage(ctx context.Context, dir string) iter.Seq2[*coverage.Report, error
 				ok := yield(&coverage.Report{
 					Path:       profile.FileName,
 					Statements: percentCovered(profile),
+					Lines:      Lines(profile),
 				}, nil)
 				if !ok {
 					return fmt.Errorf("yield failed")
@@ -73,3 +77,41 @@ func percentCovered(p *cover.Profile) float32 {
 	}
 	return float32(float64(covered) / float64(total) * 100)
 }
+
+// Lines expands the blocks of the profile into line level coverage. go profiles
+// have no notion of branches, lines spanned by multiple blocks (i.e. `if err != nil {`)
+// report each block as a branch, hits is the maximum count of the blocks.
+func Lines(p *cover.Profile) []*events.CoverageLine {
+	lines := make(map[uint32]*events.CoverageLine)
+	for _, b := range p.Blocks {
+		if b.NumStmt == 0 {
+			continue
+		}
+
+		for n := uint32(b.StartLine); n <= uint32(b.EndLine); n++ {
+			l, ok := lines[n]
+			if !ok {
+				l = &events.CoverageLine{Line: n}
+				lines[n] = l
+			}
+
+			l.Hits = max(l.Hits, uint64(b.Count))
+			l.Branches++
+			if b.Count > 0 {
+				l.BranchesHit++
+			}
+		}
+	}
+
+	sorted := make([]*events.CoverageLine, 0, len(lines))
+	for _, l := range lines {
+		// a single block isn't a branch.
+		if l.Branches == 1 {
+			l.Branches, l.BranchesHit = 0, 0
+		}
+		sorted = append(sorted, l)
+	}
+
+	slices.SortFunc(sorted, func(a, b *events.CoverageLine) int { return cmp.Compare(a.Line, b.Line) })
+	return sorted
+}
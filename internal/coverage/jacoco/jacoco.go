@@ -0,0 +1,145 @@
+// Package jacoco parses jacoco xml coverage reports.
+package jacoco
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"iter"
+	"path"
+	"strings"
+
+	"github.com/egdaemon/eg/internal/coverage"
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/interp/events"
+)
+
+type sourcefile struct {
+	Name     string    `xml:"name,attr"`
+	Lines    []line    `xml:"line"`
+	Counters []counter `xml:"counter"`
+}
+
+// line jacoco records instructions and branches per line, not execution counts.
+type line struct {
+	Number              uint32 `xml:"nr,attr"`
+	MissedInstructions  uint32 `xml:"mi,attr"`
+	CoveredInstructions uint32 `xml:"ci,attr"`
+	MissedBranches      uint32 `xml:"mb,attr"`
+	CoveredBranches     uint32 `xml:"cb,attr"`
+}
+
+type counter struct {
+	Type    string `xml:"type,attr"`
+	Missed  uint32 `xml:"missed,attr"`
+	Covered uint32 `xml:"covered,attr"`
+}
+
+func (t counter) coverage() float32 {
+	if total := t.Missed + t.Covered; total > 0 {
+		return (float32(t.Covered) / float32(total)) * 100.0
+	}
+
+	return 0
+}
+
+// Parse the jacoco report. paths are the package directory joined with the source file name,
+// i.e. com/example/Foo.java. jacoco doesn't record execution counts, lines with covered
+// instructions are reported with a single hit.
+func Parse(ctx context.Context, src io.Reader) iter.Seq2[*coverage.Report, error] {
+	return func(yield func(*coverage.Report, error) bool) {
+		var (
+			pkg string
+		)
+
+		decoder := xml.NewDecoder(src)
+
+		for {
+			tok, err := decoder.Token()
+			if err == io.EOF {
+				return
+			} else if err != nil {
+				yield(nil, errorsx.Wrap(err, "failed to read jacoco"))
+				return
+			}
+
+			el, ok := tok.(xml.StartElement)
+			if !ok {
+				continue
+			}
+
+			switch el.Name.Local {
+			case "package":
+				pkg = attr(el, "name")
+			case "sourcefile":
+				var sf sourcefile
+				if err = decoder.DecodeElement(&sf, &el); err != nil {
+					yield(nil, errorsx.Wrap(err, "invalid sourcefile"))
+					return
+				}
+
+				if !yield(report(path.Join(pkg, sf.Name), sf), nil) {
+					return
+				}
+			}
+
+			if err = ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+		}
+	}
+}
+
+func report(p string, sf sourcefile) *coverage.Report {
+	lines := make([]*events.CoverageLine, 0, len(sf.Lines))
+	for _, l := range sf.Lines {
+		cl := &events.CoverageLine{
+			Line:        l.Number,
+			Branches:    l.MissedBranches + l.CoveredBranches,
+			BranchesHit: l.CoveredBranches,
+		}
+
+		if l.CoveredInstructions > 0 {
+			cl.Hits = 1
+		}
+
+		lines = append(lines, cl)
+	}
+
+	rep := &coverage.Report{
+		Path:       p,
+		Statements: coverage.Lines(lines...),
+		Branches:   coverage.Branches(lines...),
+		Lines:      lines,
+	}
+
+	// prefer the summary counters when present.
+	for _, c := range sf.Counters {
+		switch c.Type {
+		case "LINE":
+			rep.Statements = c.coverage()
+		case "BRANCH":
+			rep.Branches = c.coverage()
+		}
+	}
+
+	return rep
+}
+
+func attr(el xml.StartElement, name string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+
+	return ""
+}
+
+// Coverage from the jacoco reports within the directory, i.e. jacoco.xml and jacocoTestReport.xml files.
+func Coverage(ctx context.Context, dir string) iter.Seq2[*coverage.Report, error] {
+	return coverage.Walk(ctx, dir, func(name string) bool {
+		return strings.HasPrefix(name, "jacoco") && strings.HasSuffix(name, ".xml")
+	}, Parse)
+}
@@ -0,0 +1,39 @@
+package jacoco_test
+
+import (
+	"testing"
+
+	"github.com/egdaemon/eg/internal/coverage"
+	"github.com/egdaemon/eg/internal/coverage/jacoco"
+	"github.com/egdaemon/eg/internal/testx"
+	"github.com/egdaemon/eg/interp/events"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	ctx, done := testx.Context(t)
+	defer done()
+
+	var (
+		reports []*coverage.Report
+	)
+
+	for rep, err := range jacoco.Parse(ctx, testx.Read(".fixtures", "jacoco.xml")) {
+		require.NoError(t, err)
+		reports = append(reports, rep)
+	}
+
+	require.Len(t, reports, 2)
+	require.Equal(t, "com/example/Calc.java", reports[0].Path)
+	require.Equal(t, float32(75), reports[0].Statements)
+	require.Equal(t, float32(50), reports[0].Branches)
+	require.Equal(t, []*events.CoverageLine{
+		{Line: 3, Hits: 1},
+		{Line: 5, Hits: 1, Branches: 2, BranchesHit: 1},
+		{Line: 6},
+		{Line: 8, Hits: 1},
+	}, reports[0].Lines)
+
+	require.Equal(t, "com/example/util/Strings.java", reports[1].Path)
+	require.Equal(t, float32(0), reports[1].Statements)
+}
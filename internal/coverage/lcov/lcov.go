@@ -2,12 +2,16 @@ package lcov
 
 import (
 	"bufio"
+	"cmp"
 	"context"
+	"fmt"
 	"io"
 	"io/fs"
 	"iter"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -22,6 +26,8 @@ const (
 	prefixExecutableLinesHit = "LH:"
 	prefixBranches           = "BRF:"
 	prefixBranchesHit        = "BRH:"
+	prefixLine               = "DA:"
+	prefixBranch             = "BRDA:"
 	prefixEnd                = "end_of_record"
 )
 
@@ -44,6 +50,7 @@ func Parse(ctx context.Context, src io.Reader) iter.Seq2[*coverage.Report, error
 			path        string
 			linesHit    HitCount
 			branceshHit HitCount
+			lines       = newlines()
 		)
 
 		scanner := bufio.NewScanner(src)
@@ -96,12 +103,30 @@ func Parse(ctx context.Context, src io.Reader) iter.Seq2[*coverage.Report, error
 				}
 			}
 
+			if strings.HasPrefix(line, prefixLine) {
+				if err := lines.hit(strings.TrimPrefix(line, prefixLine)); err != nil {
+					yield(nil, errorsx.Wrapf(err, "invalid line %s", line))
+					return
+				}
+				continue
+			}
+
+			if strings.HasPrefix(line, prefixBranch) {
+				if err := lines.branch(strings.TrimPrefix(line, prefixBranch)); err != nil {
+					yield(nil, errorsx.Wrapf(err, "invalid line %s", line))
+					return
+				}
+				continue
+			}
+
 			if strings.HasPrefix(line, prefixEnd) {
 				ok := yield(&events.Coverage{
 					Path:       path,
 					Statements: linesHit.Coverage(),
 					Branches:   branceshHit.Coverage(),
+					Lines:      lines.sorted(),
 				}, nil)
+				lines = newlines()
 				if !ok {
 					return
 				}
@@ -125,6 +150,76 @@ func Parse(ctx context.Context, src io.Reader) iter.Seq2[*coverage.Report, error
 	}
 }
 
+// lines accumulates the DA and BRDA records of a source file.
+type lines map[uint32]*events.CoverageLine
+
+func newlines() lines {
+	return make(lines)
+}
+
+func (t lines) get(n uint32) *events.CoverageLine {
+	l, ok := t[n]
+	if !ok {
+		l = &events.CoverageLine{Line: n}
+		t[n] = l
+	}
+
+	return l
+}
+
+// hit parses DA:<line>,<hits>[,<checksum>]
+func (t lines) hit(record string) error {
+	fields := strings.Split(strings.TrimSpace(record), ",")
+	if len(fields) < 2 {
+		return fmt.Errorf("expected line and hits")
+	}
+
+	n, err := strconv.ParseUint(fields[0], 10, 32)
+	if err != nil {
+		return err
+	}
+
+	// some tools report negative hit counts on overflow.
+	hits, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return err
+	}
+
+	l := t.get(uint32(n))
+	l.Hits += uint64(max(hits, 0))
+	return nil
+}
+
+// branch parses BRDA:<line>,<block>,<branch>,<taken> where taken is '-' when the branch never executed.
+func (t lines) branch(record string) error {
+	fields := strings.Split(strings.TrimSpace(record), ",")
+	if len(fields) < 4 {
+		return fmt.Errorf("expected line, block, branch and taken")
+	}
+
+	n, err := strconv.ParseUint(fields[0], 10, 32)
+	if err != nil {
+		return err
+	}
+
+	l := t.get(uint32(n))
+	l.Branches++
+
+	if taken := fields[len(fields)-1]; taken != "-" && taken != "0" {
+		l.BranchesHit++
+	}
+
+	return nil
+}
+
+func (t lines) sorted() []*events.CoverageLine {
+	if len(t) == 0 {
+		return nil
+	}
+
+	return slices.SortedFunc(maps.Values(t), func(a, b *events.CoverageLine) int { return cmp.Compare(a.Line, b.Line) })
+}
+
 func Coverage(ctx context.Context, dir string) iter.Seq2[*coverage.Report, error] {
 	return func(yield func(*coverage.Report, error) bool) {
 		err := fs.WalkDir(os.DirFS(dir), ".", func(path string, d fs.DirEntry, err error) error {
@@ -1,6 +1,7 @@
 package lcov_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/egdaemon/eg/internal/coverage"
@@ -32,3 +33,47 @@ func TestStatementCoverage(t *testing.T) {
 		0, 100, 0, 0, 0, 100, 100, 0, 0, 100, 0, 0, 100, 100, 50, 100, 0, 0, 100, 100, 100, 0, 100, 100, 100, 0, 0, 100, 100, 0, 100, 100, 100, 0, 100, 100, 0, 0, 0, 0, 80, 80, 0, 0, 100, 100, 100, 0, 0, 100, 100, 100, 0, 0, 100, 0, 100, 0, 0, 0, 0, 0, 0, 100, 71.42857, 0, 0, 66.66667, 0, 100, 100, 100, 0, 100, 100, 100, 0, 100, 0, 100, 100, 0, 100, 0, 100, 100, 0, 0, 100, 0, 66.66667, 0, 0, 100, 66.66667, 0, 100, 100, 100, 100, 0, 100, 100, 100, 100, 100, 100, 0, 100, 100, 100, 0, 100, 0, 0, 0, 100, 100, 0, 100, 0, 0, 100, 62.5, 100, 0, 100, 100, 25, 0, 100, 0, 40, 0, 0, 100, 100, 0, 100, 100, 25, 100, 0, 100, 0, 100, 0, 25, 0, 100, 0, 0, 0, 0, 100, 100, 0, 0, 100, 100, 0, 0, 100, 0, 0, 0, 0, 50, 0, 0, 0, 0, 0, 0, 0, 0, 0, 100, 0, 0, 0, 100, 100, 100, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 100, 83.33333, 100, 0, 0, 0, 60.000004, 0, 100, 0, 0, 100, 0, 100, 100, 0, 100, 0, 100, 100, 0, 100, 100, 0, 0, 100, 100, 0, 100, 0, 0, 100, 0, 0, 100, 100, 100, 100, 100, 100, 100, 100, 0, 100, 0, 0, 0, 0, 0, 0, 100, 0, 0, 0, 0, 0, 0, 0, 100, 100, 100, 100, 100, 100, 0, 0, 100, 100, 0, 0, 0, 0, 100, 100, 100, 100, 100, 100, 0, 0, 100, 0, 100, 100, 100, 100, 100, 100, 0, 100, 100, 100, 100, 0, 100, 0, 0, 44.444447, 100, 100, 0, 100, 100, 0, 0, 100,
 	}, slicesx.MapTransform(func(c *coverage.Report) float32 { return c.Branches }, reports...))
 }
+
+func TestLineCoverage(t *testing.T) {
+	ctx, done := testx.Context(t)
+	defer done()
+
+	const info = `SF:/workload/src/a.js
+DA:1,1
+DA:2,0
+DA:3,4,abc123
+BRDA:3,0,0,2
+BRDA:3,0,1,-
+BRDA:3,0,2,0
+LF:3
+LH:2
+end_of_record
+SF:/workload/src/b.js
+DA:7,0
+LF:1
+LH:0
+end_of_record
+`
+
+	var (
+		reports []*coverage.Report
+	)
+
+	for rep, err := range lcov.Parse(ctx, strings.NewReader(info)) {
+		require.NoError(t, err)
+		reports = append(reports, rep)
+	}
+
+	require.Len(t, reports, 2)
+	require.Equal(t, "/workload/src/a.js", reports[0].Path)
+	require.Len(t, reports[0].Lines, 3)
+	require.Equal(t, uint32(1), reports[0].Lines[0].Line)
+	require.Equal(t, uint64(1), reports[0].Lines[0].Hits)
+	require.Equal(t, uint64(0), reports[0].Lines[1].Hits)
+	require.Equal(t, uint64(4), reports[0].Lines[2].Hits)
+	require.Equal(t, uint32(3), reports[0].Lines[2].Branches)
+	require.Equal(t, uint32(1), reports[0].Lines[2].BranchesHit)
+
+	require.Len(t, reports[1].Lines, 1, "lines are reset between records")
+	require.Equal(t, uint32(7), reports[1].Lines[0].Line)
+}
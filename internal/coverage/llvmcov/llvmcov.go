@@ -0,0 +1,245 @@
+// Package llvmcov parses llvm-cov json exports, as produced by `llvm-cov export` and `cargo llvm-cov --json`.
+package llvmcov
+
+// useful reference code.
+// https://github.com/llvm/llvm-project/blob/main/llvm/lib/ProfileData/Coverage/CoverageMapping.cpp (LineCoverageStats)
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/egdaemon/eg/internal/coverage"
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/interp/events"
+)
+
+// ExportType of llvm-cov json exports.
+const ExportType = "llvm.coverage.json.export"
+
+type export struct {
+	Type string `json:"type"`
+	Data []struct {
+		Files []file `json:"files"`
+	} `json:"data"`
+}
+
+type file struct {
+	Filename string            `json:"filename"`
+	Segments []segment         `json:"segments"`
+	Branches [][]json.Number   `json:"branches"`
+	Summary  map[string]counts `json:"summary"`
+}
+
+type counts struct {
+	Count   uint64  `json:"count"`
+	Covered uint64  `json:"covered"`
+	Percent float32 `json:"percent"`
+}
+
+// segment [line, column, count, hasCount, isRegionEntry, isGapRegion], older exports omit isGapRegion.
+type segment struct {
+	Line     uint32
+	Count    uint64
+	HasCount bool
+	Entry    bool
+	Gap      bool
+}
+
+func (t *segment) UnmarshalJSON(b []byte) (err error) {
+	var (
+		fields []json.RawMessage
+		column uint32
+	)
+
+	if err = json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+
+	if len(fields) < 5 {
+		return fmt.Errorf("invalid segment %s", b)
+	}
+
+	dst := []any{&t.Line, &column, &t.Count, &t.HasCount, &t.Entry, &t.Gap}
+	for i := range min(len(fields), len(dst)) {
+		if err = json.Unmarshal(fields[i], dst[i]); err != nil {
+			return errorsx.Wrapf(err, "invalid segment %s", b)
+		}
+	}
+
+	return nil
+}
+
+// start of a region with an execution count.
+func (t segment) start() bool {
+	return !t.Gap && t.HasCount && t.Entry
+}
+
+// Parse the llvm-cov json export.
+func Parse(ctx context.Context, src io.Reader) iter.Seq2[*coverage.Report, error] {
+	return func(yield func(*coverage.Report, error) bool) {
+		var (
+			exp export
+		)
+
+		decoder := json.NewDecoder(src)
+		decoder.UseNumber()
+		if err := decoder.Decode(&exp); err != nil {
+			yield(nil, errorsx.Wrap(err, "failed to read llvm-cov export"))
+			return
+		}
+
+		if exp.Type != ExportType {
+			yield(nil, fmt.Errorf("unsupported export type '%s' expected %s", exp.Type, ExportType))
+			return
+		}
+
+		for _, d := range exp.Data {
+			for _, f := range d.Files {
+				lines, err := linecoverage(f)
+				if err != nil {
+					yield(nil, errorsx.Wrapf(err, "invalid file %s", f.Filename))
+					return
+				}
+
+				ok := yield(&coverage.Report{
+					Path:       f.Filename,
+					Statements: f.Summary["lines"].Percent,
+					Branches:   f.Summary["branches"].Percent,
+					Lines:      lines,
+				}, nil)
+				if !ok {
+					return
+				}
+
+				if err = ctx.Err(); err != nil {
+					yield(nil, err)
+					return
+				}
+			}
+		}
+	}
+}
+
+// linecoverage computes the coverage of each line from the segments of the file, mirroring
+// llvm's LineCoverageStats, and the branches from the branch regions.
+func linecoverage(f file) ([]*events.CoverageLine, error) {
+	var (
+		wrapped *segment
+		lines   = make(map[uint32]*events.CoverageLine)
+	)
+
+	segments := f.Segments
+	for idx := 0; idx < len(segments); {
+		lnum := segments[idx].Line
+		end := idx
+		for end < len(segments) && segments[end].Line == lnum {
+			end++
+		}
+
+		if l, ok := stats(wrapped, segments[idx:end]); ok {
+			l.Line = lnum
+			lines[lnum] = l
+		}
+
+		wrapped = &segments[end-1]
+
+		// lines between segments are determined by the wrapped segment.
+		for n := lnum + 1; end < len(segments) && n < segments[end].Line; n++ {
+			if l, ok := stats(wrapped, nil); ok {
+				l.Line = n
+				lines[n] = l
+			}
+		}
+
+		idx = end
+	}
+
+	// [lineStart, colStart, lineEnd, colEnd, trueCount, falseCount, ...]
+	for _, b := range f.Branches {
+		if len(b) < 6 {
+			return nil, fmt.Errorf("invalid branch %v", b)
+		}
+
+		lnum, err := number(b[0])
+		if err != nil {
+			return nil, err
+		}
+
+		l, ok := lines[uint32(lnum)]
+		if !ok {
+			l = &events.CoverageLine{Line: uint32(lnum)}
+			lines[uint32(lnum)] = l
+		}
+
+		for _, c := range b[4:6] {
+			count, err := number(c)
+			if err != nil {
+				return nil, err
+			}
+
+			l.Branches++
+			if count > 0 {
+				l.BranchesHit++
+			}
+		}
+	}
+
+	return slices.SortedFunc(maps.Values(lines), func(a, b *events.CoverageLine) int { return cmp.Compare(a.Line, b.Line) }), nil
+}
+
+// stats of the line from the segment active at its start and the segments starting on it.
+func stats(wrapped *segment, segments []segment) (l *events.CoverageLine, mapped bool) {
+	var (
+		regions int
+	)
+
+	for _, s := range segments {
+		if s.start() {
+			regions++
+		}
+	}
+
+	skipped := len(segments) > 0 && !segments[0].HasCount && segments[0].Entry
+	mapped = !skipped && ((wrapped != nil && wrapped.HasCount) || regions > 0)
+	if !mapped {
+		return nil, false
+	}
+
+	l = &events.CoverageLine{}
+	if wrapped != nil {
+		l.Hits = wrapped.Count
+	}
+
+	for _, s := range segments {
+		if s.start() {
+			l.Hits = max(l.Hits, s.Count)
+		}
+	}
+
+	return l, true
+}
+
+func number(n json.Number) (uint64, error) {
+	// counts are occasionally exported in exponent notation.
+	if strings.ContainsAny(n.String(), ".eE") {
+		f, err := n.Float64()
+		return uint64(f), err
+	}
+
+	i, err := n.Int64()
+	return uint64(max(i, 0)), err
+}
+
+// Coverage from the llvm-cov json exports within the directory.
+func Coverage(ctx context.Context, dir string) iter.Seq2[*coverage.Report, error] {
+	return coverage.Walk(ctx, dir, func(name string) bool {
+		return strings.HasSuffix(name, ".json")
+	}, Parse)
+}
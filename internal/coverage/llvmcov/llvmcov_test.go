@@ -0,0 +1,54 @@
+package llvmcov_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/egdaemon/eg/internal/coverage"
+	"github.com/egdaemon/eg/internal/coverage/llvmcov"
+	"github.com/egdaemon/eg/internal/slicesx"
+	"github.com/egdaemon/eg/internal/testx"
+	"github.com/egdaemon/eg/interp/events"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	ctx, done := testx.Context(t)
+	defer done()
+
+	var (
+		reports []*coverage.Report
+	)
+
+	for rep, err := range llvmcov.Parse(ctx, testx.Read(".fixtures", "cargo.llvm-cov.json")) {
+		require.NoError(t, err)
+		reports = append(reports, rep)
+	}
+
+	require.Len(t, reports, 1)
+	rep := reports[0]
+	require.Equal(t, "/workload/src/lib.rs", rep.Path)
+	require.InDelta(t, 76.92, rep.Statements, 0.01)
+	require.Equal(t, float32(100), rep.Branches)
+
+	// the computed lines agree with the summary of the export, 13 lines with 10 covered.
+	require.Equal(t, []uint32{1, 2, 3, 5, 6, 7, 8, 9, 10, 11, 13, 14, 15}, slicesx.MapTransform(func(l *events.CoverageLine) uint32 { return l.Line }, rep.Lines...))
+	require.Equal(t, []uint64{4, 4, 4, 4, 4, 3, 3, 1, 1, 4, 0, 0, 0}, slicesx.MapTransform(func(l *events.CoverageLine) uint64 { return l.Hits }, rep.Lines...))
+	require.Equal(t, float32(76.92308), coverage.Lines(rep.Lines...))
+	require.Equal(t, &events.CoverageLine{Line: 6, Hits: 4, Branches: 2, BranchesHit: 2}, rep.Lines[4])
+}
+
+func TestParseUnsupported(t *testing.T) {
+	ctx, done := testx.Context(t)
+	defer done()
+
+	var (
+		failed error
+	)
+
+	for _, err := range llvmcov.Parse(ctx, strings.NewReader(`{"type":"example"}`)) {
+		failed = err
+	}
+
+	require.ErrorContains(t, failed, "unsupported export type")
+}
@@ -7,6 +7,11 @@ import (
 	"golang.org/x/crypto/ssh"
 )
 
+func init() {
+	// allows tokens signed by ssh keys to be parsed.
+	jwt.RegisterSigningMethod(jwtsigner{}.Alg(), NewSSHSigner)
+}
+
 func NewSSHSigner() jwt.SigningMethod {
 	return jwtsigner{}
 }
@@ -0,0 +1,239 @@
+// Package modgraph maintains a content addressed graph of the packages within a go module.
+// every package is identified by the digest of its files and the packages it imports from
+// the module, allowing the outputs derived from unchanged packages to be reused between builds.
+package modgraph
+
+import (
+	"context"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/md5x"
+	"golang.org/x/mod/modfile"
+)
+
+type node struct {
+	files   []string // digests of the files within the directory.
+	imports []string // directories of the module local packages imported.
+	gofiles bool     // directory contains go source files.
+	key     string   // memoized digest of the package and its dependencies.
+}
+
+// Graph of the packages within a module, keyed by their directory relative to the module root.
+type Graph struct {
+	root    string
+	module  string
+	modules string // digest of the go.mod and go.sum files.
+	nodes   map[string]*node
+}
+
+// Scan the module rooted at the provided directory. directories are included using the same
+// rules as the ./... pattern: hidden, underscore prefixed, testdata, vendor directories and
+// nested modules are ignored.
+func Scan(ctx context.Context, root string) (g Graph, err error) {
+	g = Graph{
+		root:  root,
+		nodes: make(map[string]*node, 32),
+	}
+
+	gomod, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return g, errorsx.Wrapf(err, "unable to read go.mod: %s", root)
+	}
+
+	if g.module = modfile.ModulePath(gomod); g.module == "" {
+		return g, errorsx.Errorf("unable to determine module path: %s", root)
+	}
+
+	gosum, err := os.ReadFile(filepath.Join(root, "go.sum"))
+	if err != nil && !os.IsNotExist(err) {
+		return g, errorsx.Wrapf(err, "unable to read go.sum: %s", root)
+	}
+	g.modules = md5x.FormatHex(md5x.Digest(gomod, gosum))
+
+	fset := token.NewFileSet()
+	err = fs.WalkDir(os.DirFS(root), ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		if d.IsDir() {
+			return ignored(root, p, d.Name())
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		src, err := os.ReadFile(filepath.Join(root, p))
+		if err != nil {
+			return errorsx.WithStack(err)
+		}
+
+		dir := path.Dir(p)
+		n := g.node(dir)
+		n.files = append(n.files, md5x.FormatHex(md5x.Digest([]byte(d.Name()), src)))
+
+		if filepath.Ext(p) != ".go" || strings.HasSuffix(p, "_test.go") {
+			return nil
+		}
+
+		n.gofiles = true
+		n.imports = append(n.imports, g.local(fset, p, src)...)
+
+		return nil
+	})
+	if err != nil {
+		return g, errorsx.Wrapf(err, "unable to scan module: %s", root)
+	}
+
+	for _, n := range g.nodes {
+		slices.Sort(n.imports)
+		n.imports = slices.Compact(n.imports)
+	}
+
+	return g, nil
+}
+
+// Module path of the graph.
+func (t Graph) Module() string {
+	return t.module
+}
+
+// Packages within the module, sorted by directory.
+func (t Graph) Packages() (dirs []string) {
+	for dir, n := range t.nodes {
+		if n.gofiles {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	slices.Sort(dirs)
+	return dirs
+}
+
+// Digest of the files within the directory, empty when the directory is unknown.
+func (t Graph) Digest(dir string) string {
+	n, ok := t.nodes[dir]
+	if !ok {
+		return ""
+	}
+
+	return md5x.FormatHex(md5x.Digest(n.files...))
+}
+
+// Key of the package within the directory, changes whenever the package or any of the
+// module local packages it depends upon change. empty when the directory is unknown.
+func (t Graph) Key(dir string) string {
+	return t.key(dir, make(map[string]bool, len(t.nodes)))
+}
+
+// File computes the key for compiling a single go file of the module, covering the file,
+// the package containing it, the module local packages it imports and the go.mod and go.sum files.
+// the file does not need to be part of a scanned package, i.e. generated sources.
+func (t Graph) File(p string) (_ string, err error) {
+	src, err := os.ReadFile(p)
+	if err != nil {
+		return "", errorsx.WithStack(err)
+	}
+
+	rel, err := filepath.Rel(t.root, p)
+	if err != nil {
+		return "", errorsx.WithStack(err)
+	}
+
+	parts := []string{t.modules, filepath.ToSlash(rel), string(src), t.Key(filepath.ToSlash(filepath.Dir(rel)))}
+	for _, dir := range t.local(token.NewFileSet(), rel, src) {
+		parts = append(parts, dir, t.Key(dir))
+	}
+
+	return md5x.FormatHex(md5x.Digest(parts...)), nil
+}
+
+func (t Graph) key(dir string, visiting map[string]bool) string {
+	n, ok := t.nodes[dir]
+	if !ok || visiting[dir] {
+		// unknown directories and import cycles contribute nothing, the compiler rejects both.
+		return ""
+	}
+
+	if n.key != "" {
+		return n.key
+	}
+
+	visiting[dir] = true
+	defer delete(visiting, dir)
+
+	parts := append([]string{dir}, n.files...)
+	for _, imp := range n.imports {
+		parts = append(parts, imp, t.key(imp, visiting))
+	}
+
+	n.key = md5x.FormatHex(md5x.Digest(parts...))
+	return n.key
+}
+
+func (t Graph) node(dir string) *node {
+	if n, ok := t.nodes[dir]; ok {
+		return n
+	}
+
+	n := &node{}
+	t.nodes[dir] = n
+	return n
+}
+
+// local returns the directories of the module local packages imported by the source.
+// sources that fail to parse import nothing, the error is surfaced by the compiler.
+func (t Graph) local(fset *token.FileSet, name string, src []byte) (dirs []string) {
+	f, err := parser.ParseFile(fset, name, src, parser.ImportsOnly)
+	if err != nil {
+		return nil
+	}
+
+	for _, imp := range f.Imports {
+		ipath, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		if ipath == t.module {
+			dirs = append(dirs, ".")
+		} else if rel, ok := strings.CutPrefix(ipath, t.module+"/"); ok {
+			dirs = append(dirs, rel)
+		}
+	}
+
+	return dirs
+}
+
+func ignored(root string, p string, name string) error {
+	if p == "." {
+		return nil
+	}
+
+	if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor" {
+		return fs.SkipDir
+	}
+
+	// nested modules are not part of the module.
+	if _, err := os.Stat(filepath.Join(root, p, "go.mod")); err == nil {
+		return fs.SkipDir
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package modgraph_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/egdaemon/eg/internal/modgraph"
+	"github.com/egdaemon/eg/internal/testx"
+	"github.com/stretchr/testify/require"
+)
+
+func write(t *testing.T, path string, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func module(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	write(t, filepath.Join(root, "go.mod"), "module example.com/m\n")
+	write(t, filepath.Join(root, "main.go"), "package main\n\nimport \"example.com/m/a\"\n\nfunc main() { a.A() }\n")
+	write(t, filepath.Join(root, "a", "a.go"), "package a\n\nimport \"example.com/m/b\"\n\nfunc A() { b.B() }\n")
+	write(t, filepath.Join(root, "b", "b.go"), "package b\n\nfunc B() {}\n")
+	write(t, filepath.Join(root, "c", "c.go"), "package c\n\nfunc C() {}\n")
+	return root
+}
+
+func TestScan(t *testing.T) {
+	t.Run("should ignore directories excluded from the module", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		root := module(t)
+		write(t, filepath.Join(root, ".hidden", "h.go"), "package hidden\n")
+		write(t, filepath.Join(root, "_ignored", "i.go"), "package ignored\n")
+		write(t, filepath.Join(root, "testdata", "t.go"), "package testdata\n")
+		write(t, filepath.Join(root, "vendor", "v.go"), "package vendor\n")
+		write(t, filepath.Join(root, "nested", "go.mod"), "module example.com/nested\n")
+		write(t, filepath.Join(root, "nested", "n.go"), "package nested\n")
+		write(t, filepath.Join(root, "tests", "t_test.go"), "package tests\n")
+
+		g, err := modgraph.Scan(ctx, root)
+		require.NoError(t, err)
+		require.Equal(t, "example.com/m", g.Module())
+		require.Equal(t, []string{".", "a", "b", "c"}, g.Packages())
+	})
+
+	t.Run("should change the keys of dependent packages only", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		root := module(t)
+		before, err := modgraph.Scan(ctx, root)
+		require.NoError(t, err)
+
+		write(t, filepath.Join(root, "b", "b.go"), "package b\n\nfunc B() { println(\"b\") }\n")
+		after, err := modgraph.Scan(ctx, root)
+		require.NoError(t, err)
+
+		require.NotEqual(t, before.Digest("b"), after.Digest("b"))
+		require.Equal(t, before.Digest("a"), after.Digest("a"))
+		require.NotEqual(t, before.Key("b"), after.Key("b"))
+		require.NotEqual(t, before.Key("a"), after.Key("a"))
+		require.NotEqual(t, before.Key("."), after.Key("."))
+		require.Equal(t, before.Key("c"), after.Key("c"))
+	})
+
+	t.Run("should include the required modules in file keys", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		root := module(t)
+		before, err := modgraph.Scan(ctx, root)
+		require.NoError(t, err)
+		k1, err := before.File(filepath.Join(root, "c", "c.go"))
+		require.NoError(t, err)
+
+		write(t, filepath.Join(root, "go.sum"), "example.com/dep v1.0.0 h1:digest=\n")
+		after, err := modgraph.Scan(ctx, root)
+		require.NoError(t, err)
+		k2, err := after.File(filepath.Join(root, "c", "c.go"))
+		require.NoError(t, err)
+
+		require.Equal(t, before.Key("c"), after.Key("c"))
+		require.NotEqual(t, k1, k2)
+	})
+
+	t.Run("should key files outside of scanned packages", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		root := module(t)
+		generated := filepath.Join(root, ".genmod", "main.wasm.d", "module.1.1.go")
+		write(t, generated, "package main\n\nimport \"example.com/m/a\"\n\nfunc main() { a.A() }\n")
+
+		before, err := modgraph.Scan(ctx, root)
+		require.NoError(t, err)
+		k1, err := before.File(generated)
+		require.NoError(t, err)
+
+		write(t, filepath.Join(root, "b", "b.go"), "package b\n\nfunc B() { println(\"b\") }\n")
+		after, err := modgraph.Scan(ctx, root)
+		require.NoError(t, err)
+		k2, err := after.File(generated)
+		require.NoError(t, err)
+
+		require.NotEqual(t, k1, k2)
+	})
+
+	t.Run("should fail without a go.mod", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		_, err := modgraph.Scan(ctx, t.TempDir())
+		require.Error(t, err)
+	})
+}
+
+func TestStore(t *testing.T) {
+	type record struct {
+		Value string
+	}
+
+	t.Run("should round trip records", func(t *testing.T) {
+		s := modgraph.NewStore(t.TempDir())
+
+		var r record
+		found, err := s.Read("k1", &r)
+		require.NoError(t, err)
+		require.False(t, found)
+
+		require.NoError(t, s.Write("k1", record{Value: "hello"}))
+		found, err = s.Read("k1", &r)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, "hello", r.Value)
+	})
+
+	t.Run("should restore retained outputs", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		tmpdir := t.TempDir()
+		s := modgraph.NewStore(filepath.Join(tmpdir, "graph"))
+		src := filepath.Join(tmpdir, "src.wasm")
+		dst := filepath.Join(tmpdir, "build", "dst.wasm")
+		write(t, src, "wasm")
+
+		found, err := s.Restore(ctx, "k1", "module.wasm", dst)
+		require.NoError(t, err)
+		require.False(t, found)
+
+		require.NoError(t, s.Retain(ctx, "k1", "module.wasm", src))
+		found, err = s.Restore(ctx, "k1", "module.wasm", dst)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, "wasm", testx.ReadString(dst))
+	})
+
+	t.Run("should do nothing when disabled", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		s := modgraph.NewStore("")
+		require.False(t, s.Enabled())
+		require.NoError(t, s.Write("k1", record{Value: "hello"}))
+
+		found, err := s.Restore(ctx, "k1", "module.wasm", filepath.Join(t.TempDir(), "dst.wasm"))
+		require.NoError(t, err)
+		require.False(t, found)
+	})
+}
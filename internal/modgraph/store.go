@@ -0,0 +1,142 @@
+package modgraph
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/fsx"
+)
+
+const record = "record.json"
+
+// Store persists the outputs derived from the graph by their keys. every key is a directory
+// containing the outputs, the modification times of entries are refreshed when they are used
+// allowing unused entries to be pruned by age.
+type Store struct {
+	dir string
+}
+
+// NewStore rooted at the provided directory. an empty directory disables the store.
+func NewStore(dir string) Store {
+	return Store{dir: dir}
+}
+
+// Enabled reports if the store persists outputs.
+func (t Store) Enabled() bool {
+	return t.dir != ""
+}
+
+// Read the record stored for the key into v, reports if the record was found.
+func (t Store) Read(key string, v any) (bool, error) {
+	if !t.Enabled() {
+		return false, nil
+	}
+
+	encoded, err := os.ReadFile(filepath.Join(t.dir, key, record))
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errorsx.WithStack(err)
+	}
+
+	if err = json.Unmarshal(encoded, v); err != nil {
+		return false, errorsx.Wrapf(err, "unable to decode record: %s", key)
+	}
+
+	t.touch(key, record)
+	return true, nil
+}
+
+// Write the record for the key.
+func (t Store) Write(key string, v any) error {
+	if !t.Enabled() {
+		return nil
+	}
+
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return errorsx.Wrapf(err, "unable to encode record: %s", key)
+	}
+
+	return t.atomic(key, record, func(dst string) error {
+		return os.WriteFile(dst, encoded, 0600)
+	})
+}
+
+// Restore the named output of the key to dst, reports if the output was found.
+// outputs are hard linked when possible and copied otherwise.
+func (t Store) Restore(ctx context.Context, key string, name string, dst string) (bool, error) {
+	if !t.Enabled() {
+		return false, nil
+	}
+
+	src := filepath.Join(t.dir, key, name)
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errorsx.WithStack(err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return false, errorsx.WithStack(err)
+	}
+
+	if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+		return false, errorsx.WithStack(err)
+	}
+
+	if err := os.Link(src, dst); err != nil {
+		if err = fsx.Clone(ctx, src, dst); err != nil {
+			return false, errorsx.Wrapf(err, "unable to restore output: %s", src)
+		}
+	}
+
+	t.touch(key, name)
+	return true, nil
+}
+
+// Retain a copy of the src file as the named output of the key.
+func (t Store) Retain(ctx context.Context, key string, name string, src string) error {
+	if !t.Enabled() {
+		return nil
+	}
+
+	return t.atomic(key, name, func(dst string) error {
+		return fsx.Clone(ctx, src, dst)
+	})
+}
+
+// atomic writes the named entry of the key, concurrent builds either observe
+// the complete entry or nothing.
+func (t Store) atomic(key string, name string, write func(dst string) error) (err error) {
+	dir := filepath.Join(t.dir, key)
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		return errorsx.WithStack(err)
+	}
+
+	tmp, err := os.CreateTemp(dir, name+".*")
+	if err != nil {
+		return errorsx.WithStack(err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err = tmp.Close(); err != nil {
+		return errorsx.WithStack(err)
+	}
+
+	if err = write(tmp.Name()); err != nil {
+		return errorsx.Wrapf(err, "unable to write entry: %s/%s", key, name)
+	}
+
+	return errorsx.WithStack(os.Rename(tmp.Name(), filepath.Join(dir, name)))
+}
+
+func (t Store) touch(key string, name string) {
+	ts := time.Now()
+	errorsx.Log(errorsx.Wrap(os.Chtimes(filepath.Join(t.dir, key, name), ts, ts), "unable to refresh entry"))
+	errorsx.Log(errorsx.Wrap(os.Chtimes(filepath.Join(t.dir, key), ts, ts), "unable to refresh entry"))
+}
@@ -0,0 +1,171 @@
+// Package netpolicy restricts the network egress of workloads. policies are enforced by running the
+// workload's containers without a network, the only route out is an http proxy served by the host that
+// permits connections to the hosts and CIDRs allowed by the policy.
+package netpolicy
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/egdaemon/eg"
+	"github.com/egdaemon/eg/internal/envx"
+)
+
+type Mode string
+
+const (
+	ModeOpen  Mode = "open"  // unrestricted egress, the default.
+	ModeDeny  Mode = "deny"  // no egress.
+	ModeAllow Mode = "allow" // egress to the allow list.
+)
+
+// MetricName of the custom metric recording the connections permitted by the policy, rejected
+// connections are recorded as network violation events.
+const MetricName = "eg.network"
+
+// Registries alias within an allow list expanding to well known package and container registries.
+const Registries = "@registries"
+
+// hosts of well known package and container registries.
+var registries = []string{
+	"proxy.golang.org",
+	"sum.golang.org",
+	"storage.googleapis.com",
+	"registry.npmjs.org",
+	"registry.yarnpkg.com",
+	"pypi.org",
+	"files.pythonhosted.org",
+	"crates.io",
+	"index.crates.io",
+	"static.crates.io",
+	"rubygems.org",
+	"repo.maven.apache.org",
+	"repo1.maven.org",
+	"docker.io",
+	"*.docker.io",
+	"production.cloudflare.docker.com",
+	"ghcr.io",
+	"pkg-containers.githubusercontent.com",
+	"quay.io",
+	"*.quay.io",
+	"gcr.io",
+	"*.pkg.dev",
+	"public.ecr.aws",
+}
+
+// Policy of the hosts a workload is permitted to connect to.
+type Policy struct {
+	Mode     Mode
+	Hosts    []string       // exact host names or wildcard subdomains (*.example.com).
+	Prefixes []netip.Prefix // permitted ip address ranges.
+}
+
+// Parse a policy, "" and "open" permit everything, "deny" nothing, anything else is a
+// comma separated allow list of hosts, wildcard subdomains, CIDRs and @registries.
+func Parse(s string) (p Policy, err error) {
+	switch s = strings.TrimSpace(s); s {
+	case "", string(ModeOpen):
+		return Policy{Mode: ModeOpen}, nil
+	case string(ModeDeny):
+		return Policy{Mode: ModeDeny}, nil
+	}
+
+	p = Policy{Mode: ModeAllow}
+	for _, rule := range strings.Split(s, ",") {
+		if rule = strings.ToLower(strings.TrimSpace(rule)); rule == "" {
+			continue
+		}
+
+		if rule == Registries {
+			p.Hosts = append(p.Hosts, registries...)
+			continue
+		}
+
+		if strings.Contains(rule, "/") {
+			prefix, err := netip.ParsePrefix(rule)
+			if err != nil {
+				return p, fmt.Errorf("invalid network policy CIDR %s: %w", rule, err)
+			}
+			p.Prefixes = append(p.Prefixes, prefix.Masked())
+			continue
+		}
+
+		if addr, err := netip.ParseAddr(rule); err == nil {
+			p.Prefixes = append(p.Prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+
+		if strings.ContainsAny(rule, ":@ ") || strings.Contains(strings.TrimPrefix(rule, "*."), "*") {
+			return p, fmt.Errorf("invalid network policy host %s", rule)
+		}
+
+		p.Hosts = append(p.Hosts, rule)
+	}
+
+	// an empty allow list permits nothing.
+	if len(p.Hosts) == 0 && len(p.Prefixes) == 0 {
+		return Policy{Mode: ModeDeny}, nil
+	}
+
+	return p, nil
+}
+
+// FromEnviron parses the policy specified by eg.EnvComputeNetworkPolicy.
+func FromEnviron(environ ...string) (Policy, error) {
+	return Parse(envx.NewEnvironFromStrings(environ...).String("", eg.EnvComputeNetworkPolicy))
+}
+
+// ControlPlane hosts workloads communicate with regardless of their policy, e.g. to refresh git credentials.
+func ControlPlane() (hosts []string) {
+	for _, uri := range []string{eg.EnvContainerAPIHostDefault(), eg.EnvAPIHostDefault()} {
+		if u, err := url.Parse(uri); err == nil && u.Hostname() != "" && !slices.Contains(hosts, u.Hostname()) {
+			hosts = append(hosts, u.Hostname())
+		}
+	}
+
+	return hosts
+}
+
+// Enforced reports if the policy restricts egress.
+func (t Policy) Enforced() bool {
+	return t.Mode != ModeOpen && t.Mode != ""
+}
+
+// Permits reports if connections to the host (a name or an ip address) are allowed.
+func (t Policy) Permits(host string) bool {
+	switch t.Mode {
+	case ModeOpen, "":
+		return true
+	case ModeDeny:
+		return false
+	}
+
+	host = strings.TrimSuffix(strings.ToLower(strings.Trim(host, "[]")), ".")
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return slices.ContainsFunc(t.Prefixes, func(p netip.Prefix) bool { return p.Contains(addr.Unmap()) })
+	}
+
+	return slices.ContainsFunc(t.Hosts, func(rule string) bool {
+		if suffix, ok := strings.CutPrefix(rule, "*"); ok {
+			return strings.HasSuffix(host, suffix)
+		}
+
+		return host == rule
+	})
+}
+
+func (t Policy) String() string {
+	if t.Mode != ModeAllow {
+		return string(t.Mode)
+	}
+
+	rules := slices.Clone(t.Hosts)
+	for _, p := range t.Prefixes {
+		rules = append(rules, p.String())
+	}
+
+	return strings.Join(rules, ",")
+}
@@ -0,0 +1,193 @@
+package netpolicy_test
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/egdaemon/eg"
+	"github.com/egdaemon/eg/internal/netpolicy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy(t *testing.T) {
+	cases := []struct {
+		name     string
+		policy   string
+		host     string
+		expected bool
+	}{
+		{name: "open permits everything", policy: "", host: "example.com", expected: true},
+		{name: "deny permits nothing", policy: "deny", host: "example.com", expected: false},
+		{name: "exact host", policy: "github.com", host: "github.com", expected: true},
+		{name: "exact host is case insensitive", policy: "GitHub.com", host: "github.COM.", expected: true},
+		{name: "exact host excludes subdomains", policy: "github.com", host: "api.github.com", expected: false},
+		{name: "wildcard subdomain", policy: "*.github.com", host: "api.github.com", expected: true},
+		{name: "wildcard excludes the parent", policy: "*.github.com", host: "github.com", expected: false},
+		{name: "cidr", policy: "10.0.0.0/8", host: "10.1.2.3", expected: true},
+		{name: "cidr excludes other addresses", policy: "10.0.0.0/8", host: "192.168.1.1", expected: false},
+		{name: "ip address", policy: "::1", host: "[::1]", expected: true},
+		{name: "registries", policy: "@registries", host: "proxy.golang.org", expected: true},
+		{name: "registries exclude other hosts", policy: "@registries", host: "example.com", expected: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p, err := netpolicy.Parse(c.policy)
+			require.NoError(t, err)
+			require.Equal(t, c.expected, p.Permits(c.host))
+		})
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		for _, s := range []string{"open", "deny", "github.com,*.example.com,10.0.0.0/8"} {
+			p, err := netpolicy.Parse(s)
+			require.NoError(t, err)
+			require.Equal(t, s, p.String())
+		}
+	})
+
+	t.Run("empty allow list denies", func(t *testing.T) {
+		p, err := netpolicy.Parse(" , ")
+		require.NoError(t, err)
+		require.Equal(t, netpolicy.ModeDeny, p.Mode)
+	})
+
+	t.Run("from environ", func(t *testing.T) {
+		p, err := netpolicy.FromEnviron(eg.EnvComputeNetworkPolicy + "=@registries,10.0.0.0/8")
+		require.NoError(t, err)
+		require.True(t, p.Enforced())
+		require.True(t, p.Permits("pypi.org"))
+
+		p, err = netpolicy.FromEnviron()
+		require.NoError(t, err)
+		require.False(t, p.Enforced())
+	})
+
+	t.Run("invalid rules", func(t *testing.T) {
+		for _, s := range []string{"10.0.0.0/33", "github.com:443", "a*.github.com"} {
+			_, err := netpolicy.Parse(s)
+			require.Error(t, err, s)
+		}
+	})
+}
+
+type observed struct {
+	m           sync.Mutex
+	connections []netpolicy.Connection
+}
+
+func (t *observed) observe(_ context.Context, c netpolicy.Connection) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	t.connections = append(t.connections, c)
+}
+
+// serve the host's proxy on a unix socket and the workload's proxy tunneling through it on a tcp port.
+func serve(t *testing.T, hostpolicy, workloadpolicy netpolicy.Policy, obs *observed) *http.Client {
+	socket := filepath.Join(t.TempDir(), "network.socket")
+	hostl, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	go netpolicy.Serve(t.Context(), hostl, netpolicy.NewProxy(hostpolicy))
+
+	workloadl, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go netpolicy.Serve(t.Context(), workloadl, netpolicy.NewProxy(
+		workloadpolicy,
+		netpolicy.ProxyOptionDialer(netpolicy.TunnelDialer(socket)),
+		netpolicy.ProxyOptionObserve(obs.observe),
+	))
+
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyURL(&url.URL{Scheme: "http", Host: workloadl.Addr().String()}),
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+}
+
+func TestProxy(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "hello")
+	})
+
+	t.Run("permitted http and https requests", func(t *testing.T) {
+		var obs observed
+		plain := httptest.NewServer(handler)
+		defer plain.Close()
+		secure := httptest.NewTLSServer(handler)
+		defer secure.Close()
+
+		policy, err := netpolicy.Parse("127.0.0.1")
+		require.NoError(t, err)
+		client := serve(t, policy, policy, &obs)
+
+		for _, uri := range []string{plain.URL, secure.URL} {
+			resp, err := client.Get(uri)
+			require.NoError(t, err)
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			require.Equal(t, "hello", string(body))
+		}
+
+		require.Len(t, obs.connections, 2)
+		require.True(t, obs.connections[0].Allowed)
+	})
+
+	t.Run("denied requests are observed", func(t *testing.T) {
+		var obs observed
+		policy, err := netpolicy.Parse("deny")
+		require.NoError(t, err)
+		client := serve(t, policy, policy, &obs)
+
+		resp, err := client.Get("http://example.com/")
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		_, err = client.Get("https://example.com/")
+		require.Error(t, err)
+
+		require.Equal(t, []netpolicy.Connection{
+			{Host: "example.com", Port: 80, Allowed: false},
+			{Host: "example.com", Port: 443, Allowed: false},
+		}, obs.connections)
+	})
+
+	t.Run("permitted hosts bypass the policy", func(t *testing.T) {
+		plain := httptest.NewServer(handler)
+		defer plain.Close()
+
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		go netpolicy.Serve(t.Context(), l, netpolicy.NewProxy(netpolicy.Policy{Mode: netpolicy.ModeDeny}, netpolicy.ProxyOptionPermit("127.0.0.1")))
+
+		client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(&url.URL{Scheme: "http", Host: l.Addr().String()})}}
+		resp, err := client.Get(plain.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("host enforces the policy independently of the workload", func(t *testing.T) {
+		var obs observed
+		secure := httptest.NewTLSServer(handler)
+		defer secure.Close()
+
+		hostpolicy, err := netpolicy.Parse("deny")
+		require.NoError(t, err)
+		client := serve(t, hostpolicy, netpolicy.Policy{Mode: netpolicy.ModeOpen}, &obs)
+
+		_, err = client.Get(secure.URL)
+		require.Error(t, err)
+	})
+}
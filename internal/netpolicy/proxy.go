@@ -0,0 +1,276 @@
+package netpolicy
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/langx"
+)
+
+// Connection attempted through the proxy.
+type Connection struct {
+	Host    string `json:"host"`
+	Port    int    `json:"port"`
+	Allowed bool   `json:"allowed"`
+}
+
+type dialer func(ctx context.Context, network, addr string) (net.Conn, error)
+
+type ProxyOption func(*Proxy)
+
+// ProxyOptionDialer overrides how permitted connections are established, by default they're dialed directly.
+func ProxyOptionDialer(d func(ctx context.Context, network, addr string) (net.Conn, error)) ProxyOption {
+	return func(p *Proxy) {
+		p.dial = d
+	}
+}
+
+// ProxyOptionObserve is invoked for every connection attempted through the proxy.
+func ProxyOptionObserve(fn func(ctx context.Context, c Connection)) ProxyOption {
+	return func(p *Proxy) {
+		p.observe = fn
+	}
+}
+
+// ProxyOptionPermit hosts regardless of the policy, e.g. the control plane required by the workload.
+func ProxyOptionPermit(hosts ...string) ProxyOption {
+	return func(p *Proxy) {
+		p.permitted = append(p.permitted, hosts...)
+	}
+}
+
+// NewProxy http proxy (CONNECT and plain http requests) permitting connections allowed by the policy.
+func NewProxy(p Policy, options ...ProxyOption) *Proxy {
+	d := net.Dialer{}
+	proxy := langx.Clone(Proxy{
+		policy:  p,
+		dial:    d.DialContext,
+		observe: func(context.Context, Connection) {},
+	}, options...)
+
+	return &proxy
+}
+
+type Proxy struct {
+	policy    Policy
+	permitted []string
+	dial      dialer
+	observe   func(ctx context.Context, c Connection)
+}
+
+func (t *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		addr = r.Host
+	)
+
+	if r.Method != http.MethodConnect {
+		addr = r.URL.Host
+	}
+
+	if r.Method != http.MethodConnect && r.URL.Scheme != "http" {
+		http.Error(w, "only CONNECT and absolute http requests are supported", http.StatusBadRequest)
+		return
+	}
+
+	host, port, err := splitaddr(addr, r.Method == http.MethodConnect)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	allowed := slices.Contains(t.permitted, host) || t.policy.Permits(host)
+	t.observe(r.Context(), Connection{Host: host, Port: port, Allowed: allowed})
+	if !allowed {
+		http.Error(w, fmt.Sprintf("%s denied by the network policy", host), http.StatusForbidden)
+		return
+	}
+
+	if r.Method == http.MethodConnect {
+		t.tunnel(w, r, net.JoinHostPort(host, strconv.Itoa(port)))
+		return
+	}
+
+	t.forward(w, r)
+}
+
+func (t *Proxy) tunnel(w http.ResponseWriter, r *http.Request, addr string) {
+	upstream, err := t.dial(r.Context(), "tcp", addr)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer upstream.Close()
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "connection hijacking unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	downstream, buffered, err := hijacker.Hijack()
+	if err != nil {
+		log.Println("unable to hijack proxy connection", err)
+		return
+	}
+	defer downstream.Close()
+
+	if _, err = io.WriteString(downstream, "HTTP/1.1 200 Connection established\r\n\r\n"); err != nil {
+		return
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(upstream, buffered)
+		closewrite(upstream)
+	}()
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(downstream, upstream)
+		closewrite(downstream)
+	}()
+	wg.Wait()
+}
+
+func (t *Proxy) forward(w http.ResponseWriter, r *http.Request) {
+	transport := &http.Transport{
+		Proxy:       nil,
+		DialContext: t.dial,
+	}
+	defer transport.CloseIdleConnections()
+
+	outgoing := r.Clone(r.Context())
+	outgoing.RequestURI = ""
+	outgoing.Header.Del("Proxy-Connection")
+	outgoing.Header.Del("Proxy-Authorization")
+
+	resp, err := transport.RoundTrip(outgoing)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	for k, vs := range resp.Header {
+		for _, v := range vs {
+			w.Header().Add(k, v)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = io.Copy(w, resp.Body)
+}
+
+// Serve the proxy on the listener until the context is cancelled.
+func Serve(ctx context.Context, l net.Listener, p *Proxy) error {
+	srv := &http.Server{Handler: p}
+	go func() {
+		<-ctx.Done()
+		errorsx.Log(errorsx.Wrap(srv.Close(), "unable to close network policy proxy"))
+	}()
+
+	return errorsx.Ignore(srv.Serve(l), http.ErrServerClosed, net.ErrClosed)
+}
+
+// TunnelDialer dials through the proxy served on the unix socket, allowing workloads
+// without a network to reach the hosts permitted by the policy.
+func TunnelDialer(socket string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (_ net.Conn, err error) {
+		var (
+			d net.Dialer
+		)
+
+		conn, err := d.DialContext(ctx, "unix", socket)
+		if err != nil {
+			return nil, errorsx.Wrap(err, "unable to dial network policy proxy")
+		}
+
+		if _, err = fmt.Fprintf(conn, "CONNECT %s HTTP/1.1\r\nHost: %s\r\n\r\n", addr, addr); err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		buffered := bufio.NewReader(conn)
+		resp, err := http.ReadResponse(buffered, &http.Request{Method: http.MethodConnect})
+		if err != nil {
+			conn.Close()
+			return nil, errorsx.Wrap(err, "invalid network policy proxy response")
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			conn.Close()
+			return nil, fmt.Errorf("%s: %s", addr, resp.Status)
+		}
+
+		return bufferedConn{Conn: conn, r: buffered}, nil
+	}
+}
+
+// Environ returns the environment directing http clients to the proxy at the address.
+func Environ(addr string) []string {
+	proxy := fmt.Sprintf("http://%s", addr)
+	noproxy := "localhost,127.0.0.1,::1"
+	return []string{
+		"HTTP_PROXY=" + proxy,
+		"HTTPS_PROXY=" + proxy,
+		"http_proxy=" + proxy,
+		"https_proxy=" + proxy,
+		"NO_PROXY=" + noproxy,
+		"no_proxy=" + noproxy,
+	}
+}
+
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (t bufferedConn) Read(b []byte) (int, error) {
+	return t.r.Read(b)
+}
+
+func (t bufferedConn) CloseWrite() error {
+	if cw, ok := t.Conn.(interface{ CloseWrite() error }); ok {
+		return cw.CloseWrite()
+	}
+
+	return t.Conn.Close()
+}
+
+func closewrite(c net.Conn) {
+	if cw, ok := c.(interface{ CloseWrite() error }); ok {
+		_ = cw.CloseWrite()
+		return
+	}
+
+	_ = c.Close()
+}
+
+// splitaddr into its host and port, CONNECT requests require the port, http defaults to 80.
+func splitaddr(addr string, connect bool) (host string, port int, err error) {
+	h, p, err := net.SplitHostPort(addr)
+	if err != nil && connect {
+		return "", 0, fmt.Errorf("invalid address %s: %w", addr, err)
+	}
+
+	if err != nil {
+		h, p = strings.Trim(addr, "[]"), "80"
+	}
+
+	if port, err = strconv.Atoi(p); err != nil {
+		return "", 0, fmt.Errorf("invalid port %s", p)
+	}
+
+	return h, port, nil
+}
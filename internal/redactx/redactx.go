@@ -0,0 +1,211 @@
+// Package redactx masks secret values within log output. its opt-in (see eg.EnvComputeRedact) and a best
+// effort safety net for secrets accidentally echoed by a workload, not a replacement for keeping them out of logs.
+package redactx
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/egdaemon/eg"
+	"github.com/egdaemon/eg/internal/envx"
+)
+
+const (
+	// Mask replaces redacted values.
+	Mask = "[REDACTED]"
+	// MinimumLength values shorter than this are ignored, masking them would mangle unrelated output.
+	MinimumLength = 4
+	// MetricName of the custom metric recording the number of redactions performed during a run.
+	MetricName = "eg.redactions"
+)
+
+// Environ returns the environment enabling redaction of the variables within the provided environ.
+func Environ(enabled bool, environ ...string) []string {
+	if !enabled || len(environ) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(environ))
+	for _, v := range environ {
+		if k, _, ok := strings.Cut(v, "="); ok {
+			keys = append(keys, k)
+		}
+	}
+
+	return []string{envx.Format(eg.EnvComputeRedact, strings.Join(keys, ","))}
+}
+
+// FromEnviron creates a redactor for the values of the variables listed by eg.EnvComputeRedact, returns nil
+// when redaction isn't enabled.
+func FromEnviron(environ ...string) *Redactor {
+	var (
+		values []string
+		env    = envx.NewEnvironFromStrings(environ...)
+	)
+
+	for _, k := range strings.Split(env.String("", eg.EnvComputeRedact), ",") {
+		if k = strings.TrimSpace(k); k == "" {
+			continue
+		}
+
+		values = append(values, env.String("", k))
+	}
+
+	return New(values...)
+}
+
+// New redactor masking the values along with their base64 and url encoded forms. returns nil
+// when none of the values are long enough to be redacted, a nil redactor is a noop.
+func New(values ...string) *Redactor {
+	var patterns []string
+
+	for _, v := range values {
+		if len(v) < MinimumLength {
+			continue
+		}
+
+		patterns = append(
+			patterns,
+			v,
+			base64.StdEncoding.EncodeToString([]byte(v)),
+			base64.RawStdEncoding.EncodeToString([]byte(v)),
+			base64.URLEncoding.EncodeToString([]byte(v)),
+			base64.RawURLEncoding.EncodeToString([]byte(v)),
+			url.QueryEscape(v),
+			url.PathEscape(v),
+		)
+	}
+
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	// longest first so encodings containing another pattern, i.e. padded base64, are masked entirely.
+	slices.SortFunc(patterns, func(a, b string) int {
+		if len(a) == len(b) {
+			return strings.Compare(a, b)
+		}
+
+		return len(b) - len(a)
+	})
+	patterns = slices.Compact(patterns)
+
+	r := &Redactor{
+		patterns: make([][]byte, 0, len(patterns)),
+	}
+
+	for _, p := range patterns {
+		r.patterns = append(r.patterns, []byte(p))
+	}
+
+	return r
+}
+
+type Redactor struct {
+	patterns [][]byte
+	count    atomic.Uint64
+}
+
+// Count of values redacted so far.
+func (t *Redactor) Count() uint64 {
+	if t == nil {
+		return 0
+	}
+
+	return t.count.Load()
+}
+
+// Redact masks every occurrence of the secret values within b.
+func (t *Redactor) Redact(b []byte) []byte {
+	if t == nil {
+		return b
+	}
+
+	for _, p := range t.patterns {
+		if n := bytes.Count(b, p); n > 0 {
+			t.count.Add(uint64(n))
+			b = bytes.ReplaceAll(b, p, []byte(Mask))
+		}
+	}
+
+	return b
+}
+
+// holdback returns the length of the longest suffix of b that could be the start of a pattern.
+func (t *Redactor) holdback(b []byte) int {
+	longest := 0
+	for _, p := range t.patterns {
+		for n := min(len(p)-1, len(b)); n > longest; n-- {
+			if bytes.HasPrefix(p, b[len(b)-n:]) {
+				longest = n
+				break
+			}
+		}
+	}
+
+	return longest
+}
+
+// Writer masks secrets written to w. output that could be the start of a secret is held back
+// until the next write, use Flush to write it once no further output is expected.
+func (t *Redactor) Writer(w io.Writer) io.Writer {
+	if t == nil {
+		return w
+	}
+
+	return &writer{r: t, dst: w}
+}
+
+type writer struct {
+	m       sync.Mutex
+	r       *Redactor
+	dst     io.Writer
+	pending []byte
+}
+
+func (t *writer) Write(b []byte) (int, error) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	buf := t.r.Redact(append(t.pending, b...))
+	n := len(buf) - t.r.holdback(buf)
+	t.pending = append([]byte(nil), buf[n:]...)
+
+	if n == 0 {
+		return len(b), nil
+	}
+
+	if _, err := t.dst.Write(buf[:n]); err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+func (t *writer) Flush() error {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if len(t.pending) == 0 {
+		return nil
+	}
+
+	_, err := t.dst.Write(t.pending)
+	t.pending = nil
+	return err
+}
+
+// Flush any output held back by the writer, noop for writers not created by a redactor.
+func Flush(w io.Writer) error {
+	if f, ok := w.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package redactx_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"net/url"
+	"testing"
+
+	"github.com/egdaemon/eg"
+	"github.com/egdaemon/eg/internal/redactx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedact(t *testing.T) {
+	const secret = "hunter2/pass+word"
+
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+		count    uint64
+	}{
+		{name: "exact", input: "password is " + secret + "\n", expected: "password is [REDACTED]\n", count: 1},
+		{name: "multiple occurrences", input: secret + " " + secret, expected: "[REDACTED] [REDACTED]", count: 2},
+		{name: "base64", input: "Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte(secret)), expected: "Authorization: Basic [REDACTED]", count: 1},
+		{name: "base64 url", input: base64.RawURLEncoding.EncodeToString([]byte(secret)), expected: "[REDACTED]", count: 1},
+		{name: "query escaped", input: "https://example.com?token=" + url.QueryEscape(secret), expected: "https://example.com?token=[REDACTED]", count: 1},
+		{name: "path escaped", input: "https://example.com/" + url.PathEscape(secret), expected: "https://example.com/[REDACTED]", count: 1},
+		{name: "unrelated output", input: "hello world\n", expected: "hello world\n", count: 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := redactx.New(secret)
+			require.Equal(t, c.expected, string(r.Redact([]byte(c.input))))
+			require.Equal(t, c.count, r.Count())
+		})
+	}
+
+	t.Run("short values are ignored", func(t *testing.T) {
+		require.Nil(t, redactx.New("abc", ""))
+	})
+
+	t.Run("nil redactor is a noop", func(t *testing.T) {
+		var (
+			r   *redactx.Redactor
+			buf bytes.Buffer
+		)
+
+		require.Equal(t, "hello", string(r.Redact([]byte("hello"))))
+		require.Equal(t, io.Writer(&buf), r.Writer(&buf))
+		require.Zero(t, r.Count())
+	})
+}
+
+func TestWriter(t *testing.T) {
+	t.Run("secret split across writes", func(t *testing.T) {
+		var (
+			buf bytes.Buffer
+			r   = redactx.New("hunter2")
+			w   = r.Writer(&buf)
+		)
+
+		for _, chunk := range []string{"password is hun", "te", "r2 ok\n"} {
+			n, err := io.WriteString(w, chunk)
+			require.NoError(t, err)
+			require.Equal(t, len(chunk), n)
+		}
+
+		require.NoError(t, redactx.Flush(w))
+		require.Equal(t, "password is [REDACTED] ok\n", buf.String())
+		require.Equal(t, uint64(1), r.Count())
+	})
+
+	t.Run("partial match is written on flush", func(t *testing.T) {
+		var (
+			buf bytes.Buffer
+			w   = redactx.New("hunter2").Writer(&buf)
+		)
+
+		_, err := io.WriteString(w, "hello hunt")
+		require.NoError(t, err)
+		require.Equal(t, "hello ", buf.String())
+		require.NoError(t, redactx.Flush(w))
+		require.Equal(t, "hello hunt", buf.String())
+	})
+}
+
+func TestFromEnviron(t *testing.T) {
+	environ := []string{"TOKEN=hunter2", "PASSWORD=correcthorse", "PATH=/usr/bin"}
+
+	t.Run("disabled", func(t *testing.T) {
+		require.Nil(t, redactx.FromEnviron(environ...))
+		require.Empty(t, redactx.Environ(false, environ...))
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		enabled := redactx.Environ(true, environ[:2]...)
+		require.Equal(t, []string{eg.EnvComputeRedact + "=TOKEN,PASSWORD"}, enabled)
+
+		r := redactx.FromEnviron(append(environ, enabled...)...)
+		require.Equal(t, "[REDACTED] [REDACTED] /usr/bin", string(r.Redact([]byte("hunter2 correcthorse /usr/bin"))))
+	})
+}
@@ -125,6 +125,53 @@ func Unpack(root string, r io.Reader) (err error) {
 	}
 }
 
+// Filter copies the entries of the archive from the reader into the writer, entries
+// for which keep returns false are omitted.
+func Filter(dst io.Writer, r io.Reader, keep func(*tar.Header) bool) (err error) {
+	var (
+		gzr *gzip.Reader
+		gw  *gzip.Writer
+		tr  *tar.Reader
+		tw  *tar.Writer
+	)
+
+	if gzr, err = gzip.NewReader(r); err != nil {
+		return errorsx.Wrap(err, "failed to create gzip reader")
+	}
+	defer gzr.Close()
+
+	gw = gzip.NewWriter(dst)
+	tw = tar.NewWriter(gw)
+	tr = tar.NewReader(gzr)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		if !keep(header) {
+			continue
+		}
+
+		if err = tw.WriteHeader(header); err != nil {
+			return errorsx.Wrapf(err, "failed to write header: %s", header.Name)
+		}
+
+		if _, err = io.Copy(tw, tr); err != nil {
+			return errorsx.Wrapf(err, "failed to copy contents: %s", header.Name)
+		}
+	}
+
+	if err = tw.Close(); err != nil {
+		return errorsx.Wrap(err, "failed to close archive")
+	}
+
+	return errorsx.Wrap(gw.Close(), "failed to close compression")
+}
+
 // prints to stderr information about the archive
 func Inspect(r io.Reader) (err error) {
 	var (
@@ -0,0 +1,140 @@
+// Package dartjson parses the event stream emitted by the json reporter of dart test.
+// https://github.com/dart-lang/test/blob/master/pkgs/test/doc/json_reporter.md
+package dartjson
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"iter"
+	"strings"
+
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/testresults"
+	"github.com/egdaemon/eg/interp/events"
+)
+
+type event struct {
+	Type    string `json:"type"`
+	Time    int64  `json:"time"` // milliseconds since the start of the run.
+	TestID  int64  `json:"testID"`
+	Result  string `json:"result"`
+	Skipped bool   `json:"skipped"`
+	Hidden  bool   `json:"hidden"`
+	Error   string `json:"error"`
+	Suite   struct {
+		ID   int64  `json:"id"`
+		Path string `json:"path"`
+	} `json:"suite"`
+	Test struct {
+		ID       int64  `json:"id"`
+		Name     string `json:"name"`
+		SuiteID  int64  `json:"suiteID"`
+		Metadata struct {
+			SkipReason string `json:"skipReason"`
+		} `json:"metadata"`
+	} `json:"test"`
+}
+
+type test struct {
+	name    string
+	suite   string
+	started int64
+	skip    string
+	errors  strings.Builder
+}
+
+func Parse(ctx context.Context, src io.Reader) iter.Seq2[*testresults.Result, error] {
+	return func(yield func(*testresults.Result, error) bool) {
+		var (
+			suites = make(map[int64]string)
+			tests  = make(map[int64]*test)
+		)
+
+		scanner := bufio.NewScanner(src)
+		scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+		for scanner.Scan() {
+			var (
+				evt event
+			)
+
+			line := scanner.Bytes()
+			if !bytes.HasPrefix(line, []byte("{")) {
+				continue
+			}
+
+			if err := json.Unmarshal(line, &evt); err != nil {
+				yield(nil, errorsx.Wrapf(err, "invalid event %s", line))
+				return
+			}
+
+			switch evt.Type {
+			case "suite":
+				suites[evt.Suite.ID] = evt.Suite.Path
+			case "testStart":
+				tests[evt.Test.ID] = &test{
+					name:    evt.Test.Name,
+					suite:   suites[evt.Test.SuiteID],
+					started: evt.Time,
+					skip:    evt.Test.Metadata.SkipReason,
+				}
+			case "error":
+				if t, ok := tests[evt.TestID]; ok {
+					t.errors.WriteString(evt.Error)
+					t.errors.WriteString("\n")
+				}
+			case "testDone":
+				t, ok := tests[evt.TestID]
+				delete(tests, evt.TestID)
+
+				// hidden tests are synthetic, i.e. loading the suite.
+				if !ok || (evt.Hidden && evt.Result == "success") {
+					continue
+				}
+
+				res := &testresults.Result{
+					Status:       events.TestResult_Passed,
+					Suite:        t.suite,
+					Name:         t.name,
+					Milliseconds: evt.Time - t.started,
+					Framework:    testresults.FrameworkDart,
+				}
+
+				switch {
+				case evt.Skipped:
+					res.Status = events.TestResult_Skipped
+					res.Message = testresults.Message(t.skip)
+				case evt.Result == "failure":
+					res.Status = events.TestResult_Failed
+					res.Message = testresults.Message(t.errors.String())
+				case evt.Result == "error":
+					res.Status = events.TestResult_Error
+					res.Message = testresults.Message(t.errors.String())
+				}
+
+				if !yield(res, nil) {
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+					yield(nil, ctx.Err())
+					return
+				default:
+				}
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			yield(nil, errorsx.Wrap(err, "failed to read dart test events"))
+		}
+	}
+}
+
+// Results parses the dart json reports within the directory.
+func Results(ctx context.Context, dir string) iter.Seq2[*testresults.Result, error] {
+	return testresults.Walk(ctx, dir, "*.json", Parse)
+}
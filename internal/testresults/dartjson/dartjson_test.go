@@ -0,0 +1,35 @@
+package dartjson_test
+
+import (
+	"testing"
+
+	"github.com/egdaemon/eg/internal/slicesx"
+	"github.com/egdaemon/eg/internal/testresults"
+	"github.com/egdaemon/eg/internal/testresults/dartjson"
+	"github.com/egdaemon/eg/internal/testx"
+	"github.com/egdaemon/eg/interp/events"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	ctx, done := testx.Context(t)
+	defer done()
+
+	var (
+		results []*testresults.Result
+	)
+
+	for res, err := range dartjson.Parse(ctx, testx.Read(".fixtures", "example1.json")) {
+		require.NoError(t, err)
+		results = append(results, res)
+	}
+
+	require.Len(t, results, 3)
+	require.Equal(t, []string{"calculator adds", "calculator divides", "calculator overflows"}, slicesx.MapTransform(func(r *testresults.Result) string { return r.Name }, results...))
+	require.Equal(t, []events.TestResult_Status{events.TestResult_Passed, events.TestResult_Failed, events.TestResult_Skipped}, slicesx.MapTransform(func(r *testresults.Result) events.TestResult_Status { return r.Status }, results...))
+	require.Equal(t, "test/calculator_test.dart", results[0].Suite)
+	require.Equal(t, int64(25), results[0].Milliseconds)
+	require.Equal(t, testresults.FrameworkDart, results[0].Framework)
+	require.Equal(t, "Expected: <2>\n  Actual: <3>", results[1].Message)
+	require.Equal(t, "not implemented", results[2].Message)
+}
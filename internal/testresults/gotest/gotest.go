@@ -0,0 +1,180 @@
+// Package gotest parses the test2json event stream emitted by go test -json.
+package gotest
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"iter"
+	"strings"
+	"time"
+
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/testresults"
+	"github.com/egdaemon/eg/interp/events"
+)
+
+// see go doc test2json for the details of the event stream.
+type event struct {
+	Time    time.Time
+	Action  string
+	Package string
+	Test    string
+	Elapsed float64 // seconds
+	Output  string
+}
+
+type key struct {
+	pkg  string
+	test string
+}
+
+func Parse(ctx context.Context, src io.Reader) iter.Seq2[*testresults.Result, error] {
+	return func(yield func(*testresults.Result, error) bool) {
+		var (
+			output = make(map[key]*strings.Builder)
+		)
+
+		scanner := bufio.NewScanner(src)
+		scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+		for scanner.Scan() {
+			var (
+				evt event
+			)
+
+			line := scanner.Bytes()
+			// build failures and the like are emitted as plain text.
+			if !bytes.HasPrefix(line, []byte("{")) {
+				continue
+			}
+
+			if err := json.Unmarshal(line, &evt); err != nil {
+				yield(nil, errorsx.Wrapf(err, "invalid event %s", line))
+				return
+			}
+
+			// package level events are summaries of the tests.
+			if evt.Test == "" {
+				continue
+			}
+
+			id := key{pkg: evt.Package, test: evt.Test}
+
+			var status events.TestResult_Status
+			switch evt.Action {
+			case "output":
+				buf, ok := output[id]
+				if !ok {
+					buf = &strings.Builder{}
+					output[id] = buf
+				}
+				buf.WriteString(evt.Output)
+				continue
+			case "pass":
+				status = events.TestResult_Passed
+			case "fail":
+				status = events.TestResult_Failed
+			case "skip":
+				status = events.TestResult_Skipped
+			default:
+				continue
+			}
+
+			res := &testresults.Result{
+				Status:       status,
+				Suite:        evt.Package,
+				Name:         evt.Test,
+				Milliseconds: time.Duration(evt.Elapsed * float64(time.Second)).Milliseconds(),
+				Framework:    testresults.FrameworkGo,
+			}
+
+			if buf, ok := output[id]; ok && status != events.TestResult_Passed {
+				res.Message = testresults.Message(buf.String())
+			}
+			delete(output, id)
+
+			if !yield(res, nil) {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				yield(nil, ctx.Err())
+				return
+			default:
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			yield(nil, errorsx.Wrap(err, "failed to read go test events"))
+		}
+	}
+}
+
+// Results parses the go test event streams within the directory.
+func Results(ctx context.Context, dir string) iter.Seq2[*testresults.Result, error] {
+	return testresults.Walk(ctx, dir, "*.json", Parse)
+}
+
+// Printer writes the output of the events to the destination, allowing
+// the human readable output of go test -json to be displayed while it executes.
+// lines that are not events are written as is. closing the printer writes the
+// trailing partial line, i.e. output interrupted before its newline.
+func Printer(dst io.Writer) io.WriteCloser {
+	return &printer{dst: dst}
+}
+
+type printer struct {
+	dst     io.Writer
+	pending []byte
+}
+
+func (t *printer) Write(b []byte) (int, error) {
+	t.pending = append(t.pending, b...)
+
+	for {
+		idx := bytes.IndexByte(t.pending, '\n')
+		if idx < 0 {
+			return len(b), nil
+		}
+
+		line := t.pending[:idx+1]
+		if err := t.print(line); err != nil {
+			return len(b), err
+		}
+
+		t.pending = t.pending[idx+1:]
+	}
+}
+
+func (t *printer) print(line []byte) (err error) {
+	var (
+		evt event
+	)
+
+	if !bytes.HasPrefix(line, []byte("{")) || json.Unmarshal(line, &evt) != nil {
+		_, err = t.dst.Write(line)
+		return err
+	}
+
+	if evt.Action != "output" {
+		return nil
+	}
+
+	_, err = io.WriteString(t.dst, evt.Output)
+	return err
+}
+
+// Close writes the pending partial line, the destination is left open.
+func (t *printer) Close() error {
+	if len(t.pending) == 0 {
+		return nil
+	}
+
+	line := t.pending
+	t.pending = nil
+	return t.print(line)
+}
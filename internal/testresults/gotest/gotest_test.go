@@ -0,0 +1,89 @@
+package gotest_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/egdaemon/eg/internal/testresults"
+	"github.com/egdaemon/eg/internal/testresults/gotest"
+	"github.com/egdaemon/eg/internal/testx"
+	"github.com/egdaemon/eg/interp/events"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	ctx, done := testx.Context(t)
+	defer done()
+
+	var (
+		results []*testresults.Result
+	)
+
+	for res, err := range gotest.Parse(ctx, testx.Read(".fixtures", "example1.json")) {
+		require.NoError(t, err)
+		results = append(results, res)
+	}
+
+	require.Len(t, results, 4)
+	require.Equal(t, "example.com/pkg", results[0].Suite)
+	require.Equal(t, "TestPass", results[0].Name)
+	require.Equal(t, events.TestResult_Passed, results[0].Status)
+	require.Equal(t, int64(120), results[0].Milliseconds)
+	require.Equal(t, testresults.FrameworkGo, results[0].Framework)
+	require.Empty(t, results[0].Message)
+
+	require.Equal(t, events.TestResult_Failed, results[1].Status)
+	require.Equal(t, int64(1500), results[1].Milliseconds)
+	require.Equal(t, "=== RUN   TestFail\n    pkg_test.go:12: expected 1 got 2\n--- FAIL: TestFail (1.50s)", results[1].Message)
+
+	require.Equal(t, events.TestResult_Skipped, results[2].Status)
+	require.Contains(t, results[2].Message, "requires docker")
+
+	require.Equal(t, "TestSubtests/nested", results[3].Name)
+}
+
+func TestPrinter(t *testing.T) {
+	var (
+		buf bytes.Buffer
+	)
+
+	w := gotest.Printer(&buf)
+	_, err := io.Copy(w, testx.Read(".fixtures", "example1.json"))
+	require.NoError(t, err)
+	_, err = io.WriteString(w, "# example.com/broken\nbuild failed\n")
+	require.NoError(t, err)
+
+	require.Equal(t, "=== RUN   TestPass\n--- PASS: TestPass (0.12s)\n=== RUN   TestFail\n    pkg_test.go:12: expected 1 got 2\n--- FAIL: TestFail (1.50s)\n=== RUN   TestSkip\n    pkg_test.go:20: requires docker\n--- SKIP: TestSkip (0.00s)\nFAIL\n# example.com/broken\nbuild failed\n", buf.String())
+}
+
+func TestPrinterClose(t *testing.T) {
+	t.Run("flushes the trailing partial line", func(t *testing.T) {
+		var (
+			buf bytes.Buffer
+		)
+
+		w := gotest.Printer(&buf)
+		_, err := io.WriteString(w, "build failed\nsignal: killed")
+		require.NoError(t, err)
+		require.Equal(t, "build failed\n", buf.String())
+
+		require.NoError(t, w.Close())
+		require.Equal(t, "build failed\nsignal: killed", buf.String())
+	})
+
+	t.Run("flushes a trailing partial event", func(t *testing.T) {
+		var (
+			buf bytes.Buffer
+		)
+
+		w := gotest.Printer(&buf)
+		_, err := io.WriteString(w, `{"Action":"output","Package":"example.com/pkg","Test":"TestPass","Output":"=== RUN   TestPass\n"}`)
+		require.NoError(t, err)
+		require.Empty(t, buf.String())
+
+		require.NoError(t, w.Close())
+		require.NoError(t, w.Close())
+		require.Equal(t, "=== RUN   TestPass\n", buf.String())
+	})
+}
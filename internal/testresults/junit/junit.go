@@ -0,0 +1,151 @@
+// Package junit parses JUnit XML reports, the de facto interchange format for test results.
+package junit
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"iter"
+	"strconv"
+	"strings"
+
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/stringsx"
+	"github.com/egdaemon/eg/internal/testresults"
+	"github.com/egdaemon/eg/interp/events"
+)
+
+type outcome struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func (t *outcome) String() string {
+	if t == nil {
+		return ""
+	}
+
+	return stringsx.FirstNonBlank(strings.TrimSpace(t.Text), t.Message)
+}
+
+type testcase struct {
+	Name      string   `xml:"name,attr"`
+	Classname string   `xml:"classname,attr"`
+	Time      string   `xml:"time,attr"` // seconds
+	Failure   *outcome `xml:"failure"`
+	Error     *outcome `xml:"error"`
+	Skipped   *outcome `xml:"skipped"`
+}
+
+func (t testcase) result(suite string) (_ *testresults.Result, err error) {
+	var (
+		seconds float64
+	)
+
+	if stringsx.Present(t.Time) {
+		// some producers include thousands separators.
+		if seconds, err = strconv.ParseFloat(strings.ReplaceAll(t.Time, ",", ""), 64); err != nil {
+			return nil, errorsx.Wrapf(err, "invalid time %s for %s", t.Time, t.Name)
+		}
+	}
+
+	res := &testresults.Result{
+		Status:       events.TestResult_Passed,
+		Suite:        stringsx.FirstNonBlank(t.Classname, suite),
+		Name:         t.Name,
+		Milliseconds: int64(seconds * 1000),
+		Framework:    testresults.FrameworkJUnit,
+	}
+
+	switch {
+	case t.Error != nil:
+		res.Status = events.TestResult_Error
+		res.Message = testresults.Message(t.Error.String())
+	case t.Failure != nil:
+		res.Status = events.TestResult_Failed
+		res.Message = testresults.Message(t.Failure.String())
+	case t.Skipped != nil:
+		res.Status = events.TestResult_Skipped
+		res.Message = testresults.Message(t.Skipped.String())
+	}
+
+	return res, nil
+}
+
+func Parse(ctx context.Context, src io.Reader) iter.Seq2[*testresults.Result, error] {
+	return func(yield func(*testresults.Result, error) bool) {
+		var (
+			// testsuites can be nested.
+			suites []string
+		)
+
+		decoder := xml.NewDecoder(src)
+
+		for {
+			tok, err := decoder.Token()
+			if errors.Is(err, io.EOF) {
+				return
+			} else if err != nil {
+				yield(nil, errorsx.Wrap(err, "failed to read junit report"))
+				return
+			}
+
+			switch elem := tok.(type) {
+			case xml.StartElement:
+				switch elem.Name.Local {
+				case "testsuite":
+					suites = append(suites, attr(elem, "name"))
+				case "testcase":
+					var (
+						tc testcase
+					)
+
+					if err = decoder.DecodeElement(&tc, &elem); err != nil {
+						yield(nil, errorsx.Wrap(err, "invalid testcase"))
+						return
+					}
+
+					res, err := tc.result(current(suites))
+					if !yield(res, err) || err != nil {
+						return
+					}
+
+					select {
+					case <-ctx.Done():
+						yield(nil, ctx.Err())
+						return
+					default:
+					}
+				}
+			case xml.EndElement:
+				if elem.Name.Local == "testsuite" && len(suites) > 0 {
+					suites = suites[:len(suites)-1]
+				}
+			}
+		}
+	}
+}
+
+// Results parses the junit reports within the directory.
+func Results(ctx context.Context, dir string) iter.Seq2[*testresults.Result, error] {
+	return testresults.Walk(ctx, dir, "*.xml", Parse)
+}
+
+func attr(elem xml.StartElement, name string) string {
+	for _, a := range elem.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+
+	return ""
+}
+
+func current(suites []string) string {
+	if len(suites) == 0 {
+		return ""
+	}
+
+	return suites[len(suites)-1]
+}
@@ -0,0 +1,47 @@
+package junit_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/egdaemon/eg/internal/slicesx"
+	"github.com/egdaemon/eg/internal/testresults"
+	"github.com/egdaemon/eg/internal/testresults/junit"
+	"github.com/egdaemon/eg/internal/testx"
+	"github.com/egdaemon/eg/interp/events"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	ctx, done := testx.Context(t)
+	defer done()
+
+	var (
+		results []*testresults.Result
+	)
+
+	for res, err := range junit.Parse(ctx, testx.Read(".fixtures", "example1.xml")) {
+		require.NoError(t, err)
+		results = append(results, res)
+	}
+
+	require.Len(t, results, 5)
+	require.Equal(t, []string{"com.example.CalculatorTest", "com.example.CalculatorTest", "com.example.CalculatorTest", "database", "integration"}, slicesx.MapTransform(func(r *testresults.Result) string { return r.Suite }, results...))
+	require.Equal(t, []events.TestResult_Status{events.TestResult_Passed, events.TestResult_Failed, events.TestResult_Skipped, events.TestResult_Error, events.TestResult_Passed}, slicesx.MapTransform(func(r *testresults.Result) events.TestResult_Status { return r.Status }, results...))
+
+	require.Equal(t, int64(250), results[0].Milliseconds)
+	require.Equal(t, testresults.FrameworkJUnit, results[0].Framework)
+	require.True(t, strings.HasPrefix(results[1].Message, "java.lang.AssertionError: expected 2 but was 3\n"))
+	require.Equal(t, "not implemented", results[2].Message)
+	require.Equal(t, int64(1250500), results[3].Milliseconds)
+	require.Equal(t, "connection refused", results[3].Message)
+}
+
+func TestParseInvalid(t *testing.T) {
+	ctx, done := testx.Context(t)
+	defer done()
+
+	for _, err := range junit.Parse(ctx, strings.NewReader(`<testsuite><testcase name="a" time="soon"/></testsuite>`)) {
+		require.Error(t, err)
+	}
+}
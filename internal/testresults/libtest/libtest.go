@@ -0,0 +1,148 @@
+// Package libtest parses the human readable output of the rust test harness as
+// emitted by cargo test. the machine readable formats of libtest require nightly
+// so the pretty format, including cargo's status lines, is parsed instead.
+// libtest does not report the duration of individual tests on stable.
+package libtest
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"iter"
+	"regexp"
+	"strings"
+
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/testresults"
+	"github.com/egdaemon/eg/interp/events"
+)
+
+var (
+	// e.g. `     Running unittests src/lib.rs (target/debug/deps/example-1a2b3c)`
+	running = regexp.MustCompile(`^\s*Running\s+(.*?)(?:\s+\([^)]*\))?\s*$`)
+	// e.g. `   Doc-tests example`
+	doctests = regexp.MustCompile(`^\s*(Doc-tests\s+.*?)\s*$`)
+	// e.g. `test tests::adds ... ok`
+	testline = regexp.MustCompile(`^test (.+?) \.\.\. (ok|FAILED|ignored)(?:, (.*))?$`)
+	// e.g. `---- tests::divides stdout ----`
+	failure = regexp.MustCompile(`^---- (.+?) std(?:out|err) ----$`)
+)
+
+const (
+	prefixSummary  = "test result:"
+	prefixFailures = "failures:"
+)
+
+func Parse(ctx context.Context, src io.Reader) iter.Seq2[*testresults.Result, error] {
+	return func(yield func(*testresults.Result, error) bool) {
+		var (
+			suite   string
+			pending []*testresults.Result
+			output  = make(map[string]*strings.Builder)
+			capture *strings.Builder
+		)
+
+		// failure output is printed after the tests complete, results are
+		// held until the summary of the suite.
+		flush := func() bool {
+			defer func() {
+				pending = pending[:0]
+				clear(output)
+				capture = nil
+			}()
+
+			for _, res := range pending {
+				if buf, ok := output[res.Name]; ok {
+					res.Message = testresults.Message(buf.String())
+				}
+
+				if !yield(res, nil) {
+					return false
+				}
+			}
+
+			return true
+		}
+
+		scanner := bufio.NewScanner(src)
+		scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+		for scanner.Scan() {
+			line := scanner.Text()
+
+			if m := running.FindStringSubmatch(line); m != nil {
+				suite = m[1]
+				continue
+			}
+
+			if m := doctests.FindStringSubmatch(line); m != nil {
+				suite = m[1]
+				continue
+			}
+
+			if strings.HasPrefix(line, prefixSummary) {
+				if !flush() {
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+					yield(nil, ctx.Err())
+					return
+				default:
+				}
+				continue
+			}
+
+			if m := testline.FindStringSubmatch(line); m != nil {
+				res := &testresults.Result{
+					Status:    events.TestResult_Passed,
+					Suite:     suite,
+					Name:      m[1],
+					Framework: testresults.FrameworkLibtest,
+				}
+
+				switch m[2] {
+				case "FAILED":
+					res.Status = events.TestResult_Failed
+				case "ignored":
+					res.Status = events.TestResult_Skipped
+					res.Message = testresults.Message(m[3])
+				}
+
+				pending = append(pending, res)
+				continue
+			}
+
+			if m := failure.FindStringSubmatch(line); m != nil {
+				capture = &strings.Builder{}
+				output[m[1]] = capture
+				continue
+			}
+
+			if strings.TrimSpace(line) == prefixFailures {
+				capture = nil
+				continue
+			}
+
+			if capture != nil {
+				capture.WriteString(line)
+				capture.WriteString("\n")
+			}
+		}
+
+		// the summary is missing when the harness is interrupted.
+		if !flush() {
+			return
+		}
+
+		if err := scanner.Err(); err != nil {
+			yield(nil, errorsx.Wrap(err, "failed to read libtest output"))
+		}
+	}
+}
+
+// Results parses the captured cargo test output within the directory.
+func Results(ctx context.Context, dir string) iter.Seq2[*testresults.Result, error] {
+	return testresults.Walk(ctx, dir, "*.log", Parse)
+}
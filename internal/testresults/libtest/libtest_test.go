@@ -0,0 +1,34 @@
+package libtest_test
+
+import (
+	"testing"
+
+	"github.com/egdaemon/eg/internal/slicesx"
+	"github.com/egdaemon/eg/internal/testresults"
+	"github.com/egdaemon/eg/internal/testresults/libtest"
+	"github.com/egdaemon/eg/internal/testx"
+	"github.com/egdaemon/eg/interp/events"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	ctx, done := testx.Context(t)
+	defer done()
+
+	var (
+		results []*testresults.Result
+	)
+
+	for res, err := range libtest.Parse(ctx, testx.Read(".fixtures", "example1.log")) {
+		require.NoError(t, err)
+		results = append(results, res)
+	}
+
+	require.Len(t, results, 6)
+	require.Equal(t, []string{"tests::adds", "tests::divides", "tests::overflows", "tests::network", "connects", "src/lib.rs - add (line 3)"}, slicesx.MapTransform(func(r *testresults.Result) string { return r.Name }, results...))
+	require.Equal(t, []string{"unittests src/lib.rs", "unittests src/lib.rs", "unittests src/lib.rs", "unittests src/lib.rs", "tests/integration.rs", "Doc-tests example"}, slicesx.MapTransform(func(r *testresults.Result) string { return r.Suite }, results...))
+	require.Equal(t, []events.TestResult_Status{events.TestResult_Passed, events.TestResult_Failed, events.TestResult_Skipped, events.TestResult_Skipped, events.TestResult_Passed, events.TestResult_Passed}, slicesx.MapTransform(func(r *testresults.Result) events.TestResult_Status { return r.Status }, results...))
+	require.Equal(t, "thread 'tests::divides' panicked at src/lib.rs:21:9:\nassertion `left == right` failed\n  left: 3\n right: 2\nnote: run with `RUST_BACKTRACE=1` environment variable to display a backtrace", results[1].Message)
+	require.Equal(t, "requires network", results[3].Message)
+	require.Equal(t, testresults.FrameworkLibtest, results[0].Framework)
+}
@@ -0,0 +1,149 @@
+// Package tap parses the Test Anything Protocol, only the top level test points
+// are reported; subtests are expected to be summarized by their parent.
+package tap
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/testresults"
+	"github.com/egdaemon/eg/interp/events"
+)
+
+var (
+	testpoint = regexp.MustCompile(`^(not ok|ok)\b\s*(\d+)?\s*(?:-\s*)?((?:\\.|[^#\\])*?)\s*(?:#\s*(.*))?$`)
+	unescape  = strings.NewReplacer(`\#`, `#`, `\\`, `\`)
+	directive = regexp.MustCompile(`(?i)^(skip|todo)\S*\s*(.*)$`)
+)
+
+const (
+	prefixBailout  = "Bail out!"
+	prefixYAML     = "---"
+	prefixYAMLDone = "..."
+)
+
+// YAML diagnostics blocks are parsed naively, only the keys commonly
+// emitted by producers are considered.
+func diagnostic(res *testresults.Result, line string) {
+	k, v, ok := strings.Cut(strings.TrimSpace(line), ":")
+	if !ok {
+		return
+	}
+
+	v = strings.Trim(strings.TrimSpace(v), `'"`)
+
+	switch strings.TrimSpace(k) {
+	case "message":
+		res.Message = testresults.Message(v)
+	case "duration_ms":
+		if ms, err := strconv.ParseFloat(v, 64); err == nil {
+			res.Milliseconds = int64(ms)
+		}
+	}
+}
+
+func Parse(ctx context.Context, src io.Reader) iter.Seq2[*testresults.Result, error] {
+	return func(yield func(*testresults.Result, error) bool) {
+		var (
+			pending *testresults.Result
+			yaml    bool
+		)
+
+		flush := func() bool {
+			if pending == nil {
+				return true
+			}
+
+			res := pending
+			pending = nil
+			return yield(res, nil)
+		}
+
+		scanner := bufio.NewScanner(src)
+
+		for scanner.Scan() {
+			line := scanner.Text()
+			indented := strings.TrimLeft(line, " \t") != line
+
+			if indented {
+				trimmed := strings.TrimSpace(line)
+				switch {
+				case pending != nil && trimmed == prefixYAML:
+					yaml = true
+				case trimmed == prefixYAMLDone:
+					yaml = false
+				case yaml && pending != nil:
+					diagnostic(pending, trimmed)
+				}
+
+				// indented lines otherwise belong to subtests.
+				continue
+			}
+
+			yaml = false
+
+			if strings.HasPrefix(line, prefixBailout) {
+				if flush() {
+					yield(nil, fmt.Errorf("tap bailed out: %s", strings.TrimSpace(strings.TrimPrefix(line, prefixBailout))))
+				}
+				return
+			}
+
+			matches := testpoint.FindStringSubmatch(line)
+			if matches == nil {
+				continue
+			}
+
+			if !flush() {
+				return
+			}
+
+			pending = &testresults.Result{
+				Status:    events.TestResult_Passed,
+				Name:      unescape.Replace(strings.TrimSpace(matches[3])),
+				Framework: testresults.FrameworkTAP,
+			}
+
+			if pending.Name == "" {
+				pending.Name = matches[2]
+			}
+
+			if matches[1] == "not ok" {
+				pending.Status = events.TestResult_Failed
+			}
+
+			// skipped and todo tests do not count as failures.
+			if d := directive.FindStringSubmatch(strings.TrimSpace(matches[4])); d != nil {
+				pending.Status = events.TestResult_Skipped
+				pending.Message = testresults.Message(d[2])
+			}
+
+			select {
+			case <-ctx.Done():
+				yield(nil, ctx.Err())
+				return
+			default:
+			}
+		}
+
+		if !flush() {
+			return
+		}
+
+		if err := scanner.Err(); err != nil {
+			yield(nil, errorsx.Wrap(err, "failed to read tap"))
+		}
+	}
+}
+
+// Results parses the tap files within the directory.
+func Results(ctx context.Context, dir string) iter.Seq2[*testresults.Result, error] {
+	return testresults.Walk(ctx, dir, "*.tap", Parse)
+}
@@ -0,0 +1,57 @@
+package tap_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/egdaemon/eg/internal/slicesx"
+	"github.com/egdaemon/eg/internal/testresults"
+	"github.com/egdaemon/eg/internal/testresults/tap"
+	"github.com/egdaemon/eg/internal/testx"
+	"github.com/egdaemon/eg/interp/events"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	ctx, done := testx.Context(t)
+	defer done()
+
+	var (
+		results []*testresults.Result
+	)
+
+	for res, err := range tap.Parse(ctx, testx.Read(".fixtures", "example1.tap")) {
+		require.NoError(t, err)
+		results = append(results, res)
+	}
+
+	require.Len(t, results, 6)
+	require.Equal(t, []string{"parses input", "First line of the input valid", "Read the rest of the file", "Summarized correctly", "5", "escaped # hash"}, slicesx.MapTransform(func(r *testresults.Result) string { return r.Name }, results...))
+	require.Equal(t, []events.TestResult_Status{events.TestResult_Passed, events.TestResult_Failed, events.TestResult_Skipped, events.TestResult_Skipped, events.TestResult_Passed, events.TestResult_Failed}, slicesx.MapTransform(func(r *testresults.Result) events.TestResult_Status { return r.Status }, results...))
+	require.Equal(t, int64(12), results[0].Milliseconds)
+	require.Equal(t, "First line invalid", results[1].Message)
+	require.Equal(t, int64(3), results[1].Milliseconds)
+	require.Equal(t, "requires network", results[2].Message)
+	require.Equal(t, testresults.FrameworkTAP, results[5].Framework)
+}
+
+func TestParseBailout(t *testing.T) {
+	ctx, done := testx.Context(t)
+	defer done()
+
+	var (
+		results []*testresults.Result
+		failed  error
+	)
+
+	for res, err := range tap.Parse(ctx, strings.NewReader("1..2\nok 1 - first\nBail out! database unavailable\nok 2 - second\n")) {
+		if err != nil {
+			failed = err
+			continue
+		}
+		results = append(results, res)
+	}
+
+	require.Len(t, results, 1)
+	require.EqualError(t, failed, "tap bailed out: database unavailable")
+}
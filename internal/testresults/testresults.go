@@ -0,0 +1,87 @@
+// Package testresults provides the shared types for parsing the results of test suites
+// into events, the individual formats are implemented by the sub packages.
+package testresults
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"iter"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/interp/events"
+)
+
+const (
+	FrameworkGo      = "go"
+	FrameworkJUnit   = "junit"
+	FrameworkTAP     = "tap"
+	FrameworkDart    = "dart"
+	FrameworkLibtest = "libtest"
+)
+
+// maximum number of bytes retained from failure messages.
+const messagelimit = 4 * 1024
+
+type Result = events.TestResult
+
+// Parser decodes the test results from the provided reader.
+type Parser func(ctx context.Context, src io.Reader) iter.Seq2[*Result, error]
+
+// Message normalizes failure output, retaining the tail of large messages
+// since that is typically where the failure is reported.
+func Message(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= messagelimit {
+		return s
+	}
+
+	return s[len(s)-messagelimit:]
+}
+
+// Walk the directory parsing every file matching the pattern, results without
+// a suite default to the path of the file relative to the directory.
+func Walk(ctx context.Context, dir string, pattern string, parse Parser) iter.Seq2[*Result, error] {
+	return func(yield func(*Result, error) bool) {
+		err := fs.WalkDir(os.DirFS(dir), ".", func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return errorsx.Wrapf(err, "failed: %s", filepath.Join(dir, path))
+			}
+
+			if d.IsDir() {
+				return nil
+			}
+
+			if matched, err := filepath.Match(pattern, d.Name()); err != nil {
+				return errorsx.Wrapf(err, "invalid pattern: %s", pattern)
+			} else if !matched {
+				return nil
+			}
+
+			src, err := os.Open(filepath.Join(dir, path))
+			if err != nil {
+				return errorsx.Wrapf(err, "unable to open test results: %s", path)
+			}
+			defer src.Close()
+
+			for res, err := range parse(ctx, src) {
+				if res != nil && res.Suite == "" {
+					res.Suite = path
+				}
+
+				if !yield(res, err) {
+					return fs.SkipAll
+				}
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			yield(nil, err)
+		}
+	}
+}
@@ -42,6 +42,10 @@ func WarmCacheFromDirectoryTree(ctx context.Context, root string, cache wazero.C
 			return nil
 		}
 
+		if filepath.Ext(path) != ".wasm" { // i.e.) python modules are interpreted.
+			return nil
+		}
+
 		tracex.Println("compiling module initiated", path)
 		defer tracex.Println("compiling module completed", path)
 
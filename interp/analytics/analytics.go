@@ -0,0 +1,175 @@
+// Package analytics queries the analytics databases recorded by runs. local runs archive
+// their database allowing operations to be compared across runs.
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/fsx"
+	"github.com/egdaemon/eg/internal/iox"
+	"github.com/egdaemon/eg/internal/userx"
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	// name of the analytics database within the runtime directory.
+	Database = "analytics.db"
+	// number of archived runs retained.
+	retain = 128
+)
+
+// Directory containing the archived analytics databases.
+func Directory(rel ...string) string {
+	return userx.DefaultCacheDirectory("analytics", filepath.Join(rel...))
+}
+
+// Archive the analytics database of the run, pruning the oldest runs beyond the retention limit.
+// missing databases are ignored, i.e. the run failed before it started.
+func Archive(src string, runid string) (err error) {
+	if !fsx.FileExists(src) {
+		return nil
+	}
+
+	dir := Directory()
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		return errorsx.Wrapf(err, "unable to create analytics directory %s", dir)
+	}
+
+	dst := filepath.Join(dir, fmt.Sprintf("%s.db", runid))
+	if err = iox.Copy(src, dst); err != nil {
+		return errorsx.Wrap(err, "unable to archive analytics database")
+	}
+
+	// uncheckpointed changes are retained within the write ahead log.
+	if wal := src + ".wal"; fsx.FileExists(wal) {
+		if err = iox.Copy(wal, dst+".wal"); err != nil {
+			return errorsx.Wrap(err, "unable to archive analytics write ahead log")
+		}
+	}
+
+	runs, err := Runs()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range runs[:max(len(runs)-retain, 0)] {
+		path := filepath.Join(dir, fmt.Sprintf("%s.db", id))
+		err = errorsx.Compact(err, os.Remove(path), errorsx.Ignore(os.Remove(path+".wal"), fs.ErrNotExist))
+
+		// files archived alongside the database, i.e. the run's provenance.
+		siblings, _ := filepath.Glob(filepath.Join(dir, fmt.Sprintf("%s.*", id)))
+		for _, sibling := range siblings {
+			err = errorsx.Compact(err, errorsx.Ignore(os.Remove(sibling), fs.ErrNotExist))
+		}
+	}
+
+	return errorsx.Wrap(err, "unable to prune archived analytics")
+}
+
+// Runs returns the identifiers of the archived runs, oldest first.
+func Runs() (runs []string, err error) {
+	entries, err := os.ReadDir(Directory())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errorsx.Wrap(err, "unable to read analytics directory")
+	}
+
+	for _, e := range entries {
+		if id, ok := strings.CutSuffix(e.Name(), ".db"); ok && !e.IsDir() {
+			runs = append(runs, id)
+		}
+	}
+
+	// run identifiers are v7 uuids which sort by creation time.
+	slices.Sort(runs)
+
+	return runs, nil
+}
+
+// Since filters the runs to those initiated at or after the provided time. runs
+// without an embedded timestamp are retained.
+func Since(ts time.Time, runs ...string) (filtered []string) {
+	for _, id := range runs {
+		if created, err := uuid.TimestampFromV7(uuid.FromStringOrNil(id)); err == nil && errorsx.Zero(created.Time()).Before(ts) {
+			continue
+		}
+
+		filtered = append(filtered, id)
+	}
+
+	return filtered
+}
+
+// Resolve the analytics database from a path to a database, a runtime directory or an archived run identifier.
+func Resolve(ref string) (string, error) {
+	if fsx.FileExists(ref) {
+		return ref, nil
+	}
+
+	if path := filepath.Join(ref, Database); fsx.FileExists(path) {
+		return path, nil
+	}
+
+	if path := Directory(fmt.Sprintf("%s.db", ref)); fsx.FileExists(path) {
+		return path, nil
+	}
+
+	return "", fmt.Errorf("unable to locate analytics for %s, expected a database, runtime directory or archived run", ref)
+}
+
+// Open the analytics database read only.
+func Open(path string) (*sql.DB, error) {
+	db, err := sql.Open("duckdb", fmt.Sprintf("%s?access_mode=READ_ONLY", path))
+	if err != nil {
+		return nil, errorsx.Wrapf(err, "unable to open analytics %s", path)
+	}
+
+	return db, nil
+}
+
+// Attach the archived runs to an in memory database exposing their operations
+// through the operations view, the run column identifies the originating run.
+func Attach(ctx context.Context, runs ...string) (_ *sql.DB, err error) {
+	var (
+		db      *sql.DB
+		selects []string
+	)
+
+	if db, err = sql.Open("duckdb", ""); err != nil {
+		return nil, errorsx.Wrap(err, "unable to open in memory database")
+	}
+
+	// attached databases are scoped to the connection.
+	db.SetMaxOpenConns(1)
+
+	for i, id := range runs {
+		alias := fmt.Sprintf("r%d", i)
+		path := strings.ReplaceAll(Directory(fmt.Sprintf("%s.db", id)), "'", "''")
+		if _, err = db.ExecContext(ctx, fmt.Sprintf("ATTACH '%s' AS %s (READ_ONLY)", path, alias)); err != nil {
+			return nil, errorsx.Compact(errorsx.Wrapf(err, "unable to attach run %s", id), db.Close())
+		}
+
+		selects = append(selects, fmt.Sprintf("SELECT '%s' AS run, * FROM %s.\"eg.metrics.operation\"", id, alias))
+	}
+
+	if len(selects) == 0 {
+		selects = append(selects, "SELECT NULL::TEXT AS run, NULL::TEXT AS name, NULL::TIMESTAMP AS ts, NULL::TEXT AS module, NULL::TEXT AS op, NULL::TEXT AS state, NULL::INTERVAL AS milliseconds WHERE false")
+	}
+
+	if _, err = db.ExecContext(ctx, fmt.Sprintf("CREATE TEMP VIEW operations AS %s", strings.Join(selects, " UNION ALL BY NAME "))); err != nil {
+		return nil, errorsx.Compact(errorsx.Wrap(err, "unable to create operations view"), db.Close())
+	}
+
+	return db, nil
+}
@@ -0,0 +1,195 @@
+package analytics_test
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/duckdb/duckdb-go/v2"
+	"github.com/egdaemon/eg/internal/testx"
+	"github.com/egdaemon/eg/interp/analytics"
+	"github.com/egdaemon/eg/interp/events"
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// run identifier initiated at the provided time.
+func runid(ts time.Time) string {
+	return uuid.Must(uuid.NewV7AtTime(ts)).String()
+}
+
+// record the operations into the analytics database at the path.
+func record(t *testing.T, path string, ops ...*events.Op) {
+	db, err := sql.Open("duckdb", path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, events.PrepareDB(t.Context(), db))
+	for _, op := range ops {
+		require.NoError(t, events.RecordMetric(t.Context(), db, events.NewOp(op)))
+	}
+}
+
+func TestArchive(t *testing.T) {
+	t.Run("missing databases are ignored", func(t *testing.T) {
+		t.Setenv("CACHE_DIRECTORY", t.TempDir())
+
+		require.NoError(t, analytics.Archive(filepath.Join(t.TempDir(), analytics.Database), runid(time.Now())))
+		require.NoDirExists(t, analytics.Directory())
+	})
+
+	t.Run("copies the database and its write ahead log", func(t *testing.T) {
+		t.Setenv("CACHE_DIRECTORY", t.TempDir())
+		src := filepath.Join(t.TempDir(), analytics.Database)
+		id := runid(time.Now())
+
+		require.NoError(t, os.WriteFile(src, []byte("database"), 0600))
+		require.NoError(t, os.WriteFile(src+".wal", []byte("wal"), 0600))
+		require.NoError(t, analytics.Archive(src, id))
+
+		require.Equal(t, "database", testx.ReadString(analytics.Directory(fmt.Sprintf("%s.db", id))))
+		require.Equal(t, "wal", testx.ReadString(analytics.Directory(fmt.Sprintf("%s.db.wal", id))))
+	})
+
+	t.Run("prunes the oldest runs beyond the retention limit", func(t *testing.T) {
+		t.Setenv("CACHE_DIRECTORY", t.TempDir())
+		require.NoError(t, os.MkdirAll(analytics.Directory(), 0700))
+
+		now := time.Now()
+		retained := make([]string, 0, 128)
+		oldest := runid(now.Add(-200 * time.Hour))
+		for _, name := range []string{"db", "db.wal", "provenance.json"} {
+			require.NoError(t, os.WriteFile(analytics.Directory(fmt.Sprintf("%s.%s", oldest, name)), nil, 0600))
+		}
+
+		for i := range 127 {
+			id := runid(now.Add(-time.Duration(127-i) * time.Hour))
+			require.NoError(t, os.WriteFile(analytics.Directory(fmt.Sprintf("%s.db", id)), nil, 0600))
+			retained = append(retained, id)
+		}
+
+		src := filepath.Join(t.TempDir(), analytics.Database)
+		require.NoError(t, os.WriteFile(src, nil, 0600))
+		id := runid(now)
+		require.NoError(t, analytics.Archive(src, id))
+
+		runs, err := analytics.Runs()
+		require.NoError(t, err)
+		require.Equal(t, append(retained, id), runs)
+
+		matches, err := filepath.Glob(analytics.Directory(fmt.Sprintf("%s.*", oldest)))
+		require.NoError(t, err)
+		require.Empty(t, matches)
+	})
+}
+
+func TestRuns(t *testing.T) {
+	t.Run("missing directory has no runs", func(t *testing.T) {
+		t.Setenv("CACHE_DIRECTORY", t.TempDir())
+
+		runs, err := analytics.Runs()
+		require.NoError(t, err)
+		require.Empty(t, runs)
+	})
+
+	t.Run("oldest first ignoring other files", func(t *testing.T) {
+		t.Setenv("CACHE_DIRECTORY", t.TempDir())
+		require.NoError(t, os.MkdirAll(analytics.Directory("directory.db"), 0700))
+
+		now := time.Now()
+		recent, old := runid(now), runid(now.Add(-time.Hour))
+		for _, name := range []string{recent + ".db", old + ".db", old + ".db.wal", old + ".provenance.json"} {
+			require.NoError(t, os.WriteFile(analytics.Directory(name), nil, 0600))
+		}
+
+		runs, err := analytics.Runs()
+		require.NoError(t, err)
+		require.Equal(t, []string{old, recent}, runs)
+	})
+}
+
+func TestSince(t *testing.T) {
+	old := uuid.Must(uuid.NewV7AtTime(time.Now().Add(-48 * time.Hour))).String()
+	recent := uuid.Must(uuid.NewV7()).String()
+	require.Equal(t, []string{recent, "custom"}, analytics.Since(time.Now().Add(-time.Hour), old, recent, "custom"))
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv("CACHE_DIRECTORY", t.TempDir())
+	require.NoError(t, os.MkdirAll(analytics.Directory(), 0700))
+
+	runtimedir := t.TempDir()
+	database := filepath.Join(runtimedir, analytics.Database)
+	require.NoError(t, os.WriteFile(database, nil, 0600))
+
+	id := runid(time.Now())
+	archived := analytics.Directory(fmt.Sprintf("%s.db", id))
+	require.NoError(t, os.WriteFile(archived, nil, 0600))
+
+	t.Run("database", func(t *testing.T) {
+		require.Equal(t, database, testx.MustT(analytics.Resolve(database))(t))
+	})
+
+	t.Run("runtime directory", func(t *testing.T) {
+		require.Equal(t, database, testx.MustT(analytics.Resolve(runtimedir))(t))
+	})
+
+	t.Run("archived run", func(t *testing.T) {
+		require.Equal(t, archived, testx.MustT(analytics.Resolve(id))(t))
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := analytics.Resolve(runid(time.Now()))
+		require.Error(t, err)
+	})
+}
+
+func TestAttach(t *testing.T) {
+	t.Run("without runs", func(t *testing.T) {
+		t.Setenv("CACHE_DIRECTORY", t.TempDir())
+
+		db, err := analytics.Attach(t.Context())
+		require.NoError(t, err)
+		defer db.Close()
+
+		table, err := analytics.Query(t.Context(), db, "SELECT run, name FROM operations")
+		require.NoError(t, err)
+		require.Empty(t, table.Rows)
+	})
+
+	t.Run("operations are identified by their run", func(t *testing.T) {
+		t.Setenv("CACHE_DIRECTORY", t.TempDir())
+		require.NoError(t, os.MkdirAll(analytics.Directory(), 0700))
+
+		now := time.Now()
+		first, second := runid(now.Add(-time.Hour)), runid(now)
+		record(t, analytics.Directory(fmt.Sprintf("%s.db", first)), &events.Op{State: events.Op_Completed, Name: "build", Module: "main", Op: "1", Milliseconds: 1000})
+		record(t, analytics.Directory(fmt.Sprintf("%s.db", second)),
+			&events.Op{State: events.Op_Completed, Name: "build", Module: "main", Op: "1", Milliseconds: 2000},
+			&events.Op{State: events.Op_Error, Name: "test", Module: "main", Op: "2", Milliseconds: 500},
+		)
+
+		db, err := analytics.Attach(t.Context(), first, second)
+		require.NoError(t, err)
+		defer db.Close()
+
+		table, err := analytics.Query(t.Context(), db, "SELECT run, name, state, round(epoch(milliseconds) * 1000)::BIGINT AS milliseconds FROM operations ORDER BY run, name")
+		require.NoError(t, err)
+		require.Equal(t, []string{"run", "name", "state", "milliseconds"}, table.Columns)
+		require.Equal(t, [][]any{
+			{first, "build", "Completed", int64(1000)},
+			{second, "build", "Completed", int64(2000)},
+			{second, "test", "Error", int64(500)},
+		}, table.Rows)
+	})
+
+	t.Run("missing runs fail", func(t *testing.T) {
+		t.Setenv("CACHE_DIRECTORY", t.TempDir())
+
+		_, err := analytics.Attach(t.Context(), runid(time.Now()))
+		require.Error(t, err)
+	})
+}
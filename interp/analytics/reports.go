@@ -0,0 +1,103 @@
+package analytics
+
+import (
+	"context"
+	"time"
+
+	"github.com/egdaemon/eg/internal/sqlx"
+)
+
+// Tables lists the tables recorded within the analytics database.
+func Tables(ctx context.Context, q sqlx.Queryer) (Table, error) {
+	return Query(ctx, q, "SELECT table_name AS name FROM information_schema.tables ORDER BY table_name")
+}
+
+// Slowest operations of the run.
+func Slowest(ctx context.Context, q sqlx.Queryer, limit int) (Table, error) {
+	return Query(
+		ctx,
+		q,
+		"SELECT name, module, state, round(epoch(milliseconds) * 1000)::BIGINT AS milliseconds, ts FROM 'eg.metrics.operation' ORDER BY milliseconds DESC LIMIT ?",
+		limit,
+	)
+}
+
+// Coverage recorded by the run per path.
+func Coverage(ctx context.Context, q sqlx.Queryer) (Table, error) {
+	return Query(ctx, q, "SELECT path, statements, branches FROM 'eg.metrics.coverage' ORDER BY path")
+}
+
+// Uncovered lines recorded by the run per path, compacted into ranges. lines reported
+// multiple times (i.e. by separate test binaries) are covered when any report hit them.
+func Uncovered(ctx context.Context, q sqlx.Queryer, limit int) (Table, error) {
+	return Query(
+		ctx,
+		q,
+		`WITH lines AS (
+			SELECT path, line::BIGINT AS line, max(hits) AS hits
+			FROM 'eg.metrics.coverage.lines'
+			GROUP BY path, line
+		), islands AS (
+			SELECT path, line, line - row_number() OVER (PARTITION BY path ORDER BY line) AS island
+			FROM lines
+			WHERE hits = 0
+		), ranges AS (
+			SELECT path, min(line) AS start, max(line) AS finish, count(*) AS n
+			FROM islands
+			GROUP BY path, island
+		)
+		SELECT
+			path,
+			sum(n)::BIGINT AS uncovered,
+			string_agg(CASE WHEN start = finish THEN start::TEXT ELSE start::TEXT || '-' || finish::TEXT END, ',' ORDER BY start) AS lines
+		FROM ranges
+		GROUP BY path
+		ORDER BY uncovered DESC, path
+		LIMIT ?`,
+		limit,
+	)
+}
+
+// Tests recorded by the run, failures first followed by the slowest tests.
+func Tests(ctx context.Context, q sqlx.Queryer, limit int) (Table, error) {
+	return Query(
+		ctx,
+		q,
+		"SELECT suite, name, status, round(epoch(milliseconds) * 1000)::BIGINT AS milliseconds, framework FROM 'eg.metrics.tests' ORDER BY status IN ('Failed', 'Error') DESC, milliseconds DESC, suite, name LIMIT ?",
+		limit,
+	)
+}
+
+// Metric series of the custom metric with the given name.
+func Metric(ctx context.Context, q sqlx.Queryer, name string) (Table, error) {
+	return Query(ctx, q, "SELECT ts, metric::TEXT AS metric FROM 'eg.metrics.custom' WHERE name = ? ORDER BY ts", name)
+}
+
+// Trends compares the duration of completed operations between the earliest and latest runs
+// since the provided time, ordered by the operations that slowed down the most. requires the
+// operations view, see Attach.
+func Trends(ctx context.Context, q sqlx.Queryer, since time.Time, limit int) (Table, error) {
+	return Query(
+		ctx,
+		q,
+		`WITH runs AS (
+			SELECT run, name, min(ts) AS ts, avg(epoch(milliseconds) * 1000) AS milliseconds
+			FROM operations
+			WHERE state = 'Completed' AND ts >= ?
+			GROUP BY run, name
+		)
+		SELECT
+			name,
+			count(*) AS runs,
+			round(arg_min(milliseconds, ts))::BIGINT AS earliest,
+			round(arg_max(milliseconds, ts))::BIGINT AS latest,
+			round(arg_max(milliseconds, ts) - arg_min(milliseconds, ts))::BIGINT AS change,
+			round(avg(milliseconds))::BIGINT AS average
+		FROM runs
+		GROUP BY name
+		ORDER BY change DESC, name
+		LIMIT ?`,
+		since.UTC(),
+		limit,
+	)
+}
@@ -0,0 +1,178 @@
+package analytics
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/sqlx"
+)
+
+const (
+	FormatTable = "table"
+	FormatCSV   = "csv"
+	FormatJSON  = "json"
+)
+
+// Table of results returned by a query.
+type Table struct {
+	Columns []string
+	Rows    [][]any
+}
+
+// Query the database returning the results as a table.
+func Query(ctx context.Context, q sqlx.Queryer, query string, args ...any) (t Table, err error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return t, errorsx.Wrap(err, "query failed")
+	}
+	defer rows.Close()
+
+	if t.Columns, err = rows.Columns(); err != nil {
+		return t, errorsx.Wrap(err, "unable to retrieve columns")
+	}
+
+	for rows.Next() {
+		var (
+			values = make([]any, len(t.Columns))
+			dst    = make([]any, len(t.Columns))
+		)
+
+		for i := range values {
+			dst[i] = &values[i]
+		}
+
+		if err = rows.Scan(dst...); err != nil {
+			return t, errorsx.Wrap(err, "unable to scan row")
+		}
+
+		for i, v := range values {
+			values[i] = normalize(v)
+		}
+
+		t.Rows = append(t.Rows, values)
+	}
+
+	return t, errorsx.Wrap(rows.Err(), "unable to read rows")
+}
+
+// normalize driver specific values into values that can be rendered in every format.
+func normalize(v any) any {
+	switch v := v.(type) {
+	case nil, bool, string, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Render the table in the given format.
+func Render(w io.Writer, format string, t Table) error {
+	switch format {
+	case FormatCSV:
+		return CSV(w, t)
+	case FormatJSON:
+		return JSON(w, t)
+	default:
+		return Text(w, t)
+	}
+}
+
+// Text renders the table as aligned columns.
+func Text(w io.Writer, t Table) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, strings.Join(t.Columns, "\t")); err != nil {
+		return err
+	}
+
+	for _, row := range t.Rows {
+		if _, err := fmt.Fprintln(tw, strings.Join(cells(row), "\t")); err != nil {
+			return err
+		}
+	}
+
+	return tw.Flush()
+}
+
+// CSV renders the table with a header row.
+func CSV(w io.Writer, t Table) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(t.Columns); err != nil {
+		return err
+	}
+
+	for _, row := range t.Rows {
+		if err := cw.Write(cells(row)); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// JSON renders the table as an array of objects, preserving the order of the columns.
+func JSON(w io.Writer, t Table) error {
+	var b strings.Builder
+
+	b.WriteString("[")
+	for i, row := range t.Rows {
+		if i > 0 {
+			b.WriteString(",")
+		}
+
+		b.WriteString("\n  {")
+		for j, v := range row {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+
+			k, err := json.Marshal(t.Columns[j])
+			if err != nil {
+				return err
+			}
+
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				return errorsx.Wrapf(err, "unable to encode column %s", t.Columns[j])
+			}
+
+			fmt.Fprintf(&b, "%s: %s", k, encoded)
+		}
+		b.WriteString("}")
+	}
+
+	if len(t.Rows) > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString("]\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func cells(row []any) []string {
+	r := make([]string, 0, len(row))
+	for _, v := range row {
+		if v == nil {
+			r = append(r, "")
+			continue
+		}
+
+		r = append(r, fmt.Sprint(v))
+	}
+
+	return r
+}
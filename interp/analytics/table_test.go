@@ -0,0 +1,52 @@
+package analytics_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/egdaemon/eg/interp/analytics"
+	"github.com/stretchr/testify/require"
+)
+
+func example() analytics.Table {
+	return analytics.Table{
+		Columns: []string{"name", "milliseconds", "module"},
+		Rows: [][]any{
+			{"main.build", int64(1200), "main"},
+			{"main.test, integration", int64(800), nil},
+		},
+	}
+}
+
+func TestRender(t *testing.T) {
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, analytics.Render(&buf, analytics.FormatTable, example()))
+		require.Equal(t, "name                    milliseconds  module\nmain.build              1200          main\nmain.test, integration  800           \n", buf.String())
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, analytics.Render(&buf, analytics.FormatCSV, example()))
+		require.Equal(t, "name,milliseconds,module\nmain.build,1200,main\n\"main.test, integration\",800,\n", buf.String())
+	})
+
+	t.Run("json preserves column order", func(t *testing.T) {
+		var (
+			buf     bytes.Buffer
+			decoded []map[string]any
+		)
+		require.NoError(t, analytics.Render(&buf, analytics.FormatJSON, example()))
+		require.Contains(t, buf.String(), `{"name": "main.build", "milliseconds": 1200, "module": "main"}`)
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		require.Len(t, decoded, 2)
+		require.Nil(t, decoded[1]["module"])
+	})
+
+	t.Run("json empty", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, analytics.Render(&buf, analytics.FormatJSON, analytics.Table{Columns: []string{"name"}}))
+		require.Equal(t, "[]\n", buf.String())
+	})
+}
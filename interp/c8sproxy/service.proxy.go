@@ -15,11 +15,15 @@ import (
 	"github.com/egdaemon/eg/internal/execx"
 	"github.com/egdaemon/eg/internal/fsx"
 	"github.com/egdaemon/eg/internal/langx"
+	"github.com/egdaemon/eg/internal/netpolicy"
 	"github.com/egdaemon/eg/internal/podmanx"
+	"github.com/egdaemon/eg/internal/redactx"
 	"github.com/egdaemon/eg/internal/slicesx"
 	"github.com/egdaemon/eg/internal/stringsx"
 	"github.com/egdaemon/eg/interp/c8s"
+	"github.com/egdaemon/eg/interp/provenance"
 	"github.com/egdaemon/eg/workspaces"
+	"go.podman.io/podman/v6/pkg/bindings/images"
 	"golang.org/x/exp/slices"
 	"google.golang.org/grpc"
 )
@@ -38,6 +42,29 @@ func ServiceProxyOptionContainerOptions(v ...string) ServiceProxyOption {
 	}
 }
 
+// ServiceProxyOptionRedact masks secrets within the output of containers.
+func ServiceProxyOptionRedact(r *redactx.Redactor) ServiceProxyOption {
+	return func(ps *ProxyService) {
+		ps.redact = r
+	}
+}
+
+// ServiceProxyOptionNetworkPolicy prevents containers from replacing the network assigned by the
+// container options when the policy restricts egress. builds are run without a network when egress
+// is denied and through the policy's proxy when egress is restricted to an allow list.
+func ServiceProxyOptionNetworkPolicy(p netpolicy.Policy) ServiceProxyOption {
+	return func(ps *ProxyService) {
+		ps.netpolicy = p
+	}
+}
+
+// ServiceProxyOptionProvenance records the containers pulled, built and run as materials of the run.
+func ServiceProxyOptionProvenance(record func(ctx context.Context, c provenance.Container)) ServiceProxyOption {
+	return func(ps *ProxyService) {
+		ps.record = record
+	}
+}
+
 func ServiceProxyOptionBaremetal(ps *ProxyService) {
 	ps.remap = func(s string) (n string) {
 		old := s
@@ -70,19 +97,67 @@ type ProxyService struct {
 	remap         func(s string) string
 	cmdenv        []string
 	containeropts []string
+	redact        *redactx.Redactor
+	netpolicy     netpolicy.Policy
+	record        func(ctx context.Context, c provenance.Container)
 }
 
 func (t *ProxyService) Bind(host grpc.ServiceRegistrar) {
 	c8s.RegisterProxyServer(host, t)
 }
 
-func (t *ProxyService) prepcmd(cmd *exec.Cmd) *exec.Cmd {
-	cmd.Dir = t.ws.Root
-	cmd.Env = t.cmdenv
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = t.log.Writer()
-	cmd.Stderr = t.log.Writer()
-	return cmd
+// output of a single container, the redacted writers are created per container so output held
+// back by redaction is never interleaved with other containers. the returned function flushes
+// the held back output and must be called once the container exits.
+func (t *ProxyService) output() (prepcmd func(*exec.Cmd) *exec.Cmd, flush func()) {
+	stdout, stderr := t.redact.Writer(t.log.Writer()), t.redact.Writer(t.log.Writer())
+
+	prepcmd = func(cmd *exec.Cmd) *exec.Cmd {
+		cmd.Dir = t.ws.Root
+		cmd.Env = t.cmdenv
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		return cmd
+	}
+
+	flush = func() {
+		errorsx.Log(errorsx.Wrap(errorsx.Compact(redactx.Flush(stdout), redactx.Flush(stderr)), "unable to flush container output"))
+	}
+
+	return prepcmd, flush
+}
+
+// provenance records the container along with the digest of its image.
+func (t *ProxyService) provenance(ctx context.Context, action, image, definition string) {
+	if t.record == nil {
+		return
+	}
+
+	c := provenance.Container{Action: action, Image: image, Definition: definition}
+	if report, err := images.GetImage(ctx, image, nil); err != nil {
+		log.Println("unable to inspect image", image, err)
+	} else if report.ImageData != nil {
+		c.ID, c.Digest = report.ID, report.Digest.String()
+	}
+
+	t.record(ctx, c)
+}
+
+// network rejects options replacing the container's network when the policy restricts egress.
+func (t *ProxyService) network(options ...string) error {
+	if !t.netpolicy.Enforced() {
+		return nil
+	}
+
+	for _, o := range options {
+		flag, _, _ := strings.Cut(o, "=")
+		if flag == "--network" || flag == "--net" {
+			return fmt.Errorf("%s is prohibited by the network policy: %s", o, t.netpolicy)
+		}
+	}
+
+	return nil
 }
 
 // Build implements ProxyServer.
@@ -108,16 +183,34 @@ func (t *ProxyService) Build(ctx context.Context, req *c8s.BuildRequest) (_ *c8s
 	// determine the working directory from the request if specified or the definition file's path.
 	wdir := slicesx.FindOrZero(func(s string) bool { return !stringsx.Blank(s) }, req.Directory, filepath.Dir(abspath))
 
+	if err = t.network(req.Options...); err != nil {
+		return nil, err
+	}
+
+	// builds share the network of the root container when egress is permitted, podman passes the
+	// proxy environment through to the build so fetches are subject to the policy.
+	switch t.netpolicy.Mode {
+	case netpolicy.ModeDeny:
+		req.Options = append(req.Options, "--network", "none")
+	case netpolicy.ModeAllow:
+		req.Options = append(req.Options, "--network", "host")
+	}
+
 	if cmd, err = podmanx.Build(ctx, req.Name, wdir, abspath, req.Options...); err != nil {
 		log.Println("unable to create build command", err)
 		return nil, err
 	}
 
-	if err = execx.MaybeRun(t.prepcmd(cmd)); err != nil {
+	prepcmd, flush := t.output()
+	defer flush()
+
+	if err = execx.MaybeRun(prepcmd(cmd)); err != nil {
 		log.Println("unable to exec build command", cmd.String(), err)
 		return nil, err
 	}
 
+	t.provenance(ctx, provenance.ContainerActionBuild, req.Name, req.Definition)
+
 	return &c8s.BuildResponse{}, nil
 }
 
@@ -134,10 +227,15 @@ func (t *ProxyService) Pull(ctx context.Context, req *c8s.PullRequest) (resp *c8
 		return nil, err
 	}
 
-	if err = execx.MaybeRun(t.prepcmd(cmd)); err != nil {
+	prepcmd, flush := t.output()
+	defer flush()
+
+	if err = execx.MaybeRun(prepcmd(cmd)); err != nil {
 		return nil, err
 	}
 
+	t.provenance(ctx, provenance.ContainerActionPull, req.Name, "")
+
 	return &c8s.PullResponse{}, nil
 }
 
@@ -146,13 +244,22 @@ func (t *ProxyService) Run(ctx context.Context, req *c8s.RunRequest) (_ *c8s.Run
 	debugx.Println("PROXY CONTAINER RUN INITIATED", errorsx.Zero(os.Getwd()))
 	defer debugx.Println("PROXY CONTAINER RUN COMPLETED", errorsx.Zero(os.Getwd()))
 
+	if err = t.network(req.Options...); err != nil {
+		return nil, err
+	}
+
+	t.provenance(ctx, provenance.ContainerActionRun, req.Image, "")
+
 	options := append(t.containeropts, req.Options...)
 	options = append(
 		options,
 		"--volume", fmt.Sprintf("%s:%s:rw", eg.DefaultMountRoot(eg.WorkingDirectory), eg.DefaultMountRoot(eg.WorkingDirectory)),
 	)
 
-	if err = PodmanRun(ctx, t.prepcmd, req.Image, req.Name, req.Command, options...); err != nil {
+	prepcmd, flush := t.output()
+	defer flush()
+
+	if err = PodmanRun(ctx, prepcmd, req.Image, req.Name, req.Command, options...); err != nil {
 		log.Println("failed", req.Image, req.Name, req.Command, strings.Join(options, ", "))
 		return nil, err
 	}
@@ -171,6 +278,10 @@ func (t *ProxyService) Module(ctx context.Context, req *c8s.ModuleRequest) (_ *c
 	// log.Println("module", req.Module)
 	// log.Println("mdir", req.Mdir)
 
+	if err = t.network(req.Options...); err != nil {
+		return nil, err
+	}
+
 	{
 		// handle the wasi module volume for backwards compatibility.
 		idx := slices.IndexFunc(req.Options, func(s string) bool {
@@ -190,6 +301,8 @@ func (t *ProxyService) Module(ctx context.Context, req *c8s.ModuleRequest) (_ *c
 		req.Options = append(req.Options, "--volume", fmt.Sprintf("%s:%s:ro", path, eg.ModuleMount()))
 	}
 
+	t.provenance(ctx, provenance.ContainerActionModule, req.Image, "")
+
 	options := make([]string, 0, len(t.containeropts)+len(req.Options)+1)
 	options = append(options, t.containeropts...)
 	options = append(options, req.Options...)
@@ -197,7 +310,10 @@ func (t *ProxyService) Module(ctx context.Context, req *c8s.ModuleRequest) (_ *c
 	// log.Println("module options", options)
 	// envx.Debug(options...)
 
-	if err = PodmanModule(ctx, t.prepcmd, req.Image, req.Name, req.Mdir, options...); err != nil {
+	prepcmd, flush := t.output()
+	defer flush()
+
+	if err = PodmanModule(ctx, prepcmd, req.Image, req.Name, req.Mdir, options...); err != nil {
 		return nil, err
 	}
 
@@ -46,7 +46,7 @@ func PrepareDB(ctx context.Context, db *sql.DB) error {
 		return err
 	}
 
-	if _, err := db.ExecContext(dctx, "CREATE TABLE IF NOT EXISTS 'eg.metrics.operation' (id UUID PRIMARY KEY, name TEXT NOT NULL, name_md5 uuid GENERATED ALWAYS AS (md5(name)), ts TIMESTAMP NOT NULL, module TEXT NOT NULL, op TEXT NOT NULL, milliseconds INTERVAL NOT NULL)"); err != nil {
+	if _, err := db.ExecContext(dctx, "CREATE TABLE IF NOT EXISTS 'eg.metrics.operation' (id UUID PRIMARY KEY, name TEXT NOT NULL, name_md5 uuid GENERATED ALWAYS AS (md5(name)), ts TIMESTAMP NOT NULL, module TEXT NOT NULL, op TEXT NOT NULL, state TEXT NOT NULL DEFAULT 'Completed', milliseconds INTERVAL NOT NULL)"); err != nil {
 		return err
 	}
 
@@ -54,6 +54,18 @@ func PrepareDB(ctx context.Context, db *sql.DB) error {
 		return err
 	}
 
+	if _, err := db.ExecContext(dctx, "CREATE TABLE IF NOT EXISTS 'eg.metrics.coverage.lines' (coverage UUID NOT NULL, path TEXT NOT NULL, line UINTEGER NOT NULL, hits UBIGINT NOT NULL, branches UINTEGER NOT NULL, branches_hit UINTEGER NOT NULL)"); err != nil {
+		return err
+	}
+
+	if _, err := db.ExecContext(dctx, "CREATE TABLE IF NOT EXISTS 'eg.metrics.tests' (id UUID PRIMARY KEY, ts TIMESTAMP NOT NULL, suite TEXT NOT NULL, name TEXT NOT NULL, name_md5 uuid GENERATED ALWAYS AS (md5(suite || name)), framework TEXT NOT NULL, status TEXT NOT NULL, milliseconds INTERVAL NOT NULL, message TEXT NOT NULL)"); err != nil {
+		return err
+	}
+
+	if _, err := db.ExecContext(dctx, "CREATE TABLE IF NOT EXISTS 'eg.metrics.network.violations' (id UUID PRIMARY KEY, ts TIMESTAMP NOT NULL, host TEXT NOT NULL, port UINTEGER NOT NULL)"); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -67,7 +79,12 @@ func RecordMetric(ctx context.Context, db *sql.DB, msgs ...*Message) error {
 			}
 		case *Message_Op:
 			mz := langx.Autoderef(evt.Op)
-			if err := db.QueryRowContext(ctx, "INSERT INTO 'eg.metrics.operation' (id, name, ts, module, op, milliseconds) VALUES (?, ?, ?, ?, ?, INTERVAL (?) MILLISECONDS)", m.Id, mz.Name, time.UnixMicro(m.Ts), mz.Module, mz.Op, mz.Milliseconds).Err(); err != nil {
+			// operations are recorded once they've concluded.
+			if mz.State == Op_Initiated {
+				continue
+			}
+
+			if err := db.QueryRowContext(ctx, "INSERT INTO 'eg.metrics.operation' (id, name, ts, module, op, state, milliseconds) VALUES (?, ?, ?, ?, ?, ?, INTERVAL (?) MILLISECONDS)", m.Id, mz.Name, time.UnixMicro(m.Ts), mz.Module, mz.Op, mz.State.String(), mz.Milliseconds).Err(); err != nil {
 				return err
 			}
 		case *Message_Coverage:
@@ -76,6 +93,23 @@ func RecordMetric(ctx context.Context, db *sql.DB, msgs ...*Message) error {
 				return err
 			}
 
+			for _, l := range mz.Lines {
+				if err := db.QueryRowContext(ctx, "INSERT INTO 'eg.metrics.coverage.lines' (coverage, path, line, hits, branches, branches_hit) VALUES (?, ?, ?, ?, ?, ?)", m.Id, mz.Path, l.Line, l.Hits, l.Branches, l.BranchesHit).Err(); err != nil {
+					return err
+				}
+			}
+		case *Message_Test:
+			mz := langx.Autoderef(evt.Test)
+			if err := db.QueryRowContext(ctx, "INSERT INTO 'eg.metrics.tests' (id, ts, suite, name, framework, status, milliseconds, message) VALUES (?, ?, ?, ?, ?, ?, INTERVAL (?) MILLISECONDS, ?)", m.Id, time.UnixMicro(m.Ts), mz.Suite, mz.Name, mz.Framework, mz.Status.String(), mz.Milliseconds, mz.Message).Err(); err != nil {
+				return err
+			}
+		case *Message_Network:
+			mz := langx.Autoderef(evt.Network)
+			if err := db.QueryRowContext(ctx, "INSERT INTO 'eg.metrics.network.violations' (id, ts, host, port) VALUES (?, ?, ?, ?)", m.Id, time.UnixMicro(m.Ts), mz.Host, mz.Port).Err(); err != nil {
+				return err
+			}
+		case *Message_Output:
+			// command output is only written to the event log.
 		default:
 			log.Printf("unknown message received %T\n", evt)
 		}
@@ -25,6 +25,9 @@ type Op_State int32
 const (
 	Op_Initiated Op_State = 0
 	Op_Completed Op_State = 1
+	Op_Cancelled Op_State = 2
+	Op_Skipped   Op_State = 3
+	Op_TimedOut  Op_State = 4
 	Op_Error     Op_State = 1000
 )
 
@@ -33,11 +36,17 @@ var (
 	Op_State_name = map[int32]string{
 		0:    "Initiated",
 		1:    "Completed",
+		2:    "Cancelled",
+		3:    "Skipped",
+		4:    "TimedOut",
 		1000: "Error",
 	}
 	Op_State_value = map[string]int32{
 		"Initiated": 0,
 		"Completed": 1,
+		"Cancelled": 2,
+		"Skipped":   3,
+		"TimedOut":  4,
 		"Error":     1000,
 	}
 )
@@ -69,6 +78,58 @@ func (Op_State) EnumDescriptor() ([]byte, []int) {
 	return file_eg_interp_events_proto_rawDescGZIP(), []int{3, 0}
 }
 
+type TestResult_Status int32
+
+const (
+	TestResult_Passed  TestResult_Status = 0
+	TestResult_Failed  TestResult_Status = 1
+	TestResult_Skipped TestResult_Status = 2
+	TestResult_Error   TestResult_Status = 3
+)
+
+// Enum value maps for TestResult_Status.
+var (
+	TestResult_Status_name = map[int32]string{
+		0: "Passed",
+		1: "Failed",
+		2: "Skipped",
+		3: "Error",
+	}
+	TestResult_Status_value = map[string]int32{
+		"Passed":  0,
+		"Failed":  1,
+		"Skipped": 2,
+		"Error":   3,
+	}
+)
+
+func (x TestResult_Status) Enum() *TestResult_Status {
+	p := new(TestResult_Status)
+	*p = x
+	return p
+}
+
+func (x TestResult_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TestResult_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_eg_interp_events_proto_enumTypes[1].Descriptor()
+}
+
+func (TestResult_Status) Type() protoreflect.EnumType {
+	return &file_eg_interp_events_proto_enumTypes[1]
+}
+
+func (x TestResult_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TestResult_Status.Descriptor instead.
+func (TestResult_Status) EnumDescriptor() ([]byte, []int) {
+	return file_eg_interp_events_proto_rawDescGZIP(), []int{8, 0}
+}
+
 type RunMetadata struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -382,9 +443,10 @@ type Coverage struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Path       string  `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	Statements float32 `protobuf:"fixed32,2,opt,name=statements,proto3" json:"statements,omitempty"`
-	Branches   float32 `protobuf:"fixed32,3,opt,name=branches,proto3" json:"branches,omitempty"`
+	Path       string          `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Statements float32         `protobuf:"fixed32,2,opt,name=statements,proto3" json:"statements,omitempty"`
+	Branches   float32         `protobuf:"fixed32,3,opt,name=branches,proto3" json:"branches,omitempty"`
+	Lines      []*CoverageLine `protobuf:"bytes,4,rep,name=lines,proto3" json:"lines,omitempty"` // line level coverage of the path, when available.
 }
 
 func (x *Coverage) Reset() {
@@ -440,6 +502,293 @@ func (x *Coverage) GetBranches() float32 {
 	return 0
 }
 
+func (x *Coverage) GetLines() []*CoverageLine {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+// coverage of an individual source line.
+type CoverageLine struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Line        uint32 `protobuf:"varint,1,opt,name=line,proto3" json:"line,omitempty"`
+	Hits        uint64 `protobuf:"varint,2,opt,name=hits,proto3" json:"hits,omitempty"` // number of times the line executed.
+	Branches    uint32 `protobuf:"varint,3,opt,name=branches,proto3" json:"branches,omitempty"`
+	BranchesHit uint32 `protobuf:"varint,4,opt,name=branches_hit,json=branchesHit,proto3" json:"branches_hit,omitempty"`
+}
+
+func (x *CoverageLine) Reset() {
+	*x = CoverageLine{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eg_interp_events_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CoverageLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CoverageLine) ProtoMessage() {}
+
+func (x *CoverageLine) ProtoReflect() protoreflect.Message {
+	mi := &file_eg_interp_events_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CoverageLine.ProtoReflect.Descriptor instead.
+func (*CoverageLine) Descriptor() ([]byte, []int) {
+	return file_eg_interp_events_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CoverageLine) GetLine() uint32 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+func (x *CoverageLine) GetHits() uint64 {
+	if x != nil {
+		return x.Hits
+	}
+	return 0
+}
+
+func (x *CoverageLine) GetBranches() uint32 {
+	if x != nil {
+		return x.Branches
+	}
+	return 0
+}
+
+func (x *CoverageLine) GetBranchesHit() uint32 {
+	if x != nil {
+		return x.BranchesHit
+	}
+	return 0
+}
+
+// output written by commands executed by an operation.
+type Output struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path   []string `protobuf:"bytes,1,rep,name=path,proto3" json:"path,omitempty"` // path of the operation that executed the command.
+	Stdout []byte   `protobuf:"bytes,2,opt,name=stdout,proto3" json:"stdout,omitempty"`
+	Stderr []byte   `protobuf:"bytes,3,opt,name=stderr,proto3" json:"stderr,omitempty"`
+}
+
+func (x *Output) Reset() {
+	*x = Output{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eg_interp_events_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Output) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Output) ProtoMessage() {}
+
+func (x *Output) ProtoReflect() protoreflect.Message {
+	mi := &file_eg_interp_events_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Output.ProtoReflect.Descriptor instead.
+func (*Output) Descriptor() ([]byte, []int) {
+	return file_eg_interp_events_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Output) GetPath() []string {
+	if x != nil {
+		return x.Path
+	}
+	return nil
+}
+
+func (x *Output) GetStdout() []byte {
+	if x != nil {
+		return x.Stdout
+	}
+	return nil
+}
+
+func (x *Output) GetStderr() []byte {
+	if x != nil {
+		return x.Stderr
+	}
+	return nil
+}
+
+// result of an individual test case.
+type TestResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status       TestResult_Status `protobuf:"varint,1,opt,name=status,proto3,enum=eg.interp.events.TestResult_Status" json:"status,omitempty"`
+	Suite        string            `protobuf:"bytes,2,opt,name=suite,proto3" json:"suite,omitempty"` // package, class or file containing the test.
+	Name         string            `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Milliseconds int64             `protobuf:"varint,4,opt,name=milliseconds,proto3" json:"milliseconds,omitempty"` // duration milliseconds.
+	Framework    string            `protobuf:"bytes,5,opt,name=framework,proto3" json:"framework,omitempty"`        // format the result was reported in, i.e. go, junit, tap.
+	Message      string            `protobuf:"bytes,6,opt,name=message,proto3" json:"message,omitempty"`            // failure or skip message.
+}
+
+func (x *TestResult) Reset() {
+	*x = TestResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eg_interp_events_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TestResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestResult) ProtoMessage() {}
+
+func (x *TestResult) ProtoReflect() protoreflect.Message {
+	mi := &file_eg_interp_events_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestResult.ProtoReflect.Descriptor instead.
+func (*TestResult) Descriptor() ([]byte, []int) {
+	return file_eg_interp_events_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TestResult) GetStatus() TestResult_Status {
+	if x != nil {
+		return x.Status
+	}
+	return TestResult_Passed
+}
+
+func (x *TestResult) GetSuite() string {
+	if x != nil {
+		return x.Suite
+	}
+	return ""
+}
+
+func (x *TestResult) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TestResult) GetMilliseconds() int64 {
+	if x != nil {
+		return x.Milliseconds
+	}
+	return 0
+}
+
+func (x *TestResult) GetFramework() string {
+	if x != nil {
+		return x.Framework
+	}
+	return ""
+}
+
+func (x *TestResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// connection attempted by the workload that was rejected by the network policy.
+type NetworkViolation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Host string `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Port uint32 `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+}
+
+func (x *NetworkViolation) Reset() {
+	*x = NetworkViolation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eg_interp_events_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NetworkViolation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkViolation) ProtoMessage() {}
+
+func (x *NetworkViolation) ProtoReflect() protoreflect.Message {
+	mi := &file_eg_interp_events_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkViolation.ProtoReflect.Descriptor instead.
+func (*NetworkViolation) Descriptor() ([]byte, []int) {
+	return file_eg_interp_events_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *NetworkViolation) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *NetworkViolation) GetPort() uint32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
 // Represents every message recorded when executing a job
 type Message struct {
 	state         protoimpl.MessageState
@@ -455,13 +804,16 @@ type Message struct {
 	//	*Message_Op
 	//	*Message_Metric
 	//	*Message_Coverage
+	//	*Message_Output
+	//	*Message_Test
+	//	*Message_Network
 	Event isMessage_Event `protobuf_oneof:"Event"`
 }
 
 func (x *Message) Reset() {
 	*x = Message{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_eg_interp_events_proto_msgTypes[6]
+		mi := &file_eg_interp_events_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -474,7 +826,7 @@ func (x *Message) String() string {
 func (*Message) ProtoMessage() {}
 
 func (x *Message) ProtoReflect() protoreflect.Message {
-	mi := &file_eg_interp_events_proto_msgTypes[6]
+	mi := &file_eg_interp_events_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -487,7 +839,7 @@ func (x *Message) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Message.ProtoReflect.Descriptor instead.
 func (*Message) Descriptor() ([]byte, []int) {
-	return file_eg_interp_events_proto_rawDescGZIP(), []int{6}
+	return file_eg_interp_events_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *Message) GetId() string {
@@ -546,6 +898,27 @@ func (x *Message) GetCoverage() *Coverage {
 	return nil
 }
 
+func (x *Message) GetOutput() *Output {
+	if x, ok := x.GetEvent().(*Message_Output); ok {
+		return x.Output
+	}
+	return nil
+}
+
+func (x *Message) GetTest() *TestResult {
+	if x, ok := x.GetEvent().(*Message_Test); ok {
+		return x.Test
+	}
+	return nil
+}
+
+func (x *Message) GetNetwork() *NetworkViolation {
+	if x, ok := x.GetEvent().(*Message_Network); ok {
+		return x.Network
+	}
+	return nil
+}
+
 type isMessage_Event interface {
 	isMessage_Event()
 }
@@ -570,6 +943,18 @@ type Message_Coverage struct {
 	Coverage *Coverage `protobuf:"bytes,104,opt,name=coverage,proto3,oneof"`
 }
 
+type Message_Output struct {
+	Output *Output `protobuf:"bytes,105,opt,name=output,proto3,oneof"`
+}
+
+type Message_Test struct {
+	Test *TestResult `protobuf:"bytes,106,opt,name=test,proto3,oneof"`
+}
+
+type Message_Network struct {
+	Network *NetworkViolation `protobuf:"bytes,107,opt,name=network,proto3,oneof"`
+}
+
 func (*Message_Preamble) isMessage_Event() {}
 
 func (*Message_Heartbeat) isMessage_Event() {}
@@ -580,6 +965,12 @@ func (*Message_Metric) isMessage_Event() {}
 
 func (*Message_Coverage) isMessage_Event() {}
 
+func (*Message_Output) isMessage_Event() {}
+
+func (*Message_Test) isMessage_Event() {}
+
+func (*Message_Network) isMessage_Event() {}
+
 type RunUploadChunk struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -597,7 +988,7 @@ type RunUploadChunk struct {
 func (x *RunUploadChunk) Reset() {
 	*x = RunUploadChunk{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_eg_interp_events_proto_msgTypes[7]
+		mi := &file_eg_interp_events_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -610,7 +1001,7 @@ func (x *RunUploadChunk) String() string {
 func (*RunUploadChunk) ProtoMessage() {}
 
 func (x *RunUploadChunk) ProtoReflect() protoreflect.Message {
-	mi := &file_eg_interp_events_proto_msgTypes[7]
+	mi := &file_eg_interp_events_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -623,7 +1014,7 @@ func (x *RunUploadChunk) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RunUploadChunk.ProtoReflect.Descriptor instead.
 func (*RunUploadChunk) Descriptor() ([]byte, []int) {
-	return file_eg_interp_events_proto_rawDescGZIP(), []int{7}
+	return file_eg_interp_events_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *RunUploadChunk) GetData() []byte {
@@ -688,7 +1079,7 @@ type RunUploadResponse struct {
 func (x *RunUploadResponse) Reset() {
 	*x = RunUploadResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_eg_interp_events_proto_msgTypes[8]
+		mi := &file_eg_interp_events_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -701,7 +1092,7 @@ func (x *RunUploadResponse) String() string {
 func (*RunUploadResponse) ProtoMessage() {}
 
 func (x *RunUploadResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_eg_interp_events_proto_msgTypes[8]
+	mi := &file_eg_interp_events_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -714,7 +1105,7 @@ func (x *RunUploadResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RunUploadResponse.ProtoReflect.Descriptor instead.
 func (*RunUploadResponse) Descriptor() ([]byte, []int) {
-	return file_eg_interp_events_proto_rawDescGZIP(), []int{8}
+	return file_eg_interp_events_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *RunUploadResponse) GetRun() *RunMetadata {
@@ -735,7 +1126,7 @@ type RunLogRequest struct {
 func (x *RunLogRequest) Reset() {
 	*x = RunLogRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_eg_interp_events_proto_msgTypes[9]
+		mi := &file_eg_interp_events_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -748,7 +1139,7 @@ func (x *RunLogRequest) String() string {
 func (*RunLogRequest) ProtoMessage() {}
 
 func (x *RunLogRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_eg_interp_events_proto_msgTypes[9]
+	mi := &file_eg_interp_events_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -761,7 +1152,7 @@ func (x *RunLogRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RunLogRequest.ProtoReflect.Descriptor instead.
 func (*RunLogRequest) Descriptor() ([]byte, []int) {
-	return file_eg_interp_events_proto_rawDescGZIP(), []int{9}
+	return file_eg_interp_events_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *RunLogRequest) GetRun() *RunMetadata {
@@ -782,7 +1173,7 @@ type RunLogResponse struct {
 func (x *RunLogResponse) Reset() {
 	*x = RunLogResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_eg_interp_events_proto_msgTypes[10]
+		mi := &file_eg_interp_events_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -795,7 +1186,7 @@ func (x *RunLogResponse) String() string {
 func (*RunLogResponse) ProtoMessage() {}
 
 func (x *RunLogResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_eg_interp_events_proto_msgTypes[10]
+	mi := &file_eg_interp_events_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -808,7 +1199,7 @@ func (x *RunLogResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RunLogResponse.ProtoReflect.Descriptor instead.
 func (*RunLogResponse) Descriptor() ([]byte, []int) {
-	return file_eg_interp_events_proto_rawDescGZIP(), []int{10}
+	return file_eg_interp_events_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *RunLogResponse) GetContent() []byte {
@@ -827,7 +1218,7 @@ type RunInitiateRequest struct {
 func (x *RunInitiateRequest) Reset() {
 	*x = RunInitiateRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_eg_interp_events_proto_msgTypes[11]
+		mi := &file_eg_interp_events_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -840,7 +1231,7 @@ func (x *RunInitiateRequest) String() string {
 func (*RunInitiateRequest) ProtoMessage() {}
 
 func (x *RunInitiateRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_eg_interp_events_proto_msgTypes[11]
+	mi := &file_eg_interp_events_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -853,7 +1244,7 @@ func (x *RunInitiateRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RunInitiateRequest.ProtoReflect.Descriptor instead.
 func (*RunInitiateRequest) Descriptor() ([]byte, []int) {
-	return file_eg_interp_events_proto_rawDescGZIP(), []int{11}
+	return file_eg_interp_events_proto_rawDescGZIP(), []int{15}
 }
 
 type RunInitiateResult struct {
@@ -865,7 +1256,7 @@ type RunInitiateResult struct {
 func (x *RunInitiateResult) Reset() {
 	*x = RunInitiateResult{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_eg_interp_events_proto_msgTypes[12]
+		mi := &file_eg_interp_events_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -878,7 +1269,7 @@ func (x *RunInitiateResult) String() string {
 func (*RunInitiateResult) ProtoMessage() {}
 
 func (x *RunInitiateResult) ProtoReflect() protoreflect.Message {
-	mi := &file_eg_interp_events_proto_msgTypes[12]
+	mi := &file_eg_interp_events_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -891,19 +1282,21 @@ func (x *RunInitiateResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RunInitiateResult.ProtoReflect.Descriptor instead.
 func (*RunInitiateResult) Descriptor() ([]byte, []int) {
-	return file_eg_interp_events_proto_rawDescGZIP(), []int{12}
+	return file_eg_interp_events_proto_rawDescGZIP(), []int{16}
 }
 
 type RunCancelRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Run *RunMetadata `protobuf:"bytes,1,opt,name=run,proto3" json:"run,omitempty"`
 }
 
 func (x *RunCancelRequest) Reset() {
 	*x = RunCancelRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_eg_interp_events_proto_msgTypes[13]
+		mi := &file_eg_interp_events_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -916,7 +1309,7 @@ func (x *RunCancelRequest) String() string {
 func (*RunCancelRequest) ProtoMessage() {}
 
 func (x *RunCancelRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_eg_interp_events_proto_msgTypes[13]
+	mi := &file_eg_interp_events_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -929,7 +1322,14 @@ func (x *RunCancelRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RunCancelRequest.ProtoReflect.Descriptor instead.
 func (*RunCancelRequest) Descriptor() ([]byte, []int) {
-	return file_eg_interp_events_proto_rawDescGZIP(), []int{13}
+	return file_eg_interp_events_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RunCancelRequest) GetRun() *RunMetadata {
+	if x != nil {
+		return x.Run
+	}
+	return nil
 }
 
 type RunCancelResponse struct {
@@ -941,7 +1341,7 @@ type RunCancelResponse struct {
 func (x *RunCancelResponse) Reset() {
 	*x = RunCancelResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_eg_interp_events_proto_msgTypes[14]
+		mi := &file_eg_interp_events_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -954,7 +1354,7 @@ func (x *RunCancelResponse) String() string {
 func (*RunCancelResponse) ProtoMessage() {}
 
 func (x *RunCancelResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_eg_interp_events_proto_msgTypes[14]
+	mi := &file_eg_interp_events_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -967,7 +1367,7 @@ func (x *RunCancelResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RunCancelResponse.ProtoReflect.Descriptor instead.
 func (*RunCancelResponse) Descriptor() ([]byte, []int) {
-	return file_eg_interp_events_proto_rawDescGZIP(), []int{14}
+	return file_eg_interp_events_proto_rawDescGZIP(), []int{18}
 }
 
 type RunWatchRequest struct {
@@ -981,7 +1381,7 @@ type RunWatchRequest struct {
 func (x *RunWatchRequest) Reset() {
 	*x = RunWatchRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_eg_interp_events_proto_msgTypes[15]
+		mi := &file_eg_interp_events_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -994,7 +1394,7 @@ func (x *RunWatchRequest) String() string {
 func (*RunWatchRequest) ProtoMessage() {}
 
 func (x *RunWatchRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_eg_interp_events_proto_msgTypes[15]
+	mi := &file_eg_interp_events_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1007,7 +1407,7 @@ func (x *RunWatchRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RunWatchRequest.ProtoReflect.Descriptor instead.
 func (*RunWatchRequest) Descriptor() ([]byte, []int) {
-	return file_eg_interp_events_proto_rawDescGZIP(), []int{15}
+	return file_eg_interp_events_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *RunWatchRequest) GetRun() *RunMetadata {
@@ -1028,7 +1428,7 @@ type DispatchRequest struct {
 func (x *DispatchRequest) Reset() {
 	*x = DispatchRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_eg_interp_events_proto_msgTypes[16]
+		mi := &file_eg_interp_events_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1041,7 +1441,7 @@ func (x *DispatchRequest) String() string {
 func (*DispatchRequest) ProtoMessage() {}
 
 func (x *DispatchRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_eg_interp_events_proto_msgTypes[16]
+	mi := &file_eg_interp_events_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1054,7 +1454,7 @@ func (x *DispatchRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DispatchRequest.ProtoReflect.Descriptor instead.
 func (*DispatchRequest) Descriptor() ([]byte, []int) {
-	return file_eg_interp_events_proto_rawDescGZIP(), []int{16}
+	return file_eg_interp_events_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *DispatchRequest) GetMessages() []*Message {
@@ -1073,7 +1473,7 @@ type DispatchResponse struct {
 func (x *DispatchResponse) Reset() {
 	*x = DispatchResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_eg_interp_events_proto_msgTypes[17]
+		mi := &file_eg_interp_events_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1086,7 +1486,7 @@ func (x *DispatchResponse) String() string {
 func (*DispatchResponse) ProtoMessage() {}
 
 func (x *DispatchResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_eg_interp_events_proto_msgTypes[17]
+	mi := &file_eg_interp_events_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1099,7 +1499,7 @@ func (x *DispatchResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DispatchResponse.ProtoReflect.Descriptor instead.
 func (*DispatchResponse) Descriptor() ([]byte, []int) {
-	return file_eg_interp_events_proto_rawDescGZIP(), []int{17}
+	return file_eg_interp_events_proto_rawDescGZIP(), []int{21}
 }
 
 type RunUploadChunk_Metadata struct {
@@ -1114,7 +1514,7 @@ type RunUploadChunk_Metadata struct {
 func (x *RunUploadChunk_Metadata) Reset() {
 	*x = RunUploadChunk_Metadata{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_eg_interp_events_proto_msgTypes[18]
+		mi := &file_eg_interp_events_proto_msgTypes[22]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1127,7 +1527,7 @@ func (x *RunUploadChunk_Metadata) String() string {
 func (*RunUploadChunk_Metadata) ProtoMessage() {}
 
 func (x *RunUploadChunk_Metadata) ProtoReflect() protoreflect.Message {
-	mi := &file_eg_interp_events_proto_msgTypes[18]
+	mi := &file_eg_interp_events_proto_msgTypes[22]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1140,7 +1540,7 @@ func (x *RunUploadChunk_Metadata) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RunUploadChunk_Metadata.ProtoReflect.Descriptor instead.
 func (*RunUploadChunk_Metadata) Descriptor() ([]byte, []int) {
-	return file_eg_interp_events_proto_rawDescGZIP(), []int{7, 0}
+	return file_eg_interp_events_proto_rawDescGZIP(), []int{11, 0}
 }
 
 func (x *RunUploadChunk_Metadata) GetBytes() uint64 {
@@ -1172,7 +1572,7 @@ var file_eg_interp_events_proto_rawDesc = []byte{
 	0x05, 0x52, 0x05, 0x50, 0x61, 0x74, 0x63, 0x68, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x74, 0x73, 0x18,
 	0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x73, 0x74, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x74,
 	0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x65, 0x74, 0x73, 0x22, 0x0b, 0x0a, 0x09,
-	0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x22, 0xe4, 0x01, 0x0a, 0x02, 0x4f, 0x70,
+	0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x22, 0x8e, 0x02, 0x0a, 0x02, 0x4f, 0x70,
 	0x12, 0x30, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
 	0x1a, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x76, 0x65, 0x6e,
 	0x74, 0x73, 0x2e, 0x4f, 0x70, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61,
@@ -1183,116 +1583,169 @@ var file_eg_interp_events_proto_rawDesc = []byte{
 	0x75, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x6f, 0x64, 0x75, 0x6c,
 	0x65, 0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x6f,
 	0x70, 0x12, 0x13, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0xe8, 0x07, 0x20, 0x03, 0x28, 0x09,
-	0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0x31, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12,
+	0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0x5b, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12,
 	0x0d, 0x0a, 0x09, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x65, 0x64, 0x10, 0x00, 0x12, 0x0d,
-	0x0a, 0x09, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x10, 0x01, 0x12, 0x0a, 0x0a,
-	0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x10, 0xe8, 0x07, 0x4a, 0x05, 0x08, 0x06, 0x10, 0xe8, 0x07,
-	0x22, 0x44, 0x0a, 0x06, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
-	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1f,
-	0x0a, 0x0a, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x4a, 0x53, 0x4f, 0x4e, 0x18, 0xe8, 0x07, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x0a, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x4a, 0x53, 0x4f, 0x4e, 0x4a,
-	0x05, 0x08, 0x02, 0x10, 0xe8, 0x07, 0x22, 0x5a, 0x0a, 0x08, 0x43, 0x6f, 0x76, 0x65, 0x72, 0x61,
-	0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x65, 0x6d,
-	0x65, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x74,
-	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x62, 0x72, 0x61, 0x6e, 0x63, 0x68,
-	0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x02, 0x52, 0x08, 0x62, 0x72, 0x61, 0x6e, 0x63, 0x68,
-	0x65, 0x73, 0x22, 0xc0, 0x02, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x0e,
-	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x0e,
-	0x0a, 0x02, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x74, 0x73, 0x12, 0x39,
-	0x0a, 0x08, 0x70, 0x72, 0x65, 0x61, 0x6d, 0x62, 0x6c, 0x65, 0x18, 0x64, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x1b, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x76, 0x65,
-	0x6e, 0x74, 0x73, 0x2e, 0x4c, 0x6f, 0x67, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x48, 0x00, 0x52,
-	0x08, 0x70, 0x72, 0x65, 0x61, 0x6d, 0x62, 0x6c, 0x65, 0x12, 0x3b, 0x0a, 0x09, 0x68, 0x65, 0x61,
-	0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x18, 0x65, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x65,
+	0x0a, 0x09, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x10, 0x01, 0x12, 0x0d, 0x0a,
+	0x09, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c, 0x65, 0x64, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07,
+	0x53, 0x6b, 0x69, 0x70, 0x70, 0x65, 0x64, 0x10, 0x03, 0x12, 0x0c, 0x0a, 0x08, 0x54, 0x69, 0x6d,
+	0x65, 0x64, 0x4f, 0x75, 0x74, 0x10, 0x04, 0x12, 0x0a, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72,
+	0x10, 0xe8, 0x07, 0x4a, 0x05, 0x08, 0x06, 0x10, 0xe8, 0x07, 0x22, 0x44, 0x0a, 0x06, 0x4d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0a, 0x66, 0x69, 0x65, 0x6c,
+	0x64, 0x73, 0x4a, 0x53, 0x4f, 0x4e, 0x18, 0xe8, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x66,
+	0x69, 0x65, 0x6c, 0x64, 0x73, 0x4a, 0x53, 0x4f, 0x4e, 0x4a, 0x05, 0x08, 0x02, 0x10, 0xe8, 0x07,
+	0x22, 0x90, 0x01, 0x0a, 0x08, 0x43, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74,
+	0x68, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74,
+	0x73, 0x12, 0x1a, 0x0a, 0x08, 0x62, 0x72, 0x61, 0x6e, 0x63, 0x68, 0x65, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x02, 0x52, 0x08, 0x62, 0x72, 0x61, 0x6e, 0x63, 0x68, 0x65, 0x73, 0x12, 0x34, 0x0a,
+	0x05, 0x6c, 0x69, 0x6e, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x65,
 	0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e,
-	0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x48, 0x00, 0x52, 0x09, 0x68, 0x65, 0x61,
-	0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x12, 0x26, 0x0a, 0x02, 0x6f, 0x70, 0x18, 0x66, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65,
-	0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x4f, 0x70, 0x48, 0x00, 0x52, 0x02, 0x6f, 0x70, 0x12, 0x32,
-	0x0a, 0x06, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x67, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18,
+	0x43, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x4c, 0x69, 0x6e, 0x65, 0x52, 0x05, 0x6c, 0x69,
+	0x6e, 0x65, 0x73, 0x22, 0x75, 0x0a, 0x0c, 0x43, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x4c,
+	0x69, 0x6e, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x04, 0x6c, 0x69, 0x6e, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x69, 0x74, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x68, 0x69, 0x74, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x62,
+	0x72, 0x61, 0x6e, 0x63, 0x68, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x62,
+	0x72, 0x61, 0x6e, 0x63, 0x68, 0x65, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x72, 0x61, 0x6e, 0x63,
+	0x68, 0x65, 0x73, 0x5f, 0x68, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x62,
+	0x72, 0x61, 0x6e, 0x63, 0x68, 0x65, 0x73, 0x48, 0x69, 0x74, 0x22, 0x4c, 0x0a, 0x06, 0x4f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x64, 0x6f,
+	0x75, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x73, 0x74, 0x64, 0x6f, 0x75, 0x74,
+	0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x64, 0x65, 0x72, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x06, 0x73, 0x74, 0x64, 0x65, 0x72, 0x72, 0x22, 0x89, 0x02, 0x0a, 0x0a, 0x54, 0x65, 0x73,
+	0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x3b, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x23, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74,
+	0x65, 0x72, 0x70, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x54, 0x65, 0x73, 0x74, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x75, 0x69, 0x74, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x75, 0x69, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x22,
+	0x0a, 0x0c, 0x6d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x65, 0x63, 0x6f, 0x6e,
+	0x64, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b,
+	0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x38, 0x0a, 0x06, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x0a, 0x0a, 0x06, 0x50, 0x61, 0x73, 0x73, 0x65, 0x64, 0x10, 0x00,
+	0x12, 0x0a, 0x0a, 0x06, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07,
+	0x53, 0x6b, 0x69, 0x70, 0x70, 0x65, 0x64, 0x10, 0x02, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x72, 0x72,
+	0x6f, 0x72, 0x10, 0x03, 0x22, 0x3a, 0x0a, 0x10, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x56,
+	0x69, 0x6f, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x70, 0x6f, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74,
+	0x22, 0xe8, 0x03, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x0e, 0x0a, 0x02,
+	0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x74, 0x73, 0x12, 0x39, 0x0a, 0x08,
+	0x70, 0x72, 0x65, 0x61, 0x6d, 0x62, 0x6c, 0x65, 0x18, 0x64, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b,
 	0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74,
-	0x73, 0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x48, 0x00, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x72,
-	0x69, 0x63, 0x12, 0x38, 0x0a, 0x08, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x18, 0x68,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70,
-	0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x43, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65,
-	0x48, 0x00, 0x52, 0x08, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x42, 0x07, 0x0a, 0x05,
-	0x45, 0x76, 0x65, 0x6e, 0x74, 0x22, 0xf5, 0x01, 0x0a, 0x0e, 0x52, 0x75, 0x6e, 0x55, 0x70, 0x6c,
-	0x6f, 0x61, 0x64, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x1a, 0x0a, 0x08,
-	0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08,
-	0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x12, 0x14, 0x0a, 0x04, 0x6e, 0x6f, 0x6e, 0x65,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x04, 0x6e, 0x6f, 0x6e, 0x65, 0x12, 0x47,
-	0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x29, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x76, 0x65,
-	0x6e, 0x74, 0x73, 0x2e, 0x52, 0x75, 0x6e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x43, 0x68, 0x75,
-	0x6e, 0x6b, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x48, 0x00, 0x52, 0x08, 0x6d,
-	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x1a, 0x3c, 0x0a, 0x08, 0x4d, 0x65, 0x74, 0x61, 0x64,
-	0x61, 0x74, 0x61, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x04, 0x52, 0x05, 0x62, 0x79, 0x74, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x68, 0x65,
-	0x63, 0x6b, 0x73, 0x75, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x63, 0x68, 0x65,
-	0x63, 0x6b, 0x73, 0x75, 0x6d, 0x42, 0x16, 0x0a, 0x14, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c,
-	0x43, 0x68, 0x75, 0x6e, 0x6b, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x22, 0x44, 0x0a,
-	0x11, 0x52, 0x75, 0x6e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x2f, 0x0a, 0x03, 0x72, 0x75, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x1d, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x76, 0x65, 0x6e,
-	0x74, 0x73, 0x2e, 0x52, 0x75, 0x6e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x03,
-	0x72, 0x75, 0x6e, 0x22, 0x40, 0x0a, 0x0d, 0x52, 0x75, 0x6e, 0x4c, 0x6f, 0x67, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x2f, 0x0a, 0x03, 0x72, 0x75, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x1d, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x76,
-	0x65, 0x6e, 0x74, 0x73, 0x2e, 0x52, 0x75, 0x6e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
-	0x52, 0x03, 0x72, 0x75, 0x6e, 0x22, 0x2a, 0x0a, 0x0e, 0x52, 0x75, 0x6e, 0x4c, 0x6f, 0x67, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65,
-	0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e,
-	0x74, 0x22, 0x14, 0x0a, 0x12, 0x52, 0x75, 0x6e, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x65,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x13, 0x0a, 0x11, 0x52, 0x75, 0x6e, 0x49, 0x6e,
-	0x69, 0x74, 0x69, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x12, 0x0a, 0x10,
-	0x52, 0x75, 0x6e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x22, 0x13, 0x0a, 0x11, 0x52, 0x75, 0x6e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x42, 0x0a, 0x0f, 0x52, 0x75, 0x6e, 0x57, 0x61, 0x74, 0x63,
-	0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2f, 0x0a, 0x03, 0x72, 0x75, 0x6e, 0x18,
+	0x73, 0x2e, 0x4c, 0x6f, 0x67, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x48, 0x00, 0x52, 0x08, 0x70,
+	0x72, 0x65, 0x61, 0x6d, 0x62, 0x6c, 0x65, 0x12, 0x3b, 0x0a, 0x09, 0x68, 0x65, 0x61, 0x72, 0x74,
+	0x62, 0x65, 0x61, 0x74, 0x18, 0x65, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x65, 0x67, 0x2e,
+	0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x48, 0x65,
+	0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x48, 0x00, 0x52, 0x09, 0x68, 0x65, 0x61, 0x72, 0x74,
+	0x62, 0x65, 0x61, 0x74, 0x12, 0x26, 0x0a, 0x02, 0x6f, 0x70, 0x18, 0x66, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x14, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x76, 0x65,
+	0x6e, 0x74, 0x73, 0x2e, 0x4f, 0x70, 0x48, 0x00, 0x52, 0x02, 0x6f, 0x70, 0x12, 0x32, 0x0a, 0x06,
+	0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x67, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x65,
+	0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e,
+	0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x48, 0x00, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63,
+	0x12, 0x38, 0x0a, 0x08, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x18, 0x68, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65,
+	0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x43, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x48, 0x00,
+	0x52, 0x08, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x12, 0x32, 0x0a, 0x06, 0x6f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x18, 0x69, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x65, 0x67, 0x2e,
+	0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x4f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x48, 0x00, 0x52, 0x06, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x12, 0x32,
+	0x0a, 0x04, 0x74, 0x65, 0x73, 0x74, 0x18, 0x6a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x65,
+	0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e,
+	0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x48, 0x00, 0x52, 0x04, 0x74, 0x65,
+	0x73, 0x74, 0x12, 0x3e, 0x0a, 0x07, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x6b, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x56, 0x69,
+	0x6f, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x07, 0x6e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x42, 0x07, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x22, 0xf5, 0x01, 0x0a, 0x0e,
+	0x52, 0x75, 0x6e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x12,
+	0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61,
+	0x74, 0x61, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x12, 0x14,
+	0x0a, 0x04, 0x6e, 0x6f, 0x6e, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x04,
+	0x6e, 0x6f, 0x6e, 0x65, 0x12, 0x47, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x70, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x52, 0x75, 0x6e, 0x55, 0x70, 0x6c,
+	0x6f, 0x61, 0x64, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
+	0x61, 0x48, 0x00, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x1a, 0x3c, 0x0a,
+	0x08, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x79, 0x74,
+	0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x62, 0x79, 0x74, 0x65, 0x73, 0x12,
+	0x1a, 0x0a, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x42, 0x16, 0x0a, 0x14, 0x69,
+	0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x4d, 0x65, 0x74, 0x61, 0x64,
+	0x61, 0x74, 0x61, 0x22, 0x44, 0x0a, 0x11, 0x52, 0x75, 0x6e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x03, 0x72, 0x75, 0x6e, 0x18,
 	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72,
 	0x70, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x52, 0x75, 0x6e, 0x4d, 0x65, 0x74, 0x61,
-	0x64, 0x61, 0x74, 0x61, 0x52, 0x03, 0x72, 0x75, 0x6e, 0x22, 0x48, 0x0a, 0x0f, 0x44, 0x69, 0x73,
-	0x70, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x35, 0x0a, 0x08,
-	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19,
-	0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74,
-	0x73, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x08, 0x6d, 0x65, 0x73, 0x73, 0x61,
-	0x67, 0x65, 0x73, 0x22, 0x12, 0x0a, 0x10, 0x44, 0x69, 0x73, 0x70, 0x61, 0x74, 0x63, 0x68, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0xcb, 0x02, 0x0a, 0x05, 0x41, 0x67, 0x65, 0x6e,
-	0x74, 0x12, 0x53, 0x0a, 0x06, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x20, 0x2e, 0x65, 0x67,
+	0x64, 0x61, 0x74, 0x61, 0x52, 0x03, 0x72, 0x75, 0x6e, 0x22, 0x40, 0x0a, 0x0d, 0x52, 0x75, 0x6e,
+	0x4c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2f, 0x0a, 0x03, 0x72, 0x75,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74,
+	0x65, 0x72, 0x70, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x52, 0x75, 0x6e, 0x4d, 0x65,
+	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x03, 0x72, 0x75, 0x6e, 0x22, 0x2a, 0x0a, 0x0e, 0x52,
+	0x75, 0x6e, 0x4c, 0x6f, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07,
+	0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0x14, 0x0a, 0x12, 0x52, 0x75, 0x6e, 0x49, 0x6e,
+	0x69, 0x74, 0x69, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x13, 0x0a,
+	0x11, 0x52, 0x75, 0x6e, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x22, 0x43, 0x0a, 0x10, 0x52, 0x75, 0x6e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2f, 0x0a, 0x03, 0x72, 0x75, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x52, 0x75, 0x6e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x52, 0x03, 0x72, 0x75, 0x6e, 0x22, 0x13, 0x0a, 0x11, 0x52, 0x75, 0x6e, 0x43, 0x61,
+	0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x42, 0x0a, 0x0f,
+	0x52, 0x75, 0x6e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x2f, 0x0a, 0x03, 0x72, 0x75, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x65,
+	0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e,
+	0x52, 0x75, 0x6e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x03, 0x72, 0x75, 0x6e,
+	0x22, 0x48, 0x0a, 0x0f, 0x44, 0x69, 0x73, 0x70, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x35, 0x0a, 0x08, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x70, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x52, 0x08, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x22, 0x12, 0x0a, 0x10, 0x44, 0x69,
+	0x73, 0x70, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0xcb,
+	0x02, 0x0a, 0x05, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x12, 0x53, 0x0a, 0x06, 0x55, 0x70, 0x6c, 0x6f,
+	0x61, 0x64, 0x12, 0x20, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65,
+	0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x52, 0x75, 0x6e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x43,
+	0x68, 0x75, 0x6e, 0x6b, 0x1a, 0x23, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70,
+	0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x52, 0x75, 0x6e, 0x55, 0x70, 0x6c, 0x6f, 0x61,
+	0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x12, 0x53, 0x0a,
+	0x06, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x12, 0x22, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74,
+	0x65, 0x72, 0x70, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x52, 0x75, 0x6e, 0x43, 0x61,
+	0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x65, 0x67,
 	0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x52,
-	0x75, 0x6e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x1a, 0x23, 0x2e,
-	0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73,
-	0x2e, 0x52, 0x75, 0x6e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x12, 0x53, 0x0a, 0x06, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c,
-	0x12, 0x22, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x76, 0x65,
-	0x6e, 0x74, 0x73, 0x2e, 0x52, 0x75, 0x6e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70,
-	0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x52, 0x75, 0x6e, 0x43, 0x61, 0x6e, 0x63, 0x65,
-	0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4d, 0x0a, 0x04, 0x4c,
-	0x6f, 0x67, 0x73, 0x12, 0x1f, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e,
-	0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x52, 0x75, 0x6e, 0x4c, 0x6f, 0x67, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70,
-	0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x52, 0x75, 0x6e, 0x4c, 0x6f, 0x67, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x49, 0x0a, 0x05, 0x57, 0x61,
-	0x74, 0x63, 0x68, 0x12, 0x21, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e,
-	0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x52, 0x75, 0x6e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65,
-	0x72, 0x70, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x22, 0x00, 0x30, 0x01, 0x32, 0x5d, 0x0a, 0x06, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12,
-	0x53, 0x0a, 0x08, 0x44, 0x69, 0x73, 0x70, 0x61, 0x74, 0x63, 0x68, 0x12, 0x21, 0x2e, 0x65, 0x67,
-	0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x44,
-	0x69, 0x73, 0x70, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22,
-	0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74,
-	0x73, 0x2e, 0x44, 0x69, 0x73, 0x70, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x22, 0x00, 0x32, 0x5d, 0x0a, 0x06, 0x52, 0x75, 0x6e, 0x6e, 0x65, 0x72, 0x12, 0x53,
-	0x0a, 0x08, 0x44, 0x69, 0x73, 0x70, 0x61, 0x74, 0x63, 0x68, 0x12, 0x21, 0x2e, 0x65, 0x67, 0x2e,
-	0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x44, 0x69,
-	0x73, 0x70, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e,
+	0x75, 0x6e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x12, 0x4d, 0x0a, 0x04, 0x4c, 0x6f, 0x67, 0x73, 0x12, 0x1f, 0x2e, 0x65, 0x67, 0x2e,
+	0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x52, 0x75,
+	0x6e, 0x4c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x65, 0x67,
+	0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x52,
+	0x75, 0x6e, 0x4c, 0x6f, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30,
+	0x01, 0x12, 0x49, 0x0a, 0x05, 0x57, 0x61, 0x74, 0x63, 0x68, 0x12, 0x21, 0x2e, 0x65, 0x67, 0x2e,
+	0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x52, 0x75,
+	0x6e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e,
 	0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73,
-	0x2e, 0x44, 0x69, 0x73, 0x70, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x00, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x00, 0x30, 0x01, 0x32, 0x5d, 0x0a, 0x06,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x53, 0x0a, 0x08, 0x44, 0x69, 0x73, 0x70, 0x61, 0x74,
+	0x63, 0x68, 0x12, 0x21, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65,
+	0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x44, 0x69, 0x73, 0x70, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x70, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x44, 0x69, 0x73, 0x70, 0x61, 0x74, 0x63,
+	0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x32, 0x5d, 0x0a, 0x06, 0x52,
+	0x75, 0x6e, 0x6e, 0x65, 0x72, 0x12, 0x53, 0x0a, 0x08, 0x44, 0x69, 0x73, 0x70, 0x61, 0x74, 0x63,
+	0x68, 0x12, 0x21, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x73, 0x2e, 0x44, 0x69, 0x73, 0x70, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70,
+	0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x44, 0x69, 0x73, 0x70, 0x61, 0x74, 0x63, 0x68,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
 }
 
 var (
@@ -1307,59 +1760,70 @@ func file_eg_interp_events_proto_rawDescGZIP() []byte {
 	return file_eg_interp_events_proto_rawDescData
 }
 
-var file_eg_interp_events_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_eg_interp_events_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_eg_interp_events_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_eg_interp_events_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
 var file_eg_interp_events_proto_goTypes = []interface{}{
 	(Op_State)(0),                   // 0: eg.interp.events.Op.State
-	(*RunMetadata)(nil),             // 1: eg.interp.events.RunMetadata
-	(*LogHeader)(nil),               // 2: eg.interp.events.LogHeader
-	(*Heartbeat)(nil),               // 3: eg.interp.events.Heartbeat
-	(*Op)(nil),                      // 4: eg.interp.events.Op
-	(*Metric)(nil),                  // 5: eg.interp.events.Metric
-	(*Coverage)(nil),                // 6: eg.interp.events.Coverage
-	(*Message)(nil),                 // 7: eg.interp.events.Message
-	(*RunUploadChunk)(nil),          // 8: eg.interp.events.RunUploadChunk
-	(*RunUploadResponse)(nil),       // 9: eg.interp.events.RunUploadResponse
-	(*RunLogRequest)(nil),           // 10: eg.interp.events.RunLogRequest
-	(*RunLogResponse)(nil),          // 11: eg.interp.events.RunLogResponse
-	(*RunInitiateRequest)(nil),      // 12: eg.interp.events.RunInitiateRequest
-	(*RunInitiateResult)(nil),       // 13: eg.interp.events.RunInitiateResult
-	(*RunCancelRequest)(nil),        // 14: eg.interp.events.RunCancelRequest
-	(*RunCancelResponse)(nil),       // 15: eg.interp.events.RunCancelResponse
-	(*RunWatchRequest)(nil),         // 16: eg.interp.events.RunWatchRequest
-	(*DispatchRequest)(nil),         // 17: eg.interp.events.DispatchRequest
-	(*DispatchResponse)(nil),        // 18: eg.interp.events.DispatchResponse
-	(*RunUploadChunk_Metadata)(nil), // 19: eg.interp.events.RunUploadChunk.Metadata
+	(TestResult_Status)(0),          // 1: eg.interp.events.TestResult.Status
+	(*RunMetadata)(nil),             // 2: eg.interp.events.RunMetadata
+	(*LogHeader)(nil),               // 3: eg.interp.events.LogHeader
+	(*Heartbeat)(nil),               // 4: eg.interp.events.Heartbeat
+	(*Op)(nil),                      // 5: eg.interp.events.Op
+	(*Metric)(nil),                  // 6: eg.interp.events.Metric
+	(*Coverage)(nil),                // 7: eg.interp.events.Coverage
+	(*CoverageLine)(nil),            // 8: eg.interp.events.CoverageLine
+	(*Output)(nil),                  // 9: eg.interp.events.Output
+	(*TestResult)(nil),              // 10: eg.interp.events.TestResult
+	(*NetworkViolation)(nil),        // 11: eg.interp.events.NetworkViolation
+	(*Message)(nil),                 // 12: eg.interp.events.Message
+	(*RunUploadChunk)(nil),          // 13: eg.interp.events.RunUploadChunk
+	(*RunUploadResponse)(nil),       // 14: eg.interp.events.RunUploadResponse
+	(*RunLogRequest)(nil),           // 15: eg.interp.events.RunLogRequest
+	(*RunLogResponse)(nil),          // 16: eg.interp.events.RunLogResponse
+	(*RunInitiateRequest)(nil),      // 17: eg.interp.events.RunInitiateRequest
+	(*RunInitiateResult)(nil),       // 18: eg.interp.events.RunInitiateResult
+	(*RunCancelRequest)(nil),        // 19: eg.interp.events.RunCancelRequest
+	(*RunCancelResponse)(nil),       // 20: eg.interp.events.RunCancelResponse
+	(*RunWatchRequest)(nil),         // 21: eg.interp.events.RunWatchRequest
+	(*DispatchRequest)(nil),         // 22: eg.interp.events.DispatchRequest
+	(*DispatchResponse)(nil),        // 23: eg.interp.events.DispatchResponse
+	(*RunUploadChunk_Metadata)(nil), // 24: eg.interp.events.RunUploadChunk.Metadata
 }
 var file_eg_interp_events_proto_depIdxs = []int32{
 	0,  // 0: eg.interp.events.Op.state:type_name -> eg.interp.events.Op.State
-	2,  // 1: eg.interp.events.Message.preamble:type_name -> eg.interp.events.LogHeader
-	3,  // 2: eg.interp.events.Message.heartbeat:type_name -> eg.interp.events.Heartbeat
-	4,  // 3: eg.interp.events.Message.op:type_name -> eg.interp.events.Op
-	5,  // 4: eg.interp.events.Message.metric:type_name -> eg.interp.events.Metric
-	6,  // 5: eg.interp.events.Message.coverage:type_name -> eg.interp.events.Coverage
-	19, // 6: eg.interp.events.RunUploadChunk.metadata:type_name -> eg.interp.events.RunUploadChunk.Metadata
-	1,  // 7: eg.interp.events.RunUploadResponse.run:type_name -> eg.interp.events.RunMetadata
-	1,  // 8: eg.interp.events.RunLogRequest.run:type_name -> eg.interp.events.RunMetadata
-	1,  // 9: eg.interp.events.RunWatchRequest.run:type_name -> eg.interp.events.RunMetadata
-	7,  // 10: eg.interp.events.DispatchRequest.messages:type_name -> eg.interp.events.Message
-	8,  // 11: eg.interp.events.Agent.Upload:input_type -> eg.interp.events.RunUploadChunk
-	14, // 12: eg.interp.events.Agent.Cancel:input_type -> eg.interp.events.RunCancelRequest
-	10, // 13: eg.interp.events.Agent.Logs:input_type -> eg.interp.events.RunLogRequest
-	16, // 14: eg.interp.events.Agent.Watch:input_type -> eg.interp.events.RunWatchRequest
-	17, // 15: eg.interp.events.Events.Dispatch:input_type -> eg.interp.events.DispatchRequest
-	17, // 16: eg.interp.events.Runner.Dispatch:input_type -> eg.interp.events.DispatchRequest
-	9,  // 17: eg.interp.events.Agent.Upload:output_type -> eg.interp.events.RunUploadResponse
-	15, // 18: eg.interp.events.Agent.Cancel:output_type -> eg.interp.events.RunCancelResponse
-	11, // 19: eg.interp.events.Agent.Logs:output_type -> eg.interp.events.RunLogResponse
-	7,  // 20: eg.interp.events.Agent.Watch:output_type -> eg.interp.events.Message
-	18, // 21: eg.interp.events.Events.Dispatch:output_type -> eg.interp.events.DispatchResponse
-	18, // 22: eg.interp.events.Runner.Dispatch:output_type -> eg.interp.events.DispatchResponse
-	17, // [17:23] is the sub-list for method output_type
-	11, // [11:17] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	8,  // 1: eg.interp.events.Coverage.lines:type_name -> eg.interp.events.CoverageLine
+	1,  // 2: eg.interp.events.TestResult.status:type_name -> eg.interp.events.TestResult.Status
+	3,  // 3: eg.interp.events.Message.preamble:type_name -> eg.interp.events.LogHeader
+	4,  // 4: eg.interp.events.Message.heartbeat:type_name -> eg.interp.events.Heartbeat
+	5,  // 5: eg.interp.events.Message.op:type_name -> eg.interp.events.Op
+	6,  // 6: eg.interp.events.Message.metric:type_name -> eg.interp.events.Metric
+	7,  // 7: eg.interp.events.Message.coverage:type_name -> eg.interp.events.Coverage
+	9,  // 8: eg.interp.events.Message.output:type_name -> eg.interp.events.Output
+	10, // 9: eg.interp.events.Message.test:type_name -> eg.interp.events.TestResult
+	11, // 10: eg.interp.events.Message.network:type_name -> eg.interp.events.NetworkViolation
+	24, // 11: eg.interp.events.RunUploadChunk.metadata:type_name -> eg.interp.events.RunUploadChunk.Metadata
+	2,  // 12: eg.interp.events.RunUploadResponse.run:type_name -> eg.interp.events.RunMetadata
+	2,  // 13: eg.interp.events.RunLogRequest.run:type_name -> eg.interp.events.RunMetadata
+	2,  // 14: eg.interp.events.RunCancelRequest.run:type_name -> eg.interp.events.RunMetadata
+	2,  // 15: eg.interp.events.RunWatchRequest.run:type_name -> eg.interp.events.RunMetadata
+	12, // 16: eg.interp.events.DispatchRequest.messages:type_name -> eg.interp.events.Message
+	13, // 17: eg.interp.events.Agent.Upload:input_type -> eg.interp.events.RunUploadChunk
+	19, // 18: eg.interp.events.Agent.Cancel:input_type -> eg.interp.events.RunCancelRequest
+	15, // 19: eg.interp.events.Agent.Logs:input_type -> eg.interp.events.RunLogRequest
+	21, // 20: eg.interp.events.Agent.Watch:input_type -> eg.interp.events.RunWatchRequest
+	22, // 21: eg.interp.events.Events.Dispatch:input_type -> eg.interp.events.DispatchRequest
+	22, // 22: eg.interp.events.Runner.Dispatch:input_type -> eg.interp.events.DispatchRequest
+	14, // 23: eg.interp.events.Agent.Upload:output_type -> eg.interp.events.RunUploadResponse
+	20, // 24: eg.interp.events.Agent.Cancel:output_type -> eg.interp.events.RunCancelResponse
+	16, // 25: eg.interp.events.Agent.Logs:output_type -> eg.interp.events.RunLogResponse
+	12, // 26: eg.interp.events.Agent.Watch:output_type -> eg.interp.events.Message
+	23, // 27: eg.interp.events.Events.Dispatch:output_type -> eg.interp.events.DispatchResponse
+	23, // 28: eg.interp.events.Runner.Dispatch:output_type -> eg.interp.events.DispatchResponse
+	23, // [23:29] is the sub-list for method output_type
+	17, // [17:23] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
 }
 
 func init() { file_eg_interp_events_proto_init() }
@@ -1441,7 +1905,7 @@ func file_eg_interp_events_proto_init() {
 			}
 		}
 		file_eg_interp_events_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Message); i {
+			switch v := v.(*CoverageLine); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1453,7 +1917,7 @@ func file_eg_interp_events_proto_init() {
 			}
 		}
 		file_eg_interp_events_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RunUploadChunk); i {
+			switch v := v.(*Output); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1465,7 +1929,7 @@ func file_eg_interp_events_proto_init() {
 			}
 		}
 		file_eg_interp_events_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RunUploadResponse); i {
+			switch v := v.(*TestResult); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1477,7 +1941,7 @@ func file_eg_interp_events_proto_init() {
 			}
 		}
 		file_eg_interp_events_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RunLogRequest); i {
+			switch v := v.(*NetworkViolation); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1489,7 +1953,7 @@ func file_eg_interp_events_proto_init() {
 			}
 		}
 		file_eg_interp_events_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RunLogResponse); i {
+			switch v := v.(*Message); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1501,7 +1965,7 @@ func file_eg_interp_events_proto_init() {
 			}
 		}
 		file_eg_interp_events_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RunInitiateRequest); i {
+			switch v := v.(*RunUploadChunk); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1513,7 +1977,7 @@ func file_eg_interp_events_proto_init() {
 			}
 		}
 		file_eg_interp_events_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RunInitiateResult); i {
+			switch v := v.(*RunUploadResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1525,7 +1989,7 @@ func file_eg_interp_events_proto_init() {
 			}
 		}
 		file_eg_interp_events_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RunCancelRequest); i {
+			switch v := v.(*RunLogRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1537,7 +2001,7 @@ func file_eg_interp_events_proto_init() {
 			}
 		}
 		file_eg_interp_events_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RunCancelResponse); i {
+			switch v := v.(*RunLogResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1549,7 +2013,7 @@ func file_eg_interp_events_proto_init() {
 			}
 		}
 		file_eg_interp_events_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RunWatchRequest); i {
+			switch v := v.(*RunInitiateRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1561,7 +2025,7 @@ func file_eg_interp_events_proto_init() {
 			}
 		}
 		file_eg_interp_events_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DispatchRequest); i {
+			switch v := v.(*RunInitiateResult); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1573,7 +2037,7 @@ func file_eg_interp_events_proto_init() {
 			}
 		}
 		file_eg_interp_events_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DispatchResponse); i {
+			switch v := v.(*RunCancelRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1585,6 +2049,54 @@ func file_eg_interp_events_proto_init() {
 			}
 		}
 		file_eg_interp_events_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunCancelResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eg_interp_events_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunWatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eg_interp_events_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DispatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eg_interp_events_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DispatchResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eg_interp_events_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RunUploadChunk_Metadata); i {
 			case 0:
 				return &v.state
@@ -1597,14 +2109,17 @@ func file_eg_interp_events_proto_init() {
 			}
 		}
 	}
-	file_eg_interp_events_proto_msgTypes[6].OneofWrappers = []interface{}{
+	file_eg_interp_events_proto_msgTypes[10].OneofWrappers = []interface{}{
 		(*Message_Preamble)(nil),
 		(*Message_Heartbeat)(nil),
 		(*Message_Op)(nil),
 		(*Message_Metric)(nil),
 		(*Message_Coverage)(nil),
+		(*Message_Output)(nil),
+		(*Message_Test)(nil),
+		(*Message_Network)(nil),
 	}
-	file_eg_interp_events_proto_msgTypes[7].OneofWrappers = []interface{}{
+	file_eg_interp_events_proto_msgTypes[11].OneofWrappers = []interface{}{
 		(*RunUploadChunk_None)(nil),
 		(*RunUploadChunk_Metadata_)(nil),
 	}
@@ -1613,8 +2128,8 @@ func file_eg_interp_events_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_eg_interp_events_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   19,
+			NumEnums:      2,
+			NumMessages:   23,
 			NumExtensions: 0,
 			NumServices:   3,
 		},
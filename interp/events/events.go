@@ -1,12 +1,15 @@
 package events
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"io"
 	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,10 +17,13 @@ import (
 	"github.com/egdaemon/eg/internal/iox"
 	"github.com/egdaemon/eg/internal/protobuflog"
 	"github.com/gofrs/uuid/v5"
+	"google.golang.org/grpc/metadata"
 )
 
 const (
 	format = "2006.01.02.15.04.05.log"
+	// grpc metadata key containing the path of the operation a request originated from.
+	MetadataOperationPath = "eg.operation.path"
 )
 
 func NewMessage(evt isMessage_Event) *Message {
@@ -60,6 +66,14 @@ func OpState(err error) Op_State {
 		return Op_Completed
 	}
 
+	if errors.Is(err, context.DeadlineExceeded) {
+		return Op_TimedOut
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return Op_Cancelled
+	}
+
 	return Op_Error
 }
 
@@ -75,6 +89,38 @@ func NewCoverage(t *Coverage) *Message {
 	})
 }
 
+func NewOutput(t *Output) *Message {
+	return NewMessage(&Message_Output{
+		Output: t,
+	})
+}
+
+func NewTestResult(t *TestResult) *Message {
+	return NewMessage(&Message_Test{
+		Test: t,
+	})
+}
+
+func NewNetworkViolation(t *NetworkViolation) *Message {
+	return NewMessage(&Message_Network{
+		Network: t,
+	})
+}
+
+// IncomingOperationPath returns the path of the operation that issued the request.
+func IncomingOperationPath(ctx context.Context) []string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	for _, v := range md.Get(MetadataOperationPath) {
+		return strings.FieldsFunc(v, func(r rune) bool { return r == '/' })
+	}
+
+	return nil
+}
+
 func NewDispatch(m ...*Message) *DispatchRequest {
 	return &DispatchRequest{Messages: m}
 }
@@ -169,6 +215,10 @@ func detectFirstLogTimestamp(dir string) time.Time {
 		return filepath.SkipAll
 	})
 
+	if errors.Is(err, fs.ErrNotExist) {
+		return time.Now()
+	}
+
 	if err != nil {
 		log.Println("unable to detect first log", err)
 		return time.Now()
@@ -190,39 +240,83 @@ type Reader struct {
 	dir       string
 	duration  time.Duration
 	current   time.Time
+	fh        *os.File // current log, remains open until its window elapses.
+	pending   []byte   // bytes read from the current log that have not been decoded.
 	batchSize int
 }
 
+// Read the next batch of messages, returns io.EOF when no messages are available.
+// only the bytes appended to the log since the previous read are read.
 func (t *Reader) Read(ctx context.Context, dst *[]*Message) (err error) {
-	var (
-		fh      *os.File
-		decoder *protobuflog.Decoder[*Message]
-		buf     = make([]*Message, 0, t.batchSize)
-	)
+	for {
+		var (
+			encoded []byte
+			n       int
+		)
 
-	replacefh := func(old *os.File, path string) (_ *os.File, err error) {
-		errorsx.Log(errorsx.Wrap(iox.MaybeClose(old), "unable to close log file"))
-		return os.Open(path)
-	}
+		if t.fh == nil {
+			logname := filepath.Join(t.dir, t.current.Truncate(t.duration).Format(format))
+			if t.fh, err = os.Open(logname); os.IsNotExist(err) {
+				if t.next() {
+					continue
+				}
 
-	logname := filepath.Join(t.dir, t.current.Truncate(t.duration).Format(format))
+				return io.EOF
+			} else if err != nil {
+				return err
+			}
+		}
 
-	if fh, err = replacefh(fh, logname); os.IsNotExist(err) {
-		return io.EOF
-	} else if err != nil {
-		return err
+		if encoded, err = io.ReadAll(t.fh); err != nil {
+			return err
+		}
+		t.pending = append(t.pending, encoded...)
+
+		src := bytes.NewReader(t.pending)
+		consumed := 0
+		for ; n < t.batchSize; n++ {
+			m := &Message{}
+			// messages that are partially written are read once the writer completes them.
+			if cause := protobuflog.Decode(src, m); cause == io.EOF || cause == io.ErrUnexpectedEOF {
+				break
+			} else if cause != nil {
+				return cause
+			}
+
+			*dst = append(*dst, m)
+			consumed = len(t.pending) - src.Len()
+		}
+		t.pending = t.pending[consumed:]
+
+		if n > 0 {
+			return nil
+		}
+
+		if !t.next() {
+			return io.EOF
+		}
 	}
-	decoder = protobuflog.NewDecoder[*Message](fh)
+}
 
-	if err = decoder.Decode(&buf); err != nil {
-		return err
+// Close the current log.
+func (t *Reader) Close() error {
+	fh := t.fh
+	t.fh, t.pending = nil, nil
+	if fh == nil {
+		return nil
 	}
 
-	*dst = append(*dst, buf...)
+	return fh.Close()
+}
 
-	if len(buf) < cap(buf) && time.Since(t.current) > t.duration {
-		t.current = t.current.Add(t.duration)
+// advance to the next log once the current log's window has elapsed.
+func (t *Reader) next() bool {
+	end := t.current.Truncate(t.duration).Add(t.duration)
+	if time.Now().Before(end) {
+		return false
 	}
 
-	return nil
+	t.current = end
+	errorsx.Log(errorsx.Wrap(t.Close(), "unable to close log file"))
+	return true
 }
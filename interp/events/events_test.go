@@ -0,0 +1,69 @@
+package events_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/egdaemon/eg/internal/protobuflog"
+	"github.com/egdaemon/eg/interp/events"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReader(t *testing.T) {
+	t.Run("messages are only read once", func(t *testing.T) {
+		ctx := context.Background()
+		dir := t.TempDir()
+		l := events.NewLog(dir)
+		r := events.NewReader(dir)
+
+		var buf []*events.Message
+		require.ErrorIs(t, r.Read(ctx, &buf), io.EOF)
+
+		require.NoError(t, l.Write(ctx, events.NewHeartbeat(), events.NewHeartbeat()))
+		require.NoError(t, r.Read(ctx, &buf))
+		require.Len(t, buf, 2)
+
+		require.ErrorIs(t, r.Read(ctx, &buf), io.EOF)
+		require.Len(t, buf, 2)
+
+		require.NoError(t, l.Write(ctx, events.NewHeartbeat()))
+		require.NoError(t, r.Read(ctx, &buf))
+		require.Len(t, buf, 3)
+	})
+	t.Run("partially written messages are read once completed", func(t *testing.T) {
+		ctx := context.Background()
+		dir := t.TempDir()
+		l := events.NewLog(dir)
+		r := events.NewReader(dir)
+		defer r.Close()
+
+		var buf []*events.Message
+		require.NoError(t, l.Write(ctx, events.NewHeartbeat()))
+		require.NoError(t, r.Read(ctx, &buf))
+		require.Len(t, buf, 1)
+
+		logs, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+
+		encoded, err := protobuflog.Encode(events.NewHeartbeat())
+		require.NoError(t, err)
+
+		fh, err := os.OpenFile(filepath.Join(dir, logs[0].Name()), os.O_APPEND|os.O_WRONLY, 0600)
+		require.NoError(t, err)
+		defer fh.Close()
+
+		_, err = fh.Write(encoded[:len(encoded)/2])
+		require.NoError(t, err)
+		require.ErrorIs(t, r.Read(ctx, &buf), io.EOF)
+		require.Len(t, buf, 1)
+
+		_, err = fh.Write(encoded[len(encoded)/2:])
+		require.NoError(t, err)
+		require.NoError(t, r.Read(ctx, &buf))
+		require.Len(t, buf, 2)
+	})
+}
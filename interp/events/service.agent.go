@@ -10,20 +10,36 @@ import (
 	"time"
 
 	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/langx"
 	"github.com/egdaemon/eg/internal/md5x"
 	"github.com/gofrs/uuid/v5"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-func NewServiceAgent(root string) *AgentService {
-	return &AgentService{
-		dir: root,
+type AgentServiceOption func(*AgentService)
+
+// AgentServiceOptionCancel cancels the run with the provided id.
+func AgentServiceOptionCancel(fn func(ctx context.Context, id uuid.UUID) error) AgentServiceOption {
+	return func(as *AgentService) {
+		as.cancel = fn
 	}
 }
 
+func NewServiceAgent(root string, options ...AgentServiceOption) *AgentService {
+	return langx.Autoptr(langx.Clone(AgentService{
+		dir: root,
+		cancel: func(ctx context.Context, id uuid.UUID) error {
+			return status.Error(codes.Unimplemented, "cancellation is not supported")
+		},
+	}, options...))
+}
+
 type AgentService struct {
 	UnimplementedAgentServer
-	dir string
+	dir    string
+	cancel func(ctx context.Context, id uuid.UUID) error
 }
 
 func (t *AgentService) Bind(host grpc.ServiceRegistrar) {
@@ -78,14 +94,25 @@ func (*AgentService) Initiate(ctx context.Context, evt *RunInitiateRequest) (*Ru
 	panic("unimplemented")
 }
 
-func (*AgentService) Cancel(ctx context.Context, evt *RunCancelRequest) (*RunCancelResponse, error) {
-	panic("unimplemented")
+// Cancel the run, the run is given a grace period to perform its cleanup operations.
+func (t *AgentService) Cancel(ctx context.Context, evt *RunCancelRequest) (*RunCancelResponse, error) {
+	id, err := uuid.FromBytes(evt.GetRun().GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid run id")
+	}
+
+	if err = t.cancel(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return &RunCancelResponse{}, nil
 }
 
 func (t *AgentService) Logs(l *RunLogRequest, s Agent_LogsServer) (err error) {
 	r := NewReader(
 		NewLogDirFromRun(t.dir, l.Run),
 	)
+	defer r.Close()
 
 	for err == nil {
 		var (
@@ -108,6 +135,7 @@ func (t *AgentService) Watch(rw *RunWatchRequest, s Agent_WatchServer) (err erro
 	r := NewReader(
 		NewLogDirFromRun(t.dir, rw.Run),
 	)
+	defer r.Close()
 
 	for err == nil {
 		var (
@@ -5,18 +5,31 @@ import (
 	"database/sql"
 
 	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/langx"
 	"google.golang.org/grpc"
 )
 
-func NewServiceDispatch(db *sql.DB) *EventsService {
-	return &EventsService{
-		db: db,
+type DispatchOption func(*EventsService)
+
+// DispatchOptionLog appends every dispatched message to the log, allowing the run to be watched.
+func DispatchOptionLog(l *Log) DispatchOption {
+	return func(es *EventsService) {
+		es.log = l
 	}
 }
 
+func NewServiceDispatch(db *sql.DB, options ...DispatchOption) *EventsService {
+	svc := langx.Clone(EventsService{
+		db: db,
+	}, options...)
+
+	return &svc
+}
+
 type EventsService struct {
 	UnimplementedEventsServer
-	db *sql.DB
+	db  *sql.DB
+	log *Log
 }
 
 func (t *EventsService) Bind(host grpc.ServiceRegistrar) {
@@ -28,5 +41,13 @@ func (t *EventsService) Dispatch(ctx context.Context, dr *DispatchRequest) (_ *D
 		return nil, errorsx.WithStack(err)
 	}
 
+	if t.log == nil {
+		return &DispatchResponse{}, nil
+	}
+
+	if err = t.log.Write(ctx, dr.Messages...); err != nil {
+		return nil, errorsx.Wrap(err, "unable to write event log")
+	}
+
 	return &DispatchResponse{}, nil
 }
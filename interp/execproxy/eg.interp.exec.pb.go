@@ -129,6 +129,69 @@ func (*ExecResponse) Descriptor() ([]byte, []int) {
 	return file_eg_interp_exec_proto_rawDescGZIP(), []int{1}
 }
 
+type ExecOutput struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Stdout []byte `protobuf:"bytes,1,opt,name=stdout,proto3" json:"stdout,omitempty"`
+	Stderr []byte `protobuf:"bytes,2,opt,name=stderr,proto3" json:"stderr,omitempty"`
+	Exit   int32  `protobuf:"varint,3,opt,name=exit,proto3" json:"exit,omitempty"`
+}
+
+func (x *ExecOutput) Reset() {
+	*x = ExecOutput{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eg_interp_exec_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExecOutput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecOutput) ProtoMessage() {}
+
+func (x *ExecOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_eg_interp_exec_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecOutput.ProtoReflect.Descriptor instead.
+func (*ExecOutput) Descriptor() ([]byte, []int) {
+	return file_eg_interp_exec_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ExecOutput) GetStdout() []byte {
+	if x != nil {
+		return x.Stdout
+	}
+	return nil
+}
+
+func (x *ExecOutput) GetStderr() []byte {
+	if x != nil {
+		return x.Stderr
+	}
+	return nil
+}
+
+func (x *ExecOutput) GetExit() int32 {
+	if x != nil {
+		return x.Exit
+	}
+	return 0
+}
+
 var File_eg_interp_exec_proto protoreflect.FileDescriptor
 
 var file_eg_interp_exec_proto_rawDesc = []byte{
@@ -142,12 +205,22 @@ var file_eg_interp_exec_proto_rawDesc = []byte{
 	0x67, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x65, 0x6e, 0x76, 0x69, 0x72,
 	0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x65, 0x6e,
 	0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x0e, 0x0a, 0x0c, 0x45, 0x78, 0x65,
-	0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0x4c, 0x0a, 0x05, 0x50, 0x72, 0x6f,
-	0x78, 0x79, 0x12, 0x43, 0x0a, 0x04, 0x45, 0x78, 0x65, 0x63, 0x12, 0x1b, 0x2e, 0x65, 0x67, 0x2e,
+	0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x50, 0x0a, 0x0a, 0x45, 0x78, 0x65,
+	0x63, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x64, 0x6f, 0x75,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x73, 0x74, 0x64, 0x6f, 0x75, 0x74, 0x12,
+	0x16, 0x0a, 0x06, 0x73, 0x74, 0x64, 0x65, 0x72, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x06, 0x73, 0x74, 0x64, 0x65, 0x72, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x65, 0x78, 0x69, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x65, 0x78, 0x69, 0x74, 0x32, 0x93, 0x01, 0x0a, 0x05,
+	0x50, 0x72, 0x6f, 0x78, 0x79, 0x12, 0x43, 0x0a, 0x04, 0x45, 0x78, 0x65, 0x63, 0x12, 0x1b, 0x2e,
+	0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x2e, 0x45,
+	0x78, 0x65, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x65, 0x67, 0x2e,
 	0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x2e, 0x45, 0x78, 0x65, 0x63,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74,
-	0x65, 0x72, 0x70, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x45, 0x0a, 0x06, 0x4f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x12, 0x1b, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70,
+	0x2e, 0x65, 0x78, 0x65, 0x63, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1a, 0x2e, 0x65, 0x67, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x2e, 0x65, 0x78,
+	0x65, 0x63, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x22, 0x00, 0x30,
+	0x01, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -162,16 +235,19 @@ func file_eg_interp_exec_proto_rawDescGZIP() []byte {
 	return file_eg_interp_exec_proto_rawDescData
 }
 
-var file_eg_interp_exec_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_eg_interp_exec_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_eg_interp_exec_proto_goTypes = []interface{}{
 	(*ExecRequest)(nil),  // 0: eg.interp.exec.ExecRequest
 	(*ExecResponse)(nil), // 1: eg.interp.exec.ExecResponse
+	(*ExecOutput)(nil),   // 2: eg.interp.exec.ExecOutput
 }
 var file_eg_interp_exec_proto_depIdxs = []int32{
 	0, // 0: eg.interp.exec.Proxy.Exec:input_type -> eg.interp.exec.ExecRequest
-	1, // 1: eg.interp.exec.Proxy.Exec:output_type -> eg.interp.exec.ExecResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
+	0, // 1: eg.interp.exec.Proxy.Output:input_type -> eg.interp.exec.ExecRequest
+	1, // 2: eg.interp.exec.Proxy.Exec:output_type -> eg.interp.exec.ExecResponse
+	2, // 3: eg.interp.exec.Proxy.Output:output_type -> eg.interp.exec.ExecOutput
+	2, // [2:4] is the sub-list for method output_type
+	0, // [0:2] is the sub-list for method input_type
 	0, // [0:0] is the sub-list for extension type_name
 	0, // [0:0] is the sub-list for extension extendee
 	0, // [0:0] is the sub-list for field type_name
@@ -207,6 +283,18 @@ func file_eg_interp_exec_proto_init() {
 				return nil
 			}
 		}
+		file_eg_interp_exec_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecOutput); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -214,7 +302,7 @@ func file_eg_interp_exec_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_eg_interp_exec_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
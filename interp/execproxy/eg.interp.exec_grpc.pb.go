@@ -19,7 +19,8 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Proxy_Exec_FullMethodName = "/eg.interp.exec.Proxy/Exec"
+	Proxy_Exec_FullMethodName   = "/eg.interp.exec.Proxy/Exec"
+	Proxy_Output_FullMethodName = "/eg.interp.exec.Proxy/Output"
 )
 
 // ProxyClient is the client API for Proxy service.
@@ -27,6 +28,7 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ProxyClient interface {
 	Exec(ctx context.Context, in *ExecRequest, opts ...grpc.CallOption) (*ExecResponse, error)
+	Output(ctx context.Context, in *ExecRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExecOutput], error)
 }
 
 type proxyClient struct {
@@ -47,11 +49,31 @@ func (c *proxyClient) Exec(ctx context.Context, in *ExecRequest, opts ...grpc.Ca
 	return out, nil
 }
 
+func (c *proxyClient) Output(ctx context.Context, in *ExecRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExecOutput], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Proxy_ServiceDesc.Streams[0], Proxy_Output_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExecRequest, ExecOutput]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Proxy_OutputClient = grpc.ServerStreamingClient[ExecOutput]
+
 // ProxyServer is the server API for Proxy service.
 // All implementations must embed UnimplementedProxyServer
 // for forward compatibility.
 type ProxyServer interface {
 	Exec(context.Context, *ExecRequest) (*ExecResponse, error)
+	Output(*ExecRequest, grpc.ServerStreamingServer[ExecOutput]) error
 	mustEmbedUnimplementedProxyServer()
 }
 
@@ -65,6 +87,9 @@ type UnimplementedProxyServer struct{}
 func (UnimplementedProxyServer) Exec(context.Context, *ExecRequest) (*ExecResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Exec not implemented")
 }
+func (UnimplementedProxyServer) Output(*ExecRequest, grpc.ServerStreamingServer[ExecOutput]) error {
+	return status.Error(codes.Unimplemented, "method Output not implemented")
+}
 func (UnimplementedProxyServer) mustEmbedUnimplementedProxyServer() {}
 func (UnimplementedProxyServer) testEmbeddedByValue()               {}
 
@@ -104,6 +129,17 @@ func _Proxy_Exec_Handler(srv interface{}, ctx context.Context, dec func(interfac
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Proxy_Output_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExecRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProxyServer).Output(m, &grpc.GenericServerStream[ExecRequest, ExecOutput]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Proxy_OutputServer = grpc.ServerStreamingServer[ExecOutput]
+
 // Proxy_ServiceDesc is the grpc.ServiceDesc for Proxy service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -116,6 +152,12 @@ var Proxy_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _Proxy_Exec_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Output",
+			Handler:       _Proxy_Output_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "eg.interp.exec.proto",
 }
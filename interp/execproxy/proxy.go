@@ -2,37 +2,73 @@ package execproxy
 
 import (
 	context "context"
+	"errors"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
 
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/langx"
+	"github.com/egdaemon/eg/internal/redactx"
+	"github.com/egdaemon/eg/interp/events"
+	"github.com/egdaemon/eg/interp/provenance"
 	"github.com/egdaemon/eg/runtime/x/wasi/execx"
 	"google.golang.org/grpc"
 )
 
-func NewExecProxy(root string, environ []string) *ExecProxy {
-	return &ExecProxy{
+type ExecProxyOption func(*ExecProxy)
+
+// ExecProxyOptionEvents dispatches the output of commands as events attributed
+// to the operation that executed them.
+func ExecProxyOptionEvents(d events.EventsClient) ExecProxyOption {
+	return func(ep *ExecProxy) {
+		ep.events = d
+	}
+}
+
+// ExecProxyOptionRedact masks secrets within the output of commands.
+func ExecProxyOptionRedact(r *redactx.Redactor) ExecProxyOption {
+	return func(ep *ExecProxy) {
+		ep.redact = r
+	}
+}
+
+// ExecProxyOptionProvenance records the commands executed as materials of the run, secrets are
+// redacted from their arguments.
+func ExecProxyOptionProvenance(record func(ctx context.Context, c provenance.Command)) ExecProxyOption {
+	return func(ep *ExecProxy) {
+		ep.record = record
+	}
+}
+
+func NewExecProxy(root string, environ []string, options ...ExecProxyOption) *ExecProxy {
+	svc := langx.Clone(ExecProxy{
 		dir:     root,
 		environ: environ,
-	}
+	}, options...)
+
+	return &svc
 }
 
 type ExecProxy struct {
 	UnimplementedProxyServer
 	dir     string
 	environ []string
+	events  events.EventsClient
+	redact  *redactx.Redactor
+	record  func(ctx context.Context, c provenance.Command)
 }
 
 func (t *ExecProxy) Bind(host grpc.ServiceRegistrar) {
 	RegisterProxyServer(host, t)
 }
 
-// Upload implements RunServer.
-func (t *ExecProxy) Exec(ctx context.Context, req *ExecRequest) (resp *ExecResponse, err error) {
-	var (
-		cmd *exec.Cmd = exec.CommandContext(ctx, req.Cmd, req.Arguments...)
-	)
-
+func (t *ExecProxy) command(ctx context.Context, req *ExecRequest) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, req.Cmd, req.Arguments...)
 	cmd.Dir = req.Dir
 	if !filepath.IsAbs(cmd.Dir) {
 		cmd.Dir = filepath.Join(t.dir, cmd.Dir)
@@ -40,8 +76,56 @@ func (t *ExecProxy) Exec(ctx context.Context, req *ExecRequest) (resp *ExecRespo
 
 	cmd.Env = append(t.environ, req.Environment...)
 	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+
+	// when the caller's deadline is exceeded terminate rather than kill the process allowing
+	// it to clean up its children, i.e. sudo relays the signal to the command it is running.
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(syscall.SIGTERM)
+	}
+	cmd.WaitDelay = 10 * time.Second
+
+	if t.record != nil {
+		args := make([]string, 0, len(req.Arguments))
+		for _, a := range req.Arguments {
+			args = append(args, string(t.redact.Redact([]byte(a))))
+		}
+
+		t.record(ctx, provenance.Command{Command: req.Cmd, Arguments: args, Directory: cmd.Dir})
+	}
+
+	return cmd
+}
+
+// tee the command's output to the events service when configured, secrets are redacted
+// before the output reaches either destination. the returned function flushes any held back
+// output and must be called once the command has completed.
+func (t *ExecProxy) tee(ctx context.Context, stdout, stderr io.Writer) (_ io.Writer, _ io.Writer, flush func()) {
+	if t.events == nil {
+		stdout, stderr = t.redact.Writer(stdout), t.redact.Writer(stderr)
+		return stdout, stderr, func() {
+			errorsx.Log(errorsx.Wrap(errorsx.Compact(redactx.Flush(stdout), redactx.Flush(stderr)), "unable to flush command output"))
+		}
+	}
+
+	var (
+		path = events.IncomingOperationPath(ctx)
+		dctx = context.WithoutCancel(ctx) // trailing output of cancelled commands is still recorded.
+		eout = newEventWriter(dctx, t.events, func(b []byte) *events.Output { return &events.Output{Path: path, Stdout: b} })
+		eerr = newEventWriter(dctx, t.events, func(b []byte) *events.Output { return &events.Output{Path: path, Stderr: b} })
+	)
+
+	stdout, stderr = t.redact.Writer(io.MultiWriter(stdout, eout)), t.redact.Writer(io.MultiWriter(stderr, eerr))
+	return stdout, stderr, func() {
+		errorsx.Log(errorsx.Wrap(errorsx.Compact(redactx.Flush(stdout), redactx.Flush(stderr), eout.Flush(), eerr.Flush()), "unable to flush command output"))
+	}
+}
+
+// Upload implements RunServer.
+func (t *ExecProxy) Exec(ctx context.Context, req *ExecRequest) (resp *ExecResponse, err error) {
+	var flush func()
+	cmd := t.command(ctx, req)
+	cmd.Stdout, cmd.Stderr, flush = t.tee(ctx, os.Stdout, os.Stderr)
+	defer flush()
 
 	if err = execx.MaybeRun(cmd); err != nil {
 		return nil, err
@@ -49,3 +133,113 @@ func (t *ExecProxy) Exec(ctx context.Context, req *ExecRequest) (resp *ExecRespo
 
 	return &ExecResponse{}, nil
 }
+
+// Output implements ProxyServer, streaming the command's stdout and stderr back to the caller.
+func (t *ExecProxy) Output(req *ExecRequest, stream grpc.ServerStreamingServer[ExecOutput]) (err error) {
+	var (
+		m     sync.Mutex
+		flush func()
+		cmd   = t.command(stream.Context(), req)
+	)
+
+	cmd.Stdout, cmd.Stderr, flush = t.tee(
+		stream.Context(),
+		outputWriter{m: &m, stream: stream, encode: func(b []byte) *ExecOutput { return &ExecOutput{Stdout: b} }},
+		outputWriter{m: &m, stream: stream, encode: func(b []byte) *ExecOutput { return &ExecOutput{Stderr: b} }},
+	)
+
+	// flush before reporting the exit code, it must be the final message.
+	err = execx.MaybeRun(cmd)
+	if flush(); err == nil {
+		return nil
+	}
+
+	// report the exit code allowing callers to decide how to handle the failure.
+	if exit := (*exec.ExitError)(nil); errors.As(err, &exit) {
+		return errorsx.Compact(stream.Send(&ExecOutput{Exit: int32(exit.ExitCode())}), err)
+	}
+
+	return err
+}
+
+// forwards writes to the stream, stdout and stderr are written concurrently so they share a lock.
+type outputWriter struct {
+	m      *sync.Mutex
+	stream grpc.ServerStreamingServer[ExecOutput]
+	encode func([]byte) *ExecOutput
+}
+
+func (t outputWriter) Write(b []byte) (int, error) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if err := t.stream.Send(t.encode(b)); err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+const (
+	// maximum duration output is buffered before being dispatched.
+	eventInterval = 250 * time.Millisecond
+	// output is dispatched immediately once the buffer reaches this size.
+	eventBuffer = 32 * 1024
+)
+
+// buffers writes and dispatches them as output events, commands emit output in many small
+// chunks so dispatching every write would issue a request per chunk. failures are logged
+// rather than failing the command.
+type eventWriter struct {
+	m       sync.Mutex
+	ctx     context.Context
+	d       events.EventsClient
+	encode  func([]byte) *events.Output
+	pending []byte
+	timer   *time.Timer
+}
+
+func newEventWriter(ctx context.Context, d events.EventsClient, encode func([]byte) *events.Output) *eventWriter {
+	return &eventWriter{ctx: ctx, d: d, encode: encode}
+}
+
+func (t *eventWriter) Write(b []byte) (int, error) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	t.pending = append(t.pending, b...)
+
+	if len(t.pending) >= eventBuffer {
+		errorsx.Log(t.dispatch())
+	} else if t.timer == nil {
+		t.timer = time.AfterFunc(eventInterval, func() {
+			errorsx.Log(t.Flush())
+		})
+	}
+
+	return len(b), nil
+}
+
+// Flush dispatches any buffered output.
+func (t *eventWriter) Flush() error {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	return t.dispatch()
+}
+
+func (t *eventWriter) dispatch() error {
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+
+	if len(t.pending) == 0 {
+		return nil
+	}
+
+	pending := t.pending
+	t.pending = nil
+	_, err := t.d.Dispatch(t.ctx, events.NewDispatch(events.NewOutput(t.encode(pending))))
+	return errorsx.Wrap(err, "unable to dispatch command output")
+}
@@ -0,0 +1,39 @@
+package interp
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/fsx"
+	"github.com/egdaemon/eg/interp/runtime/wasi/ffi"
+)
+
+const errCancelled = errorsx.String("workload cancelled")
+
+// interrupts the in flight host calls once the cancellation marker appears.
+func interruptOnCancel(ctx context.Context, marker string, i *ffi.Interrupts) {
+	onCancel(ctx, marker, func() {
+		log.Println("workload cancelled, interrupting in flight operations")
+		i.Interrupt(errCancelled)
+	})
+}
+
+// invokes fn once the cancellation marker appears.
+func onCancel(ctx context.Context, marker string, fn func()) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+
+		if !fsx.FileExists(marker) {
+			continue
+		}
+
+		fn()
+		return
+	}
+}
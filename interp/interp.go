@@ -20,8 +20,10 @@ import (
 	"github.com/egdaemon/eg/internal/fsx"
 	"github.com/egdaemon/eg/internal/langx"
 	"github.com/egdaemon/eg/internal/md5x"
+	"github.com/egdaemon/eg/internal/redactx"
 	"github.com/egdaemon/eg/internal/wasix"
 	"github.com/egdaemon/eg/interp/c8s"
+	"github.com/egdaemon/eg/interp/runtime/wasi/ffi"
 	"github.com/egdaemon/eg/interp/runtime/wasi/ffiegcontainer"
 	"github.com/egdaemon/eg/interp/runtime/wasi/ffiexec"
 	"github.com/egdaemon/eg/interp/runtime/wasi/ffigit"
@@ -46,6 +48,20 @@ func OptionEnviron(s ...string) Option {
 	}
 }
 
+// informs the module it is being analysed instead of executed.
+func OptionAnalysing(b bool) Option {
+	return func(r *runner) {
+		r.analysing = b
+	}
+}
+
+// OptionRedact masks secrets within the output of the module and the commands it executes.
+func OptionRedact(redact *redactx.Redactor) Option {
+	return func(r *runner) {
+		r.redact = redact
+	}
+}
+
 type runtimefn func(r runner, host wazero.HostModuleBuilder) wazero.HostModuleBuilder
 
 // Remote uses the api to implement particular actions like building and running containers.
@@ -60,14 +76,33 @@ func Remote(ctx context.Context, wshost workspaces.Context, aid string, runid st
 		opt(&r)
 	}
 
+	r.stdout, r.stderr = r.redact.Writer(os.Stdout), r.redact.Writer(os.Stderr)
+	defer func() {
+		errorsx.Log(errorsx.Wrap(errorsx.Compact(redactx.Flush(r.stdout), redactx.Flush(r.stderr)), "unable to flush output"))
+	}()
+
 	debugx.Println("interp workspace context", spew.Sdump(wshost))
 
+	// python modules are executed by the interpreter rather than wazero.
+	if interpreted(module) {
+		return r.python(ctx, wshost, aid, runid, module)
+	}
+
 	containers := c8s.NewProxyClient(svc)
 
+	// analysing modules must not modify the working directory.
+	clone := func(r runner) any {
+		if r.analysing {
+			return ffigit.NoopCloneV2
+		}
+
+		return ffigit.CloneV2(wshost.WorkingDir, wshost.RuntimeDir)
+	}
+
 	runtimeenv := func(r runner, host wazero.HostModuleBuilder) wazero.HostModuleBuilder {
 		return host.
 			NewFunctionBuilder().WithFunc(ffigraph.NoopTrace).Export("github.com/egdaemon/eg/runtime/wasi/runtime/graph.Trace").
-			NewFunctionBuilder().WithFunc(ffigraph.Analysing(false)).Export("github.com/egdaemon/eg/runtime/wasi/runtime/graph.Analysing").
+			NewFunctionBuilder().WithFunc(ffigraph.Analysing(r.analysing)).Export("github.com/egdaemon/eg/runtime/wasi/runtime/graph.Analysing").
 			NewFunctionBuilder().WithFunc(ffigraph.NoopTraceEventPush).Export("github.com/egdaemon/eg/runtime/wasi/runtime/graph.Push").
 			NewFunctionBuilder().WithFunc(ffigraph.NoopTraceEventPop).Export("github.com/egdaemon/eg/runtime/wasi/runtime/graph.Pop").
 			NewFunctionBuilder().WithFunc(ffiegcontainer.Pull(func(ctx context.Context, name, wdir string, options ...string) (err error) {
@@ -130,8 +165,8 @@ func Remote(ctx context.Context, wshost workspaces.Context, aid string, runid st
 			}
 			cmd.Env = append(r.environ, cmd.Env...)
 			cmd.Stdin = os.Stdin
-			cmd.Stdout = os.Stdout
-			cmd.Stderr = os.Stderr
+			cmd.Stdout = r.stdout
+			cmd.Stderr = r.stderr
 
 			return cmd
 		})).Export("github.com/egdaemon/eg/runtime/wasi/runtime/ffiexec.Command").
@@ -139,7 +174,7 @@ func Remote(ctx context.Context, wshost workspaces.Context, aid string, runid st
 			ffigit.Commitish(wshost.WorkingDir),
 		).Export("github.com/egdaemon/eg/runtime/wasi/runtime/ffigit.Commitish").
 			NewFunctionBuilder().WithFunc(
-			ffigit.CloneV2(wshost.WorkingDir, wshost.RuntimeDir),
+			clone(r),
 		).Export("github.com/egdaemon/eg/runtime/wasi/runtime/ffigit.CloneV2").
 			NewFunctionBuilder().WithFunc(
 			ffigit.Bearer(wshost.RuntimeDir),
@@ -157,8 +192,12 @@ func Remote(ctx context.Context, wshost workspaces.Context, aid string, runid st
 }
 
 type runner struct {
-	environ  []string
-	initonce *sync.Once
+	environ   []string
+	analysing bool
+	initonce  *sync.Once
+	redact    *redactx.Redactor
+	stdout    io.Writer
+	stderr    io.Writer
 }
 
 func (t runner) perform(ctx context.Context, wshost workspaces.Context, runid, path string, rtb runtimefn) (err error) {
@@ -207,7 +246,7 @@ func (t runner) perform(ctx context.Context, wshost workspaces.Context, runid, p
 	defer outpr.Close()
 	defer outpw.CloseWithError(io.EOF)
 	go func() {
-		_, _err := io.Copy(os.Stdout, outpr)
+		_, _err := io.Copy(t.stdout, outpr)
 		_err = errorsx.Ignore(_err, io.ErrClosedPipe)
 		errorsx.Log(errorsx.Wrapf(_err, "failed copying to stdout: %T", _err))
 		outpw.CloseWithError(_err)
@@ -217,7 +256,7 @@ func (t runner) perform(ctx context.Context, wshost workspaces.Context, runid, p
 	defer errpr.Close()
 	defer errpw.CloseWithError(io.EOF)
 	go func() {
-		_, _err := io.Copy(os.Stderr, errpr)
+		_, _err := io.Copy(t.stderr, errpr)
 		_err = errorsx.Ignore(_err, io.ErrClosedPipe)
 		errorsx.Log(errorsx.Wrap(_err, "failed copying to stderr"))
 		errpw.CloseWithError(_err)
@@ -335,7 +374,15 @@ func (t runner) perform(ctx context.Context, wshost workspaces.Context, runid, p
 
 	debugx.Println("interp initiated", path)
 	defer debugx.Println("interp completed", path)
-	m, err := runtime.InstantiateModule(ctx, c, mcfg.WithName(path))
+
+	// cancelling the workload interrupts the in flight host calls, the module is
+	// then responsible for running its cleanup operations. see eg.OnCancel.
+	interrupts := ffi.NewInterrupts()
+	cctx, done := context.WithCancel(ctx)
+	defer done()
+	go interruptOnCancel(cctx, filepath.Join(wshost.RuntimeDir, eg.CancelFile), interrupts)
+
+	m, err := runtime.InstantiateModule(ffi.WithInterrupts(ctx, interrupts), c, mcfg.WithName(path))
 	if err != nil {
 		return errorsx.Wrap(err, "unable to run module")
 	}
@@ -0,0 +1,105 @@
+package interp
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/egdaemon/eg"
+	"github.com/egdaemon/eg/internal/debugx"
+	"github.com/egdaemon/eg/internal/envx"
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/workspaces"
+)
+
+// interpreted reports if the module is a python zip application, see compile.Python.
+func interpreted(path string) bool {
+	magic := make([]byte, 4)
+
+	src, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer src.Close()
+
+	if _, err = io.ReadFull(src, magic); err != nil {
+		return false
+	}
+
+	return bytes.Equal(magic, []byte("PK\x03\x04"))
+}
+
+// python executes the module within the workload environment. unlike wasm modules the
+// interpreter has direct access to the container, the egpy sdk reaches the
+// control socket through the runtime directory.
+func (t runner) python(ctx context.Context, wshost workspaces.Context, aid, runid, path string) (err error) {
+	environ, err := envx.Build().FromEnv(
+		"PATH",
+		"TERM",
+		"HOME",
+		"LANG",
+	).Var(
+		"CI", envx.String("true", "CI"),
+	).Var(
+		eg.EnvComputeRunID, runid,
+	).Var(
+		eg.EnvComputeAccountID, aid,
+	).Var(
+		eg.EnvComputeModuleNestedLevel, strconv.Itoa(envx.Int(0, eg.EnvComputeModuleNestedLevel)),
+	).Var(
+		eg.EnvComputeLoggingVerbosity, envx.String("-1", eg.EnvComputeLoggingVerbosity),
+	).Var(
+		eg.EnvComputeRuntimeDirectory, wshost.RuntimeDir,
+	).Var(
+		"PWD", wshost.WorkingDir,
+	).Var(
+		"PYTHONUNBUFFERED", "1",
+	).Var(
+		"PYTHONDONTWRITEBYTECODE", "1",
+	).FromEnviron(
+		t.environ...,
+	).FromEnviron(
+		errorsx.Zero(envx.FromPath(eg.DefaultMountRoot(eg.RuntimeDirectory, eg.EnvironFile)))...,
+	).Environ()
+	if err != nil {
+		return errorsx.Wrap(err, "unable to generate module environment")
+	}
+
+	cmd := exec.CommandContext(ctx, "python3", path)
+	cmd.Dir = wshost.WorkingDir
+	cmd.Env = environ
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = t.stdout
+	cmd.Stderr = t.stderr
+
+	// allow the module to run its cleanup operations when the deadline is exceeded.
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(syscall.SIGTERM)
+	}
+	cmd.WaitDelay = 10 * time.Second
+
+	debugx.Println("interp initiated", path)
+	defer debugx.Println("interp completed", path)
+
+	if err = cmd.Start(); err != nil {
+		return errorsx.Wrap(err, "unable to run module")
+	}
+
+	// cancelling the workload interrupts the module, egpy then runs the operations
+	// registered with on_cancel.
+	cctx, done := context.WithCancel(ctx)
+	defer done()
+	go onCancel(cctx, filepath.Join(wshost.RuntimeDir, eg.CancelFile), func() {
+		log.Println("workload cancelled, interrupting module")
+		errorsx.Log(errorsx.Wrap(cmd.Process.Signal(os.Interrupt), "unable to interrupt module"))
+	})
+
+	return errorsx.Wrap(cmd.Wait(), "module failed")
+}
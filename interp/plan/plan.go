@@ -0,0 +1,164 @@
+// Package plan records the operations a module would perform without executing
+// any of its side effects. commands, container builds, runs and module dispatches
+// are stubbed out and recorded instead.
+package plan
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/egdaemon/eg/interp/events"
+)
+
+const (
+	KindPlan      = "plan"
+	KindOperation = "operation"
+	KindExec      = "exec"
+	KindPull      = "pull"
+	KindBuild     = "build"
+	KindRun       = "run"
+	KindModule    = "module"
+)
+
+// Node within the operation tree.
+type Node struct {
+	ID       string  `json:"id"`
+	Kind     string  `json:"kind"`
+	Name     string  `json:"name"`
+	State    string  `json:"state,omitempty"`
+	Detail   string  `json:"detail,omitempty"`
+	Children []*Node `json:"children,omitempty"`
+}
+
+// Skipped reports if the node was not executed, i.e. a conditional operation whose condition was not met.
+func (t *Node) Skipped() bool {
+	return t.State == events.Op_Skipped.String()
+}
+
+type record struct {
+	Node
+	path    []string
+	started time.Time
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{
+		m: &sync.Mutex{},
+	}
+}
+
+// Recorder collects the operations and actions reported by the module.
+type Recorder struct {
+	m       *sync.Mutex
+	seq     int
+	records []record
+}
+
+// Op records a completed operation event.
+func (t *Recorder) Op(ts time.Time, op *events.Op) {
+	// initiated events are only emitted when a run is being watched.
+	if op.State == events.Op_Initiated {
+		return
+	}
+
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	id := op.Op
+	if id == "" {
+		id = op.Name
+	}
+
+	t.records = append(t.records, record{
+		Node: Node{
+			ID:    id,
+			Kind:  KindOperation,
+			Name:  op.Name,
+			State: op.State.String(),
+		},
+		path:    slices.Clone(op.Path),
+		started: ts.Add(-time.Duration(op.Milliseconds) * time.Millisecond),
+	})
+}
+
+// Action records a stubbed action issued by the operation at the given path.
+// returns the identifier assigned to the action.
+func (t *Recorder) Action(path []string, kind, name, detail string) string {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	t.seq++
+	id := fmt.Sprintf("%s%d", kind, t.seq)
+	t.records = append(t.records, record{
+		Node: Node{
+			ID:     id,
+			Kind:   kind,
+			Name:   name,
+			State:  events.Op_Completed.String(),
+			Detail: detail,
+		},
+		path:    slices.Clone(path),
+		started: time.Now(),
+	})
+
+	return id
+}
+
+// Tree assembles the recorded operations into a tree ordered by when they were initiated.
+func (t *Recorder) Tree() *Node {
+	t.m.Lock()
+	records := slices.Clone(t.records)
+	t.m.Unlock()
+
+	slices.SortStableFunc(records, func(a, b record) int {
+		return a.started.Compare(b.started)
+	})
+
+	root := &Node{Kind: KindPlan, Name: KindPlan}
+	index := map[string]*Node{}
+	placeholders := map[*Node]bool{}
+
+	var ensure func(path []string) *Node
+	ensure = func(path []string) *Node {
+		if len(path) == 0 {
+			return root
+		}
+
+		key := strings.Join(path, "/")
+		if n, ok := index[key]; ok {
+			return n
+		}
+
+		// operations that were never reported, i.e. the record arrived out of order or the
+		// operation is not traceable, are represented by their identifier.
+		id := path[len(path)-1]
+		n := &Node{ID: id, Kind: KindOperation, Name: id}
+		parent := ensure(path[:len(path)-1])
+		parent.Children = append(parent.Children, n)
+		index[key] = n
+		placeholders[n] = true
+		return n
+	}
+
+	for _, r := range records {
+		key := strings.Join(append(slices.Clone(r.path), r.ID), "/")
+		if n, ok := index[key]; ok && placeholders[n] {
+			children := n.Children
+			*n = r.Node
+			n.Children = children
+			delete(placeholders, n)
+			continue
+		}
+
+		// operations can be executed multiple times, subsequent records are attributed to the latest execution.
+		n := &r.Node
+		parent := ensure(r.path)
+		parent.Children = append(parent.Children, n)
+		index[key] = n
+	}
+
+	return root
+}
@@ -0,0 +1,86 @@
+package plan_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/egdaemon/eg/interp/events"
+	"github.com/egdaemon/eg/interp/plan"
+	"github.com/stretchr/testify/require"
+)
+
+func example() *plan.Recorder {
+	ts := time.Now()
+	r := plan.NewRecorder()
+	r.Op(ts.Add(30*time.Millisecond), &events.Op{Op: "ref1", Name: "main.build", State: events.Op_Completed, Milliseconds: 20})
+	r.Op(ts.Add(10*time.Millisecond), &events.Op{Op: "ref2", Name: "main.compile", State: events.Op_Completed, Milliseconds: 5, Path: []string{"ref1"}})
+	r.Op(ts.Add(40*time.Millisecond), &events.Op{Op: "ref3", Name: "main.release", State: events.Op_Skipped})
+	return r
+}
+
+func TestRecorderTree(t *testing.T) {
+	t.Run("operations are nested by their path", func(t *testing.T) {
+		root := example().Tree()
+		require.Equal(t, plan.KindPlan, root.Kind)
+		require.Len(t, root.Children, 2)
+		require.Equal(t, "main.build", root.Children[0].Name)
+		require.Equal(t, "main.release", root.Children[1].Name)
+		require.True(t, root.Children[1].Skipped())
+		require.Len(t, root.Children[0].Children, 1)
+		require.Equal(t, "main.compile", root.Children[0].Children[0].Name)
+	})
+
+	t.Run("actions are attributed to the issuing operation", func(t *testing.T) {
+		r := example()
+		id := r.Action([]string{"ref1", "ref2"}, plan.KindExec, "go build ./...", "")
+		require.Equal(t, "exec1", id)
+
+		root := r.Tree()
+		compile := root.Children[0].Children[0]
+		require.Len(t, compile.Children, 1)
+		require.Equal(t, plan.KindExec, compile.Children[0].Kind)
+		require.Equal(t, "go build ./...", compile.Children[0].Name)
+	})
+
+	t.Run("unreported parents are represented by their identifier", func(t *testing.T) {
+		r := plan.NewRecorder()
+		r.Action([]string{"ref9"}, plan.KindBuild, "eg.ubuntu", "")
+		root := r.Tree()
+		require.Len(t, root.Children, 1)
+		require.Equal(t, "ref9", root.Children[0].Name)
+		require.Equal(t, plan.KindBuild, root.Children[0].Children[0].Kind)
+	})
+}
+
+func TestRender(t *testing.T) {
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, plan.Render(&buf, plan.FormatText, example().Tree()))
+		require.Equal(t, "plan\n  main.build\n    main.compile\n  main.release (skipped)\n", buf.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var (
+			buf     bytes.Buffer
+			decoded plan.Node
+		)
+		require.NoError(t, plan.Render(&buf, plan.FormatJSON, example().Tree()))
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		require.Equal(t, "main.compile", decoded.Children[0].Children[0].Name)
+		require.Equal(t, "Skipped", decoded.Children[1].State)
+	})
+
+	t.Run("dot", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, plan.Render(&buf, plan.FormatDOT, example().Tree()))
+		require.Contains(t, buf.String(), "digraph plan {")
+		require.Contains(t, buf.String(), "n0 -> n1;")
+		require.Contains(t, buf.String(), "n3 [label=\"main.release (skipped)\", style=dashed];")
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		require.Error(t, plan.Render(&bytes.Buffer{}, "yaml", example().Tree()))
+	})
+}
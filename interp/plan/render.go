@@ -0,0 +1,123 @@
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+	FormatDOT  = "dot"
+)
+
+// Render the tree in the specified format.
+func Render(w io.Writer, format string, root *Node) error {
+	switch format {
+	case FormatJSON:
+		return JSON(w, root)
+	case FormatDOT:
+		return DOT(w, root)
+	case FormatText, "":
+		return Text(w, root)
+	default:
+		return fmt.Errorf("unknown plan format: %s", format)
+	}
+}
+
+func label(n *Node) string {
+	var b strings.Builder
+	if n.Kind != KindOperation && n.Kind != KindPlan {
+		b.WriteString(n.Kind)
+		b.WriteString(" ")
+	}
+
+	b.WriteString(n.Name)
+
+	if n.Detail != "" && n.Kind != KindExec {
+		b.WriteString(" ")
+		b.WriteString(n.Detail)
+	}
+
+	if n.Skipped() {
+		b.WriteString(" (skipped)")
+	}
+
+	return b.String()
+}
+
+// Text renders the tree as an indented outline.
+func Text(w io.Writer, root *Node) (err error) {
+	var walk func(n *Node, depth int) error
+	walk = func(n *Node, depth int) error {
+		if _, err = fmt.Fprintf(w, "%s%s\n", strings.Repeat("  ", depth), label(n)); err != nil {
+			return err
+		}
+
+		for _, c := range n.Children {
+			if err = walk(c, depth+1); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return walk(root, 0)
+}
+
+// JSON renders the tree as a json document.
+func JSON(w io.Writer, root *Node) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(root)
+}
+
+// DOT renders the tree as a graphviz digraph, skipped operations are dashed.
+func DOT(w io.Writer, root *Node) (err error) {
+	var (
+		seq  int
+		walk func(n *Node) (string, error)
+	)
+
+	walk = func(n *Node) (string, error) {
+		id := fmt.Sprintf("n%d", seq)
+		seq++
+
+		style := "solid"
+		if n.Skipped() {
+			style = "dashed"
+		}
+
+		if _, err := fmt.Fprintf(w, "  %s [label=%s, style=%s];\n", id, strconv.Quote(label(n)), style); err != nil {
+			return id, err
+		}
+
+		for _, c := range n.Children {
+			cid, err := walk(c)
+			if err != nil {
+				return id, err
+			}
+
+			if _, err = fmt.Fprintf(w, "  %s -> %s;\n", id, cid); err != nil {
+				return id, err
+			}
+		}
+
+		return id, nil
+	}
+
+	if _, err = fmt.Fprintln(w, "digraph plan {"); err != nil {
+		return err
+	}
+
+	if _, err = walk(root); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, "}")
+	return err
+}
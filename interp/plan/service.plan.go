@@ -0,0 +1,115 @@
+package plan
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/egdaemon/eg/interp/c8s"
+	"github.com/egdaemon/eg/interp/events"
+	"github.com/egdaemon/eg/interp/execproxy"
+	"google.golang.org/grpc"
+)
+
+func NewServiceEvents(r *Recorder) *EventsService {
+	return &EventsService{r: r}
+}
+
+// EventsService records operation events instead of persisting them.
+type EventsService struct {
+	events.UnimplementedEventsServer
+	r *Recorder
+}
+
+func (t *EventsService) Bind(host grpc.ServiceRegistrar) {
+	events.RegisterEventsServer(host, t)
+}
+
+// Dispatch implements events.EventsServer.
+func (t *EventsService) Dispatch(ctx context.Context, dr *events.DispatchRequest) (_ *events.DispatchResponse, err error) {
+	for _, m := range dr.Messages {
+		if evt, ok := m.Event.(*events.Message_Op); ok && evt.Op != nil {
+			t.r.Op(time.UnixMicro(m.Ts), evt.Op)
+		}
+	}
+
+	return &events.DispatchResponse{}, nil
+}
+
+func NewServiceExec(r *Recorder) *ExecService {
+	return &ExecService{r: r}
+}
+
+// ExecService records commands instead of executing them.
+type ExecService struct {
+	execproxy.UnimplementedProxyServer
+	r *Recorder
+}
+
+func (t *ExecService) Bind(host grpc.ServiceRegistrar) {
+	execproxy.RegisterProxyServer(host, t)
+}
+
+// Exec implements execproxy.ProxyServer.
+func (t *ExecService) Exec(ctx context.Context, req *execproxy.ExecRequest) (*execproxy.ExecResponse, error) {
+	t.r.Action(events.IncomingOperationPath(ctx), KindExec, strings.Join(append([]string{req.Cmd}, req.Arguments...), " "), req.Dir)
+	return &execproxy.ExecResponse{}, nil
+}
+
+// Output implements execproxy.ProxyServer, no output is produced.
+func (t *ExecService) Output(req *execproxy.ExecRequest, stream grpc.ServerStreamingServer[execproxy.ExecOutput]) error {
+	t.r.Action(events.IncomingOperationPath(stream.Context()), KindExec, strings.Join(append([]string{req.Cmd}, req.Arguments...), " "), req.Dir)
+	return nil
+}
+
+// Dispatcher plans a nested module, the path is the operation path the nested module is rooted at.
+type Dispatcher func(ctx context.Context, path []string, req *c8s.ModuleRequest) error
+
+// DispatcherNoop records the module dispatch without planning the nested module.
+func DispatcherNoop(ctx context.Context, path []string, req *c8s.ModuleRequest) error {
+	return nil
+}
+
+func NewServiceContainers(r *Recorder, d Dispatcher) *ContainersService {
+	return &ContainersService{r: r, d: d}
+}
+
+// ContainersService records container operations instead of executing them.
+type ContainersService struct {
+	c8s.UnimplementedProxyServer
+	r *Recorder
+	d Dispatcher
+}
+
+func (t *ContainersService) Bind(host grpc.ServiceRegistrar) {
+	c8s.RegisterProxyServer(host, t)
+}
+
+// Pull implements c8s.ProxyServer.
+func (t *ContainersService) Pull(ctx context.Context, req *c8s.PullRequest) (*c8s.PullResponse, error) {
+	t.r.Action(events.IncomingOperationPath(ctx), KindPull, req.Name, strings.Join(req.Options, " "))
+	return &c8s.PullResponse{}, nil
+}
+
+// Build implements c8s.ProxyServer.
+func (t *ContainersService) Build(ctx context.Context, req *c8s.BuildRequest) (*c8s.BuildResponse, error) {
+	t.r.Action(events.IncomingOperationPath(ctx), KindBuild, req.Name, req.Definition)
+	return &c8s.BuildResponse{}, nil
+}
+
+// Run implements c8s.ProxyServer.
+func (t *ContainersService) Run(ctx context.Context, req *c8s.RunRequest) (*c8s.RunResponse, error) {
+	t.r.Action(events.IncomingOperationPath(ctx), KindRun, req.Image, strings.Join(req.Command, " "))
+	return &c8s.RunResponse{}, nil
+}
+
+// Module implements c8s.ProxyServer.
+func (t *ContainersService) Module(ctx context.Context, req *c8s.ModuleRequest) (*c8s.ModuleResponse, error) {
+	path := events.IncomingOperationPath(ctx)
+	id := t.r.Action(path, KindModule, req.Image, req.Module)
+	if err := t.d(ctx, append(path, id), req); err != nil {
+		return nil, err
+	}
+
+	return &c8s.ModuleResponse{}, nil
+}
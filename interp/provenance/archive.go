@@ -0,0 +1,47 @@
+package provenance
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/fsx"
+	"github.com/egdaemon/eg/internal/iox"
+	"github.com/egdaemon/eg/interp/analytics"
+)
+
+// Archive the provenance of the run alongside its archived analytics. runs without
+// provenance are ignored.
+func Archive(dir string, runid string) (err error) {
+	if err = os.MkdirAll(analytics.Directory(), 0700); err != nil {
+		return errorsx.Wrap(err, "unable to create analytics directory")
+	}
+
+	for _, name := range []string{Statement, SBOM} {
+		src := filepath.Join(dir, name)
+		if !fsx.FileExists(src) {
+			continue
+		}
+
+		if err = iox.Copy(src, analytics.Directory(fmt.Sprintf("%s.%s", runid, name))); err != nil {
+			return errorsx.Wrapf(err, "unable to archive %s", name)
+		}
+	}
+
+	return nil
+}
+
+// Resolve the path of the named provenance file (Statement or SBOM) from a runtime directory
+// or an archived run identifier.
+func Resolve(ref string, name string) (string, error) {
+	if path := filepath.Join(ref, name); fsx.FileExists(path) {
+		return path, nil
+	}
+
+	if path := analytics.Directory(fmt.Sprintf("%s.%s", ref, name)); fsx.FileExists(path) {
+		return path, nil
+	}
+
+	return "", fmt.Errorf("unable to locate %s for %s, expected a runtime directory or archived run", name, ref)
+}
@@ -0,0 +1,118 @@
+package provenance
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/egdaemon/eg/internal/errorsx"
+	"golang.org/x/crypto/ssh"
+)
+
+// PayloadType of in-toto statements within DSSE envelopes.
+const PayloadType = "application/vnd.in-toto+json"
+
+// ErrNoTrustedKeys returned when verifying without any keys to trust.
+const ErrNoTrustedKeys = errorsx.String("unable to verify provenance without trusted keys")
+
+// Envelope DSSE envelope, the key id of each signature is the signer's public key
+// in authorized_keys format.
+type Envelope struct {
+	PayloadType string      `json:"payloadType"`
+	Payload     string      `json:"payload"`
+	Signatures  []Signature `json:"signatures"`
+}
+
+type Signature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// pae pre authentication encoding of the payload, see https://github.com/secure-systems-lab/dsse/blob/master/protocol.md
+func pae(payloadtype string, payload []byte) []byte {
+	return fmt.Appendf(nil, "DSSEv1 %d %s %d %s", len(payloadtype), payloadtype, len(payload), payload)
+}
+
+// Sign the payload with the ssh key.
+func Sign(s ssh.Signer, payloadtype string, payload []byte) (e Envelope, err error) {
+	sig, err := s.Sign(rand.Reader, pae(payloadtype, payload))
+	if err != nil {
+		return e, errorsx.Wrap(err, "unable to sign payload")
+	}
+
+	return Envelope{
+		PayloadType: payloadtype,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures: []Signature{{
+			KeyID: strings.TrimSpace(string(ssh.MarshalAuthorizedKey(s.PublicKey()))),
+			Sig:   base64.StdEncoding.EncodeToString(ssh.Marshal(sig)),
+		}},
+	}, nil
+}
+
+// Verify the envelope returning its payload and the key that signed it. the envelope
+// must be signed by one of the keys, the key id of a signature is self declared and
+// is never trusted on its own.
+func Verify(e Envelope, keys ...ssh.PublicKey) (payload []byte, signer ssh.PublicKey, err error) {
+	if len(keys) == 0 {
+		return nil, nil, ErrNoTrustedKeys
+	}
+
+	if payload, err = base64.StdEncoding.DecodeString(e.Payload); err != nil {
+		return nil, nil, errorsx.Wrap(err, "invalid payload encoding")
+	}
+
+	for _, s := range e.Signatures {
+		var (
+			sig ssh.Signature
+		)
+
+		pub, _, _, _, cause := ssh.ParseAuthorizedKey([]byte(s.KeyID))
+		if cause != nil {
+			err = errorsx.Compact(err, errorsx.Wrap(cause, "invalid key id"))
+			continue
+		}
+
+		if !slices.ContainsFunc(keys, func(k ssh.PublicKey) bool { return bytes.Equal(k.Marshal(), pub.Marshal()) }) {
+			err = errorsx.Compact(err, fmt.Errorf("unauthorized key %s", ssh.FingerprintSHA256(pub)))
+			continue
+		}
+
+		encoded, cause := base64.StdEncoding.DecodeString(s.Sig)
+		if cause != nil {
+			err = errorsx.Compact(err, errorsx.Wrap(cause, "invalid signature encoding"))
+			continue
+		}
+
+		if cause = ssh.Unmarshal(encoded, &sig); cause != nil {
+			err = errorsx.Compact(err, errorsx.Wrap(cause, "invalid signature"))
+			continue
+		}
+
+		if cause = pub.Verify(pae(e.PayloadType, payload), &sig); cause != nil {
+			err = errorsx.Compact(err, errorsx.Wrap(cause, "invalid signature"))
+			continue
+		}
+
+		return payload, pub, nil
+	}
+
+	// report the first signature's failure, or the absence of signatures.
+	return nil, nil, errorsx.Compact(err, errors.New("unsigned provenance"))
+}
+
+// ReadEnvelope from the path.
+func ReadEnvelope(path string) (e Envelope, err error) {
+	encoded, err := os.ReadFile(path)
+	if err != nil {
+		return e, errorsx.Wrap(err, "unable to read provenance")
+	}
+
+	return e, errorsx.Wrapf(json.Unmarshal(encoded, &e), "invalid provenance %s", path)
+}
@@ -0,0 +1,390 @@
+// Package provenance attests to what a run consumed and produced. materials (containers, commands,
+// toolchains and released artifacts) are recorded as custom metrics within the run's analytics
+// while it executes, at completion the runner generates a CycloneDX SBOM and an in-toto statement
+// with a SLSA provenance predicate, signing the statement with its ssh key.
+package provenance
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/stringsx"
+	"github.com/egdaemon/eg/interp/events"
+	"github.com/gofrs/uuid/v5"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// names of the custom metrics recording the materials of the run.
+	MetricContainer = "eg.provenance.container"
+	MetricCommand   = "eg.provenance.command"
+	MetricToolchain = "eg.provenance.toolchain"
+	MetricSubject   = "eg.provenance.subject"
+)
+
+const (
+	// Statement signed in-toto statement (DSSE envelope) written to the runtime directory.
+	Statement = "provenance.intoto.json"
+	// SBOM CycloneDX document written to the runtime directory.
+	SBOM = "sbom.cdx.json"
+	// BuildType of the provenance predicate.
+	BuildType = "https://egdaemon.com/provenance/workload/v1"
+)
+
+const (
+	ContainerActionPull   = "pull"
+	ContainerActionBuild  = "build"
+	ContainerActionRun    = "run"
+	ContainerActionModule = "module"
+)
+
+// Container pulled, built or run by the workload.
+type Container struct {
+	Action     string `json:"action"`
+	Image      string `json:"image"`
+	Definition string `json:"definition,omitempty"`
+	ID         string `json:"id,omitempty"`
+	Digest     string `json:"digest,omitempty"`
+}
+
+// Command executed by the workload, arguments are redacted when redaction is enabled.
+type Command struct {
+	Command   string   `json:"command"`
+	Arguments []string `json:"arguments,omitempty"`
+	Directory string   `json:"directory,omitempty"`
+}
+
+// Toolchain available to the workload.
+type Toolchain struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Path    string `json:"path"`
+}
+
+// Subject artifact produced by the workload, i.e. assets uploaded to a release.
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Materials recorded by the run.
+type Materials struct {
+	Containers []Container
+	Commands   []Command
+	Toolchains []Toolchain
+	Subjects   []Subject
+}
+
+// Run being attested.
+type Run struct {
+	ID         string
+	Repository string
+	Commit     string
+	Builder    string
+	Started    time.Time
+	Finished   time.Time
+}
+
+// NewSubject from the contents of the file.
+func NewSubject(name string, content []byte) Subject {
+	digest := sha256.Sum256(content)
+	return Subject{Name: name, Digest: map[string]string{"sha256": hex.EncodeToString(digest[:])}}
+}
+
+// Metric encodes the material as a custom metric.
+func Metric(name string, v any) (*events.Message, error) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, errorsx.Wrapf(err, "unable to encode %s", name)
+	}
+
+	return events.NewMetric(name, encoded), nil
+}
+
+// Builder identifies the runner by the fingerprint of its key.
+func Builder(k ssh.PublicKey) string {
+	return fmt.Sprintf("urn:eg:runner:%s", ssh.FingerprintSHA256(k))
+}
+
+// Load the materials recorded within the run's analytics.
+func Load(ctx context.Context, db *sql.DB) (m Materials, err error) {
+	rows, err := db.QueryContext(ctx, "SELECT name, metric::TEXT FROM 'eg.metrics.custom' WHERE name IN (?, ?, ?, ?) ORDER BY ts ASC, id ASC", MetricContainer, MetricCommand, MetricToolchain, MetricSubject)
+	if err != nil {
+		return m, errorsx.Wrap(err, "unable to query materials")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			name    string
+			encoded string
+		)
+
+		if err = rows.Scan(&name, &encoded); err != nil {
+			return m, errorsx.Wrap(err, "unable to scan material")
+		}
+
+		switch name {
+		case MetricContainer:
+			err = decode(encoded, &m.Containers)
+		case MetricCommand:
+			err = decode(encoded, &m.Commands)
+		case MetricToolchain:
+			err = decode(encoded, &m.Toolchains)
+		case MetricSubject:
+			err = decode(encoded, &m.Subjects)
+		}
+
+		if err != nil {
+			return m, errorsx.Wrapf(err, "invalid %s", name)
+		}
+	}
+
+	return m, errorsx.Wrap(rows.Err(), "unable to read materials")
+}
+
+func decode[T any](encoded string, dst *[]T) error {
+	var v T
+	if err := json.Unmarshal([]byte(encoded), &v); err != nil {
+		return err
+	}
+
+	*dst = append(*dst, v)
+	return nil
+}
+
+// Generate the SBOM and the unsigned in-toto statement of the run, the SBOM is a subject of the statement.
+func Generate(r Run, m Materials) (statement []byte, sbom []byte, err error) {
+	if sbom, err = json.MarshalIndent(newbom(r, m), "", "  "); err != nil {
+		return nil, nil, errorsx.Wrap(err, "unable to encode sbom")
+	}
+
+	if statement, err = json.MarshalIndent(newstatement(r, m, NewSubject(SBOM, sbom)), "", "  "); err != nil {
+		return nil, nil, errorsx.Wrap(err, "unable to encode provenance")
+	}
+
+	return statement, sbom, nil
+}
+
+// Write the SBOM and the signed statement into the directory.
+func Write(dir string, s ssh.Signer, r Run, m Materials) (err error) {
+	statement, sbom, err := Generate(r, m)
+	if err != nil {
+		return err
+	}
+
+	envelope, err := Sign(s, PayloadType, statement)
+	if err != nil {
+		return err
+	}
+
+	encoded, err := json.MarshalIndent(envelope, "", "  ")
+	if err != nil {
+		return errorsx.Wrap(err, "unable to encode envelope")
+	}
+
+	if err = os.WriteFile(filepath.Join(dir, SBOM), sbom, 0600); err != nil {
+		return errorsx.Wrap(err, "unable to write sbom")
+	}
+
+	return errorsx.Wrap(os.WriteFile(filepath.Join(dir, Statement), encoded, 0600), "unable to write provenance")
+}
+
+// Attest the run from the analytics database, writing the SBOM and signed statement next to it.
+func Attest(ctx context.Context, db *sql.DB, dir string, s ssh.Signer, r Run) error {
+	m, err := Load(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	if r.Builder == "" {
+		r.Builder = Builder(s.PublicKey())
+	}
+
+	return Write(dir, s, r, m)
+}
+
+type statement struct {
+	Type          string    `json:"_type"`
+	Subject       []Subject `json:"subject"`
+	PredicateType string    `json:"predicateType"`
+	Predicate     predicate `json:"predicate"`
+}
+
+type predicate struct {
+	BuildDefinition struct {
+		BuildType            string         `json:"buildType"`
+		ExternalParameters   map[string]any `json:"externalParameters"`
+		InternalParameters   map[string]any `json:"internalParameters,omitempty"`
+		ResolvedDependencies []descriptor   `json:"resolvedDependencies,omitempty"`
+	} `json:"buildDefinition"`
+	RunDetails struct {
+		Builder struct {
+			ID string `json:"id"`
+		} `json:"builder"`
+		Metadata struct {
+			InvocationID string    `json:"invocationId"`
+			StartedOn    time.Time `json:"startedOn"`
+			FinishedOn   time.Time `json:"finishedOn"`
+		} `json:"metadata"`
+	} `json:"runDetails"`
+}
+
+type descriptor struct {
+	URI         string            `json:"uri"`
+	Name        string            `json:"name,omitempty"`
+	Digest      map[string]string `json:"digest,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+func newstatement(r Run, m Materials, subjects ...Subject) statement {
+	var (
+		p predicate
+	)
+
+	p.BuildDefinition.BuildType = BuildType
+	p.BuildDefinition.ExternalParameters = map[string]any{
+		"repository": r.Repository,
+		"commit":     r.Commit,
+	}
+
+	if len(m.Commands) > 0 {
+		p.BuildDefinition.InternalParameters = map[string]any{"commands": m.Commands}
+	}
+
+	if r.Repository != "" {
+		p.BuildDefinition.ResolvedDependencies = append(p.BuildDefinition.ResolvedDependencies, descriptor{
+			URI:    r.Repository,
+			Digest: map[string]string{"gitCommit": r.Commit},
+		})
+	}
+
+	for _, c := range containers(m.Containers) {
+		d := descriptor{
+			URI:         "oci://" + c.Image,
+			Name:        c.Image,
+			Annotations: map[string]string{"action": c.Action},
+		}
+
+		if algo, digest, ok := strings.Cut(c.Digest, ":"); ok {
+			d.Digest = map[string]string{algo: digest}
+		}
+
+		p.BuildDefinition.ResolvedDependencies = append(p.BuildDefinition.ResolvedDependencies, d)
+	}
+
+	p.RunDetails.Builder.ID = r.Builder
+	p.RunDetails.Metadata.InvocationID = r.ID
+	p.RunDetails.Metadata.StartedOn = r.Started.UTC()
+	p.RunDetails.Metadata.FinishedOn = r.Finished.UTC()
+
+	return statement{
+		Type:          "https://in-toto.io/Statement/v1",
+		Subject:       append(slices.Clone(m.Subjects), subjects...),
+		PredicateType: "https://slsa.dev/provenance/v1",
+		Predicate:     p,
+	}
+}
+
+// containers deduplicated by image, the first action involving the image is retained.
+func containers(cs []Container) (unique []Container) {
+	for _, c := range cs {
+		if idx := slices.IndexFunc(unique, func(u Container) bool { return u.Image == c.Image }); idx > -1 {
+			unique[idx].Digest = stringsx.First(unique[idx].Digest, c.Digest)
+			unique[idx].ID = stringsx.First(unique[idx].ID, c.ID)
+			continue
+		}
+
+		unique = append(unique, c)
+	}
+
+	return unique
+}
+
+type bom struct {
+	Format      string      `json:"bomFormat"`
+	SpecVersion string      `json:"specVersion"`
+	Serial      string      `json:"serialNumber"`
+	Version     int         `json:"version"`
+	Metadata    bommetadata `json:"metadata"`
+	Components  []component `json:"components"`
+}
+
+type bommetadata struct {
+	Timestamp time.Time `json:"timestamp"`
+	Component component `json:"component"`
+}
+
+type component struct {
+	Ref        string     `json:"bom-ref,omitempty"`
+	Type       string     `json:"type"`
+	Name       string     `json:"name"`
+	Version    string     `json:"version,omitempty"`
+	Hashes     []bomhash  `json:"hashes,omitempty"`
+	Properties []property `json:"properties,omitempty"`
+}
+
+type bomhash struct {
+	Algorithm string `json:"alg"`
+	Content   string `json:"content"`
+}
+
+type property struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func newbom(r Run, m Materials) bom {
+	b := bom{
+		Format:      "CycloneDX",
+		SpecVersion: "1.5",
+		Serial:      "urn:uuid:" + uuid.NewV5(uuid.NamespaceURL, r.Repository+"#"+r.ID).String(),
+		Version:     1,
+		Metadata: bommetadata{
+			Timestamp: r.Finished.UTC(),
+			Component: component{Type: "application", Name: r.Repository, Version: r.Commit},
+		},
+		Components: []component{},
+	}
+
+	for _, c := range containers(m.Containers) {
+		cc := component{
+			Ref:        "container:" + c.Image,
+			Type:       "container",
+			Name:       c.Image,
+			Properties: []property{{Name: "eg:action", Value: c.Action}},
+		}
+
+		if algo, digest, ok := strings.Cut(c.Digest, ":"); ok && algo == "sha256" {
+			cc.Hashes = append(cc.Hashes, bomhash{Algorithm: "SHA-256", Content: digest})
+		}
+
+		if c.Definition != "" {
+			cc.Properties = append(cc.Properties, property{Name: "eg:definition", Value: c.Definition})
+		}
+
+		b.Components = append(b.Components, cc)
+	}
+
+	for _, t := range m.Toolchains {
+		b.Components = append(b.Components, component{
+			Ref:        "toolchain:" + t.Name,
+			Type:       "application",
+			Name:       t.Name,
+			Version:    t.Version,
+			Properties: []property{{Name: "eg:path", Value: t.Path}},
+		})
+	}
+
+	return b
+}
@@ -0,0 +1,163 @@
+package provenance_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/egdaemon/eg/internal/sshx"
+	"github.com/egdaemon/eg/interp/provenance"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func signer(t *testing.T, seed string) ssh.Signer {
+	s, err := sshx.SignerFromGenerator(sshx.NewKeyGenSeeded(seed))
+	require.NoError(t, err)
+	return s
+}
+
+func materials() provenance.Materials {
+	return provenance.Materials{
+		Containers: []provenance.Container{
+			{Action: provenance.ContainerActionPull, Image: "docker.io/library/alpine:latest", Digest: "sha256:deadbeef"},
+			{Action: provenance.ContainerActionRun, Image: "docker.io/library/alpine:latest", ID: "abc123"},
+			{Action: provenance.ContainerActionBuild, Image: "eg.example", Definition: ".eg/Containerfile"},
+		},
+		Commands:   []provenance.Command{{Command: "go", Arguments: []string{"build", "./..."}, Directory: "/workload"}},
+		Toolchains: []provenance.Toolchain{{Name: "go", Version: "go version go1.25.0 linux/amd64", Path: "/usr/bin/go"}},
+		Subjects:   []provenance.Subject{provenance.NewSubject("eg.tar.gz", []byte("release"))},
+	}
+}
+
+func run() provenance.Run {
+	return provenance.Run{
+		ID:         "0198a8f0-0000-7000-8000-000000000000",
+		Repository: "https://github.com/egdaemon/eg.git",
+		Commit:     "5f1b1c3",
+		Builder:    "urn:eg:runner:test",
+		Started:    time.Unix(1700000000, 0),
+		Finished:   time.Unix(1700000060, 0),
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	var (
+		statement struct {
+			Type      string               `json:"_type"`
+			Subject   []provenance.Subject `json:"subject"`
+			Predicate struct {
+				BuildDefinition struct {
+					BuildType            string `json:"buildType"`
+					ResolvedDependencies []struct {
+						URI    string            `json:"uri"`
+						Digest map[string]string `json:"digest"`
+					} `json:"resolvedDependencies"`
+				} `json:"buildDefinition"`
+			} `json:"predicate"`
+		}
+		bom struct {
+			Format     string `json:"bomFormat"`
+			Components []struct {
+				Type string `json:"type"`
+				Name string `json:"name"`
+			} `json:"components"`
+		}
+	)
+
+	encoded, sbom, err := provenance.Generate(run(), materials())
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(encoded, &statement))
+	require.NoError(t, json.Unmarshal(sbom, &bom))
+
+	require.Equal(t, "https://in-toto.io/Statement/v1", statement.Type)
+	require.Equal(t, provenance.BuildType, statement.Predicate.BuildDefinition.BuildType)
+	require.Equal(t, []provenance.Subject{
+		provenance.NewSubject("eg.tar.gz", []byte("release")),
+		provenance.NewSubject(provenance.SBOM, sbom),
+	}, statement.Subject)
+
+	deps := statement.Predicate.BuildDefinition.ResolvedDependencies
+	require.Len(t, deps, 3, "repository and the deduplicated containers")
+	require.Equal(t, map[string]string{"gitCommit": "5f1b1c3"}, deps[0].Digest)
+	require.Equal(t, "oci://docker.io/library/alpine:latest", deps[1].URI)
+	require.Equal(t, map[string]string{"sha256": "deadbeef"}, deps[1].Digest)
+
+	require.Equal(t, "CycloneDX", bom.Format)
+	require.Len(t, bom.Components, 3)
+	require.Equal(t, "container", bom.Components[0].Type)
+	require.Equal(t, "go", bom.Components[2].Name)
+
+	_, again, err := provenance.Generate(run(), materials())
+	require.NoError(t, err)
+	require.Equal(t, sbom, again, "generation is deterministic")
+}
+
+func TestSignVerify(t *testing.T) {
+	s := signer(t, "runner")
+	payload := []byte(`{"_type":"https://in-toto.io/Statement/v1"}`)
+
+	envelope, err := provenance.Sign(s, provenance.PayloadType, payload)
+	require.NoError(t, err)
+
+	t.Run("without trusted keys", func(t *testing.T) {
+		_, _, err := provenance.Verify(envelope)
+		require.ErrorIs(t, err, provenance.ErrNoTrustedKeys)
+	})
+
+	t.Run("authorized key", func(t *testing.T) {
+		verified, pub, err := provenance.Verify(envelope, signer(t, "other").PublicKey(), s.PublicKey())
+		require.NoError(t, err)
+		require.Equal(t, payload, verified)
+		require.Equal(t, s.PublicKey().Marshal(), pub.Marshal())
+	})
+
+	t.Run("unauthorized key", func(t *testing.T) {
+		_, _, err := provenance.Verify(envelope, signer(t, "other").PublicKey())
+		require.ErrorContains(t, err, "unauthorized key")
+	})
+
+	t.Run("tampered payload", func(t *testing.T) {
+		tampered := envelope
+		tampered.Payload = base64.StdEncoding.EncodeToString([]byte(`{"_type":"tampered"}`))
+		_, _, err := provenance.Verify(tampered, s.PublicKey())
+		require.ErrorContains(t, err, "invalid signature")
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		unsigned := envelope
+		unsigned.Signatures = nil
+		_, _, err := provenance.Verify(unsigned, s.PublicKey())
+		require.ErrorContains(t, err, "unsigned provenance")
+	})
+}
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	s := signer(t, "runner")
+
+	require.NoError(t, provenance.Write(dir, s, run(), materials()))
+
+	envelope, err := provenance.ReadEnvelope(filepath.Join(dir, provenance.Statement))
+	require.NoError(t, err)
+	require.Equal(t, provenance.PayloadType, envelope.PayloadType)
+
+	payload, _, err := provenance.Verify(envelope, s.PublicKey())
+	require.NoError(t, err)
+
+	sbom, err := os.ReadFile(filepath.Join(dir, provenance.SBOM))
+	require.NoError(t, err)
+
+	var statement struct {
+		Subject []provenance.Subject `json:"subject"`
+	}
+	require.NoError(t, json.Unmarshal(payload, &statement))
+	require.Contains(t, statement.Subject, provenance.NewSubject(provenance.SBOM, sbom))
+
+	path, err := provenance.Resolve(dir, provenance.Statement)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, provenance.Statement), path)
+}
@@ -0,0 +1,66 @@
+package provenance
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// well known toolchains and the arguments reporting their version.
+var toolchains = []struct {
+	name string
+	args []string
+}{
+	{name: "go", args: []string{"version"}},
+	{name: "rustc", args: []string{"--version"}},
+	{name: "cargo", args: []string{"--version"}},
+	{name: "node", args: []string{"--version"}},
+	{name: "npm", args: []string{"--version"}},
+	{name: "yarn", args: []string{"--version"}},
+	{name: "python3", args: []string{"--version"}},
+	{name: "java", args: []string{"-version"}},
+	{name: "gcc", args: []string{"--version"}},
+	{name: "clang", args: []string{"--version"}},
+	{name: "dotnet", args: []string{"--version"}},
+	{name: "ruby", args: []string{"--version"}},
+	{name: "dart", args: []string{"--version"}},
+	{name: "terraform", args: []string{"version"}},
+	{name: "podman", args: []string{"--version"}},
+	{name: "git", args: []string{"--version"}},
+}
+
+// Toolchains detects the well known toolchains available within PATH.
+func Toolchains(ctx context.Context) (detected []Toolchain) {
+	for _, t := range toolchains {
+		path, err := exec.LookPath(t.name)
+		if err != nil {
+			continue
+		}
+
+		vctx, done := context.WithTimeout(ctx, 5*time.Second)
+		output, err := exec.CommandContext(vctx, path, t.args...).CombinedOutput()
+		done()
+		if err != nil {
+			continue
+		}
+
+		detected = append(detected, Toolchain{Name: t.name, Version: version(output), Path: path})
+	}
+
+	return detected
+}
+
+// version is the first non blank line of the output.
+func version(output []byte) string {
+	s := bufio.NewScanner(bytes.NewReader(output))
+	for s.Scan() {
+		if line := strings.TrimSpace(s.Text()); line != "" {
+			return line
+		}
+	}
+
+	return ""
+}
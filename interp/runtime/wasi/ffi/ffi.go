@@ -84,7 +84,12 @@ func ReadArrayElement(m api.Memory, offset, len uint32) (data []byte, err error)
 }
 
 func ReadMicroDeadline(ctx context.Context, deadline int64) (context.Context, context.CancelFunc) {
-	return context.WithDeadline(ctx, time.UnixMicro(deadline))
+	dctx, done := context.WithDeadline(ctx, time.UnixMicro(deadline))
+	if i, ok := ctx.Value(interruptkey{}).(*Interrupts); ok {
+		return i.track(dctx, done)
+	}
+
+	return dctx, done
 }
 
 func NewFile(m api.Memory, root fs.FS, fd int64, offset uint32, l uint32) (_ fs.File, err error) {
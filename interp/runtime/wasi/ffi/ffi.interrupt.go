@@ -0,0 +1,58 @@
+package ffi
+
+import (
+	"context"
+	"sync"
+)
+
+type interruptkey struct{}
+
+// NewInterrupts tracks the in flight host calls of a module.
+func NewInterrupts() *Interrupts {
+	return &Interrupts{
+		inflight: make(map[uint64]context.CancelCauseFunc),
+	}
+}
+
+// Interrupts cancels the host calls in flight at the time of the interrupt, calls
+// initiated afterwards are unaffected allowing the module to perform cleanup operations.
+type Interrupts struct {
+	m        sync.Mutex
+	next     uint64
+	inflight map[uint64]context.CancelCauseFunc
+}
+
+// Interrupt the in flight host calls with the provided cause.
+func (t *Interrupts) Interrupt(cause error) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	for id, cancel := range t.inflight {
+		cancel(cause)
+		delete(t.inflight, id)
+	}
+}
+
+func (t *Interrupts) track(ctx context.Context, done context.CancelFunc) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	t.m.Lock()
+	id := t.next
+	t.next++
+	t.inflight[id] = cancel
+	t.m.Unlock()
+
+	return ctx, func() {
+		t.m.Lock()
+		delete(t.inflight, id)
+		t.m.Unlock()
+		cancel(nil)
+		done()
+	}
+}
+
+// WithInterrupts associates the interrupts with the context, host calls made with
+// the context are interruptible.
+func WithInterrupts(ctx context.Context, i *Interrupts) context.Context {
+	return context.WithValue(ctx, interruptkey{}, i)
+}
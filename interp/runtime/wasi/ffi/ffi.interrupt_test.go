@@ -0,0 +1,28 @@
+package ffi_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/egdaemon/eg/interp/runtime/wasi/ffi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterrupts(t *testing.T) {
+	cause := errors.New("interrupted")
+	i := ffi.NewInterrupts()
+	ctx := ffi.WithInterrupts(context.Background(), i)
+
+	inflight, done := ffi.ReadMicroDeadline(ctx, math.MaxInt64)
+	defer done()
+
+	i.Interrupt(cause)
+	require.ErrorIs(t, context.Cause(inflight), cause)
+
+	// calls initiated after the interrupt are unaffected.
+	after, done := ffi.ReadMicroDeadline(ctx, math.MaxInt64)
+	defer done()
+	require.NoError(t, after.Err())
+}
@@ -208,3 +208,17 @@ func CloneV2(dir string, runtimedir string) func(
 		return 0
 	}
 }
+
+// NoopCloneV2 skips cloning the repository, used when the module is analysed instead of executed.
+func NoopCloneV2(
+	ctx context.Context,
+	m api.Module,
+	deadline int64,
+	uriptr, urilen uint32,
+	remoteptr, remotelen uint32,
+	treeishptr, treeishlen uint32,
+	envoffset uint32, envlen uint32, envsize uint32,
+) (errcode uint32) {
+	log.Println("clone skipped while analysing")
+	return 0
+}
@@ -0,0 +1,221 @@
+package watch
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/egdaemon/eg/interp/events"
+)
+
+const (
+	// number of trailing output lines displayed for an expanded operation.
+	outputlines = 15
+	// delay before exiting once the run concludes, allowing the watch stream to deliver trailing events.
+	linger = 2 * time.Second
+)
+
+var (
+	stylefaint    = lipgloss.NewStyle().Faint(true)
+	stylecursor   = lipgloss.NewStyle().Bold(true)
+	stylecomplete = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	styleerror    = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	stylecancel   = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+)
+
+type doneMsg struct {
+	err error
+}
+
+type quitMsg struct{}
+
+// Done notifies the model the run has concluded.
+func Done(err error) tea.Msg {
+	return doneMsg{err: err}
+}
+
+// Writer attributes everything written to it to the run itself, i.e. the output of the root container.
+func Writer(p *tea.Program) io.Writer {
+	return writer{p: p}
+}
+
+type writer struct {
+	p *tea.Program
+}
+
+func (t writer) Write(b []byte) (int, error) {
+	t.p.Send(events.NewOutput(&events.Output{Stdout: slices.Clone(b)}))
+	return len(b), nil
+}
+
+// New model displaying the operations of the run as it executes.
+// the model consumes *events.Message provided via tea.Program.Send.
+func New(name string) Model {
+	return Model{
+		tree:    NewTree(name, time.Now()),
+		spinner: spinner.New(spinner.WithSpinner(spinner.MiniDot)),
+	}
+}
+
+type Model struct {
+	tree        *Tree
+	spinner     spinner.Model
+	cursor      int
+	width       int
+	done        bool
+	interrupted bool
+	err         error
+}
+
+// Interrupted reports if the user exited before the run concluded.
+func (t Model) Interrupted() bool {
+	return t.interrupted
+}
+
+func (t Model) Init() tea.Cmd {
+	return t.spinner.Tick
+}
+
+func (t Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case *events.Message:
+		t.tree.Apply(msg)
+	case doneMsg:
+		t.done, t.err = true, msg.err
+		t.tree.Conclude(events.OpState(msg.err), time.Now())
+		return t, tea.Tick(linger, func(time.Time) tea.Msg { return quitMsg{} })
+	case quitMsg:
+		return t, tea.Quit
+	case tea.WindowSizeMsg:
+		t.width = msg.Width
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			t.interrupted = !t.done
+			return t, tea.Quit
+		case "up", "k":
+			t.cursor = max(t.cursor-1, 0)
+		case "down", "j":
+			t.cursor = min(t.cursor+1, len(t.tree.rows())-1)
+		case "enter", " ", "tab":
+			if rows := t.tree.rows(); t.cursor < len(rows) {
+				rows[t.cursor].expanded = !rows[t.cursor].expanded
+			}
+		}
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		t.spinner, cmd = t.spinner.Update(msg)
+		return t, cmd
+	}
+
+	return t, nil
+}
+
+func (t Model) View() string {
+	var (
+		b   strings.Builder
+		now = time.Now()
+	)
+
+	for i, r := range t.tree.rows() {
+		line := fmt.Sprintf("%s%s %s %s", strings.Repeat("  ", r.depth), t.glyph(r.Node), r.Name, stylefaint.Render(duration(r.Elapsed(now))))
+		if lines := strings.Count(string(r.Output), "\n"); lines > 0 && !r.expanded {
+			line += stylefaint.Render(fmt.Sprintf(" [+%d lines]", lines))
+		}
+
+		if i == t.cursor && !t.done {
+			line = stylecursor.Render("> ") + line
+		} else {
+			line = "  " + line
+		}
+
+		b.WriteString(t.truncate(line))
+		b.WriteString("\n")
+
+		if r.expanded {
+			indent := strings.Repeat("  ", r.depth+3)
+			for _, l := range tail(r.Output, outputlines) {
+				b.WriteString(t.truncate(indent + stylefaint.Render(l)))
+				b.WriteString("\n")
+			}
+		}
+	}
+
+	if !t.done {
+		b.WriteString(stylefaint.Render("↑/↓ select • enter toggle output • q quit"))
+		b.WriteString("\n")
+	} else if t.err != nil {
+		b.WriteString(styleerror.Render(t.err.Error()))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+func (t Model) glyph(n *Node) string {
+	if n.Running {
+		return t.spinner.View()
+	}
+
+	switch n.State {
+	case events.Op_Completed:
+		return stylecomplete.Render("✓")
+	case events.Op_Error, events.Op_TimedOut:
+		return styleerror.Render("✗")
+	case events.Op_Cancelled:
+		return stylecancel.Render("⊘")
+	case events.Op_Skipped:
+		return stylefaint.Render("○")
+	default:
+		return stylefaint.Render("·")
+	}
+}
+
+func (t Model) truncate(s string) string {
+	if t.width <= 0 {
+		return s
+	}
+
+	return ansi.Truncate(s, t.width, "…")
+}
+
+func duration(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+
+	return d.Round(100 * time.Millisecond).String()
+}
+
+// trailing lines of the output, carriage returns are used by progress indicators to
+// rewrite the current line so only the final rewrite is kept.
+func tail(output []byte, n int) []string {
+	if len(output) == 0 {
+		return nil
+	}
+
+	lines := strings.Split(strings.TrimRight(string(output), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+
+	for i, l := range lines {
+		l = strings.TrimRight(l, "\r")
+		if idx := strings.LastIndex(l, "\r"); idx >= 0 {
+			l = l[idx+1:]
+		}
+		lines[i] = l
+	}
+
+	return lines
+}
@@ -0,0 +1,154 @@
+// Package watch assembles the events of a run into an operation tree as the run
+// executes and renders it within the terminal.
+package watch
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/egdaemon/eg/interp/events"
+)
+
+const (
+	// maximum amount of output retained per operation.
+	outputlimit = 64 * 1024
+)
+
+// Node within the operation tree.
+type Node struct {
+	ID       string
+	Name     string
+	State    events.Op_State
+	Running  bool
+	Started  time.Time
+	Duration time.Duration
+	Output   []byte
+	Children []*Node
+	expanded bool
+}
+
+// Elapsed duration of the operation, running operations are measured against the provided time.
+func (t *Node) Elapsed(now time.Time) time.Duration {
+	if t.Running {
+		return now.Sub(t.Started)
+	}
+
+	return t.Duration
+}
+
+// Pending reports if the operation has not been reported, i.e. the parent of an
+// operation whose events arrived out of order.
+func (t *Node) Pending() bool {
+	return !t.Running && t.State == events.Op_Initiated
+}
+
+func NewTree(name string, started time.Time) *Tree {
+	root := &Node{ID: name, Name: name, Running: true, Started: started}
+	return &Tree{
+		Root:  root,
+		index: map[string]*Node{"": root},
+	}
+}
+
+// Tree of operations assembled from the events of a run.
+type Tree struct {
+	Root  *Node
+	index map[string]*Node
+}
+
+// Apply the event to the tree, events unrelated to operations are ignored.
+func (t *Tree) Apply(m *events.Message) {
+	switch evt := m.Event.(type) {
+	case *events.Message_Op:
+		t.op(time.UnixMicro(m.Ts), evt.Op)
+	case *events.Message_Output:
+		t.output(evt.Output)
+	}
+}
+
+// Conclude the run, marking the root operation with the provided state.
+func (t *Tree) Conclude(state events.Op_State, ts time.Time) {
+	t.Root.Running = false
+	t.Root.State = state
+	t.Root.Duration = ts.Sub(t.Root.Started)
+}
+
+func (t *Tree) ensure(path []string) *Node {
+	key := strings.Join(path, "/")
+	if n, ok := t.index[key]; ok {
+		return n
+	}
+
+	id := path[len(path)-1]
+	n := &Node{ID: id, Name: id}
+	parent := t.ensure(path[:len(path)-1])
+	parent.Children = append(parent.Children, n)
+	t.index[key] = n
+	return n
+}
+
+func (t *Tree) op(ts time.Time, op *events.Op) {
+	if op == nil {
+		return
+	}
+
+	id := op.Op
+	if id == "" {
+		id = op.Name
+	}
+
+	n := t.ensure(append(slices.Clone(op.Path), id))
+	if op.Name != "" {
+		n.Name = op.Name
+	}
+
+	switch op.State {
+	case events.Op_Initiated:
+		// operations can be executed multiple times, the latest execution is displayed.
+		n.Running = true
+		n.State = events.Op_Initiated
+		n.Started = ts
+		n.Duration = 0
+	default:
+		n.Running = false
+		n.State = op.State
+		n.Duration = time.Duration(op.Milliseconds) * time.Millisecond
+	}
+}
+
+func (t *Tree) output(o *events.Output) {
+	if o == nil {
+		return
+	}
+
+	n := t.Root
+	if len(o.Path) > 0 {
+		n = t.ensure(o.Path)
+	}
+
+	n.Output = append(n.Output, o.Stdout...)
+	n.Output = append(n.Output, o.Stderr...)
+	if overflow := len(n.Output) - outputlimit; overflow > 0 {
+		n.Output = n.Output[overflow:]
+	}
+}
+
+type row struct {
+	*Node
+	depth int
+}
+
+// flatten the tree depth first into the rows that are displayed.
+func (t *Tree) rows() (rows []row) {
+	var walk func(n *Node, depth int)
+	walk = func(n *Node, depth int) {
+		rows = append(rows, row{Node: n, depth: depth})
+		for _, c := range n.Children {
+			walk(c, depth+1)
+		}
+	}
+	walk(t.Root, 0)
+
+	return rows
+}
@@ -0,0 +1,60 @@
+package watch_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/egdaemon/eg/interp/events"
+	"github.com/egdaemon/eg/interp/watch"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTree(t *testing.T) {
+	t.Run("operations are running until they conclude", func(t *testing.T) {
+		tree := watch.NewTree("run", time.Now())
+		tree.Apply(events.NewOp(&events.Op{Op: "ref1", Name: "main.build", State: events.Op_Initiated}))
+		require.Len(t, tree.Root.Children, 1)
+		build := tree.Root.Children[0]
+		require.True(t, build.Running)
+
+		tree.Apply(events.NewOp(&events.Op{Op: "ref1", Name: "main.build", State: events.Op_Completed, Milliseconds: 1500}))
+		require.Len(t, tree.Root.Children, 1)
+		require.False(t, build.Running)
+		require.Equal(t, events.Op_Completed, build.State)
+		require.Equal(t, 1500*time.Millisecond, build.Elapsed(time.Now()))
+	})
+
+	t.Run("operations are nested by their path", func(t *testing.T) {
+		tree := watch.NewTree("run", time.Now())
+		tree.Apply(events.NewOp(&events.Op{Op: "ref2", Name: "main.compile", State: events.Op_Error, Path: []string{"ref1"}}))
+		require.Len(t, tree.Root.Children, 1)
+		parent := tree.Root.Children[0]
+		require.True(t, parent.Pending())
+		require.Equal(t, "ref1", parent.Name)
+		require.Len(t, parent.Children, 1)
+		require.Equal(t, events.Op_Error, parent.Children[0].State)
+
+		tree.Apply(events.NewOp(&events.Op{Op: "ref1", Name: "main.build", State: events.Op_Initiated}))
+		require.Len(t, tree.Root.Children, 1)
+		require.Equal(t, "main.build", parent.Name)
+		require.False(t, parent.Pending())
+	})
+
+	t.Run("output is attributed to the operation that executed the command", func(t *testing.T) {
+		tree := watch.NewTree("run", time.Now())
+		tree.Apply(events.NewOp(&events.Op{Op: "ref1", Name: "main.build", State: events.Op_Initiated}))
+		tree.Apply(events.NewOutput(&events.Output{Path: []string{"ref1"}, Stdout: []byte("hello\n")}))
+		tree.Apply(events.NewOutput(&events.Output{Path: []string{"ref1"}, Stderr: []byte("world\n")}))
+		tree.Apply(events.NewOutput(&events.Output{Stdout: []byte("container\n")}))
+		require.Equal(t, "hello\nworld\n", string(tree.Root.Children[0].Output))
+		require.Equal(t, "container\n", string(tree.Root.Output))
+	})
+
+	t.Run("concluding the run stops the root operation", func(t *testing.T) {
+		ts := time.Now()
+		tree := watch.NewTree("run", ts)
+		tree.Conclude(events.Op_Completed, ts.Add(time.Second))
+		require.False(t, tree.Root.Running)
+		require.Equal(t, time.Second, tree.Root.Elapsed(time.Now()))
+	})
+}
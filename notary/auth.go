@@ -1,8 +1,11 @@
 package notary
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 
 	"github.com/egdaemon/eg/internal/errorsx"
@@ -77,6 +80,30 @@ func AutoSignerInfo() (fp string, pub []byte, err error) {
 	return ssh.FingerprintSHA256(pubk), encoded, nil
 }
 
+// AuthorizedKeys loads the public keys from files in the authorized keys format,
+// missing files are ignored.
+func AuthorizedKeys(paths ...string) (keys []ssh.PublicKey, err error) {
+	for _, path := range paths {
+		encoded, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, errorsx.Wrapf(err, "unable to read authorized keys %s", path)
+		}
+
+		for len(bytes.TrimSpace(encoded)) > 0 {
+			var pubk ssh.PublicKey
+			if pubk, _, _, encoded, err = ssh.ParseAuthorizedKey(encoded); err != nil {
+				return nil, errorsx.Wrapf(err, "unable to parse authorized keys %s", path)
+			}
+
+			keys = append(keys, pubk)
+		}
+	}
+
+	return keys, nil
+}
+
 func newAutoSignerPath(location string, comment string, kgen keygen) (s Signer, err error) {
 	var (
 		ss ssh.Signer
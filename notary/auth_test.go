@@ -37,3 +37,24 @@ func TestNewAutoSignerPath(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestAuthorizedKeys(t *testing.T) {
+	t.Run("should ignore missing files", func(t *testing.T) {
+		keys, err := AuthorizedKeys(filepath.Join(t.TempDir(), "missing.pub"))
+		require.NoError(t, err)
+		require.Empty(t, keys)
+	})
+
+	t.Run("should load every key within the file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "authorized_keys")
+		_, pub1, err := sshx.NewKeyGenSeeded("key1").Generate()
+		require.NoError(t, err)
+		_, pub2, err := sshx.NewKeyGenSeeded("key2").Generate()
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, append(append(pub1, '\n'), pub2...), 0600))
+
+		keys, err := AuthorizedKeys(path)
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+	})
+}
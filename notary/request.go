@@ -0,0 +1,439 @@
+package notary
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/egdaemon/eg/compute"
+	"github.com/egdaemon/eg/internal/bytesx"
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/jwtx"
+	"github.com/gofrs/uuid/v5"
+	"github.com/golang-jwt/jwt/v4"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	HeaderFingerprint = "X-EG-Fingerprint"
+	HeaderTimestamp   = "X-EG-Timestamp"
+	HeaderNonce       = "X-EG-Nonce"
+	HeaderDigest      = "X-EG-Digest"
+	HeaderSignature   = "X-EG-Signature"
+)
+
+const (
+	// ErrUnauthenticated used when the request's signature, timestamp, nonce or token are invalid.
+	ErrUnauthenticated = errorsx.String("request authentication failed")
+	// ErrUnauthorized used when the authenticated token doesn't permit the request.
+	ErrUnauthorized = errorsx.String("request not authorized")
+	// ErrDigestMismatch used when the body of a request doesn't match its signed digest.
+	ErrDigestMismatch = errorsx.String("request body does not match the signed digest")
+)
+
+// maximum size of a request body buffered in memory while verifying its digest,
+// larger bodies are spooled to disk.
+const spoolmemory = int64(4 * bytesx.MiB)
+
+// SignRequest signs the method, uri, authorization header and body digest of the request
+// along with a timestamp and nonce which prevents the request from being replayed.
+func SignRequest(s ssh.Signer, r *http.Request) (err error) {
+	var (
+		digest []byte
+		sig    *ssh.Signature
+	)
+
+	if digest, err = bodydigest(r); err != nil {
+		return errorsx.Wrap(err, "unable to digest request body")
+	}
+
+	r.Header.Set(HeaderFingerprint, ssh.FingerprintSHA256(s.PublicKey()))
+	r.Header.Set(HeaderTimestamp, time.Now().UTC().Format(time.RFC3339Nano))
+	r.Header.Set(HeaderNonce, uuid.Must(uuid.NewV4()).String())
+	r.Header.Set(HeaderDigest, base64.RawURLEncoding.EncodeToString(digest))
+
+	if sig, err = s.Sign(rand.Reader, canonical(r)); err != nil {
+		return errorsx.Wrap(err, "unable to sign request")
+	}
+
+	r.Header.Set(HeaderSignature, base64.RawURLEncoding.EncodeToString(ssh.Marshal(sig)))
+
+	return nil
+}
+
+// NewComputeToken generates a token permitting compute requests against the account.
+func NewComputeToken(account string, d time.Duration) *compute.Token {
+	ts := time.Now()
+	return &compute.Token{
+		Id:            uuid.Must(uuid.NewV4()).String(),
+		AccountId:     account,
+		Issued:        ts.Unix(),
+		NotBefore:     ts.Unix(),
+		Expires:       ts.Add(d).Unix(),
+		ComputeRead:   true,
+		ComputeModify: true,
+	}
+}
+
+// SignToken encodes the token as a jwt signed by the key.
+func SignToken(s ssh.Signer, token *compute.Token) (string, error) {
+	return jwt.NewWithClaims(jwtx.NewSSHSigner(), token).SignedString(s)
+}
+
+// NewHTTP signs every request issued by the client, each request carries a short lived
+// compute token for the account.
+func NewHTTP(c *http.Client, s ssh.Signer, account string) *http.Client {
+	d := c.Transport
+	if d == nil {
+		d = http.DefaultTransport
+	}
+
+	c.Transport = HTTPTransport{
+		s:       s,
+		account: account,
+		d:       d,
+	}
+
+	return c
+}
+
+type HTTPTransport struct {
+	s       ssh.Signer
+	account string
+	d       http.RoundTripper
+}
+
+func (t HTTPTransport) RoundTrip(req *http.Request) (resp *http.Response, err error) {
+	signed, err := SignToken(t.s, NewComputeToken(t.account, time.Minute))
+	if err != nil {
+		return nil, errorsx.Wrap(err, "unable to sign token")
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "BEARER "+signed)
+
+	if err = SignRequest(t.s, req); err != nil {
+		return nil, err
+	}
+
+	return t.d.RoundTrip(req)
+}
+
+type VerifierOption func(*Verifier)
+
+// VerifierOptionSkew the maximum difference between the timestamp of a request and
+// the verifier's clock, requests outside of the window are rejected.
+func VerifierOptionSkew(d time.Duration) VerifierOption {
+	return func(v *Verifier) {
+		v.skew = d
+	}
+}
+
+// AuthorizedKey a key permitted to sign requests along with the accounts it may act on.
+// the account of a request's compute token is claimed by the client, binding keys to
+// accounts prevents an authorized key from acting on accounts it doesn't belong to.
+type AuthorizedKey struct {
+	Key      ssh.PublicKey
+	Accounts []string
+}
+
+// Bind the key to the accounts.
+func Bind(k ssh.PublicKey, accounts ...string) AuthorizedKey {
+	return AuthorizedKey{Key: k, Accounts: accounts}
+}
+
+// NewVerifier authenticates requests signed by any of the provided keys.
+func NewVerifier(keys []AuthorizedKey, options ...VerifierOption) Verifier {
+	v := Verifier{
+		keys:   make(map[string]AuthorizedKey, len(keys)),
+		skew:   time.Minute,
+		nonces: &nonces{seen: make(map[string]time.Time)},
+	}
+
+	for _, k := range keys {
+		v.keys[ssh.FingerprintSHA256(k.Key)] = k
+	}
+
+	for _, opt := range options {
+		opt(&v)
+	}
+
+	return v
+}
+
+type Verifier struct {
+	keys   map[string]AuthorizedKey
+	skew   time.Duration
+	nonces *nonces
+}
+
+// Verify authenticates the request returning the compute token it was issued with.
+// the body of the request is read and verified against its signed digest before Verify
+// returns, the body is replaced with the verified content; callers should close it.
+func (t Verifier) Verify(r *http.Request) (_ *compute.Token, err error) {
+	var (
+		ts     time.Time
+		sigb   []byte
+		sig    ssh.Signature
+		digest []byte
+		token  compute.Token
+	)
+
+	fingerprint := r.Header.Get(HeaderFingerprint)
+	authorized, ok := t.keys[fingerprint]
+	if !ok {
+		return nil, errorsx.Wrapf(ErrUnauthenticated, "unknown key %s", fingerprint)
+	}
+
+	if ts, err = time.Parse(time.RFC3339Nano, r.Header.Get(HeaderTimestamp)); err != nil {
+		return nil, errorsx.Wrap(ErrUnauthenticated, "invalid timestamp")
+	}
+
+	if delta := time.Since(ts); delta > t.skew || delta < -t.skew {
+		return nil, errorsx.Wrapf(ErrUnauthenticated, "request expired %s", ts)
+	}
+
+	nonce := r.Header.Get(HeaderNonce)
+	if nonce == "" {
+		return nil, errorsx.Wrap(ErrUnauthenticated, "missing nonce")
+	}
+
+	if digest, err = base64.RawURLEncoding.DecodeString(r.Header.Get(HeaderDigest)); err != nil || len(digest) != sha256.Size {
+		return nil, errorsx.Wrap(ErrUnauthenticated, "invalid digest")
+	}
+
+	if sigb, err = base64.RawURLEncoding.DecodeString(r.Header.Get(HeaderSignature)); err != nil {
+		return nil, errorsx.Wrap(ErrUnauthenticated, "invalid signature encoding")
+	}
+
+	if err = ssh.Unmarshal(sigb, &sig); err != nil {
+		return nil, errorsx.Wrap(ErrUnauthenticated, "invalid signature encoding")
+	}
+
+	if err = authorized.Key.Verify(canonical(r), &sig); err != nil {
+		return nil, errorsx.Wrap(ErrUnauthenticated, "invalid signature")
+	}
+
+	// only claim the nonce once the signature is verified, otherwise forged requests could
+	// exhaust the nonces of legitimate ones.
+	if !t.nonces.claim(fingerprint+nonce, ts.Add(t.skew)) {
+		return nil, errorsx.Wrapf(ErrUnauthenticated, "replayed nonce %s", nonce)
+	}
+
+	scheme, bearer, _ := strings.Cut(strings.TrimSpace(r.Header.Get("Authorization")), " ")
+	if !strings.EqualFold(scheme, "bearer") {
+		return nil, errorsx.Wrap(ErrUnauthenticated, "missing bearer token")
+	}
+
+	_, err = jwt.ParseWithClaims(strings.TrimSpace(bearer), &token, func(*jwt.Token) (interface{}, error) {
+		return authorized.Key, nil
+	}, jwt.WithValidMethods([]string{jwtx.NewSSHSigner().Alg()}))
+	if err != nil {
+		return nil, errorsx.Wrapf(ErrUnauthenticated, "invalid token: %v", err)
+	}
+
+	if !slices.Contains(authorized.Accounts, token.AccountId) {
+		return nil, errorsx.Wrapf(ErrUnauthorized, "key %s is not bound to account %s", fingerprint, token.AccountId)
+	}
+
+	if r.Body, err = spool(r.Body, digest); err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// Permission determines if a token permits a request.
+type Permission func(*compute.Token) bool
+
+// ComputeRead permits tokens that can read the account's compute.
+func ComputeRead(t *compute.Token) bool {
+	return t.ComputeRead || t.ComputeModify
+}
+
+// ComputeModify permits tokens that can modify the account's compute.
+func ComputeModify(t *compute.Token) bool {
+	return t.ComputeModify
+}
+
+// Authorize the token to perform the request against the account.
+func Authorize(account string, token *compute.Token, permitted Permission) error {
+	if token.AccountId != account {
+		return errorsx.Wrapf(ErrUnauthorized, "token account %s does not match %s", token.AccountId, account)
+	}
+
+	if !permitted(token) {
+		return errorsx.Wrap(ErrUnauthorized, "token lacks the required permissions")
+	}
+
+	return nil
+}
+
+// Middleware authenticates and authorizes requests against the account, rejecting
+// unauthenticated requests with 401 and unauthorized requests with 403.
+func (t Verifier) Middleware(account string, permitted Permission) func(http.Handler) http.Handler {
+	return func(original http.Handler) http.Handler {
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			token, err := t.Verify(req)
+			if errors.Is(err, ErrUnauthorized) {
+				errorsx.Log(err)
+				resp.WriteHeader(http.StatusForbidden)
+				return
+			} else if errors.Is(err, ErrDigestMismatch) {
+				errorsx.Log(err)
+				resp.WriteHeader(http.StatusBadRequest)
+				return
+			} else if err != nil {
+				errorsx.Log(err)
+				resp.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			defer req.Body.Close()
+
+			if err = Authorize(account, token, permitted); err != nil {
+				errorsx.Log(err)
+				resp.WriteHeader(http.StatusForbidden)
+				return
+			}
+
+			original.ServeHTTP(resp, req)
+		})
+	}
+}
+
+// canonical representation of the request covered by its signature.
+func canonical(r *http.Request) []byte {
+	return []byte(strings.Join([]string{
+		r.Method,
+		r.URL.RequestURI(),
+		r.Header.Get("Authorization"),
+		r.Header.Get(HeaderFingerprint),
+		r.Header.Get(HeaderTimestamp),
+		r.Header.Get(HeaderNonce),
+		r.Header.Get(HeaderDigest),
+	}, "\n"))
+}
+
+// bodydigest computes the digest of the request body, the body is replaced
+// when it can't be retrieved a second time.
+func bodydigest(r *http.Request) (_ []byte, err error) {
+	var (
+		body io.ReadCloser
+		h    = sha256.New()
+	)
+
+	if r.Body == nil || r.Body == http.NoBody {
+		return h.Sum(nil), nil
+	}
+
+	if r.GetBody == nil {
+		buf, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(buf))
+		r.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(buf)), nil
+		}
+	}
+
+	if body, err = r.GetBody(); err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if _, err = io.Copy(h, body); err != nil {
+		return nil, err
+	}
+
+	return h.Sum(nil), nil
+}
+
+// spool reads the entire body verifying it against the expected digest, handlers only ever
+// observe verified content. bodies exceeding spoolmemory are spooled to an unlinked
+// temporary file which is released when the returned body is closed.
+func spool(body io.ReadCloser, expected []byte) (_ io.ReadCloser, err error) {
+	var (
+		h   = sha256.New()
+		buf = bytes.NewBuffer(nil)
+		tmp *os.File
+	)
+
+	if body == nil {
+		body = http.NoBody
+	}
+	defer body.Close()
+
+	n, err := io.Copy(io.MultiWriter(h, buf), io.LimitReader(body, spoolmemory+1))
+	if err != nil {
+		return nil, errorsx.Wrap(err, "unable to read request body")
+	}
+
+	if n <= spoolmemory {
+		if subtle.ConstantTimeCompare(h.Sum(nil), expected) != 1 {
+			return nil, ErrDigestMismatch
+		}
+
+		return io.NopCloser(buf), nil
+	}
+
+	if tmp, err = os.CreateTemp("", "eg.notary.*"); err != nil {
+		return nil, errorsx.Wrap(err, "unable to spool request body")
+	}
+	errorsx.Log(errorsx.Wrap(os.Remove(tmp.Name()), "unable to unlink spooled request body"))
+
+	// the buffered prefix is already digested.
+	if _, err = io.Copy(tmp, buf); err != nil {
+		return nil, errorsx.Compact(errorsx.Wrap(err, "unable to spool request body"), tmp.Close())
+	}
+
+	if _, err = io.Copy(io.MultiWriter(h, tmp), body); err != nil {
+		return nil, errorsx.Compact(errorsx.Wrap(err, "unable to spool request body"), tmp.Close())
+	}
+
+	if subtle.ConstantTimeCompare(h.Sum(nil), expected) != 1 {
+		return nil, errorsx.Compact(ErrDigestMismatch, tmp.Close())
+	}
+
+	if _, err = tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, errorsx.Compact(errorsx.WithStack(err), tmp.Close())
+	}
+
+	return tmp, nil
+}
+
+// nonces tracks the nonces observed until their request would have expired.
+type nonces struct {
+	m    sync.Mutex
+	seen map[string]time.Time
+}
+
+func (t *nonces) claim(nonce string, expires time.Time) bool {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	now := time.Now()
+	for k, ts := range t.seen {
+		if ts.Before(now) {
+			delete(t.seen, k)
+		}
+	}
+
+	if _, ok := t.seen[nonce]; ok {
+		return false
+	}
+
+	t.seen[nonce] = expires
+	return true
+}
@@ -0,0 +1,311 @@
+package notary
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/egdaemon/eg/compute"
+	"github.com/egdaemon/eg/internal/sshx"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func testsigner(t *testing.T, seed string) ssh.Signer {
+	s, err := sshx.SignerFromGenerator(sshx.NewKeyGenSeeded(seed))
+	require.NoError(t, err)
+	return s
+}
+
+func testrequest(t *testing.T, s ssh.Signer, method string, body string, token *compute.Token) *http.Request {
+	signed, err := SignToken(s, token)
+	require.NoError(t, err)
+
+	r := httptest.NewRequest(method, "/c/enqueue", strings.NewReader(body))
+	r.Header.Set("Authorization", "BEARER "+signed)
+	require.NoError(t, SignRequest(s, r))
+	return r
+}
+
+// resign the request after its headers have been tampered with.
+func resign(t *testing.T, s ssh.Signer, r *http.Request) {
+	sig, err := s.Sign(rand.Reader, canonical(r))
+	require.NoError(t, err)
+	r.Header.Set(HeaderSignature, base64.RawURLEncoding.EncodeToString(ssh.Marshal(sig)))
+}
+
+func TestVerifierMiddleware(t *testing.T) {
+	const account = "account-1"
+	var (
+		daemon   = testsigner(t, "daemon")
+		client   = testsigner(t, "client")
+		attacker = testsigner(t, "attacker")
+		other    = testsigner(t, "other")
+	)
+
+	expired := NewComputeToken(account, time.Minute)
+	expired.Issued = time.Now().Add(-2 * time.Hour).Unix()
+	expired.NotBefore = expired.Issued
+	expired.Expires = time.Now().Add(-time.Hour).Unix()
+
+	readonly := NewComputeToken(account, time.Minute)
+	readonly.ComputeModify = false
+
+	cases := []struct {
+		name     string
+		request  func(t *testing.T) *http.Request
+		expected int
+	}{
+		{
+			name: "signed by the daemon key",
+			request: func(t *testing.T) *http.Request {
+				return testrequest(t, daemon, http.MethodPost, "hello world", NewComputeToken(account, time.Minute))
+			},
+			expected: http.StatusOK,
+		},
+		{
+			name: "signed by an authorized client key",
+			request: func(t *testing.T) *http.Request {
+				return testrequest(t, client, http.MethodPost, "hello world", NewComputeToken(account, time.Minute))
+			},
+			expected: http.StatusOK,
+		},
+		{
+			name: "signed by an unknown key",
+			request: func(t *testing.T) *http.Request {
+				return testrequest(t, attacker, http.MethodPost, "hello world", NewComputeToken(account, time.Minute))
+			},
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name: "forged fingerprint",
+			request: func(t *testing.T) *http.Request {
+				r := testrequest(t, attacker, http.MethodPost, "hello world", NewComputeToken(account, time.Minute))
+				r.Header.Set(HeaderFingerprint, ssh.FingerprintSHA256(client.PublicKey()))
+				resign(t, attacker, r)
+				return r
+			},
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name: "unsigned",
+			request: func(t *testing.T) *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/c/enqueue", strings.NewReader("hello world"))
+			},
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name: "tampered path",
+			request: func(t *testing.T) *http.Request {
+				r := testrequest(t, client, http.MethodPost, "hello world", NewComputeToken(account, time.Minute))
+				r.URL.Path = "/b/upload"
+				return r
+			},
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name: "tampered body",
+			request: func(t *testing.T) *http.Request {
+				r := testrequest(t, client, http.MethodPost, "hello world", NewComputeToken(account, time.Minute))
+				r.Body = io.NopCloser(strings.NewReader("goodbye world"))
+				return r
+			},
+			expected: http.StatusBadRequest,
+		},
+		{
+			name: "token signed by another key",
+			request: func(t *testing.T) *http.Request {
+				r := testrequest(t, client, http.MethodPost, "hello world", NewComputeToken(account, time.Minute))
+				signed, err := SignToken(attacker, NewComputeToken(account, time.Minute))
+				require.NoError(t, err)
+				r.Header.Set("Authorization", "BEARER "+signed)
+				resign(t, client, r)
+				return r
+			},
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name: "expired timestamp",
+			request: func(t *testing.T) *http.Request {
+				r := testrequest(t, client, http.MethodPost, "hello world", NewComputeToken(account, time.Minute))
+				r.Header.Set(HeaderTimestamp, time.Now().Add(-10*time.Minute).UTC().Format(time.RFC3339Nano))
+				resign(t, client, r)
+				return r
+			},
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name: "future timestamp",
+			request: func(t *testing.T) *http.Request {
+				r := testrequest(t, client, http.MethodPost, "hello world", NewComputeToken(account, time.Minute))
+				r.Header.Set(HeaderTimestamp, time.Now().Add(10*time.Minute).UTC().Format(time.RFC3339Nano))
+				resign(t, client, r)
+				return r
+			},
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name: "expired token",
+			request: func(t *testing.T) *http.Request {
+				return testrequest(t, client, http.MethodPost, "hello world", expired)
+			},
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name: "wrong account",
+			request: func(t *testing.T) *http.Request {
+				return testrequest(t, client, http.MethodPost, "hello world", NewComputeToken("account-2", time.Minute))
+			},
+			expected: http.StatusForbidden,
+		},
+		{
+			name: "account not bound to the key",
+			request: func(t *testing.T) *http.Request {
+				return testrequest(t, other, http.MethodPost, "hello world", NewComputeToken(account, time.Minute))
+			},
+			expected: http.StatusForbidden,
+		},
+		{
+			name: "lowercase bearer",
+			request: func(t *testing.T) *http.Request {
+				r := testrequest(t, client, http.MethodPost, "hello world", NewComputeToken(account, time.Minute))
+				r.Header.Set("Authorization", strings.Replace(r.Header.Get("Authorization"), "BEARER", "Bearer", 1))
+				resign(t, client, r)
+				return r
+			},
+			expected: http.StatusOK,
+		},
+		{
+			name: "missing bearer",
+			request: func(t *testing.T) *http.Request {
+				r := testrequest(t, client, http.MethodPost, "hello world", NewComputeToken(account, time.Minute))
+				r.Header.Set("Authorization", strings.TrimPrefix(r.Header.Get("Authorization"), "BEARER "))
+				resign(t, client, r)
+				return r
+			},
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name: "missing permission",
+			request: func(t *testing.T) *http.Request {
+				return testrequest(t, client, http.MethodPost, "hello world", readonly)
+			},
+			expected: http.StatusForbidden,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			v := NewVerifier([]AuthorizedKey{Bind(daemon.PublicKey(), account), Bind(client.PublicKey(), account, "account-2"), Bind(other.PublicKey(), "account-3")})
+			handler := v.Middleware(account, ComputeModify)(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				if _, err := io.ReadAll(req.Body); err != nil {
+					resp.WriteHeader(http.StatusBadRequest)
+					return
+				}
+			}))
+
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, c.request(t))
+			require.Equal(t, c.expected, resp.Code)
+		})
+	}
+}
+
+func TestVerifierReplay(t *testing.T) {
+	s := testsigner(t, "client")
+	v := NewVerifier([]AuthorizedKey{Bind(s.PublicKey(), "account-1")})
+	r := testrequest(t, s, http.MethodGet, "", NewComputeToken("account-1", time.Minute))
+
+	_, err := v.Verify(r)
+	require.NoError(t, err)
+	_, err = v.Verify(r)
+	require.ErrorIs(t, err, ErrUnauthenticated)
+}
+
+func TestHTTPTransport(t *testing.T) {
+	var (
+		s        = testsigner(t, "client")
+		v        = NewVerifier([]AuthorizedKey{Bind(s.PublicKey(), "account-1", "account-2")})
+		received []byte
+	)
+
+	srv := httptest.NewServer(v.Middleware("account-1", ComputeModify)(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		var err error
+		received, err = io.ReadAll(req.Body)
+		require.NoError(t, err)
+	})))
+	defer srv.Close()
+
+	c := NewHTTP(&http.Client{}, s, "account-1")
+	for range 2 {
+		resp, err := c.Post(srv.URL+"/c/enqueue", "text/plain", bytes.NewBufferString("hello world"))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "hello world", string(received))
+	}
+
+	resp, err := NewHTTP(&http.Client{}, s, "account-2").Get(srv.URL + "/q")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestVerifierBody(t *testing.T) {
+	const account = "account-1"
+	var (
+		s     = testsigner(t, "client")
+		small = "hello world"
+		large = strings.Repeat("a", int(spoolmemory)+1024)
+	)
+
+	cases := []struct {
+		name     string
+		body     string
+		tampered string
+		expected int
+	}{
+		{name: "small body", body: small, expected: http.StatusOK},
+		{name: "large body", body: large, expected: http.StatusOK},
+		{name: "tampered small body", body: small, tampered: "goodbye world", expected: http.StatusBadRequest},
+		{name: "tampered large body", body: large, tampered: large[1:] + "b", expected: http.StatusBadRequest},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var (
+				invoked  bool
+				received []byte
+			)
+
+			v := NewVerifier([]AuthorizedKey{Bind(s.PublicKey(), account)})
+			handler := v.Middleware(account, ComputeModify)(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				var err error
+				invoked = true
+				received, err = io.ReadAll(req.Body)
+				require.NoError(t, err)
+			}))
+
+			r := testrequest(t, s, http.MethodPost, c.body, NewComputeToken(account, time.Minute))
+			if c.tampered != "" {
+				r.Body = io.NopCloser(strings.NewReader(c.tampered))
+			}
+
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, r)
+			require.Equal(t, c.expected, resp.Code)
+
+			// the handler must never observe unverified content.
+			require.Equal(t, c.tampered == "", invoked)
+			if invoked {
+				require.Equal(t, c.body, string(received))
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package runners
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/egdaemon/eg"
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/netpolicy"
+)
+
+// removes the root container's network when the policy restricts egress, the only
+// route out is the proxy served by NetworkPolicyServe.
+func AgentOptionNetworkPolicy(p netpolicy.Policy) AgentOption {
+	if !p.Enforced() {
+		return AgentOptionNoop
+	}
+
+	return AgentOptionCommandLine("--network", "none")
+}
+
+// serves the egress proxy for the workload within its runtime directory, the root module
+// tunnels the connections permitted by the policy through it. the proxy is shutdown when
+// the context is cancelled.
+func NetworkPolicyServe(ctx context.Context, runtimedir string, p netpolicy.Policy) (err error) {
+	if !p.Enforced() {
+		return nil
+	}
+
+	path := filepath.Join(runtimedir, eg.SocketNetwork)
+	errorsx.Log(errorsx.Ignore(os.Remove(path), os.ErrNotExist)) // recovered workloads may have left a socket behind.
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return errorsx.Wrapf(err, "unable to create %s", path)
+	}
+
+	// the workload's user within the container may differ from the daemon's.
+	if err = os.Chmod(path, 0666); err != nil {
+		return errorsx.Wrapf(err, "unable to set permissions of %s", path)
+	}
+
+	go func() {
+		proxy := netpolicy.NewProxy(p, netpolicy.ProxyOptionPermit(netpolicy.ControlPlane()...))
+		errorsx.Log(errorsx.Wrap(netpolicy.Serve(ctx, l, proxy), "network policy proxy failed"))
+	}()
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package runners
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/egdaemon/eg"
+	"github.com/egdaemon/eg/internal/errorsx"
+)
+
+const (
+	// ErrWorkloadCancelled is the cause of workloads cancelled by an operator.
+	ErrWorkloadCancelled = errorsx.String("workload cancelled")
+	// ErrWorkloadNotRunning is returned when cancelling a workload that isn't executing.
+	ErrWorkloadNotRunning = errorsx.String("workload is not running")
+)
+
+// NewCancellations grace is the duration a cancelled workload is given to run its
+// cleanup operations before being terminated.
+func NewCancellations(grace time.Duration) *Cancellations {
+	return &Cancellations{
+		grace:   grace,
+		running: make(map[string]*cancellation),
+	}
+}
+
+// Cancellations tracks the executing workloads allowing them to be cancelled.
+type Cancellations struct {
+	m       sync.Mutex
+	grace   time.Duration
+	running map[string]*cancellation
+}
+
+type cancellation struct {
+	runtimedir string
+	cancel     context.CancelCauseFunc
+	terminate  *time.Timer
+}
+
+// Cancel the running workload. the workload is informed of the cancellation, interrupting
+// its in flight operations, and terminated once the grace period elapses.
+func (t *Cancellations) Cancel(id string) error {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	c, ok := t.running[id]
+	if !ok {
+		return ErrWorkloadNotRunning
+	}
+
+	if c.terminate != nil {
+		return nil
+	}
+
+	if err := os.WriteFile(filepath.Join(c.runtimedir, eg.CancelFile), nil, 0600); err != nil {
+		// unable to inform the workload, terminate immediately.
+		c.cancel(ErrWorkloadCancelled)
+		return errorsx.Wrap(err, "unable to inform the workload of its cancellation")
+	}
+
+	c.terminate = time.AfterFunc(t.grace, func() {
+		c.cancel(ErrWorkloadCancelled)
+	})
+
+	return nil
+}
+
+// track the workload until the returned function is invoked. nil safe, in which case
+// the workload is not cancellable.
+func (t *Cancellations) track(ctx context.Context, id string, runtimedir string) (context.Context, context.CancelFunc) {
+	if t == nil {
+		return context.WithCancel(ctx)
+	}
+
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	c := &cancellation{runtimedir: runtimedir, cancel: cancel}
+	t.running[id] = c
+
+	return ctx, func() {
+		t.m.Lock()
+		defer t.m.Unlock()
+
+		if c.terminate != nil {
+			c.terminate.Stop()
+		}
+
+		if t.running[id] == c {
+			delete(t.running, id)
+		}
+
+		cancel(nil)
+	}
+}
+
+// cancelled reports if the workload was cancelled, i.e. informed of its cancellation.
+func (t *Cancellations) cancelled(id string) bool {
+	if t == nil {
+		return false
+	}
+
+	t.m.Lock()
+	defer t.m.Unlock()
+	c, ok := t.running[id]
+	return ok && c.terminate != nil
+}
@@ -0,0 +1,47 @@
+package runners
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/egdaemon/eg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCancellations(t *testing.T) {
+	t.Run("cancelled workloads are informed and terminated after the grace period", func(t *testing.T) {
+		dir := t.TempDir()
+		c := NewCancellations(10 * time.Millisecond)
+
+		ctx, done := c.track(context.Background(), "a", dir)
+		defer done()
+
+		require.NoError(t, c.Cancel("a"))
+		require.FileExists(t, filepath.Join(dir, eg.CancelFile))
+		require.True(t, c.cancelled("a"))
+		require.NoError(t, c.Cancel("a"))
+
+		<-ctx.Done()
+		require.ErrorIs(t, context.Cause(ctx), ErrWorkloadCancelled)
+	})
+
+	t.Run("completed workloads are not running", func(t *testing.T) {
+		c := NewCancellations(time.Minute)
+
+		ctx, done := c.track(context.Background(), "a", t.TempDir())
+		done()
+
+		require.ErrorIs(t, c.Cancel("a"), ErrWorkloadNotRunning)
+		require.ErrorIs(t, context.Cause(ctx), context.Canceled)
+		require.False(t, c.cancelled("a"))
+	})
+
+	t.Run("nil cancellations are never cancelled", func(t *testing.T) {
+		var c *Cancellations
+		_, done := c.track(context.Background(), "a", t.TempDir())
+		defer done()
+		require.False(t, c.cancelled("a"))
+	})
+}
@@ -0,0 +1,62 @@
+package runners
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/httpx"
+)
+
+// Interrogates and controls the workloads of a local runner, expects the client
+// to dial the runner's socket.
+func NewQueueClient(c *http.Client) *QueueClient {
+	return &QueueClient{
+		c:    c,
+		host: "http://localhost",
+	}
+}
+
+type QueueClient struct {
+	c    *http.Client
+	host string
+}
+
+func (t QueueClient) Status(ctx context.Context) (s QueueStatus, err error) {
+	httpreq, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/q", t.host), nil)
+	if err != nil {
+		return s, err
+	}
+
+	resp, err := httpx.AsError(t.c.Do(httpreq))
+	defer func() { errorsx.Log(httpx.AutoClose(resp)) }()
+	if err != nil {
+		return s, err
+	}
+
+	return s, errorsx.Wrap(httpx.DecodeJSON(resp, &s), "unable to decode queue status")
+}
+
+func (t QueueClient) Cancel(ctx context.Context, id string) (err error) {
+	return t.post(ctx, fmt.Sprintf("%s/q/%s/cancel", t.host, id))
+}
+
+func (t QueueClient) Requeue(ctx context.Context, id string) (err error) {
+	return t.post(ctx, fmt.Sprintf("%s/q/%s/requeue", t.host, id))
+}
+
+func (t QueueClient) post(ctx context.Context, endpoint string) (err error) {
+	httpreq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := httpx.AsError(t.c.Do(httpreq))
+	defer func() { errorsx.Log(httpx.AutoClose(resp)) }()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -33,7 +33,7 @@ func compileEntrypoint(ctx context.Context, ws workspaces.Context) (*transpile.C
 		return nil, err
 	}
 
-	if err = compile.EnsureRequiredPackages(ctx, filepath.Join(ws.Root, ws.TransDir)); err != nil {
+	if err = compile.EnsureRequiredPackages(ctx, ws); err != nil {
 		return nil, err
 	}
 
@@ -125,9 +125,9 @@ func compileWorkload(ctx context.Context, c *http.Client, dir string, rundirs Sp
 		return errorsx.Wrap(err, "unable to compile module")
 	}
 
-	entry, err := filepath.Rel(filepath.Join(ws.Root, ws.BuildDir), module.Path)
+	entry, err := workspaces.PathEntry(ws, module.Path)
 	if err != nil {
-		return errorsx.Wrap(err, "unable to determine entry relative path")
+		return err
 	}
 
 	envb := envx.Build().FromEnviron(errorsx.Zero(gitx.HeadEnv(repo, req.Enqueued.VcsUri, req.Enqueued.VcsUri, req.Enqueued.VcsCommit))...)
@@ -89,6 +89,9 @@ func compileOne(ctx context.Context, c *http.Client, compiledirs, rundirs SpoolD
 			continue
 		}
 
+		// compile jobs are never deferred, they're committed once dequeued.
+		compiledirs.Dispatched(spooledmetadata(dir).GetAccountId())
+
 		log.Println("compiling workload initiated", dir)
 		if err := compileWorkload(ctx, c, dir, rundirs); err != nil {
 			log.Println(errorsx.Wrap(err, "compile failed"))
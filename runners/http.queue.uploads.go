@@ -2,6 +2,7 @@ package runners
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"mime/multipart"
 	"strconv"
@@ -95,6 +96,25 @@ func NewEnqueueUpload(enq *Enqueued, archive io.Reader) (mimetype string, body i
 	})
 }
 
+// completion status of a workload.
+const (
+	CompletionSucceeded = "succeeded"
+	CompletionFailed    = "failed"
+	CompletionCancelled = "cancelled"
+)
+
+// CompletionStatus of the workload given the cause of its completion.
+func CompletionStatus(cause error) string {
+	switch {
+	case cause == nil:
+		return CompletionSucceeded
+	case errors.Is(cause, ErrWorkloadCancelled):
+		return CompletionCancelled
+	default:
+		return CompletionFailed
+	}
+}
+
 func NewEnqueueCompletion(cause error, duration time.Duration, logs io.Reader, analytics io.Reader) (mimetype string, body io.ReadCloser, err error) {
 	return httpx.Multipart(func(w *multipart.Writer) error {
 		if err = w.WriteField("duration", strconv.FormatUint(uint64(duration.Milliseconds()), 10)); err != nil {
@@ -105,6 +125,10 @@ func NewEnqueueCompletion(cause error, duration time.Duration, logs io.Reader, a
 			return errorsx.Wrap(err, "unable to write completion state")
 		}
 
+		if err = w.WriteField("status", CompletionStatus(cause)); err != nil {
+			return errorsx.Wrap(err, "unable to write completion status")
+		}
+
 		part, lerr := w.CreatePart(httpx.NewMultipartHeader("text/plain", "logs", "daemon.logs"))
 		if lerr != nil {
 			return errorsx.Wrap(lerr, "unable to create logs part")
@@ -0,0 +1,27 @@
+package runners
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/interp/analytics"
+	"github.com/egdaemon/eg/interp/provenance"
+	"golang.org/x/crypto/ssh"
+)
+
+// Attest the run from the materials recorded within its analytics, writing the SBOM and
+// the provenance signed by the runner's key into the runtime directory.
+func Attest(ctx context.Context, runtimedir string, s ssh.Signer, r provenance.Run) (err error) {
+	var (
+		db *sql.DB
+	)
+
+	if db, err = sql.Open("duckdb", filepath.Join(runtimedir, analytics.Database)); err != nil {
+		return errorsx.Wrap(err, "unable to open analytics")
+	}
+	defer db.Close()
+
+	return provenance.Attest(ctx, db, runtimedir, s, r)
+}
@@ -32,15 +32,19 @@ import (
 	"github.com/egdaemon/eg/internal/iox"
 	"github.com/egdaemon/eg/internal/langx"
 	"github.com/egdaemon/eg/internal/md5x"
+	"github.com/egdaemon/eg/internal/netpolicy"
 	"github.com/egdaemon/eg/internal/podmanx"
+	"github.com/egdaemon/eg/internal/redactx"
 	"github.com/egdaemon/eg/internal/tarx"
 	"github.com/egdaemon/eg/internal/userx"
 	"github.com/egdaemon/eg/internal/wasix"
 	"github.com/egdaemon/eg/interp/c8sproxy"
 	"github.com/egdaemon/eg/interp/events"
+	"github.com/egdaemon/eg/interp/provenance"
 	"github.com/egdaemon/eg/workspaces"
 	"github.com/fsnotify/fsnotify"
 	"github.com/gofrs/uuid/v5"
+	"golang.org/x/crypto/ssh"
 
 	"github.com/alitto/pond/v2"
 )
@@ -114,11 +118,13 @@ type metadata struct {
 	reload       chan error
 	downloader
 	completion
-	failure   func(cause error)
-	dirs      *SpoolDirs
-	rm        *ResourceManager
-	agentopts []AgentOption
-	gpu       bool
+	failure       func(cause error)
+	dirs          *SpoolDirs
+	rm            *ResourceManager
+	cancellations *Cancellations
+	agentopts     []AgentOption
+	gpu           bool
+	signer        ssh.Signer
 }
 
 type QueueOption func(*metadata)
@@ -148,6 +154,20 @@ func QueueOptionGPU(enabled bool) QueueOption {
 	}
 }
 
+// allow the running workloads to be cancelled.
+func QueueOptionCancellations(c *Cancellations) QueueOption {
+	return func(m *metadata) {
+		m.cancellations = c
+	}
+}
+
+// sign the provenance of successful runs with the runner's key.
+func QueueOptionSigner(s ssh.Signer) QueueOption {
+	return func(m *metadata) {
+		m.signer = s
+	}
+}
+
 func QueueOptionFailure(fn func(cause error)) QueueOption {
 	return func(m *metadata) {
 		m.failure = fn
@@ -475,6 +495,8 @@ func beginwork(ctx context.Context, md metadata, dir string) state {
 		return failure(md, errorsx.Wrap(err, "unable to claim repo lock"), idle(md))
 	}
 
+	md.dirs.Dispatched(workload.Enqueued.AccountId)
+
 	md.rm.Reserve(NewRuntimeResourcesFromDequeued(workload.Enqueued))
 
 	if archive, err = os.Open(filepath.Join(dir, "archive.tar.gz")); err != nil {
@@ -540,6 +562,11 @@ func beginwork(ctx context.Context, md metadata, dir string) state {
 		return completed(workload.Enqueued, md, bucket, ws, 0, errorsx.Wrap(err, "unable to configure gpu support"))
 	}
 
+	policy, err := netpolicy.FromEnviron(errorsx.Zero(envb.Environ())...)
+	if err != nil {
+		return completed(workload.Enqueued, md, bucket, ws, 0, errorsx.Wrap(err, "invalid network policy"))
+	}
+
 	aopts := make([]AgentOption, 0, len(md.agentopts)+32)
 	aopts = append(aopts, md.agentopts...)
 	aopts = append(
@@ -550,6 +577,7 @@ func beginwork(ctx context.Context, md metadata, dir string) state {
 		AgentOptionCores(workload.Enqueued.Cores),
 		AgentOptionMemory(workload.Enqueued.Memory),
 		AgentOptionHostOS(),
+		AgentOptionNetworkPolicy(policy),
 		gpu,
 	)
 
@@ -634,17 +662,50 @@ func (t staterunning) Update(ctx context.Context) state {
 		"--volume", AgentMountReadWrite(t.ws.CacheDir, eg.DefaultMountRoot(eg.CacheDirectory)),
 	)
 
-	logger := log.New(io.MultiWriter(os.Stderr, logdst), t.ragent.id, log.Flags())
+	// secrets are redacted from daemon.log when the workload opted in, see eg.EnvComputeRedact.
+	redact := redactx.FromEnviron(errorsx.Zero(envx.FromPath(filepath.Join(t.ws.RuntimeDir, eg.EnvironFile)))...)
+	output := redact.Writer(io.MultiWriter(os.Stderr, logdst))
+	logger := log.New(output, t.ragent.id, log.Flags())
 	prepcmd := func(cmd *exec.Cmd) *exec.Cmd {
 		cmd.Dir = t.ws.Root
 		cmd.Stdout = logger.Writer()
 		cmd.Stderr = logger.Writer()
 		return cmd
 	}
+	defer func() {
+		errorsx.Log(errorsx.Wrap(redactx.Flush(output), "unable to flush daemon.log"))
+		if n := redact.Count(); n > 0 {
+			logger.Println("redacted", n, "occurrences of secrets from the output")
+		}
+	}()
+
+	wctx, done := t.cancellations.track(ctx, t.workload.Id, t.ws.RuntimeDir)
+	defer done()
+
+	// see eg.EnvComputeNetworkPolicy, the policy was validated when the workload was initialized.
+	policy := errorsx.Zero(netpolicy.FromEnviron(errorsx.Zero(envx.FromPath(filepath.Join(t.ws.RuntimeDir, eg.EnvironFile)))...))
+	if err = NetworkPolicyServe(wctx, t.ws.RuntimeDir, policy); err != nil {
+		return completed(t.workload, t.metadata, t.bucket, t.ws, 0, err)
+	}
 
 	ts := time.Now()
 	// TODO REVISIT using t.ws.RuntimeDir as moduledir.
-	err = c8sproxy.PodmanModule(ctx, prepcmd, "eg", fmt.Sprintf("eg-%s", t.ragent.id), t.ws.RuntimeDir, options...)
+	err = c8sproxy.PodmanModule(wctx, prepcmd, "eg", fmt.Sprintf("eg-%s", t.ragent.id), t.ws.RuntimeDir, options...)
+	if err != nil && t.cancellations.cancelled(t.workload.Id) {
+		err = fmt.Errorf("%w: %w", ErrWorkloadCancelled, err)
+	}
+
+	// successful runs are attested by the runner, see provenance.Statement.
+	if err == nil && t.signer != nil {
+		errorsx.Log(errorsx.Wrap(Attest(ctx, t.ws.RuntimeDir, t.signer, provenance.Run{
+			ID:         t.workload.Id,
+			Repository: t.workload.VcsUri,
+			Commit:     t.workload.VcsCommit,
+			Started:    ts,
+			Finished:   time.Now(),
+		}), "unable to attest run"))
+	}
+
 	return completed(t.workload, t.metadata, t.bucket, t.ws, time.Since(ts), err)
 }
 
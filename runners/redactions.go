@@ -0,0 +1,22 @@
+package runners
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/egdaemon/eg/internal/redactx"
+	"github.com/egdaemon/eg/interp/events"
+)
+
+// RecordRedactions records the number of redactions so users know secrets were echoed by the workload.
+func RecordRedactions(ctx context.Context, analytics *sql.DB, r *redactx.Redactor) error {
+	n := r.Count()
+	if n == 0 {
+		return nil
+	}
+
+	log.Println("redacted", n, "occurrences of secrets from the output")
+	return events.RecordMetric(ctx, analytics, events.NewMetric(redactx.MetricName, fmt.Appendf(nil, `{"count":%d}`, n)))
+}
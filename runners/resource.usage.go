@@ -32,9 +32,9 @@ func NewRuntimeResourcesFromDequeued(d *Enqueued) RuntimeResources {
 }
 
 type RuntimeResources struct {
-	Cores  uint64
-	Memory uint64
-	Vram   uint64
+	Cores  uint64 `json:"cores"`
+	Memory uint64 `json:"memory"`
+	Vram   uint64 `json:"vram"`
 }
 
 func (t RuntimeResources) Reserve(limits RuntimeResources) RuntimeResources {
@@ -1,7 +1,9 @@
 package runners
 
 import (
+	"archive/tar"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/fs"
@@ -9,10 +11,15 @@ import (
 	"math/rand/v2"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
+	"time"
 
+	"github.com/egdaemon/eg"
 	"github.com/egdaemon/eg/internal/errorsx"
 	"github.com/egdaemon/eg/internal/fsx"
+	"github.com/egdaemon/eg/internal/langx"
+	"github.com/egdaemon/eg/internal/tarx"
 	"github.com/egdaemon/eg/internal/userx"
 	"github.com/gofrs/uuid/v5"
 	"github.com/pkg/errors"
@@ -31,6 +38,13 @@ func SpoolOptionPopLimit(n int) SpoolOption {
 	}
 }
 
+// SpoolOptionAging the duration a queued workload must wait to be promoted a priority class.
+func SpoolOptionAging(d time.Duration) SpoolOption {
+	return func(sd *SpoolDirs) {
+		sd.aging = d
+	}
+}
+
 type SpoolDirs struct {
 	defaultPerms fs.FileMode
 	renamemux    *sync.Mutex
@@ -39,10 +53,14 @@ type SpoolDirs struct {
 	Running      string
 	Tombstoned   string
 	Blocked      string
+	Archived     string
 	poplimit     int
+	archivelimit int
+	aging        time.Duration
+	shares       *fairshare
 }
 
-func NewSpoolDir(root string) SpoolDirs {
+func NewSpoolDir(root string, options ...SpoolOption) SpoolDirs {
 	const defaultPerms = 0700 | os.ModeSetgid
 	dirs := SpoolDirs{
 		defaultPerms: defaultPerms,
@@ -52,10 +70,15 @@ func NewSpoolDir(root string) SpoolDirs {
 		Running:      filepath.Join(root, "r"),
 		Tombstoned:   filepath.Join(root, "t"),
 		Blocked:      filepath.Join(root, "b"),
+		Archived:     filepath.Join(root, "a"),
 		poplimit:     100,
+		archivelimit: 16,
+		aging:        5 * time.Minute,
 	}
+	dirs = langx.Clone(dirs, options...)
+	dirs.shares = newfairshare(dirs.aging)
 
-	errorsx.Log(errors.Wrap(fsx.MkDirs(defaultPerms, dirs.Downloading, dirs.Queued, dirs.Running, dirs.Tombstoned, dirs.Blocked), "unable to make spool directories"))
+	errorsx.Log(errors.Wrap(fsx.MkDirs(defaultPerms, dirs.Downloading, dirs.Queued, dirs.Running, dirs.Tombstoned, dirs.Blocked, dirs.Archived), "unable to make spool directories"))
 
 	return dirs
 }
@@ -99,16 +122,26 @@ func (t SpoolDirs) Download(uid uuid.UUID, name string, content io.Reader) (err
 	return nil
 }
 
+// Enqueue the downloaded workload. the time the workload is first queued is recorded, it determines
+// how long the workload has waited when scheduling.
 func (t SpoolDirs) Enqueue(uid uuid.UUID) (err error) {
-	return os.Rename(filepath.Join(t.Downloading, Queued().Dirname(uid)), filepath.Join(t.Queued, Queued().Dirname(uid)))
+	src := filepath.Join(t.Downloading, Queued().Dirname(uid))
+	if _, err = os.Stat(filepath.Join(src, enqueuedname)); fsx.ErrIsNotExist(err) != nil {
+		if err = enqueued(src, time.Now()); err != nil {
+			return errorsx.Wrap(err, "unable to record enqueued timestamp")
+		}
+	}
+
+	return os.Rename(src, filepath.Join(t.Queued, Queued().Dirname(uid)))
 }
 
 func (t SpoolDirs) Dequeue() (_ string, err error) {
 	for range 100 {
-		dir, err := peek(t.Queued, t.poplimit)
+		next, err := t.schedule()
 		if err != nil {
 			return "", err
 		}
+		dir := next.entry
 
 		if err = t.dequeueRename(dir); fsx.ErrIsNotExist(err) != nil {
 			continue
@@ -126,6 +159,12 @@ func (t SpoolDirs) Dequeue() (_ string, err error) {
 	return "", errorsx.Wrap(err, "exhausted dequeue attempts, try later")
 }
 
+// Dispatched charges the account's fair share once its dequeued workload is committed to execute.
+// workloads that are deferred after being dequeued, i.e. parked by CacheResolution.Claim, are not charged.
+func (t SpoolDirs) Dispatched(account string) {
+	t.shares.charge(account, time.Now())
+}
+
 func (t SpoolDirs) dequeueRename(dir fs.DirEntry) error {
 	t.renamemux.Lock()
 	defer t.renamemux.Unlock()
@@ -133,6 +172,13 @@ func (t SpoolDirs) dequeueRename(dir fs.DirEntry) error {
 }
 
 func (t SpoolDirs) Completed(uid uuid.UUID) (err error) {
+	// retain the workload, minus its workspace, allowing it to be inspected and requeued.
+	if err = t.archive(filepath.Join(t.Running, Queued().Dirname(uid))); err == nil {
+		return nil
+	} else {
+		log.Println(err)
+	}
+
 	// we tombstone before removing. we do this because if there are permission issues within
 	// the directory structure deleting as this user running the command will not work and we need to let the OS handle it.
 	// We can fail to remove a folder due to a permission issue in files and subfolders. optimistically attempt to remove the
@@ -155,6 +201,151 @@ func (t SpoolDirs) Completed(uid uuid.UUID) (err error) {
 	)
 }
 
+// Requeue an archived workload. archived workloads are stripped of their environment and access token,
+// the requeued workload is executed without them, i.e. private repositories are inaccessible.
+func (t SpoolDirs) Requeue(uid uuid.UUID) (err error) {
+	t.renamemux.Lock()
+	defer t.renamemux.Unlock()
+
+	if fsx.DirExists(filepath.Join(t.Running, Queued().Dirname(uid))) == nil || fsx.DirExists(filepath.Join(t.Queued, Queued().Dirname(uid))) == nil {
+		return errorsx.Wrapf(os.ErrExist, "workload is already pending: %s", uid)
+	}
+
+	// requeued workloads wait from the time they're requeued.
+	if err = enqueued(filepath.Join(t.Archived, Queued().Dirname(uid)), time.Now()); fsx.ErrIsNotExist(err) != nil {
+		return errorsx.Wrapf(err, "unable to requeue workload: %s", uid)
+	} else if err != nil {
+		return errorsx.Wrapf(err, "unable to record enqueued timestamp: %s", uid)
+	}
+
+	return errorsx.Wrapf(
+		os.Rename(filepath.Join(t.Archived, Queued().Dirname(uid)), filepath.Join(t.Queued, Queued().Dirname(uid))),
+		"unable to requeue workload: %s",
+		uid,
+	)
+}
+
+func (t SpoolDirs) archive(dir string) (err error) {
+	dst := filepath.Join(t.Archived, filepath.Base(dir))
+
+	if err = os.RemoveAll(filepath.Join(dir, workdirname)); err != nil {
+		return errorsx.Wrap(err, "unable to remove workspace")
+	}
+
+	if err = strip(dir); err != nil {
+		return errorsx.Wrap(err, "unable to strip workload credentials")
+	}
+
+	// workloads can be executed multiple times, only the latest execution is retained.
+	if err = os.RemoveAll(dst); err != nil {
+		return errorsx.Wrap(err, "unable to remove previous archive")
+	}
+
+	if err = os.Rename(dir, dst); err != nil {
+		return errorsx.Wrap(err, "unable to archive workload")
+	}
+
+	// the modification time orders the archived workloads.
+	ts := time.Now()
+	errorsx.Log(errorsx.Wrap(os.Chtimes(dst, ts, ts), "unable to update archived workload timestamp"))
+	errorsx.Log(errorsx.Wrap(t.prune(), "unable to prune archived workloads"))
+
+	return nil
+}
+
+// strip the credentials from the workload prior to archiving. the access token is removed from
+// the metadata and the environment, which holds the resolved secrets of the workload, is removed
+// from the workload directory and its archive. requeued workloads are executed without either.
+// metadata and archives that can't be decoded are removed since they can't be stripped.
+func strip(dir string) (err error) {
+	var (
+		encoded  []byte
+		workload EnqueuedDequeueResponse
+		path     = filepath.Join(dir, "metadata.json")
+	)
+
+	if err = errorsx.Ignore(os.Remove(filepath.Join(dir, eg.EnvironFile)), fs.ErrNotExist); err != nil {
+		return err
+	}
+
+	if err = stripenviron(filepath.Join(dir, "archive.tar.gz")); err != nil {
+		return err
+	}
+
+	if encoded, err = os.ReadFile(path); fsx.ErrIsNotExist(err) != nil {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(encoded, &workload); err != nil {
+		return os.Remove(path)
+	}
+
+	workload.AccessToken = ""
+
+	if encoded, err = json.Marshal(&workload); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, encoded, 0600)
+}
+
+// stripenviron rewrites the archive omitting the environment files.
+func stripenviron(path string) (err error) {
+	src, err := os.Open(path)
+	if fsx.ErrIsNotExist(err) != nil {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.CreateTemp(filepath.Dir(path), "archive.*.tar.gz")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(dst.Name())
+	defer dst.Close()
+
+	if err = tarx.Filter(dst, src, func(h *tar.Header) bool { return filepath.Base(h.Name) != eg.EnvironFile }); err != nil {
+		log.Println(errorsx.Wrapf(err, "unable to strip environment from archive, removing it: %s", path))
+		return os.Remove(path)
+	}
+
+	return os.Rename(dst.Name(), path)
+}
+
+// prune the oldest archived workloads beyond the limit.
+func (t SpoolDirs) prune() (err error) {
+	entries, err := os.ReadDir(t.Archived)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) <= t.archivelimit {
+		return nil
+	}
+
+	modified := func(e fs.DirEntry) time.Time {
+		info, err := e.Info()
+		if err != nil {
+			return time.Time{}
+		}
+		return info.ModTime()
+	}
+
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return modified(a).Compare(modified(b))
+	})
+
+	for _, e := range entries[:len(entries)-t.archivelimit] {
+		err = errorsx.Compact(err, t.Discard(filepath.Join(t.Archived, e.Name())))
+	}
+
+	return err
+}
+
 func (t SpoolDirs) Discard(dir string) (err error) {
 	// we tombstone before removing. we do this because if there are permission issues within
 	// the directory structure deleting as this user running the command will not work and we need to let the OS handle it.
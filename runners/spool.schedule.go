@@ -0,0 +1,229 @@
+package runners
+
+import (
+	"cmp"
+	"io"
+	"io/fs"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// priority classes are assigned to workloads using their labels, workloads without
+// a priority label are scheduled as normal priority.
+const (
+	PriorityLabelHigh = "priority:high"
+	PriorityLabelLow  = "priority:low"
+)
+
+const (
+	PriorityLow = iota
+	PriorityNormal
+	PriorityHigh
+)
+
+// file within the workload directory recording when it was queued.
+const enqueuedname = "enqueued"
+
+// Priority class of the workload labels, the highest class wins when multiple are present.
+func Priority(labels ...string) (p int) {
+	p = PriorityNormal
+	for _, l := range labels {
+		switch strings.ToLower(strings.TrimSpace(l)) {
+		case PriorityLabelHigh:
+			return PriorityHigh
+		case PriorityLabelLow:
+			p = min(p, PriorityLow)
+		}
+	}
+
+	return p
+}
+
+// scheduled workload within the queued directory.
+type scheduled struct {
+	entry    fs.DirEntry
+	priority int
+	waited   time.Duration
+	usage    float64
+}
+
+// schedule selects the next workload to execute from the queued directory.
+// workloads are ordered by their priority class, promoted one class for every
+// aging interval they have waited, allowing deferred workloads to eventually run.
+// workloads within the same class are ordered by the usage of their account,
+// i.e. accounts with fewer running and recently dispatched workloads go first,
+// and finally by how long they have waited. the entire queue is considered,
+// reading poplimit entries at a time.
+func (t SpoolDirs) schedule() (_ scheduled, err error) {
+	dirfs, err := os.Open(t.Queued)
+	if err != nil {
+		return scheduled{}, err
+	}
+	defer dirfs.Close()
+
+	var (
+		now     = time.Now()
+		running = t.accounts(t.Running)
+		usage   = t.shares.snapshot(now)
+		found   = false
+		next    scheduled
+	)
+
+	for {
+		entries, err := dirfs.ReadDir(max(t.poplimit, 1))
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return scheduled{}, err
+		}
+
+		for _, e := range entries {
+			ts, err := enqueuedat(filepath.Join(t.Queued, e.Name()))
+			if err != nil {
+				// the workload was dequeued concurrently.
+				continue
+			}
+
+			enq := spooledmetadata(filepath.Join(t.Queued, e.Name()))
+			waited := max(now.Sub(ts), 0)
+			candidate := scheduled{
+				entry:    e,
+				priority: Priority(enq.GetLabels()...) + aged(waited, t.aging),
+				waited:   waited,
+				usage:    float64(running[enq.GetAccountId()]) + usage[enq.GetAccountId()],
+			}
+
+			if !found || precedes(candidate, next) {
+				found, next = true, candidate
+			}
+		}
+	}
+
+	// io.EOF when the queue is empty.
+	if !found {
+		return scheduled{}, io.EOF
+	}
+
+	return next, nil
+}
+
+// enqueued records when the workload was queued, the timestamp is retained as the workload
+// moves between the spool directories and is unaffected by modifications to the workload.
+func enqueued(dir string, ts time.Time) error {
+	return os.WriteFile(filepath.Join(dir, enqueuedname), []byte(ts.Format(time.RFC3339Nano)), 0600)
+}
+
+// enqueuedat the time the workload was queued. workloads queued prior to the timestamp being
+// recorded fallback to the modification time of their directory.
+func enqueuedat(dir string) (time.Time, error) {
+	encoded, err := os.ReadFile(filepath.Join(dir, enqueuedname))
+	if err == nil {
+		if ts, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(string(encoded))); err == nil {
+			return ts, nil
+		}
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return info.ModTime(), nil
+}
+
+// precedes reports if workload a should be scheduled before workload b.
+func precedes(a, b scheduled) bool {
+	return cmp.Or(
+		cmp.Compare(b.priority, a.priority),
+		cmp.Compare(a.usage, b.usage),
+		cmp.Compare(b.waited, a.waited),
+	) < 0
+}
+
+// number of priority classes the workload has been promoted by.
+func aged(waited time.Duration, interval time.Duration) int {
+	if interval <= 0 {
+		return 0
+	}
+
+	return int(waited / interval)
+}
+
+// accounts counts the workloads within the directory by account.
+func (t SpoolDirs) accounts(dir string) map[string]int {
+	counts := make(map[string]int)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return counts
+	}
+
+	for _, e := range entries {
+		counts[spooledmetadata(filepath.Join(dir, e.Name())).GetAccountId()]++
+	}
+
+	return counts
+}
+
+func newfairshare(halflife time.Duration) *fairshare {
+	return &fairshare{
+		halflife: halflife,
+		usage:    make(map[string]float64),
+	}
+}
+
+// fairshare tracks the workloads recently dispatched per account, the usage
+// decays exponentially allowing accounts to recover their share over time.
+type fairshare struct {
+	m        sync.Mutex
+	halflife time.Duration
+	updated  time.Time
+	usage    map[string]float64
+}
+
+// decay the usage up to the provided time, assumes the lock is held.
+func (t *fairshare) decay(now time.Time) {
+	if t.updated.IsZero() || t.halflife <= 0 {
+		t.updated = now
+		return
+	}
+
+	factor := math.Pow(0.5, float64(now.Sub(t.updated))/float64(t.halflife))
+	for account, u := range t.usage {
+		if u *= factor; u < 0.01 {
+			delete(t.usage, account)
+			continue
+		}
+
+		t.usage[account] = u
+	}
+
+	t.updated = now
+}
+
+func (t *fairshare) charge(account string, now time.Time) {
+	if t == nil {
+		return
+	}
+
+	t.m.Lock()
+	defer t.m.Unlock()
+	t.decay(now)
+	t.usage[account]++
+}
+
+func (t *fairshare) snapshot(now time.Time) map[string]float64 {
+	if t == nil {
+		return nil
+	}
+
+	t.m.Lock()
+	defer t.m.Unlock()
+	t.decay(now)
+
+	return maps.Clone(t.usage)
+}
@@ -0,0 +1,101 @@
+package runners
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/egdaemon/eg/internal/errorsx"
+)
+
+// Workload spooled by the runner.
+type Workload struct {
+	ID       string    `json:"id"`
+	Modified time.Time `json:"modified"`
+	Enqueued *Enqueued `json:"enqueued,omitempty"` // missing when the workload's metadata is unavailable.
+}
+
+// QueueStatus describes the workloads of the runner and the resources they've reserved.
+type QueueStatus struct {
+	Queued   []Workload       `json:"queued"`
+	Running  []Workload       `json:"running"`
+	Archived []Workload       `json:"archived"`
+	Limit    RuntimeResources `json:"limit"`
+	Reserved RuntimeResources `json:"reserved"`
+}
+
+func NewQueueStatus(dirs SpoolDirs, rm *ResourceManager) (s QueueStatus, err error) {
+	if s.Queued, err = dirs.Workloads(dirs.Queued); err != nil {
+		return s, err
+	}
+
+	if s.Running, err = dirs.Workloads(dirs.Running); err != nil {
+		return s, err
+	}
+
+	if s.Archived, err = dirs.Workloads(dirs.Archived); err != nil {
+		return s, err
+	}
+
+	s.Limit = rm.Limit
+	s.Reserved = rm.Snapshot()
+
+	return s, nil
+}
+
+// Workloads within the spool directory ordered by their modification time, oldest first.
+func (t SpoolDirs) Workloads(dir string) (workloads []Workload, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errorsx.Wrapf(err, "unable to read spool directory: %s", dir)
+	}
+
+	workloads = make([]Workload, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+
+		info, err := e.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			// the workload moved between spool directories.
+			continue
+		} else if err != nil {
+			return nil, errorsx.Wrapf(err, "unable to stat workload: %s", e.Name())
+		}
+
+		workloads = append(workloads, Workload{
+			ID:       Queued().Id(e.Name()).String(),
+			Modified: info.ModTime(),
+			Enqueued: spooledmetadata(filepath.Join(dir, e.Name())),
+		})
+	}
+
+	slices.SortFunc(workloads, func(a, b Workload) int {
+		return a.Modified.Compare(b.Modified)
+	})
+
+	return workloads, nil
+}
+
+// only the enqueued metadata is exposed, the spooled metadata contains credentials.
+func spooledmetadata(dir string) *Enqueued {
+	var (
+		workload EnqueuedDequeueResponse
+	)
+
+	encoded, err := os.ReadFile(filepath.Join(dir, "metadata.json"))
+	if err != nil {
+		return nil
+	}
+
+	if err = json.Unmarshal(encoded, &workload); err != nil {
+		return nil
+	}
+
+	return workload.Enqueued
+}
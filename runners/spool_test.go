@@ -2,10 +2,17 @@ package runners
 
 import (
 	"bytes"
+	"encoding/json"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/egdaemon/eg"
+	"github.com/egdaemon/eg/internal/tarx"
+	"github.com/egdaemon/eg/internal/testx"
 	"github.com/gofrs/uuid/v5"
 	"github.com/stretchr/testify/require"
 )
@@ -33,3 +40,273 @@ func TestSpoolDequeue(t *testing.T) {
 		require.Equal(t, "", ruid)
 	})
 }
+
+// archive of the files, paths are relative to the archive root.
+func packed(t *testing.T, files map[string]string) *bytes.Buffer {
+	var (
+		buf  bytes.Buffer
+		root = t.TempDir()
+	)
+
+	for path, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, filepath.Dir(path)), 0700))
+		require.NoError(t, os.WriteFile(filepath.Join(root, path), []byte(content), 0600))
+	}
+
+	require.NoError(t, tarx.Pack(&buf, root))
+	return &buf
+}
+
+func TestSpoolRequeue(t *testing.T) {
+	t.Run("completed workloads are archived without their workspace", func(t *testing.T) {
+		dirs := NewSpoolDir(t.TempDir())
+
+		uid := uuid.Must(uuid.NewV7())
+		require.NoError(t, dirs.Download(uid, "archive.tar.gz", packed(t, map[string]string{"main.wasm": "module"})))
+		require.NoError(t, dirs.Enqueue(uid))
+
+		running, err := dirs.Dequeue()
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(filepath.Join(running, workdirname), 0700))
+		require.NoError(t, dirs.Completed(uid))
+
+		require.NoDirExists(t, running)
+		require.FileExists(t, filepath.Join(dirs.Archived, Queued().Dirname(uid), "archive.tar.gz"))
+		require.NoDirExists(t, filepath.Join(dirs.Archived, Queued().Dirname(uid), workdirname))
+
+		workloads, err := dirs.Workloads(dirs.Archived)
+		require.NoError(t, err)
+		require.Len(t, workloads, 1)
+		require.Equal(t, uid.String(), workloads[0].ID)
+		require.Nil(t, workloads[0].Enqueued)
+	})
+
+	t.Run("completed workloads are archived without their credentials", func(t *testing.T) {
+		dirs := NewSpoolDir(t.TempDir())
+
+		uid := uuid.Must(uuid.NewV7())
+		encoded, err := json.Marshal(&EnqueuedDequeueResponse{Enqueued: &Enqueued{Id: uid.String(), AccountId: "account"}, AccessToken: "secret"})
+		require.NoError(t, err)
+		require.NoError(t, dirs.Download(uid, "metadata.json", bytes.NewReader(encoded)))
+		require.NoError(t, dirs.Enqueue(uid))
+
+		_, err = dirs.Dequeue()
+		require.NoError(t, err)
+		require.NoError(t, dirs.Completed(uid))
+
+		archived, err := os.ReadFile(filepath.Join(dirs.Archived, Queued().Dirname(uid), "metadata.json"))
+		require.NoError(t, err)
+		require.NotContains(t, string(archived), "secret")
+
+		var workload EnqueuedDequeueResponse
+		require.NoError(t, json.Unmarshal(archived, &workload))
+		require.Equal(t, uid.String(), workload.Enqueued.Id)
+		require.Equal(t, "account", workload.Enqueued.AccountId)
+	})
+
+	t.Run("completed workloads are archived without their secrets", func(t *testing.T) {
+		dirs := NewSpoolDir(t.TempDir())
+
+		uid := uuid.Must(uuid.NewV7())
+		environ := "EG_SECRET=hunter2\n"
+		require.NoError(t, dirs.Download(uid, eg.EnvironFile, bytes.NewBufferString(environ)))
+		require.NoError(t, dirs.Download(uid, "archive.tar.gz", packed(t, map[string]string{
+			"main.wasm":                             "module",
+			eg.EnvironFile:                          environ,
+			filepath.Join("nested", eg.EnvironFile): environ,
+		})))
+		require.NoError(t, dirs.Enqueue(uid))
+
+		_, err := dirs.Dequeue()
+		require.NoError(t, err)
+		require.NoError(t, dirs.Completed(uid))
+
+		archived := filepath.Join(dirs.Archived, Queued().Dirname(uid))
+		require.NoFileExists(t, filepath.Join(archived, eg.EnvironFile))
+
+		unpacked := t.TempDir()
+		archive, err := os.Open(filepath.Join(archived, "archive.tar.gz"))
+		require.NoError(t, err)
+		defer archive.Close()
+		require.NoError(t, tarx.Unpack(unpacked, archive))
+		require.FileExists(t, filepath.Join(unpacked, "main.wasm"))
+
+		require.NoError(t, filepath.WalkDir(archived, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			require.NotContains(t, testx.ReadString(path), "hunter2", path)
+			return nil
+		}))
+		require.NoError(t, filepath.WalkDir(unpacked, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			require.NotContains(t, testx.ReadString(path), "hunter2", path)
+			return nil
+		}))
+	})
+
+	t.Run("archived workloads can be requeued once", func(t *testing.T) {
+		dirs := NewSpoolDir(t.TempDir())
+
+		uid := uuid.Must(uuid.NewV7())
+		require.NoError(t, dirs.Download(uid, "archive.tar.gz", bytes.NewBufferString("")))
+		require.NoError(t, dirs.Enqueue(uid))
+
+		_, err := dirs.Dequeue()
+		require.NoError(t, err)
+		require.NoError(t, dirs.Completed(uid))
+
+		require.NoError(t, dirs.Requeue(uid))
+		require.DirExists(t, filepath.Join(dirs.Queued, Queued().Dirname(uid)))
+		require.ErrorIs(t, dirs.Requeue(uid), os.ErrExist)
+	})
+
+	t.Run("unknown workloads", func(t *testing.T) {
+		dirs := NewSpoolDir(t.TempDir())
+		require.ErrorIs(t, dirs.Requeue(uuid.Must(uuid.NewV7())), os.ErrNotExist)
+	})
+
+	t.Run("archive is pruned to the limit", func(t *testing.T) {
+		dirs := NewSpoolDir(t.TempDir())
+		dirs.archivelimit = 2
+
+		for range 3 {
+			uid := uuid.Must(uuid.NewV7())
+			require.NoError(t, dirs.Download(uid, "archive.tar.gz", bytes.NewBufferString("")))
+			require.NoError(t, dirs.Enqueue(uid))
+			_, err := dirs.Dequeue()
+			require.NoError(t, err)
+			require.NoError(t, dirs.Completed(uid))
+		}
+
+		entries, err := os.ReadDir(dirs.Archived)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+	})
+}
+
+func TestSpoolSchedule(t *testing.T) {
+	enqueue := func(t *testing.T, dirs SpoolDirs, enq *Enqueued, ts time.Time) uuid.UUID {
+		uid := uuid.Must(uuid.NewV7())
+		enq.Id = uid.String()
+		encoded, err := json.Marshal(EnqueuedDequeueResponse{Enqueued: enq})
+		require.NoError(t, err)
+		require.NoError(t, dirs.Download(uid, "metadata.json", bytes.NewReader(encoded)))
+		require.NoError(t, enqueued(filepath.Join(dirs.Downloading, Queued().Dirname(uid)), ts))
+		require.NoError(t, dirs.Enqueue(uid))
+		return uid
+	}
+
+	dequeued := func(t *testing.T, dirs SpoolDirs) uuid.UUID {
+		dir, err := dirs.Dequeue()
+		require.NoError(t, err)
+		return Queued().Id(dir)
+	}
+
+	t.Run("higher priority classes are dequeued first", func(t *testing.T) {
+		dirs := NewSpoolDir(t.TempDir())
+		now := time.Now()
+
+		low := enqueue(t, dirs, &Enqueued{Labels: []string{PriorityLabelLow}}, now.Add(-time.Minute))
+		normal := enqueue(t, dirs, &Enqueued{}, now.Add(-time.Minute))
+		high := enqueue(t, dirs, &Enqueued{Labels: []string{PriorityLabelHigh}}, now)
+
+		require.Equal(t, high, dequeued(t, dirs))
+		require.Equal(t, normal, dequeued(t, dirs))
+		require.Equal(t, low, dequeued(t, dirs))
+
+		_, err := dirs.Dequeue()
+		require.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("waiting workloads are promoted", func(t *testing.T) {
+		dirs := NewSpoolDir(t.TempDir(), SpoolOptionAging(time.Minute))
+		now := time.Now()
+
+		high := enqueue(t, dirs, &Enqueued{Labels: []string{PriorityLabelHigh}}, now)
+		low := enqueue(t, dirs, &Enqueued{Labels: []string{PriorityLabelLow}}, now.Add(-3*time.Minute))
+
+		require.Equal(t, low, dequeued(t, dirs))
+		require.Equal(t, high, dequeued(t, dirs))
+	})
+
+	t.Run("modifying a workload does not reset its wait", func(t *testing.T) {
+		dirs := NewSpoolDir(t.TempDir(), SpoolOptionAging(time.Minute))
+		now := time.Now()
+
+		high := enqueue(t, dirs, &Enqueued{Labels: []string{PriorityLabelHigh}}, now)
+		low := enqueue(t, dirs, &Enqueued{Labels: []string{PriorityLabelLow}}, now.Add(-3*time.Minute))
+		require.NoError(t, os.Chtimes(filepath.Join(dirs.Queued, Queued().Dirname(low)), now, now))
+
+		require.Equal(t, low, dequeued(t, dirs))
+		require.Equal(t, high, dequeued(t, dirs))
+	})
+
+	t.Run("requeued workloads wait from when they're requeued", func(t *testing.T) {
+		dirs := NewSpoolDir(t.TempDir(), SpoolOptionAging(time.Minute))
+		now := time.Now()
+
+		low := enqueue(t, dirs, &Enqueued{Labels: []string{PriorityLabelLow}}, now.Add(-3*time.Minute))
+		require.Equal(t, low, dequeued(t, dirs))
+		require.NoError(t, dirs.Completed(low))
+		require.NoError(t, dirs.Requeue(low))
+
+		normal := enqueue(t, dirs, &Enqueued{}, now)
+		require.Equal(t, normal, dequeued(t, dirs))
+		require.Equal(t, low, dequeued(t, dirs))
+	})
+
+	t.Run("accounts share the runner", func(t *testing.T) {
+		dirs := NewSpoolDir(t.TempDir())
+		now := time.Now()
+
+		noisy1 := enqueue(t, dirs, &Enqueued{AccountId: "noisy"}, now.Add(-3*time.Second))
+		noisy2 := enqueue(t, dirs, &Enqueued{AccountId: "noisy"}, now.Add(-2*time.Second))
+		quiet := enqueue(t, dirs, &Enqueued{AccountId: "quiet"}, now.Add(-time.Second))
+
+		require.Equal(t, noisy1, dequeued(t, dirs))
+		require.Equal(t, quiet, dequeued(t, dirs))
+		require.Equal(t, noisy2, dequeued(t, dirs))
+	})
+
+	t.Run("only dispatched workloads are charged", func(t *testing.T) {
+		dirs := NewSpoolDir(t.TempDir())
+		now := time.Now()
+
+		noisy1 := enqueue(t, dirs, &Enqueued{AccountId: "noisy"}, now.Add(-4*time.Second))
+		noisy2 := enqueue(t, dirs, &Enqueued{AccountId: "noisy"}, now.Add(-3*time.Second))
+		noisy3 := enqueue(t, dirs, &Enqueued{AccountId: "noisy"}, now.Add(-2*time.Second))
+		quiet := enqueue(t, dirs, &Enqueued{AccountId: "quiet"}, now.Add(-time.Second))
+
+		// workloads that leave the running directory without being dispatched are not charged.
+		require.Equal(t, noisy1, dequeued(t, dirs))
+		require.NoError(t, dirs.Discard(filepath.Join(dirs.Running, Queued().Dirname(noisy1))))
+		require.Equal(t, noisy2, dequeued(t, dirs))
+		require.NoError(t, dirs.Discard(filepath.Join(dirs.Running, Queued().Dirname(noisy2))))
+
+		dirs.Dispatched("noisy")
+		require.Equal(t, quiet, dequeued(t, dirs))
+		require.Equal(t, noisy3, dequeued(t, dirs))
+	})
+
+	t.Run("the entire queue is considered", func(t *testing.T) {
+		dirs := NewSpoolDir(t.TempDir(), SpoolOptionPopLimit(1))
+		now := time.Now()
+
+		for i := range 8 {
+			enqueue(t, dirs, &Enqueued{Labels: []string{PriorityLabelLow}}, now.Add(-time.Duration(i)*time.Second))
+		}
+		high := enqueue(t, dirs, &Enqueued{Labels: []string{PriorityLabelHigh}}, now)
+
+		require.Equal(t, high, dequeued(t, dirs))
+	})
+}
+
+func TestPriority(t *testing.T) {
+	require.Equal(t, PriorityNormal, Priority())
+	require.Equal(t, PriorityNormal, Priority("gpu"))
+	require.Equal(t, PriorityLow, Priority("gpu", PriorityLabelLow))
+	require.Equal(t, PriorityHigh, Priority(PriorityLabelLow, "Priority:High"))
+}
@@ -0,0 +1,371 @@
+package eg
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/md5x"
+	"github.com/egdaemon/eg/internal/tarx"
+	"github.com/egdaemon/eg/runtime/wasi/egenv"
+	"github.com/egdaemon/eg/runtime/wasi/egunsafe/ffigraph"
+)
+
+type cached struct {
+	analysing bool
+	root      string
+	cache     string
+	inputs    []string
+	environ   []string
+	outputs   []string
+	digests   []func(hash.Hash) error
+}
+
+type CacheOption func(*cached)
+
+// files, relative to the working directory, whose contents are included in the cache key.
+// patterns without a slash match the name of a file or directory at any depth, i.e. "*.go"
+// matches every go file within the tree. patterns containing a slash are matched against the
+// path relative to the working directory segment by segment using path.Match, where a "**"
+// segment matches any number of directories, i.e. "cmd/**/*.go". a leading slash anchors a
+// pattern without any other slash to the working directory, i.e. "/go.mod". a pattern matching
+// a directory includes every file within it.
+func CacheInputs(patterns ...string) CacheOption {
+	return func(c *cached) {
+		c.inputs = append(c.inputs, patterns...)
+	}
+}
+
+// environment variables whose values are included in the cache key.
+func CacheEnviron(keys ...string) CacheOption {
+	return func(c *cached) {
+		c.environ = append(c.environ, keys...)
+	}
+}
+
+// include the container definition in the cache key.
+func CacheContainer(r ContainerRunner) CacheOption {
+	return func(c *cached) {
+		c.digests = append(c.digests, func(h hash.Hash) error {
+			fmt.Fprintln(h, r.name, r.pull, strings.Join(r.cmd, " "))
+			for _, o := range r.options {
+				fmt.Fprintln(h, strings.Join(o, " "))
+			}
+
+			if r.definition == "" {
+				return nil
+			}
+
+			return digestfile(h, r.definition)
+		})
+	}
+}
+
+// directories, relative to the working directory, snapshotted after a successful
+// execution and restored on a cache hit.
+func CacheOutputs(dirs ...string) CacheOption {
+	return func(c *cached) {
+		c.outputs = append(c.outputs, dirs...)
+	}
+}
+
+// override the root directory inputs and outputs are resolved against. defaults to the working directory.
+func CacheRoot(dir string) CacheOption {
+	return func(c *cached) {
+		c.root = dir
+	}
+}
+
+// override the directory markers and snapshots are stored within. defaults to the eg cache directory.
+func CacheDirectory(dir string) CacheOption {
+	return func(c *cached) {
+		c.cache = dir
+	}
+}
+
+func digestfile(h hash.Hash, p string) error {
+	src, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	_, err = io.Copy(h, src)
+	return err
+}
+
+// pattern of cached inputs split into its path segments.
+type pattern struct {
+	segments []string
+	name     bool // match the name of a file or directory at any depth.
+}
+
+func newpattern(p string) pattern {
+	p = path.Clean(filepath.ToSlash(p))
+	if !strings.Contains(p, "/") {
+		return pattern{segments: []string{p}, name: true}
+	}
+
+	return pattern{segments: strings.Split(strings.TrimPrefix(p, "/"), "/")}
+}
+
+// matches the path or any of its parent directories.
+func (t pattern) matches(segments []string) bool {
+	if t.name {
+		return slices.ContainsFunc(segments, func(s string) bool {
+			ok, _ := path.Match(t.segments[0], s)
+			return ok
+		})
+	}
+
+	for idx := range segments {
+		if segmatch(t.segments, segments[:idx+1]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// descend reports if files within the directory can match the pattern.
+func (t pattern) descend(segments []string) bool {
+	return t.name || segprefix(t.segments, segments)
+}
+
+// segmatch reports if the pattern matches every segment of the path.
+func segmatch(pattern []string, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+
+	if pattern[0] == "**" {
+		for idx := range len(segments) + 1 {
+			if segmatch(pattern[1:], segments[idx:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+
+	ok, _ := path.Match(pattern[0], segments[0])
+	return ok && segmatch(pattern[1:], segments[1:])
+}
+
+// segprefix reports if the segments of the directory are consumed by the pattern,
+// or the pattern matched one of its parents.
+func segprefix(pattern []string, segments []string) bool {
+	if len(pattern) == 0 || len(segments) == 0 || pattern[0] == "**" {
+		return true
+	}
+
+	ok, _ := path.Match(pattern[0], segments[0])
+	return ok && segprefix(pattern[1:], segments[1:])
+}
+
+func (t cached) patterns() (patterns []pattern) {
+	for _, p := range t.inputs {
+		patterns = append(patterns, newpattern(p))
+	}
+
+	return patterns
+}
+
+// digest computes the content address of the operation's inputs.
+func (t cached) digest(key string) (_ string, err error) {
+	h := sha256.New()
+	fmt.Fprintln(h, key)
+
+	if len(t.inputs) > 0 {
+		files := make([]string, 0, 128)
+		patterns := t.patterns()
+		err = filepath.WalkDir(t.root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			rel, err := filepath.Rel(t.root, p)
+			if err != nil {
+				return err
+			}
+
+			if rel == "." {
+				return nil
+			}
+
+			segments := strings.Split(filepath.ToSlash(rel), "/")
+
+			if d.IsDir() {
+				// skip directories none of the patterns can match within.
+				if d.Name() == ".git" || !slices.ContainsFunc(patterns, func(pt pattern) bool { return pt.descend(segments) }) {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			if !d.Type().IsRegular() {
+				return nil
+			}
+
+			if slices.ContainsFunc(patterns, func(pt pattern) bool { return pt.matches(segments) }) {
+				files = append(files, rel)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return "", errorsx.Wrap(err, "unable to resolve inputs")
+		}
+
+		slices.Sort(files)
+		for _, f := range files {
+			fmt.Fprintln(h, f)
+			if err = digestfile(h, filepath.Join(t.root, f)); err != nil {
+				return "", errorsx.Wrapf(err, "unable to digest input: %s", f)
+			}
+		}
+	}
+
+	for _, k := range t.environ {
+		fmt.Fprintf(h, "%s=%s\n", k, os.Getenv(k))
+	}
+
+	for _, fn := range t.digests {
+		if err = fn(h); err != nil {
+			return "", errorsx.Wrap(err, "unable to digest container definition")
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (t cached) snapshot(dir string) error {
+	for _, o := range t.outputs {
+		src := filepath.Join(t.root, o)
+		if _, err := os.Stat(src); os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		dst, err := os.Create(filepath.Join(dir, fmt.Sprintf("%s.tar.gz", md5x.String(o))))
+		if err != nil {
+			return err
+		}
+
+		if err = errorsx.Compact(tarx.Pack(dst, src), dst.Close()); err != nil {
+			return errorsx.Wrapf(err, "unable to snapshot output: %s", o)
+		}
+	}
+
+	return nil
+}
+
+func (t cached) restore(dir string) error {
+	for _, o := range t.outputs {
+		archive, err := os.Open(filepath.Join(dir, fmt.Sprintf("%s.tar.gz", md5x.String(o))))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		dst := filepath.Join(t.root, o)
+		err = errorsx.Compact(os.RemoveAll(dst), tarx.Unpack(dst, archive), archive.Close())
+		if err != nil {
+			return errorsx.Wrapf(err, "unable to restore output: %s", o)
+		}
+	}
+
+	return nil
+}
+
+// Cached skips the operation when it previously succeeded with identical inputs.
+// the inputs are the key along with the declared files, environment variables and container
+// definitions. on success a marker is recorded within the cache directory along with a snapshot
+// of any declared outputs which are restored on subsequent hits, hits are recorded in the graph
+// as skipped. while the module is analysed the operation is always traced and the cache is
+// neither consulted nor recorded.
+//
+//	eg.Cached(
+//		"golang.compile",
+//		eggolang.AutoCompile(),
+//		eg.CacheInputs("*.go", "go.mod", "go.sum"),
+//		eg.CacheEnviron("GOOS", "GOARCH"),
+//	)
+func Cached(key string, op OpFn, options ...CacheOption) OpFn {
+	return func(ctx context.Context, o Op) (err error) {
+		var (
+			digest string
+			c      = cached{
+				analysing: ffigraph.Analysing(),
+				root:      egenv.WorkingDirectory(),
+				cache:     egenv.CacheDirectory(".eg", "ops"),
+			}
+		)
+
+		for _, opt := range options {
+			opt(&c)
+		}
+
+		// analysing must not modify the cache or working directory, the operation is
+		// traced without consulting or recording the cache.
+		if c.analysing {
+			r := ref(op)
+			return ffigraph.TraceErr(ctx, r, traceOp(op, r))
+		}
+
+		if digest, err = c.digest(key); err != nil {
+			return errorsx.Wrapf(err, "unable to compute cache key: %s", key)
+		}
+
+		keydir := filepath.Join(c.cache, md5x.String(key))
+		marker := filepath.Join(keydir, digest)
+
+		if _, err = os.Stat(marker); err == nil {
+			if err = c.restore(marker); err == nil {
+				log.Println("cache hit, skipping operation", key, digest)
+				ffigraph.Skipped(ctx, ref(op))
+				return nil
+			}
+
+			log.Println("unable to restore cached outputs, executing operation", key, err)
+		}
+
+		r := ref(op)
+		if err = ffigraph.TraceErr(ctx, r, traceOp(op, r)); err != nil {
+			return err
+		}
+
+		// only the latest result for a key is retained.
+		if err = os.RemoveAll(keydir); err != nil {
+			return errorsx.Wrapf(err, "unable to clear previous cache entries: %s", key)
+		}
+
+		if err = os.MkdirAll(marker, 0755); err != nil {
+			return errorsx.Wrapf(err, "unable to record cache marker: %s", key)
+		}
+
+		if err = c.snapshot(marker); err != nil {
+			return errorsx.Compact(err, os.RemoveAll(keydir))
+		}
+
+		return nil
+	}
+}
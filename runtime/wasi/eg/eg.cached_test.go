@@ -0,0 +1,149 @@
+package eg_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/egdaemon/eg/internal/testx"
+	"github.com/egdaemon/eg/runtime/wasi/eg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCached(t *testing.T) {
+	setup := func(t *testing.T) (root string, cache string, counter *int, op eg.OpFn) {
+		root = t.TempDir()
+		cache = t.TempDir()
+		counter = new(int)
+		require.NoError(t, os.MkdirAll(filepath.Join(root, "src"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, "src", "main.go"), []byte("package main"), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(root, "README.md"), []byte("readme"), 0600))
+
+		op = func(ctx context.Context, o eg.Op) error {
+			*counter++
+			if err := os.MkdirAll(filepath.Join(root, "dist"), 0755); err != nil {
+				return err
+			}
+			return os.WriteFile(filepath.Join(root, "dist", "output.txt"), []byte("built"), 0600)
+		}
+
+		return root, cache, counter, op
+	}
+
+	t.Run("unchanged inputs skip the operation", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		root, cache, counter, op := setup(t)
+		cached := eg.Cached("example", op, eg.CacheRoot(root), eg.CacheDirectory(cache), eg.CacheInputs("src"))
+		require.NoError(t, eg.Perform(ctx, cached, cached))
+		require.Equal(t, 1, *counter)
+	})
+
+	t.Run("changed inputs execute the operation", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		root, cache, counter, op := setup(t)
+		cached := eg.Cached("example", op, eg.CacheRoot(root), eg.CacheDirectory(cache), eg.CacheInputs("src/*.go"))
+		require.NoError(t, eg.Perform(ctx, cached))
+		require.NoError(t, os.WriteFile(filepath.Join(root, "src", "main.go"), []byte("package main // changed"), 0600))
+		require.NoError(t, eg.Perform(ctx, cached))
+		require.Equal(t, 2, *counter)
+	})
+
+	t.Run("name patterns match nested inputs", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		root, cache, counter, op := setup(t)
+		require.NoError(t, os.MkdirAll(filepath.Join(root, "src", "pkg", "nested"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, "src", "pkg", "nested", "nested.go"), []byte("package nested"), 0600))
+		cached := eg.Cached("example", op, eg.CacheRoot(root), eg.CacheDirectory(cache), eg.CacheInputs("*.go"))
+		require.NoError(t, eg.Perform(ctx, cached))
+		require.NoError(t, os.WriteFile(filepath.Join(root, "src", "pkg", "nested", "nested.go"), []byte("package nested // changed"), 0600))
+		require.NoError(t, eg.Perform(ctx, cached))
+		require.NoError(t, os.WriteFile(filepath.Join(root, "README.md"), []byte("changed"), 0600))
+		require.NoError(t, eg.Perform(ctx, cached))
+		require.Equal(t, 2, *counter)
+	})
+
+	t.Run("recursive patterns match nested inputs", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		root, cache, counter, op := setup(t)
+		require.NoError(t, os.MkdirAll(filepath.Join(root, "src", "pkg", "nested"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, "src", "pkg", "nested", "nested.go"), []byte("package nested"), 0600))
+		require.NoError(t, os.MkdirAll(filepath.Join(root, "docs"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, "docs", "docs.go"), []byte("package docs"), 0600))
+		cached := eg.Cached("example", op, eg.CacheRoot(root), eg.CacheDirectory(cache), eg.CacheInputs("src/**/*.go"))
+		require.NoError(t, eg.Perform(ctx, cached))
+		require.NoError(t, os.WriteFile(filepath.Join(root, "docs", "docs.go"), []byte("package docs // changed"), 0600))
+		require.NoError(t, eg.Perform(ctx, cached))
+		require.Equal(t, 1, *counter)
+		require.NoError(t, os.WriteFile(filepath.Join(root, "src", "main.go"), []byte("package main // changed"), 0600))
+		require.NoError(t, eg.Perform(ctx, cached))
+		require.NoError(t, os.WriteFile(filepath.Join(root, "src", "pkg", "nested", "nested.go"), []byte("package nested // changed"), 0600))
+		require.NoError(t, eg.Perform(ctx, cached))
+		require.Equal(t, 3, *counter)
+	})
+
+	t.Run("undeclared inputs are ignored", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		root, cache, counter, op := setup(t)
+		cached := eg.Cached("example", op, eg.CacheRoot(root), eg.CacheDirectory(cache), eg.CacheInputs("src"))
+		require.NoError(t, eg.Perform(ctx, cached))
+		require.NoError(t, os.WriteFile(filepath.Join(root, "README.md"), []byte("changed"), 0600))
+		require.NoError(t, eg.Perform(ctx, cached))
+		require.Equal(t, 1, *counter)
+	})
+
+	t.Run("environment changes execute the operation", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		root, cache, counter, op := setup(t)
+		cached := eg.Cached("example", op, eg.CacheRoot(root), eg.CacheDirectory(cache), eg.CacheEnviron("EG_TEST_CACHED"))
+		t.Setenv("EG_TEST_CACHED", "a")
+		require.NoError(t, eg.Perform(ctx, cached))
+		t.Setenv("EG_TEST_CACHED", "b")
+		require.NoError(t, eg.Perform(ctx, cached))
+		require.Equal(t, 2, *counter)
+	})
+
+	t.Run("outputs are restored on a hit", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		root, cache, counter, op := setup(t)
+		cached := eg.Cached("example", op, eg.CacheRoot(root), eg.CacheDirectory(cache), eg.CacheInputs("src"), eg.CacheOutputs("dist"))
+		require.NoError(t, eg.Perform(ctx, cached))
+		require.NoError(t, os.RemoveAll(filepath.Join(root, "dist")))
+		require.NoError(t, eg.Perform(ctx, cached))
+		require.Equal(t, 1, *counter)
+
+		restored, err := os.ReadFile(filepath.Join(root, "dist", "output.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "built", string(restored))
+	})
+
+	t.Run("failures are not cached", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		root, cache, _, _ := setup(t)
+		counter := 0
+		failure := func(ctx context.Context, o eg.Op) error {
+			counter++
+			return context.DeadlineExceeded
+		}
+		cached := eg.Cached("example", failure, eg.CacheRoot(root), eg.CacheDirectory(cache))
+		require.Error(t, eg.Perform(ctx, cached))
+		require.Error(t, eg.Perform(ctx, cached))
+		require.Equal(t, 2, counter)
+	})
+}
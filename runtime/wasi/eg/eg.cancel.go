@@ -0,0 +1,70 @@
+package eg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/egdaemon/eg"
+	"github.com/egdaemon/eg/runtime/wasi/egunsafe"
+	"github.com/egdaemon/eg/runtime/wasi/egunsafe/ffigraph"
+)
+
+var (
+	// created by the runner when the workload is cancelled.
+	cancelmarker = egunsafe.RuntimeDirectory(eg.CancelFile)
+	oncancel     = struct {
+		sync.Mutex
+		operations []OpFn
+	}{}
+)
+
+// OnCancel registers operations to execute when the workload is cancelled. cancelling
+// a workload interrupts the operations in flight, the registered operations are then
+// executed in order and given a grace period to complete before the workload is terminated.
+//
+//	eg.OnCancel(shell.Op(shell.New("podman stop --all")))
+func OnCancel(operations ...OpFn) {
+	oncancel.Lock()
+	defer oncancel.Unlock()
+	oncancel.operations = append(oncancel.operations, operations...)
+}
+
+// Cancelled reports if the workload has been cancelled.
+func Cancelled() bool {
+	_, err := os.Stat(cancelmarker)
+	return err == nil
+}
+
+// interrupted ensures failures of operations interrupted by the cancellation of the
+// workload are reported as cancelled.
+func interrupted(cause error) error {
+	if cause == nil || errors.Is(cause, context.Canceled) || !Cancelled() {
+		return cause
+	}
+
+	return fmt.Errorf("%w: %w", context.Canceled, cause)
+}
+
+// cleanup executes the registered cancellation operations, each operation is
+// executed at most once regardless of failures.
+func cleanup(ctx context.Context, cause error) error {
+	if cause == nil || !Cancelled() {
+		return cause
+	}
+
+	oncancel.Lock()
+	operations := oncancel.operations
+	oncancel.operations = nil
+	oncancel.Unlock()
+
+	errs := []error{cause}
+	for _, op := range operations {
+		r := ref(op)
+		errs = append(errs, ffigraph.TraceErr(ctx, r, traceOp(op, r)))
+	}
+
+	return errors.Join(errs...)
+}
@@ -2,6 +2,7 @@ package eg
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"runtime"
@@ -60,6 +61,7 @@ func (t runtimeref) OpInfo(ts time.Time, cause error, path []string) *events.Op
 		Milliseconds: int64(time.Since(ts) / time.Millisecond),
 		Name:         name,
 		Module:       file,
+		Op:           t.ID(),
 		Path:         path,
 	}
 }
@@ -93,7 +95,7 @@ func (t transpiledref) ID() string {
 
 func traceOp(op OpFn, r Reference) func(ctx context.Context) error {
 	return func(ctx context.Context) error {
-		return op(ctx, r)
+		return interrupted(op(ctx, r))
 	}
 }
 
@@ -106,12 +108,13 @@ func UnsafeTranspiledRef(name string, o OpFn) Reference {
 	}
 }
 
-// execute the provided tasks in sequential order.
+// execute the provided tasks in sequential order. when the workload is cancelled
+// the operations registered with OnCancel are executed before returning.
 func Perform(octx context.Context, operations ...OpFn) error {
 	for _, op := range operations {
 		r := ref(op)
 		if err := ffigraph.TraceErr(octx, r, traceOp(op, r)); err != nil {
-			return err
+			return cleanup(octx, err)
 		}
 	}
 
@@ -135,20 +138,76 @@ func Sequential(operations ...OpFn) OpFn {
 
 // Run operations in parallel.
 func Parallel(operations ...OpFn) OpFn {
+	return parallel(0, false, operations...)
+}
+
+// Run operations in parallel with at most limit operations executing at a time.
+// a limit less than 1 is unbounded.
+func ParallelN(limit int, operations ...OpFn) OpFn {
+	return parallel(limit, false, operations...)
+}
+
+// Run operations in parallel with at most limit operations executing at a time.
+// the first failure cancels the context of the remaining operations, operations
+// yet to start are skipped. cancelled operations are recorded with the cancelled state.
+// a limit less than 1 is unbounded.
+func ParallelFailFast(limit int, operations ...OpFn) OpFn {
+	return parallel(limit, true, operations...)
+}
+
+// cancelled ensures errors from operations whose context was cancelled are
+// reported as a cancellation along with the reason the context was cancelled.
+func cancelled(ctx context.Context, cause error) error {
+	if cause == nil || ctx.Err() == nil {
+		return cause
+	}
+
+	if reason := context.Cause(ctx); reason != nil && !errors.Is(cause, reason) {
+		cause = fmt.Errorf("%w: %w", cause, reason)
+	}
+
+	if errors.Is(cause, context.Canceled) {
+		return cause
+	}
+
+	return fmt.Errorf("%w: %w", context.Canceled, cause)
+}
+
+func parallel(limit int, failfast bool, operations ...OpFn) OpFn {
 	return func(octx context.Context, o Op) (err error) {
 		parent := prefixedop("par", o)
 		errs := make(chan error, len(operations))
-		defer close(errs)
 
-		ffigraph.Wrap(octx, parent, func(mctx context.Context) {
+		n := limit
+		if n < 1 {
+			n = len(operations)
+		}
+		available := make(chan struct{}, n)
+
+		cctx, cancel := context.WithCancelCause(octx)
+		defer cancel(nil)
+
+		ffigraph.Wrap(cctx, parent, func(mctx context.Context) {
 			for _, o := range operations {
 				go func(iop OpFn) {
 					r := ref(iop)
+
 					select {
-					case <-octx.Done():
-						errs <- octx.Err()
-					case errs <- ffigraph.TraceErr(mctx, r, traceOp(iop, r)):
+					case available <- struct{}{}:
+						defer func() { <-available }()
+					case <-mctx.Done():
 					}
+
+					if mctx.Err() != nil {
+						errs <- ffigraph.TraceErr(mctx, r, func(ctx context.Context) error {
+							return cancelled(ctx, ctx.Err())
+						})
+						return
+					}
+
+					errs <- ffigraph.TraceErr(mctx, r, func(ctx context.Context) error {
+						return cancelled(ctx, iop(ctx, r))
+					})
 				}(o)
 			}
 		})
@@ -158,6 +217,9 @@ func Parallel(operations ...OpFn) OpFn {
 			case <-octx.Done():
 				return octx.Err()
 			case cause := <-errs:
+				if cause != nil && failfast {
+					cancel(errorsx.Wrap(cause, "sibling operation failed"))
+				}
 				err = errorsx.Compact(err, cause)
 			}
 		}
@@ -174,11 +236,12 @@ func When(b bool, o OpFn) OpFn {
 // make an operation conditional on a boolean function.
 func WhenFn(b func(ctx context.Context) bool, o OpFn) OpFn {
 	return func(ctx context.Context, i Op) error {
+		r := ref(o)
 		if !b(ctx) {
+			ffigraph.Skipped(ctx, r)
 			return nil
 		}
 
-		r := ref(o)
 		return ffigraph.TraceErr(ctx, r, traceOp(o, r))
 	}
 }
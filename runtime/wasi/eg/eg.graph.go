@@ -0,0 +1,214 @@
+package eg
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/runtime/wasi/egunsafe/ffigraph"
+)
+
+// Graph creates a builder for operations whose execution order is determined by
+// their declared dependencies instead of by nesting. an operation is started as soon
+// as every operation it depends on has completed successfully.
+//
+//	eg.Perform(
+//		ctx,
+//		eg.Graph().
+//			Node("build", build).
+//			Node("lint", lint).
+//			Node("test", test).After("build", "lint").
+//			Op,
+//	)
+func Graph() *DAG {
+	return &DAG{
+		index: make(map[string]*dagnode),
+	}
+}
+
+type dagnode struct {
+	name string
+	op   OpFn
+	deps []string
+}
+
+// DAG of operations, see Graph.
+type DAG struct {
+	limit int
+	nodes []*dagnode
+	index map[string]*dagnode
+}
+
+// DAGNode is returned when adding an operation to the graph allowing
+// its dependencies to be declared.
+type DAGNode struct {
+	*DAG
+	n *dagnode
+}
+
+// After declares the operations that must successfully complete before this
+// operation is started.
+func (t DAGNode) After(names ...string) *DAG {
+	t.n.deps = append(t.n.deps, names...)
+	return t.DAG
+}
+
+// Node adds a named operation to the graph. names must be unique, adding a node
+// with an existing name replaces the previous operation.
+func (t *DAG) Node(name string, op OpFn) DAGNode {
+	if n, ok := t.index[name]; ok {
+		n.op = op
+		return DAGNode{DAG: t, n: n}
+	}
+
+	n := &dagnode{name: name, op: op}
+	t.nodes = append(t.nodes, n)
+	t.index[name] = n
+	return DAGNode{DAG: t, n: n}
+}
+
+// Concurrency limits the number of operations executing at a time.
+// values less than 1 allow unbounded concurrency. default is unbounded.
+func (t *DAG) Concurrency(n int) *DAG {
+	t.limit = n
+	return t
+}
+
+// validate the graph ensuring every dependency exists and that there are no cycles.
+// returns the nodes in a valid execution order.
+func (t *DAG) validate() (order []*dagnode, err error) {
+	indegree := make(map[string]int, len(t.nodes))
+	dependents := make(map[string][]*dagnode, len(t.nodes))
+
+	for _, n := range t.nodes {
+		for _, d := range n.deps {
+			if _, ok := t.index[d]; !ok {
+				return nil, fmt.Errorf("operation %s depends on unknown operation %s", n.name, d)
+			}
+
+			indegree[n.name]++
+			dependents[d] = append(dependents[d], n)
+		}
+	}
+
+	queue := make([]*dagnode, 0, len(t.nodes))
+	for _, n := range t.nodes {
+		if indegree[n.name] == 0 {
+			queue = append(queue, n)
+		}
+	}
+
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		order = append(order, n)
+		for _, c := range dependents[n.name] {
+			if indegree[c.name]--; indegree[c.name] == 0 {
+				queue = append(queue, c)
+			}
+		}
+	}
+
+	if len(order) != len(t.nodes) {
+		cycle := make([]string, 0, len(t.nodes)-len(order))
+		for _, n := range t.nodes {
+			if indegree[n.name] > 0 {
+				cycle = append(cycle, n.name)
+			}
+		}
+		slices.Sort(cycle)
+		return nil, fmt.Errorf("dependency cycle detected between operations: %v", cycle)
+	}
+
+	return order, nil
+}
+
+// Op executes the graph, implements the OpFn signature.
+// once an operation fails no further operations are started, operations already
+// running are allowed to complete and the first error encountered is returned.
+func (t *DAG) Op(octx context.Context, o Op) error {
+	if _, err := t.validate(); err != nil {
+		return err
+	}
+
+	parent := prefixedop("dag", o)
+	return ffigraph.TraceErr(octx, parent, func(mctx context.Context) (err error) {
+		type result struct {
+			n     *dagnode
+			cause error
+		}
+
+		var (
+			running    int
+			completed  = make(map[string]bool, len(t.nodes))
+			started    = make(map[string]bool, len(t.nodes))
+			results    = make(chan result, len(t.nodes))
+			wg         sync.WaitGroup
+			concurrent = t.limit
+		)
+
+		if concurrent < 1 {
+			concurrent = len(t.nodes)
+		}
+
+		ready := func(n *dagnode) bool {
+			if started[n.name] {
+				return false
+			}
+
+			for _, d := range n.deps {
+				if !completed[d] {
+					return false
+				}
+			}
+
+			return true
+		}
+
+		schedule := func() {
+			for _, n := range t.nodes {
+				if running >= concurrent {
+					return
+				}
+
+				if !ready(n) {
+					continue
+				}
+
+				started[n.name] = true
+				running++
+				wg.Add(1)
+				go func(n *dagnode) {
+					defer wg.Done()
+					r := ref(n.op)
+					results <- result{n: n, cause: ffigraph.TraceErr(mctx, r, traceOp(n.op, r))}
+				}(n)
+			}
+		}
+
+		defer wg.Wait()
+
+		schedule()
+		for running > 0 {
+			select {
+			case <-octx.Done():
+				return errorsx.Compact(err, octx.Err())
+			case res := <-results:
+				running--
+				if res.cause != nil {
+					err = errorsx.Compact(err, errorsx.Wrapf(res.cause, "operation %s failed", res.n.name))
+					continue
+				}
+
+				completed[res.n.name] = true
+				if err == nil {
+					schedule()
+				}
+			}
+		}
+
+		return err
+	})
+}
@@ -0,0 +1,120 @@
+package eg_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+
+	"github.com/egdaemon/eg/internal/egtest"
+	"github.com/egdaemon/eg/internal/testx"
+	"github.com/egdaemon/eg/runtime/wasi/eg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphExecution(t *testing.T) {
+	t.Run("dependencies complete before dependents", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		c := egtest.NewBuffer()
+		g := eg.Graph().
+			Node("test", c.Op('c')).After("build", "lint").
+			Node("build", c.Op('a')).
+			Node("lint", c.Op('b')).
+			Node("release", c.Op('d')).After("test")
+
+		require.NoError(t, eg.Perform(ctx, g.Op))
+		res := c.Current()
+		require.Len(t, res, 4)
+		require.ElementsMatch(t, []byte{'a', 'b'}, res[:2])
+		require.Equal(t, []byte{'c', 'd'}, res[2:])
+	})
+
+	t.Run("independent operations all execute", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		c := egtest.NewBuffer()
+		g := eg.Graph().
+			Node("a", c.Op('a')).
+			Node("b", c.Op('b')).
+			Node("c", c.Op('c'))
+
+		require.NoError(t, eg.Perform(ctx, g.Op))
+		res := c.Current()
+		slices.Sort(res)
+		require.Equal(t, []byte{'a', 'b', 'c'}, res)
+	})
+
+	t.Run("cycles are rejected", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		c := egtest.NewBuffer()
+		g := eg.Graph().
+			Node("a", c.Op('a')).After("c").
+			Node("b", c.Op('b')).After("a").
+			Node("c", c.Op('c')).After("b").
+			Node("d", c.Op('d'))
+
+		require.ErrorContains(t, eg.Perform(ctx, g.Op), "dependency cycle detected between operations: [a b c]")
+		require.Empty(t, c.Current())
+	})
+
+	t.Run("unknown dependencies are rejected", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		c := egtest.NewBuffer()
+		g := eg.Graph().Node("a", c.Op('a')).After("missing")
+		require.ErrorContains(t, eg.Perform(ctx, g.Op), "operation a depends on unknown operation missing")
+		require.Empty(t, c.Current())
+	})
+
+	t.Run("failures prevent dependents from executing", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		expected := errors.New("boom")
+		c := egtest.NewBuffer()
+		g := eg.Graph().
+			Node("a", c.Op('a')).
+			Node("b", func(ctx context.Context, o eg.Op) error { return expected }).After("a").
+			Node("c", c.Op('c')).After("b")
+
+		require.ErrorIs(t, eg.Perform(ctx, g.Op), expected)
+		require.Equal(t, []byte{'a'}, c.Current())
+	})
+
+	t.Run("concurrency is bounded", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		var (
+			active  atomic.Int32
+			highest atomic.Int32
+		)
+
+		op := func(ctx context.Context, o eg.Op) error {
+			n := active.Add(1)
+			defer active.Add(-1)
+			for {
+				h := highest.Load()
+				if n <= h || highest.CompareAndSwap(h, n) {
+					break
+				}
+			}
+			return nil
+		}
+
+		g := eg.Graph().Concurrency(2)
+		for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+			g.Node(name, op)
+		}
+
+		require.NoError(t, eg.Perform(ctx, g.Op))
+		require.LessOrEqual(t, highest.Load(), int32(2))
+	})
+}
@@ -1,6 +1,10 @@
 package eg
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -16,3 +20,136 @@ func TestContainerRunnerClone(t *testing.T) {
 		require.Equal(t, []string{"echo", "${FOO}"}, dup.cmd)
 	})
 }
+
+func TestOnCancel(t *testing.T) {
+	failure := errors.New("signal: killed")
+	failed := func(ctx context.Context, o Op) error {
+		return failure
+	}
+
+	setup := func(t *testing.T, cancelled bool) (invoked *int) {
+		invoked = new(int)
+		marker := filepath.Join(t.TempDir(), "cancelled")
+		if cancelled {
+			require.NoError(t, os.WriteFile(marker, nil, 0600))
+		}
+
+		original := cancelmarker
+		cancelmarker = marker
+		t.Cleanup(func() {
+			cancelmarker = original
+			oncancel.operations = nil
+		})
+
+		OnCancel(func(ctx context.Context, o Op) error {
+			*invoked++
+			return nil
+		})
+
+		return invoked
+	}
+
+	t.Run("cleanup operations are executed once when cancelled", func(t *testing.T) {
+		invoked := setup(t, true)
+
+		err := Perform(context.Background(), failed)
+		require.ErrorIs(t, err, failure)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, 1, *invoked)
+
+		require.Error(t, Perform(context.Background(), failed))
+		require.Equal(t, 1, *invoked)
+	})
+
+	t.Run("failures are unaffected when not cancelled", func(t *testing.T) {
+		invoked := setup(t, false)
+
+		err := Perform(context.Background(), failed)
+		require.ErrorIs(t, err, failure)
+		require.NotErrorIs(t, err, context.Canceled)
+		require.Equal(t, 0, *invoked)
+	})
+
+	t.Run("successful operations do not cleanup", func(t *testing.T) {
+		invoked := setup(t, true)
+
+		require.NoError(t, Perform(context.Background(), func(ctx context.Context, o Op) error { return nil }))
+		require.Equal(t, 0, *invoked)
+	})
+}
+
+func TestCancelled(t *testing.T) {
+	t.Run("cancelled operations report why they were cancelled", func(t *testing.T) {
+		failure := errors.New("boom")
+		ctx, cancel := context.WithCancelCause(context.Background())
+		cancel(failure)
+
+		err := cancelled(ctx, ctx.Err())
+		require.ErrorIs(t, err, context.Canceled)
+		require.ErrorIs(t, err, failure)
+	})
+
+	t.Run("failures of cancelled operations are reported as cancelled", func(t *testing.T) {
+		failure, killed := errors.New("boom"), errors.New("signal: killed")
+		ctx, cancel := context.WithCancelCause(context.Background())
+		cancel(failure)
+
+		err := cancelled(ctx, killed)
+		require.ErrorIs(t, err, context.Canceled)
+		require.ErrorIs(t, err, failure)
+		require.ErrorIs(t, err, killed)
+	})
+
+	t.Run("operations that are not cancelled are unaffected", func(t *testing.T) {
+		failure := errors.New("boom")
+		require.Equal(t, failure, cancelled(context.Background(), failure))
+		require.NoError(t, cancelled(context.Background(), nil))
+	})
+}
+
+func TestCachedAnalysing(t *testing.T) {
+	setup := func(t *testing.T) (root string, cache string, counter *int, op OpFn) {
+		root, cache, counter = t.TempDir(), t.TempDir(), new(int)
+		require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main"), 0600))
+
+		op = func(ctx context.Context, o Op) error {
+			*counter++
+			if err := os.MkdirAll(filepath.Join(root, "dist"), 0755); err != nil {
+				return err
+			}
+			return os.WriteFile(filepath.Join(root, "dist", "output.txt"), []byte("built"), 0600)
+		}
+
+		return root, cache, counter, op
+	}
+
+	analysing := func(c *cached) {
+		c.analysing = true
+	}
+
+	t.Run("planned operations execute during the next run", func(t *testing.T) {
+		root, cache, counter, op := setup(t)
+		options := []CacheOption{CacheRoot(root), CacheDirectory(cache), CacheInputs("*.go"), CacheOutputs("dist")}
+
+		require.NoError(t, Perform(context.Background(), Cached("example", op, append(options, analysing)...)))
+		entries, err := os.ReadDir(cache)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+
+		require.NoError(t, Perform(context.Background(), Cached("example", op, options...)))
+		require.Equal(t, 2, *counter)
+	})
+
+	t.Run("planning ignores cached outputs", func(t *testing.T) {
+		root, cache, counter, op := setup(t)
+		options := []CacheOption{CacheRoot(root), CacheDirectory(cache), CacheInputs("*.go"), CacheOutputs("dist")}
+
+		require.NoError(t, Perform(context.Background(), Cached("example", op, options...)))
+		require.NoError(t, os.RemoveAll(filepath.Join(root, "dist")))
+
+		noop := func(ctx context.Context, o Op) error { return nil }
+		require.NoError(t, Perform(context.Background(), Cached("example", noop, append(options, analysing)...)))
+		require.NoDirExists(t, filepath.Join(root, "dist"))
+		require.Equal(t, 1, *counter)
+	})
+}
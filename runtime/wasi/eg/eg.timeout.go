@@ -0,0 +1,43 @@
+package eg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/egdaemon/eg/runtime/wasi/egunsafe/ffigraph"
+)
+
+// Timeout bounds the duration of an operation. once exceeded the operation's context
+// is cancelled, terminating any commands, containers and modules it started, and the
+// operation is recorded as timed out.
+//
+//	eg.Timeout(10*time.Minute, eg.Module(ctx, c, ops...))
+func Timeout(d time.Duration, op OpFn) OpFn {
+	return func(ctx context.Context, o Op) error {
+		return Deadline(time.Now().Add(d), op)(ctx, o)
+	}
+}
+
+// Deadline bounds the operation to complete before the provided time, see Timeout.
+func Deadline(t time.Time, op OpFn) OpFn {
+	return func(ctx context.Context, o Op) error {
+		r := ref(op)
+		return ffigraph.TraceErr(ctx, r, func(ctx context.Context) error {
+			dctx, done := context.WithDeadline(ctx, t)
+			defer done()
+			return timedout(dctx, op(dctx, r))
+		})
+	}
+}
+
+// ensure failures caused by the deadline are reported as timeouts. operations
+// commonly return errors unrelated to the deadline when they're interrupted, i.e. a killed process.
+func timedout(ctx context.Context, cause error) error {
+	if cause == nil || !errors.Is(ctx.Err(), context.DeadlineExceeded) || errors.Is(cause, context.DeadlineExceeded) {
+		return cause
+	}
+
+	return fmt.Errorf("%w: %w", context.DeadlineExceeded, cause)
+}
@@ -0,0 +1,71 @@
+package eg_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/egdaemon/eg/internal/egtest"
+	"github.com/egdaemon/eg/internal/testx"
+	"github.com/egdaemon/eg/interp/events"
+	"github.com/egdaemon/eg/runtime/wasi/eg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeout(t *testing.T) {
+	blocking := func(ctx context.Context, o eg.Op) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	t.Run("operations completing within the timeout succeed", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		c := egtest.NewBuffer()
+		require.NoError(t, eg.Perform(ctx, eg.Timeout(time.Minute, c.Op('a'))))
+		require.Equal(t, []byte{'a'}, c.Current())
+	})
+
+	t.Run("exceeding the timeout cancels the operation", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		err := eg.Perform(ctx, eg.Timeout(10*time.Millisecond, blocking))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, events.Op_TimedOut, events.OpState(err))
+		require.NoError(t, ctx.Err())
+	})
+
+	t.Run("failures caused by the deadline are reported as timeouts", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		killed := errors.New("signal: terminated")
+		err := eg.Perform(ctx, eg.Timeout(10*time.Millisecond, func(ctx context.Context, o eg.Op) error {
+			<-ctx.Done()
+			return killed
+		}))
+		require.ErrorIs(t, err, killed)
+		require.Equal(t, events.Op_TimedOut, events.OpState(err))
+	})
+
+	t.Run("failures unrelated to the deadline are errors", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		err := eg.Perform(ctx, eg.Timeout(time.Minute, func(ctx context.Context, o eg.Op) error {
+			return errors.New("boom")
+		}))
+		require.Equal(t, events.Op_Error, events.OpState(err))
+	})
+
+	t.Run("deadlines in the past time out immediately", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		err := eg.Perform(ctx, eg.Deadline(time.Now().Add(-time.Second), blocking))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -1,11 +1,17 @@
 package eg_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"slices"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/egdaemon/eg/internal/egtest"
 	"github.com/egdaemon/eg/internal/testx"
+	"github.com/egdaemon/eg/interp/events"
 	"github.com/egdaemon/eg/runtime/wasi/eg"
 	"github.com/stretchr/testify/require"
 )
@@ -20,3 +26,72 @@ func TestParallelExecution(t *testing.T) {
 	slices.Sort(res)
 	require.Equal(t, []byte{'a', 'b', 'c', 'd'}, res)
 }
+
+func TestParallelNExecution(t *testing.T) {
+	ctx, done := testx.Context(t)
+	defer done()
+
+	var (
+		active  atomic.Int32
+		highest atomic.Int32
+	)
+
+	op := func(ctx context.Context, o eg.Op) error {
+		n := active.Add(1)
+		defer active.Add(-1)
+		for {
+			h := highest.Load()
+			if n <= h || highest.CompareAndSwap(h, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	}
+
+	require.NoError(t, eg.Perform(ctx, eg.ParallelN(2, op, op, op, op, op, op)))
+	require.Equal(t, int32(2), highest.Load())
+}
+
+func TestParallelFailFast(t *testing.T) {
+	t.Run("cancels siblings on the first failure", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		expected := errors.New("boom")
+		failure := func(ctx context.Context, o eg.Op) error {
+			return expected
+		}
+
+		var cancelled atomic.Int32
+		blocked := func(ctx context.Context, o eg.Op) error {
+			<-ctx.Done()
+			cancelled.Add(1)
+			return ctx.Err()
+		}
+
+		err := eg.Perform(ctx, eg.ParallelFailFast(0, blocked, failure, blocked))
+		require.ErrorIs(t, err, expected)
+		require.Equal(t, int32(2), cancelled.Load())
+	})
+
+	t.Run("skips operations yet to start", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		expected := errors.New("boom")
+		c := egtest.NewBuffer()
+		failure := func(ctx context.Context, o eg.Op) error {
+			return expected
+		}
+
+		require.ErrorIs(t, eg.Perform(ctx, eg.ParallelFailFast(1, failure, c.Op('a'), c.Op('b'))), expected)
+		require.LessOrEqual(t, len(c.Current()), 2)
+	})
+
+	t.Run("cancelled operations are recorded as cancelled", func(t *testing.T) {
+		require.Equal(t, events.Op_Cancelled, events.OpState(fmt.Errorf("%w: %w", context.Canceled, errors.New("killed"))))
+		require.Equal(t, events.Op_Error, events.OpState(errors.New("boom")))
+		require.Equal(t, events.Op_Completed, events.OpState(nil))
+	})
+}
@@ -9,6 +9,10 @@ import (
 	"github.com/egdaemon/eg/runtime/wasi/egunsafe"
 )
 
+// maximum number of coverage lines dispatched at once, keeps line level
+// coverage well within the message size limits of the control socket.
+const maxlines = 32 * 1024
+
 func Report(ctx context.Context, batch ...*events.Coverage) (err error) {
 	cc, err := egunsafe.DialControlSocket(ctx)
 	if err != nil {
@@ -16,8 +20,25 @@ func Report(ctx context.Context, batch ...*events.Coverage) (err error) {
 	}
 	d := events.NewEventsClient(cc)
 
-	if _, err = d.Dispatch(ctx, events.NewDispatch(slicesx.MapTransform(func(rep *events.Coverage) *events.Message { return events.NewCoverage(rep) }, batch...)...)); err != nil {
-		return errorsx.Wrap(err, "unable to report coverage")
+	dispatch := func(reps ...*events.Coverage) error {
+		if _, err = d.Dispatch(ctx, events.NewDispatch(slicesx.MapTransform(func(rep *events.Coverage) *events.Message { return events.NewCoverage(rep) }, reps...)...)); err != nil {
+			return errorsx.Wrap(err, "unable to report coverage")
+		}
+
+		return nil
+	}
+
+	offset, lines := 0, 0
+	for idx, rep := range batch {
+		if lines > 0 && lines+len(rep.Lines) > maxlines {
+			if err = dispatch(batch[offset:idx]...); err != nil {
+				return err
+			}
+			offset, lines = idx, 0
+		}
+
+		lines += len(rep.Lines)
 	}
-	return nil
+
+	return dispatch(batch[offset:]...)
 }
@@ -9,21 +9,21 @@ import (
 	"github.com/egdaemon/eg/internal/errorsx"
 	"github.com/egdaemon/eg/internal/md5x"
 	"github.com/egdaemon/eg/interp/c8s"
-	"github.com/egdaemon/eg/interp/runtime/wasi/ffiguest"
 	"github.com/egdaemon/eg/runtime/wasi/egunsafe"
+	"github.com/egdaemon/eg/runtime/wasi/egunsafe/ffigraph"
 )
 
 func Pull(ctx context.Context, name string, args []string) error {
-	nameptr, namelen := ffiguest.String(name)
-	argsptr, argslen, argssize := ffiguest.StringArray(args...)
-	return ffiguest.Error(
-		pull(
-			ffiguest.ContextDeadline(ctx),
-			nameptr, namelen,
-			argsptr, argslen, argssize,
-		),
-		fmt.Errorf("pull failed"),
-	)
+	cc, err := egunsafe.DialControlSocket(ctx)
+	if err != nil {
+		return err
+	}
+	containers := c8s.NewProxyClient(cc)
+	_, err = containers.Pull(ffigraph.Outgoing(ctx), &c8s.PullRequest{
+		Name:    name,
+		Options: args,
+	})
+	return errorsx.Wrap(err, "pull failed")
 }
 
 func Build(ctx context.Context, name, definition string, args []string) error {
@@ -32,7 +32,7 @@ func Build(ctx context.Context, name, definition string, args []string) error {
 		return err
 	}
 	containers := c8s.NewProxyClient(cc)
-	_, err = containers.Build(ctx, &c8s.BuildRequest{
+	_, err = containers.Build(ffigraph.Outgoing(ctx), &c8s.BuildRequest{
 		Name:       name,
 		Definition: definition,
 		Options:    args,
@@ -47,7 +47,7 @@ func Run(ctx context.Context, name, modulepath string, cmd []string, args []stri
 	}
 	containers := c8s.NewProxyClient(cc)
 
-	_, err = containers.Run(ctx, &c8s.RunRequest{
+	_, err = containers.Run(ffigraph.Outgoing(ctx), &c8s.RunRequest{
 		Image:   name,
 		Name:    fmt.Sprintf("%s.%s", name, md5x.String(modulepath+envx.String(eg.EnvComputeRunID))),
 		Command: cmd,
@@ -65,7 +65,7 @@ func Module(ctx context.Context, name, modulepath string, options []string) erro
 
 	cname := fmt.Sprintf("%s.%s", name, md5x.String(modulepath+envx.String("", eg.EnvComputeRunID)))
 
-	_, err = containers.Module(ctx, &c8s.ModuleRequest{
+	_, err = containers.Module(ffigraph.Outgoing(ctx), &c8s.ModuleRequest{
 		Image:   name,
 		Name:    cname,
 		Module:  modulepath,
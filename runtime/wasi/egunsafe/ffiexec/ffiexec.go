@@ -2,9 +2,12 @@ package ffiexec
 
 import (
 	"context"
+	"fmt"
+	"io"
 
 	"github.com/egdaemon/eg/interp/execproxy"
 	"github.com/egdaemon/eg/runtime/wasi/egunsafe"
+	"github.com/egdaemon/eg/runtime/wasi/egunsafe/ffigraph"
 )
 
 func Command(ctx context.Context, dir string, environ []string, cmd string, args []string) error {
@@ -14,7 +17,7 @@ func Command(ctx context.Context, dir string, environ []string, cmd string, args
 	}
 	svc := execproxy.NewProxyClient(cc)
 
-	_, err = svc.Exec(ctx, &execproxy.ExecRequest{
+	_, err = svc.Exec(ffigraph.Outgoing(ctx), &execproxy.ExecRequest{
 		Cmd:         cmd,
 		Dir:         dir,
 		Arguments:   args,
@@ -23,3 +26,65 @@ func Command(ctx context.Context, dir string, environ []string, cmd string, args
 
 	return err
 }
+
+// ExitError reports the exit code of a command that failed.
+type ExitError struct {
+	Code  int
+	cause error
+}
+
+func (t ExitError) Error() string {
+	return fmt.Sprintf("exit status %d: %v", t.Code, t.cause)
+}
+
+func (t ExitError) Unwrap() error {
+	return t.cause
+}
+
+func (t ExitError) ExitCode() int {
+	return t.Code
+}
+
+// Output executes the command writing its stdout and stderr to the provided writers.
+func Output(ctx context.Context, dir string, environ []string, cmd string, args []string, stdout io.Writer, stderr io.Writer) error {
+	cc, err := egunsafe.DialModuleControlSocket(ctx)
+	if err != nil {
+		return err
+	}
+	defer cc.Close()
+	svc := execproxy.NewProxyClient(cc)
+
+	stream, err := svc.Output(ffigraph.Outgoing(ctx), &execproxy.ExecRequest{
+		Cmd:         cmd,
+		Dir:         dir,
+		Arguments:   args,
+		Environment: environ,
+	})
+	if err != nil {
+		return err
+	}
+
+	exit := 0
+	for {
+		out, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil && exit != 0 {
+			return ExitError{Code: exit, cause: err}
+		} else if err != nil {
+			return err
+		}
+
+		if out.Exit != 0 {
+			exit = int(out.Exit)
+		}
+
+		if _, err = stdout.Write(out.Stdout); err != nil {
+			return err
+		}
+
+		if _, err = stderr.Write(out.Stderr); err != nil {
+			return err
+		}
+	}
+}
@@ -2,11 +2,16 @@ package ffigraph
 
 import (
 	"context"
+	"os"
+	"strings"
 	"time"
 
+	"github.com/egdaemon/eg"
+	"github.com/egdaemon/eg/internal/envx"
 	"github.com/egdaemon/eg/internal/errorsx"
 	"github.com/egdaemon/eg/interp/events"
 	"github.com/egdaemon/eg/runtime/wasi/egunsafe"
+	"google.golang.org/grpc/metadata"
 )
 
 type node interface {
@@ -41,6 +46,15 @@ const (
 	contextkey keys = iota
 )
 
+// current operation path, nested modules are rooted at the operation they were dispatched from.
+func operationpath(ctx context.Context) path {
+	if p, ok := ctx.Value(contextkey).(path); ok {
+		return p
+	}
+
+	return strings.FieldsFunc(os.Getenv(eg.EnvComputeOperationPath), func(r rune) bool { return r == '/' })
+}
+
 func pushv0(ctx context.Context, n node, fn func(ctx context.Context) error) (err error) {
 	np := tracer(n)
 	if np == nil {
@@ -48,16 +62,30 @@ func pushv0(ctx context.Context, n node, fn func(ctx context.Context) error) (er
 		return fn(ctx)
 	}
 
-	current, _ := ctx.Value(contextkey).(path)
+	current := operationpath(ctx)
 	latest := append(current, n.ID())
 	dctx := context.WithValue(ctx, contextkey, latest)
 	ts := time.Now()
+	if envx.Boolean(false, eg.EnvComputeEventLog) {
+		errorsx.Log(recordevt(ctx, initiated(np.OpInfo(ts, nil, current))))
+	}
 	defer func() {
 		errorsx.Log(recordevt(ctx, np.OpInfo(ts, err, current)))
 	}()
 	return fn(dctx)
 }
 
+// initiated events allow watchers to observe operations as they execute.
+func initiated(op *events.Op) *events.Op {
+	if op == nil {
+		return nil
+	}
+
+	op.State = events.Op_Initiated
+	op.Milliseconds = 0
+	return op
+}
+
 func TraceErr(ctx context.Context, op node, fn func(ctx context.Context) error) error {
 	return pushv0(ctx, op, fn)
 }
@@ -69,11 +97,49 @@ func Wrap(ctx context.Context, op node, fn func(ctx context.Context)) {
 	})
 }
 
+// Analysing reports if the module is being analysed, i.e. planned, instead of executed.
+// operations must avoid side effects while analysing.
+func Analysing() bool {
+	return analysing() == 0
+}
+
+// Skipped records the operation without executing it, used for conditional operations
+// whose condition was not met and cached operations whose previous result was reused.
+func Skipped(ctx context.Context, n node) {
+	np := tracer(n)
+	if np == nil {
+		return
+	}
+
+	evt := np.OpInfo(time.Now(), nil, operationpath(ctx))
+	if evt == nil {
+		return
+	}
+
+	evt.State = events.Op_Skipped
+	errorsx.Log(recordevt(ctx, evt))
+}
+
+// Outgoing annotates the context with the current operation path allowing
+// the control socket to attribute requests to the operation that issued them.
+func Outgoing(ctx context.Context) context.Context {
+	p := operationpath(ctx)
+	if len(p) == 0 {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, events.MetadataOperationPath, strings.Join(p, "/"))
+}
+
 func recordevt(ctx context.Context, op *events.Op) (err error) {
 	if op == nil {
 		return nil
 	}
 
+	// operations are recorded even when they were cancelled or timed out.
+	ctx, done := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer done()
+
 	cc, err := egunsafe.DialControlSocket(ctx)
 	if err != nil {
 		return err
@@ -0,0 +1,9 @@
+//go:build !wasm
+
+package ffigraph
+
+import "github.com/egdaemon/eg/interp/runtime/wasi/ffierrors"
+
+func analysing() uint32 {
+	return ffierrors.ErrNotImplemented
+}
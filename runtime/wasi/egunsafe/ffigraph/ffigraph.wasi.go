@@ -0,0 +1,6 @@
+//go:build wasm
+
+package ffigraph
+
+//go:wasmimport env github.com/egdaemon/eg/runtime/wasi/runtime/graph.Analysing
+func analysing() uint32
@@ -25,7 +25,8 @@ func execlocal(ctx context.Context, dir string, environ []string, cmd string, ar
 	c := exec.CommandContext(ctx, cmd, args...)
 	c.Dir = dir
 	c.Env = append(os.Environ(), environ...)
-	c.Stdout = os.Stdout
-	c.Stderr = os.Stderr
+	o, _ := outputFromContext(ctx)
+	c.Stdout = o.stdout
+	c.Stderr = o.stderr
 	return c.Run()
 }
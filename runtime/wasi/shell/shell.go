@@ -1,15 +1,17 @@
 package shell
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"log"
-	"math"
 	"os"
 	"os/user"
 	"time"
 
 	_eg "github.com/egdaemon/eg"
+	"github.com/egdaemon/eg/backoff"
 	"github.com/egdaemon/eg/internal/errorsx"
 	"github.com/egdaemon/eg/internal/stringsx"
 	"github.com/egdaemon/eg/internal/userx"
@@ -35,6 +37,35 @@ type Command struct {
 	lenient   bool
 	entry     entrypoint
 	exec      Execer
+	stdout    io.Writer
+	stderr    io.Writer
+	backoff   backoff.Strategy
+	retryable []RetryPredicate
+}
+
+type contextkey int
+
+const (
+	outputkey contextkey = iota
+)
+
+// destinations for a command's output, unset writers default to the terminal.
+type output struct {
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func outputFromContext(ctx context.Context) (o output, ok bool) {
+	o, ok = ctx.Value(outputkey).(output)
+	if o.stdout == nil {
+		o.stdout = os.Stdout
+	}
+
+	if o.stderr == nil {
+		o.stderr = os.Stderr
+	}
+
+	return o, ok
 }
 
 // number of attempts to make before giving up.
@@ -43,6 +74,23 @@ func (t Command) Attempts(a int16) Command {
 	return t
 }
 
+// strategy for the delay between attempts. default is a constant 200 milliseconds.
+// has no effect unless multiple attempts are allowed.
+//
+//	shell.New("apt-get update").Attempts(5).Retry(backoff.New(backoff.Exponential(time.Second), backoff.Maximum(time.Minute)))
+func (t Command) Retry(s backoff.Strategy) Command {
+	t.backoff = s
+	return t
+}
+
+// only retry failures matching at least one of the predicates. default is to retry every failure.
+//
+//	shell.New("docker push example").Attempts(3).RetryWhen(shell.RetryOnExitCode(1), shell.RetryOnStderr(regexp.MustCompile("i/o timeout")))
+func (t Command) RetryWhen(predicates ...RetryPredicate) Command {
+	t.retryable = append(t.retryable, predicates...)
+	return t
+}
+
 // directory to run the command in. must be a relative path.
 func (t Command) Directory(d string) Command {
 	t.directory = d
@@ -80,6 +128,18 @@ func (t Command) Environ(k string, v any) Command {
 	return t
 }
 
+// write the command's standard output to the provided writer instead of the terminal.
+func (t Command) Stdout(w io.Writer) Command {
+	t.stdout = w
+	return t
+}
+
+// write the command's standard error to the provided writer instead of the terminal.
+func (t Command) Stderr(w io.Writer) Command {
+	t.stderr = w
+	return t
+}
+
 // user to run the command as
 func (t Command) User(u string) Command {
 	t.user = u
@@ -167,7 +227,7 @@ func New(cmd string) Command {
 		cmd:      cmd,
 		timeout:  DefaultTimeout,
 		entry:    run,
-		exec:     ffiexec.Command,
+		exec:     execwasi,
 		attempts: 1,
 	}
 }
@@ -218,10 +278,14 @@ func Parallel(cmds ...Command) eg.OpFn {
 // Run the provided commands using the operation.
 func Run(ctx context.Context, cmds ...Command) error {
 	for _, cmd := range cmds {
-		if err := retry(ctx, cmd, func() error {
+		if err := retry(ctx, cmd, func(ctx context.Context, cmd Command) error {
 			cctx, done := context.WithTimeout(ctx, cmd.timeout)
 			defer done()
 
+			if cmd.stdout != nil || cmd.stderr != nil {
+				cctx = context.WithValue(cctx, outputkey, output{stdout: cmd.stdout, stderr: cmd.stderr})
+			}
+
 			if cause := cmd.entry(cctx, cmd.user, cmd.group, cmd.cmd, cmd.directory, cmd.environ, cmd.exec); cmd.lenient && cause != nil {
 				log.Println("command failed, but lenient mode enable, ignoring", cause)
 				return nil
@@ -238,31 +302,35 @@ func Run(ctx context.Context, cmds ...Command) error {
 	return nil
 }
 
-func retry(ctx context.Context, c Command, do func() error) (err error) {
-	attempts := c.attempts
-	switch attempts {
-	case 0, 1: // handle zero and single attempt case. 0 attempts makes no sense, so assume 1.
-		return do()
-	case -1: // unlimited attempts.
-		attempts = math.MaxInt16
-	default:
+// Output runs the command and returns its standard output. standard error
+// is written to the terminal unless redirected via Command.Stderr.
+//
+//	version, err := shell.Output(ctx, runtime.New("git describe --tags"))
+func Output(ctx context.Context, cmd Command) ([]byte, error) {
+	var (
+		buf   bytes.Buffer
+		entry = cmd.entry
+	)
+
+	// discard the output of failed attempts.
+	cmd.entry = func(ctx context.Context, user, group, c, directory string, environ []string, do Execer) error {
+		buf.Reset()
+		return entry(ctx, user, group, c, directory, environ, do)
 	}
 
-	for i := int16(0); i < attempts; i++ {
-		if cause := do(); cause == nil {
-			return nil
-		} else {
-			err = errorsx.Compact(err, cause)
-		}
+	if err := Run(ctx, cmd.Stdout(&buf)); err != nil {
+		return nil, err
+	}
 
-		select {
-		case <-time.After(200 * time.Millisecond):
-		case <-ctx.Done():
-			return ctx.Err()
-		}
+	return buf.Bytes(), nil
+}
+
+func execwasi(ctx context.Context, dir string, environ []string, cmd string, args []string) error {
+	if o, ok := outputFromContext(ctx); ok {
+		return ffiexec.Output(ctx, dir, environ, cmd, args, o.stdout, o.stderr)
 	}
 
-	return err
+	return ffiexec.Command(ctx, dir, environ, cmd, args)
 }
 
 func run(ctx context.Context, user string, group string, cmd string, directory string, environ []string, exec Execer) (err error) {
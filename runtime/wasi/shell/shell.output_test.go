@@ -0,0 +1,50 @@
+package shell_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/egdaemon/eg/internal/testx"
+	"github.com/egdaemon/eg/runtime/wasi/shell"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutput(t *testing.T) {
+	t.Run("captures stdout", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		out, err := shell.Output(ctx, shell.NewLocal().New("echo hello world"))
+		require.NoError(t, err)
+		require.Equal(t, "hello world\n", string(out))
+	})
+
+	t.Run("stderr is written to the provided writer", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		var stdout, stderr bytes.Buffer
+		require.NoError(t, shell.Run(ctx, shell.NewLocal().New("echo out; echo err 1>&2").Stdout(&stdout).Stderr(&stderr)))
+		require.Equal(t, "out\n", stdout.String())
+		require.Equal(t, "err\n", stderr.String())
+	})
+
+	t.Run("failed attempts are discarded", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		marker := filepath.Join(t.TempDir(), "marker")
+		out, err := shell.Output(ctx, shell.NewLocal().Newf("if [ -f %s ]; then echo second; else touch %s; echo first; exit 1; fi", marker, marker).Attempts(2))
+		require.NoError(t, err)
+		require.Equal(t, "second\n", string(out))
+	})
+
+	t.Run("failures return an error", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		_, err := shell.Output(ctx, shell.NewLocal().New("exit 1"))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,148 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/egdaemon/eg/backoff"
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/md5x"
+	"github.com/egdaemon/eg/interp/events"
+	"github.com/egdaemon/eg/runtime/wasi/egunsafe/ffigraph"
+)
+
+const (
+	// maximum amount of stderr retained for retry predicates.
+	stderrtail = 64 * 1024
+)
+
+// Failure describes a failed attempt of a command.
+type Failure struct {
+	Attempt int16
+	Cause   error
+	Exit    int    // exit code of the command, -1 when unavailable.
+	Stderr  []byte // trailing output written to stderr by the attempt.
+}
+
+// RetryPredicate determines if a failed attempt should be retried.
+type RetryPredicate func(Failure) bool
+
+// retry when the command exits with any of the provided codes.
+func RetryOnExitCode(codes ...int) RetryPredicate {
+	return func(f Failure) bool {
+		return slices.Contains(codes, f.Exit)
+	}
+}
+
+// retry when the command's stderr matches the regular expression.
+func RetryOnStderr(re *regexp.Regexp) RetryPredicate {
+	return func(f Failure) bool {
+		return re.Match(f.Stderr)
+	}
+}
+
+func exitcode(err error) int {
+	var exit interface{ ExitCode() int }
+	if errors.As(err, &exit) {
+		return exit.ExitCode()
+	}
+
+	return -1
+}
+
+// retains the trailing output written to it.
+type tail struct {
+	buf []byte
+}
+
+func (t *tail) Write(b []byte) (int, error) {
+	t.buf = append(t.buf, b...)
+	if overflow := len(t.buf) - stderrtail; overflow > 0 {
+		t.buf = t.buf[overflow:]
+	}
+
+	return len(b), nil
+}
+
+// an individual attempt of a command, recorded as its own operation.
+type attempt struct {
+	cmd string
+	n   int16
+}
+
+func (t attempt) ID() string {
+	return fmt.Sprintf("attempt%d%s", t.n, md5x.String(t.cmd))
+}
+
+func (t attempt) OpInfo(ts time.Time, cause error, path []string) *events.Op {
+	return &events.Op{
+		State:        events.OpState(cause),
+		Milliseconds: int64(time.Since(ts) / time.Millisecond),
+		Name:         fmt.Sprintf("%s (attempt %d)", t.cmd, t.n),
+		Module:       "shell",
+		Op:           t.ID(),
+		Path:         path,
+	}
+}
+
+func retry(ctx context.Context, c Command, do func(context.Context, Command) error) (err error) {
+	attempts := c.attempts
+	switch attempts {
+	case 0, 1: // handle zero and single attempt case. 0 attempts makes no sense, so assume 1.
+		return do(ctx, c)
+	case -1: // unlimited attempts.
+		attempts = math.MaxInt16
+	default:
+	}
+
+	strategy := c.backoff
+	if strategy == nil {
+		strategy = backoff.Constant(200 * time.Millisecond)
+	}
+
+	for i := int16(0); i < attempts; i++ {
+		var (
+			stderr tail
+			ac     = c
+		)
+
+		if len(c.retryable) > 0 && c.stderr == nil {
+			ac.stderr = io.MultiWriter(os.Stderr, &stderr)
+		} else if len(c.retryable) > 0 {
+			ac.stderr = io.MultiWriter(c.stderr, &stderr)
+		}
+
+		cause := ffigraph.TraceErr(ctx, attempt{cmd: c.cmd, n: i + 1}, func(ctx context.Context) error {
+			return do(ctx, ac)
+		})
+		if cause == nil {
+			return nil
+		}
+
+		err = errorsx.Compact(err, cause)
+
+		failure := Failure{Attempt: i + 1, Cause: cause, Exit: exitcode(cause), Stderr: stderr.buf}
+		if len(c.retryable) > 0 && !slices.ContainsFunc(c.retryable, func(p RetryPredicate) bool { return p(failure) }) {
+			return err
+		}
+
+		if i+1 == attempts {
+			break
+		}
+
+		select {
+		case <-time.After(strategy.Backoff(int64(i))):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,73 @@
+package shell_test
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/egdaemon/eg/backoff"
+	"github.com/egdaemon/eg/internal/testx"
+	"github.com/egdaemon/eg/runtime/wasi/shell"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetry(t *testing.T) {
+	// counts the attempts made by appending to a file before failing.
+	failing := func(t *testing.T, script string) (shell.Command, func() int) {
+		counter := filepath.Join(t.TempDir(), "attempts")
+		cmd := shell.NewLocal().Newf("echo >> %s; %s", counter, script).Retry(backoff.Constant(time.Millisecond))
+		return cmd, func() int {
+			raw, err := os.ReadFile(counter)
+			require.NoError(t, err)
+			return strings.Count(string(raw), "\n")
+		}
+	}
+
+	t.Run("every failure is retried by default", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		cmd, attempts := failing(t, "exit 1")
+		require.Error(t, shell.Run(ctx, cmd.Attempts(3)))
+		require.Equal(t, 3, attempts())
+	})
+
+	t.Run("exit codes not matching the predicate are not retried", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		cmd, attempts := failing(t, "exit 2")
+		require.Error(t, shell.Run(ctx, cmd.Attempts(3).RetryWhen(shell.RetryOnExitCode(3))))
+		require.Equal(t, 1, attempts())
+	})
+
+	t.Run("exit codes matching the predicate are retried", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		cmd, attempts := failing(t, "exit 3")
+		require.Error(t, shell.Run(ctx, cmd.Attempts(3).RetryWhen(shell.RetryOnExitCode(3))))
+		require.Equal(t, 3, attempts())
+	})
+
+	t.Run("stderr matching the predicate is retried", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		cmd, attempts := failing(t, "echo 'connection reset by peer' 1>&2; exit 1")
+		require.Error(t, shell.Run(ctx, cmd.Attempts(3).RetryWhen(shell.RetryOnStderr(regexp.MustCompile("connection reset")))))
+		require.Equal(t, 3, attempts())
+	})
+
+	t.Run("stderr not matching the predicate is not retried", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		cmd, attempts := failing(t, "echo 'permission denied' 1>&2; exit 1")
+		require.Error(t, shell.Run(ctx, cmd.Attempts(3).RetryWhen(shell.RetryOnStderr(regexp.MustCompile("connection reset")))))
+		require.Equal(t, 1, attempts())
+	})
+}
@@ -3,6 +3,7 @@ package egcargo
 import (
 	"context"
 	"fmt"
+	"io"
 	"io/fs"
 	"iter"
 	"os"
@@ -10,12 +11,18 @@ import (
 	"strings"
 
 	_eg "github.com/egdaemon/eg"
+	"github.com/egdaemon/eg/internal/coverage"
+	"github.com/egdaemon/eg/internal/coverage/llvmcov"
 	"github.com/egdaemon/eg/internal/envx"
 	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/md5x"
 	"github.com/egdaemon/eg/internal/stringsx"
+	"github.com/egdaemon/eg/internal/testresults/libtest"
 	"github.com/egdaemon/eg/runtime/wasi/eg"
 	"github.com/egdaemon/eg/runtime/wasi/egenv"
 	"github.com/egdaemon/eg/runtime/wasi/shell"
+	"github.com/egdaemon/eg/runtime/x/wasi/egcoverage"
+	"github.com/egdaemon/eg/runtime/x/wasi/egtests"
 )
 
 func CacheDirectory(dirs ...string) string {
@@ -46,7 +53,7 @@ func Runtime() shell.Command {
 }
 
 func AutoTest() eg.OpFn {
-	return eg.OpFn(func(ctx context.Context, _ eg.Op) (err error) {
+	return eg.OpFn(func(ctx context.Context, op eg.Op) (err error) {
 		var (
 			cenv []string
 		)
@@ -56,18 +63,75 @@ func AutoTest() eg.OpFn {
 		}
 
 		runtime := shell.Runtime().EnvironFrom(cenv...)
+		covpath := coveragedir()
+		testspath := egtests.Directory("cargo")
+		if err := shell.Run(ctx, shell.Newf("mkdir -p %s %s", covpath, testspath)); err != nil {
+			return errorsx.Wrap(err, "unable to run tests")
+		}
+
+		// when cargo-llvm-cov is installed the tests are run under it, recording their coverage.
+		llvmcov := shell.Run(ctx, runtime.New("cargo llvm-cov --version > /dev/null 2>&1")) == nil
 
 		for croot := range findroot(egenv.WorkingDirectory()) {
 			cmd := stringsx.Join(" ", "cargo", "test")
-			if err := shell.Run(ctx, runtime.New(cmd).Directory(croot)); err != nil {
-				return errorsx.Wrap(err, "unable to run tests")
+			if llvmcov {
+				cmd = stringsx.Join(" ", "cargo", "llvm-cov", "--json", "--output-path", filepath.Join(covpath, fmt.Sprintf("%s.json", md5x.String(croot))))
+			}
+
+			if err := test(ctx, runtime.New(cmd).Directory(croot), filepath.Join(testspath, fmt.Sprintf("%s.log", md5x.String(croot)))); err != nil {
+				return err
 			}
 		}
 
-		return nil
+		if !llvmcov {
+			return nil
+		}
+
+		return RecordCoverage(ctx, op)
 	})
 }
 
+// Record the coverage exported by AutoTest into the duckdb database, requires cargo-llvm-cov.
+func RecordCoverage(ctx context.Context, op eg.Op) error {
+	return egcoverage.Report(coveragesource)(ctx, op)
+}
+
+// DiffCoverage fails when the coverage of the lines changed between the base and head commits
+// is below the threshold percentage. requires the coverage exported by AutoTest.
+func DiffCoverage(threshold float32) eg.OpFn {
+	return egcoverage.Diff(threshold, coveragesource)
+}
+
+func coveragesource(ctx context.Context) iter.Seq2[*coverage.Report, error] {
+	return llvmcov.Coverage(ctx, coveragedir())
+}
+
+func coveragedir() string {
+	return egenv.EphemeralDirectory(".eg.coverage.cargo")
+}
+
+// run the tests reporting the results of the individual tests, the
+// output of the harness is retained at the provided path.
+func test(ctx context.Context, cmd shell.Command, path string) (err error) {
+	results, err := os.Create(path)
+	if err != nil {
+		return errorsx.Wrap(err, "unable to record test results")
+	}
+	defer results.Close()
+
+	// cargo reports the test binary being executed on stderr.
+	failed := shell.Run(ctx, cmd.Stdout(io.MultiWriter(results, os.Stdout)).Stderr(io.MultiWriter(results, os.Stderr)))
+
+	if _, err = results.Seek(0, io.SeekStart); err != nil {
+		return errorsx.Compact(errorsx.Wrap(failed, "unable to run tests"), errorsx.Wrap(err, "unable to read test results"))
+	}
+
+	return errorsx.Compact(
+		errorsx.Wrap(failed, "unable to run tests"),
+		egtests.Record(ctx, libtest.Parse(ctx, results)),
+	)
+}
+
 func findroot(root string) iter.Seq[string] {
 	tree := os.DirFS(root)
 
@@ -91,8 +155,9 @@ func findroot(root string) iter.Seq[string] {
 				return nil
 			}
 
-			if !yield(filepath.Join(root, path)) {
-				return fmt.Errorf("failed to yield path: %s", filepath.Join(root, path))
+			// yield the crate directory, cargo runs against the Cargo.toml within it.
+			if dir := filepath.Join(root, filepath.Dir(path)); !yield(dir) {
+				return fmt.Errorf("failed to yield path: %s", dir)
 			}
 
 			return nil
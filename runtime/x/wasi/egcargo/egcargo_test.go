@@ -0,0 +1,26 @@
+package egcargo
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindRoot(t *testing.T) {
+	t.Run("yields crate directories", func(t *testing.T) {
+		root := t.TempDir()
+		for _, path := range []string{"Cargo.toml", "a/Cargo.toml", "b/c/Cargo.toml", "b/c/src/main.rs", ".hidden/Cargo.toml"} {
+			require.NoError(t, os.MkdirAll(filepath.Join(root, filepath.Dir(path)), 0700))
+			require.NoError(t, os.WriteFile(filepath.Join(root, path), nil, 0600))
+		}
+
+		require.Equal(t, []string{
+			root,
+			filepath.Join(root, "a"),
+			filepath.Join(root, "b", "c"),
+		}, slices.Collect(findroot(root)))
+	})
+}
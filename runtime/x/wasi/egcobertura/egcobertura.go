@@ -0,0 +1,19 @@
+// Package egcobertura provides the functionality to report test coverage from cobertura xml reports (coverage.xml, cobertura*.xml) within a directory.
+package egcobertura
+
+import (
+	"github.com/egdaemon/eg/internal/coverage/cobertura"
+	"github.com/egdaemon/eg/runtime/wasi/eg"
+	"github.com/egdaemon/eg/runtime/x/wasi/egcoverage"
+)
+
+// report coverage from cobertura xml reports (coverage.xml, cobertura*.xml) within a directory.
+func ReportCoverage(dir string) eg.OpFn {
+	return egcoverage.Report(egcoverage.Directory(cobertura.Coverage, dir))
+}
+
+// DiffCoverage fails when the coverage of the lines changed between the base and head commits
+// is below the threshold percentage, using the cobertura xml reports (coverage.xml, cobertura*.xml) within the directory.
+func DiffCoverage(dir string, threshold float32) eg.OpFn {
+	return egcoverage.Diff(threshold, egcoverage.Directory(cobertura.Coverage, dir))
+}
@@ -0,0 +1,189 @@
+// Package egcoverage gates runs on the coverage of the lines changed between the base and head commits.
+package egcoverage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"log"
+	"os"
+	"strings"
+
+	_eg "github.com/egdaemon/eg"
+	"github.com/egdaemon/eg/internal/coverage"
+	"github.com/egdaemon/eg/internal/coverage/diffcov"
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/stringsx"
+	"github.com/egdaemon/eg/interp/events"
+	"github.com/egdaemon/eg/runtime/wasi/eg"
+	"github.com/egdaemon/eg/runtime/wasi/egenv"
+	"github.com/egdaemon/eg/runtime/wasi/egunsafe/fficoverage"
+	"github.com/egdaemon/eg/runtime/wasi/egunsafe/ffimetric"
+	"github.com/egdaemon/eg/runtime/wasi/shell"
+)
+
+// Source of line level coverage reports, i.e. golangcov.Coverage or lcov.Coverage.
+type Source func(ctx context.Context) iter.Seq2[*coverage.Report, error]
+
+// Directory source of the reports within the directory read by the importer, i.e. egcoverage.Directory(lcov.Coverage, dir).
+func Directory(importer func(ctx context.Context, dir string) iter.Seq2[*coverage.Report, error], dir string) Source {
+	return func(ctx context.Context) iter.Seq2[*coverage.Report, error] {
+		return importer(ctx, dir)
+	}
+}
+
+// ErrUnknownChanges indicates the changes between the base and head commits can't be determined,
+// i.e. shallow clones missing the base commit.
+const ErrUnknownChanges = errorsx.String("unable to determine the changes between the base and head commits")
+
+// Changes detects the lines changed by the head commit since it diverged from the base commit.
+// returns no changes when the commits are identical and ErrUnknownChanges when either commit is
+// unavailable within the repository.
+func Changes(ctx context.Context) (diffcov.Changes, error) {
+	return changes(ctx, shell.Runtime())
+}
+
+func changes(ctx context.Context, runtime shell.Command) (diffcov.Changes, error) {
+	var (
+		path    = egenv.RuntimeDirectory("eg.git.diff")
+		hcommit = egenv.String("", _eg.EnvGitHeadCommit)
+		bcommit = egenv.String(hcommit, _eg.EnvGitBaseCommit)
+	)
+
+	if stringsx.Blank(hcommit) {
+		return nil, errorsx.Wrapf(ErrUnknownChanges, "environment variable %s is empty", _eg.EnvGitHeadCommit)
+	}
+
+	if hcommit == bcommit {
+		return nil, nil
+	}
+
+	err := shell.Run(
+		ctx,
+		runtime.Newf("git cat-file -e %s^{commit} > /dev/null 2>&1", bcommit).Directory(egenv.WorkingDirectory()),
+		runtime.Newf("git cat-file -e %s^{commit} > /dev/null 2>&1", hcommit).Directory(egenv.WorkingDirectory()),
+	)
+	if err != nil {
+		return nil, errorsx.Wrapf(ErrUnknownChanges, "unable to locate commits %s %s", bcommit, hcommit)
+	}
+
+	err = shell.Run(
+		ctx,
+		runtime.Newf(
+			"git diff --unified=0 --no-color --no-ext-diff %s...%s > %s", bcommit, hcommit, path,
+		).Directory(egenv.WorkingDirectory()),
+	)
+	if err != nil {
+		return nil, errorsx.Wrap(err, "git diff failed")
+	}
+
+	diff, err := os.Open(path)
+	if err != nil {
+		return nil, errorsx.Wrap(err, "unable to open diff")
+	}
+	defer diff.Close()
+
+	return diffcov.ParseUnified(diff)
+}
+
+func concat(ctx context.Context, sources ...Source) iter.Seq2[*coverage.Report, error] {
+	return func(yield func(*coverage.Report, error) bool) {
+		for _, src := range sources {
+			for rep, err := range src(ctx) {
+				if !yield(rep, err) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Report the coverage from the sources into the run's analytics.
+func Report(sources ...Source) eg.OpFn {
+	return func(ctx context.Context, _ eg.Op) error {
+		batch := make([]*events.Coverage, 0, 128)
+		for rep, err := range concat(ctx, sources...) {
+			if err != nil {
+				return err
+			}
+
+			batch = append(batch, rep)
+
+			if len(batch) == cap(batch) {
+				if err := fficoverage.Report(ctx, batch...); err != nil {
+					return err
+				}
+				batch = batch[:0]
+			}
+		}
+
+		return fficoverage.Report(ctx, batch...)
+	}
+}
+
+// Diff reports the coverage of the lines changed between the base and head commits, recording it as
+// the eg.coverage.diff metric. fails when the percentage of changed executable lines covered is below
+// the threshold. every line is treated as changed when the base or head commit is unavailable.
+func Diff(threshold float32, sources ...Source) eg.OpFn {
+	return func(ctx context.Context, _ eg.Op) error {
+		r, err := compute(ctx, Changes, sources...)
+		if errors.Is(err, errNoChanges) {
+			log.Println("diff coverage no changes detected")
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if err = ffimetric.Record(ctx, diffcov.MetricName, r); err != nil {
+			return err
+		}
+
+		log.Println(report(r))
+
+		if r.Percentage() < threshold {
+			return fmt.Errorf("diff coverage %.2f%% is below the threshold %.2f%%", r.Percentage(), threshold)
+		}
+
+		return nil
+	}
+}
+
+const errNoChanges = errorsx.String("no changes detected")
+
+// compute the coverage of the changes, every line is treated as changed when the
+// changes are unknown.
+func compute(ctx context.Context, detect func(context.Context) (diffcov.Changes, error), sources ...Source) (r diffcov.Report, err error) {
+	changes, err := detect(ctx)
+	if errors.Is(err, ErrUnknownChanges) {
+		log.Println("diff coverage treating every line as changed", err)
+		r, err = diffcov.Everything(ctx, concat(ctx, sources...))
+		return r, errorsx.Wrap(err, "unable to compute diff coverage")
+	} else if err != nil {
+		return r, err
+	}
+
+	if len(changes) == 0 {
+		return r, errNoChanges
+	}
+
+	r, err = diffcov.Compute(ctx, changes, concat(ctx, sources...))
+	return r, errorsx.Wrap(err, "unable to compute diff coverage")
+}
+
+func report(r diffcov.Report) string {
+	var (
+		out strings.Builder
+	)
+
+	fmt.Fprintf(&out, "diff coverage %.2f%% %d/%d changed lines covered\n", r.Percentage(), r.Covered, r.Changed)
+	for _, f := range r.Files {
+		if len(f.Uncovered) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&out, "%s %d/%d uncovered %s\n", f.Path, f.Covered, f.Changed, diffcov.Ranges(f.Uncovered...))
+	}
+
+	return strings.TrimSuffix(out.String(), "\n")
+}
@@ -0,0 +1,149 @@
+package egcoverage
+
+import (
+	"context"
+	"iter"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	_eg "github.com/egdaemon/eg"
+	"github.com/egdaemon/eg/internal/coverage"
+	"github.com/egdaemon/eg/internal/coverage/diffcov"
+	"github.com/egdaemon/eg/interp/events"
+	"github.com/egdaemon/eg/runtime/wasi/shell"
+	"github.com/stretchr/testify/require"
+)
+
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, "git %v: %s", args, out)
+	return strings.TrimSpace(string(out))
+}
+
+func commit(t *testing.T, dir string, path string, content string) string {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, path), []byte(content), 0644))
+	git(t, dir, "add", ".")
+	git(t, dir, "commit", "-q", "-m", path)
+	return git(t, dir, "rev-parse", "HEAD")
+}
+
+func repository(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	git(t, dir, "init", "-q")
+	git(t, dir, "config", "user.email", "test@test.com")
+	git(t, dir, "config", "user.name", "test")
+	t.Setenv(_eg.EnvComputeWorkingDirectory, dir)
+	t.Setenv(_eg.EnvComputeRuntimeDirectory, t.TempDir())
+	return dir
+}
+
+func commits(t *testing.T, base, head string) {
+	t.Setenv(_eg.EnvGitBaseCommit, base)
+	t.Setenv(_eg.EnvGitHeadCommit, head)
+}
+
+func reports(reps ...*coverage.Report) Source {
+	return func(ctx context.Context) iter.Seq2[*coverage.Report, error] {
+		return func(yield func(*coverage.Report, error) bool) {
+			for _, rep := range reps {
+				if !yield(rep, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func TestChanges(t *testing.T) {
+	t.Run("lines changed by the head commit", func(t *testing.T) {
+		dir := repository(t)
+		base := commit(t, dir, "a.go", "1\n2\n3\n")
+		head := commit(t, dir, "a.go", "1\n2 modified\n3\n4\n")
+		commits(t, base, head)
+
+		changes, err := changes(t.Context(), shell.NewLocal())
+		require.NoError(t, err)
+		require.Equal(t, diffcov.Changes{"a.go": {2, 4}}, changes)
+	})
+
+	t.Run("changes to the base after the head diverged are ignored", func(t *testing.T) {
+		dir := repository(t)
+		commit(t, dir, "a.go", "1\n")
+		git(t, dir, "checkout", "-q", "-b", "feature")
+		head := commit(t, dir, "b.go", "1\n")
+		git(t, dir, "checkout", "-q", "-")
+		base := commit(t, dir, "c.go", "1\n")
+		commits(t, base, head)
+
+		changes, err := changes(t.Context(), shell.NewLocal())
+		require.NoError(t, err)
+		require.Equal(t, diffcov.Changes{"b.go": {1}}, changes)
+	})
+
+	t.Run("identical commits have no changes", func(t *testing.T) {
+		dir := repository(t)
+		head := commit(t, dir, "a.go", "1\n")
+		commits(t, head, head)
+
+		changes, err := changes(t.Context(), shell.NewLocal())
+		require.NoError(t, err)
+		require.Empty(t, changes)
+	})
+
+	t.Run("missing base commit is unknown", func(t *testing.T) {
+		dir := repository(t)
+		head := commit(t, dir, "a.go", "1\n")
+		commits(t, "0000000000000000000000000000000000000001", head)
+
+		_, err := changes(t.Context(), shell.NewLocal())
+		require.ErrorIs(t, err, ErrUnknownChanges)
+	})
+
+	t.Run("missing head commit is unknown", func(t *testing.T) {
+		repository(t)
+		commits(t, "", "")
+
+		_, err := changes(t.Context(), shell.NewLocal())
+		require.ErrorIs(t, err, ErrUnknownChanges)
+	})
+}
+
+func TestCompute(t *testing.T) {
+	src := reports(
+		&coverage.Report{Path: "a.go", Lines: []*events.CoverageLine{{Line: 1, Hits: 1}, {Line: 2, Hits: 0}}},
+		&coverage.Report{Path: "b.go", Lines: []*events.CoverageLine{{Line: 1, Hits: 0}}},
+	)
+
+	t.Run("unknown changes treat every line as changed", func(t *testing.T) {
+		r, err := compute(t.Context(), func(context.Context) (diffcov.Changes, error) {
+			return nil, ErrUnknownChanges
+		}, src)
+		require.NoError(t, err)
+		require.Equal(t, 3, r.Changed)
+		require.Equal(t, 1, r.Covered)
+	})
+
+	t.Run("only changed lines are measured", func(t *testing.T) {
+		r, err := compute(t.Context(), func(context.Context) (diffcov.Changes, error) {
+			return diffcov.Changes{"a.go": {1}}, nil
+		}, src)
+		require.NoError(t, err)
+		require.Equal(t, 1, r.Changed)
+		require.Equal(t, 1, r.Covered)
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		_, err := compute(t.Context(), func(context.Context) (diffcov.Changes, error) {
+			return nil, nil
+		}, src)
+		require.ErrorIs(t, err, errNoChanges)
+	})
+}
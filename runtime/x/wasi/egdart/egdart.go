@@ -3,6 +3,7 @@ package egdart
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
 	"iter"
@@ -16,11 +17,14 @@ import (
 	"github.com/egdaemon/eg/internal/envx"
 	"github.com/egdaemon/eg/internal/errorsx"
 	"github.com/egdaemon/eg/internal/langx"
+	"github.com/egdaemon/eg/internal/md5x"
 	"github.com/egdaemon/eg/internal/stringsx"
+	"github.com/egdaemon/eg/internal/testresults/dartjson"
 	"github.com/egdaemon/eg/internal/timex"
 	"github.com/egdaemon/eg/runtime/wasi/eg"
 	"github.com/egdaemon/eg/runtime/wasi/egenv"
 	"github.com/egdaemon/eg/runtime/wasi/shell"
+	"github.com/egdaemon/eg/runtime/x/wasi/egtests"
 )
 
 func CacheDirectory(dirs ...string) string {
@@ -137,11 +141,17 @@ func AutoTest(options ...toption) eg.OpFn {
 
 		runtime := shell.Runtime().EnvironFrom(denv...)
 		timeout := timex.DurationMin(contextx.Until(ctx), timex.DurationFirstNonZero(opts.timeout, shell.DefaultTimeout))
+		testspath := egtests.Directory("dart")
+		if err := shell.Run(ctx, shell.Newf("mkdir -p %s", testspath)); err != nil {
+			return errorsx.Wrap(err, "unable to run tests")
+		}
 
 		for pubspec := range FindRoots(egenv.WorkingDirectory()) {
-			cmd := stringsx.Join(" ", "dart", "test", fmt.Sprintf("--timeout=%s", timeout), flags)
-			if err := shell.Run(ctx, runtime.New(cmd).Directory(filepath.Dir(pubspec)).Timeout(timeout+time.Second)); err != nil {
-				return errorsx.Wrap(err, "unable to run tests")
+			results := filepath.Join(testspath, fmt.Sprintf("%s.json", md5x.String(pubspec)))
+			cmd := stringsx.Join(" ", "dart", "test", fmt.Sprintf("--timeout=%s", timeout), fmt.Sprintf("--file-reporter json:%s", results), flags)
+			failed := shell.Run(ctx, runtime.New(cmd).Directory(filepath.Dir(pubspec)).Timeout(timeout+time.Second))
+			if err := errorsx.Compact(errorsx.Wrap(failed, "unable to run tests"), record(ctx, results)); err != nil {
+				return err
 			}
 		}
 
@@ -149,6 +159,20 @@ func AutoTest(options ...toption) eg.OpFn {
 	})
 }
 
+// report the results of the individual tests written by the json file reporter.
+func record(ctx context.Context, path string) error {
+	results, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		// dart failed before running any tests.
+		return nil
+	} else if err != nil {
+		return errorsx.Wrap(err, "unable to read test results")
+	}
+	defer results.Close()
+
+	return egtests.Record(ctx, dartjson.Parse(ctx, results))
+}
+
 // AutoAnalyze finds pubspec.yaml files and runs dart analyze in each project directory.
 func AutoAnalyze() eg.OpFn {
 	return eg.OpFn(func(ctx context.Context, _ eg.Op) (err error) {
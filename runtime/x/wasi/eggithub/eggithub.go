@@ -8,11 +8,14 @@ import (
 	"time"
 
 	"github.com/egdaemon/eg/internal/fsx"
+	"github.com/egdaemon/eg/interp/provenance"
 	"github.com/egdaemon/eg/runtime/wasi/eg"
 	"github.com/egdaemon/eg/runtime/wasi/egenv"
 	"github.com/egdaemon/eg/runtime/wasi/eggit"
 	"github.com/egdaemon/eg/runtime/wasi/egunsafe/ffigit"
+	"github.com/egdaemon/eg/runtime/wasi/egunsafe/ffimetric"
 	"github.com/egdaemon/eg/runtime/wasi/shell"
+	"github.com/egdaemon/eg/runtime/x/wasi/egsha256x"
 )
 
 // provides the version pattern based on a github commit.
@@ -56,10 +59,15 @@ func Draft(patterns ...string) eg.OpFn {
 		)
 
 		if shell.Run(ctx, runtime.Newf("gh release view %s", version)) != nil {
-			return shell.Run(
+			err := shell.Run(
 				ctx,
 				runtime.Newf("gh release create --draft --target %s %s %s", c.Hash.String(), version, strings.Join(patterns, " ")),
 			)
+			if err != nil {
+				return err
+			}
+
+			return attest(ctx, patterns...)
 		}
 
 		return Upload(version, patterns...)(ctx, o)
@@ -194,9 +202,40 @@ func UploadRuntime(rt shell.Command, release string, patterns ...string) eg.OpFn
 			"GH_TOKEN", ffigit.Bearer(),
 		)
 
-		return shell.Run(
+		err := shell.Run(
 			ctx,
 			runtime.Newf("gh release upload --clobber %s %s", release, strings.Join(patterns, " ")),
 		)
+		if err != nil {
+			return err
+		}
+
+		return attest(ctx, patterns...)
 	}
 }
+
+// attest records the uploaded assets as subjects of the run's provenance, the runner signs
+// the provenance at completion allowing the released assets to be verified against it.
+// see `eg provenance verify`.
+func attest(ctx context.Context, patterns ...string) error {
+	for _, p := range patterns {
+		matches, err := filepath.Glob(p)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range matches {
+			digest := egsha256x.DigestFile(m)
+			if digest == nil {
+				return fmt.Errorf("unable to digest asset: %s", m)
+			}
+
+			subject := provenance.Subject{Name: filepath.Base(m), Digest: map[string]string{"sha256": egsha256x.FormatHex(digest)}}
+			if err = ffimetric.Record(ctx, provenance.MetricSubject, subject); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"go/build"
+	"io"
+	"iter"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,6 +13,7 @@ import (
 
 	_eg "github.com/egdaemon/eg"
 	"github.com/egdaemon/eg/internal/contextx"
+	"github.com/egdaemon/eg/internal/coverage"
 	"github.com/egdaemon/eg/internal/coverage/golangcov"
 	"github.com/egdaemon/eg/internal/envx"
 	"github.com/egdaemon/eg/internal/errorsx"
@@ -19,12 +22,13 @@ import (
 	"github.com/egdaemon/eg/internal/modfilex"
 	"github.com/egdaemon/eg/internal/slicesx"
 	"github.com/egdaemon/eg/internal/stringsx"
+	"github.com/egdaemon/eg/internal/testresults/gotest"
 	"github.com/egdaemon/eg/internal/timex"
-	"github.com/egdaemon/eg/interp/events"
 	"github.com/egdaemon/eg/runtime/wasi/eg"
 	"github.com/egdaemon/eg/runtime/wasi/egenv"
-	"github.com/egdaemon/eg/runtime/wasi/egunsafe/fficoverage"
 	"github.com/egdaemon/eg/runtime/wasi/shell"
+	"github.com/egdaemon/eg/runtime/x/wasi/egcoverage"
+	"github.com/egdaemon/eg/runtime/x/wasi/egtests"
 )
 
 var BuildOption = boption(nil)
@@ -297,7 +301,8 @@ func AutoTest(options ...toption) eg.OpFn {
 		)
 
 		covpath := coveragedir()
-		if err := shell.Run(ctx, shell.Newf("mkdir -p %s", covpath)); err != nil {
+		testspath := egtests.Directory("golang")
+		if err := shell.Run(ctx, shell.Newf("mkdir -p %s %s", covpath, testspath)); err != nil {
 			return errorsx.Wrap(err, "unable to run tests")
 		}
 
@@ -309,9 +314,9 @@ func AutoTest(options ...toption) eg.OpFn {
 		timeout := timex.DurationMin(contextx.Until(ctx), timex.DurationFirstNonZero(opts.timeout, shell.DefaultTimeout))
 
 		for gomod := range modfilex.FindModules(egenv.WorkingDirectory()) {
-			cmd := stringsx.Join(" ", "go", "-C", filepath.Dir(gomod), "test", fmt.Sprintf("-timeout=%s", timeout), flags, fmt.Sprintf("-coverprofile %s", filepath.Join(covpath, md5x.String(gomod))), "./...")
-			if err := shell.Run(ctx, runtime.New(cmd).Timeout(timeout+time.Second)); err != nil {
-				return errorsx.Wrap(err, "unable to run tests")
+			cmd := stringsx.Join(" ", "go", "-C", filepath.Dir(gomod), "test", "-json", fmt.Sprintf("-timeout=%s", timeout), flags, fmt.Sprintf("-coverprofile %s", filepath.Join(covpath, md5x.String(gomod))), "./...")
+			if err := test(ctx, runtime.New(cmd).Timeout(timeout+time.Second), filepath.Join(testspath, fmt.Sprintf("%s.json", md5x.String(gomod)))); err != nil {
+				return err
 			}
 		}
 
@@ -319,32 +324,40 @@ func AutoTest(options ...toption) eg.OpFn {
 	})
 }
 
-// Record the coverage profile into the duckdb database.
-func RecordCoverage(ctx context.Context, _ eg.Op) (err error) {
-	covpath := coveragedir()
-
-	// recover metrics
-	batch := make([]*events.Coverage, 0, 128)
-	for rep, err := range golangcov.Coverage(ctx, covpath) {
-		if err != nil {
-			return err
-		}
+// run the tests reporting the results of the individual tests, the
+// events are retained at the provided path.
+func test(ctx context.Context, cmd shell.Command, path string) (err error) {
+	results, err := os.Create(path)
+	if err != nil {
+		return errorsx.Wrap(err, "unable to record test results")
+	}
+	defer results.Close()
 
-		batch = append(batch, rep)
+	printer := gotest.Printer(os.Stdout)
+	failed := shell.Run(ctx, cmd.Stdout(io.MultiWriter(results, printer)))
+	errorsx.Log(errorsx.Wrap(printer.Close(), "unable to print test output"))
 
-		if len(batch) == cap(batch) {
-			if err := fficoverage.Report(ctx, batch...); err != nil {
-				return err
-			}
-			batch = batch[:0]
-		}
+	if _, err = results.Seek(0, io.SeekStart); err != nil {
+		return errorsx.Compact(errorsx.Wrap(failed, "unable to run tests"), errorsx.Wrap(err, "unable to read test results"))
 	}
 
-	if err := fficoverage.Report(ctx, batch...); err != nil {
-		return err
-	}
+	return errorsx.Compact(
+		errorsx.Wrap(failed, "unable to run tests"),
+		egtests.Record(ctx, gotest.Parse(ctx, results)),
+	)
+}
 
-	return nil
+// Record the coverage profile into the duckdb database.
+func RecordCoverage(ctx context.Context, op eg.Op) (err error) {
+	return egcoverage.Report(egcoverage.Directory(golangcov.Coverage, coveragedir()))(ctx, op)
+}
+
+// DiffCoverage fails when the coverage of the lines changed between the base and head commits
+// is below the threshold percentage. requires the coverage profile generated by the tests.
+func DiffCoverage(threshold float32) eg.OpFn {
+	return egcoverage.Diff(threshold, func(ctx context.Context) iter.Seq2[*coverage.Report, error] {
+		return golangcov.Coverage(ctx, coveragedir())
+	})
 }
 
 func CacheDirectory(dirs ...string) string {
@@ -0,0 +1,19 @@
+// Package egjacoco provides the functionality to report test coverage from jacoco xml reports (jacoco*.xml) within a directory.
+package egjacoco
+
+import (
+	"github.com/egdaemon/eg/internal/coverage/jacoco"
+	"github.com/egdaemon/eg/runtime/wasi/eg"
+	"github.com/egdaemon/eg/runtime/x/wasi/egcoverage"
+)
+
+// report coverage from jacoco xml reports (jacoco*.xml) within a directory.
+func ReportCoverage(dir string) eg.OpFn {
+	return egcoverage.Report(egcoverage.Directory(jacoco.Coverage, dir))
+}
+
+// DiffCoverage fails when the coverage of the lines changed between the base and head commits
+// is below the threshold percentage, using the jacoco xml reports (jacoco*.xml) within the directory.
+func DiffCoverage(dir string, threshold float32) eg.OpFn {
+	return egcoverage.Diff(threshold, egcoverage.Directory(jacoco.Coverage, dir))
+}
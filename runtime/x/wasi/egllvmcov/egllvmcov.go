@@ -0,0 +1,19 @@
+// Package egllvmcov provides the functionality to report test coverage from llvm-cov json exports within a directory.
+package egllvmcov
+
+import (
+	"github.com/egdaemon/eg/internal/coverage/llvmcov"
+	"github.com/egdaemon/eg/runtime/wasi/eg"
+	"github.com/egdaemon/eg/runtime/x/wasi/egcoverage"
+)
+
+// report coverage from llvm-cov json exports within a directory.
+func ReportCoverage(dir string) eg.OpFn {
+	return egcoverage.Report(egcoverage.Directory(llvmcov.Coverage, dir))
+}
+
+// DiffCoverage fails when the coverage of the lines changed between the base and head commits
+// is below the threshold percentage, using the llvm-cov json exports within the directory.
+func DiffCoverage(dir string, threshold float32) eg.OpFn {
+	return egcoverage.Diff(threshold, egcoverage.Directory(llvmcov.Coverage, dir))
+}
@@ -174,3 +174,14 @@ func Op[T any](b Builder[T], mkop func(*T) eg.OpFn) eg.OpFn {
 		return nil
 	}
 }
+
+// ParallelOp generates an operation for every permutation and executes them in parallel
+// with at most limit operations executing at a time. a limit less than 1 is unbounded.
+func ParallelOp[T any](limit int, b Builder[T], mkop func(*T) eg.OpFn) eg.OpFn {
+	ops := make([]eg.OpFn, 0, 16)
+	for v := range b.Perm() {
+		ops = append(ops, mkop(&v))
+	}
+
+	return eg.ParallelN(limit, ops...)
+}
@@ -18,12 +18,15 @@ func NewReader(ctx context.Context, uris ...string) io.Reader {
 	return secrets.NewReader(ctx, uris...)
 }
 
-// Read a secret from the given URI. Supported schemes: gcpsm://, awssm://, chachasm://, file://
+// Read a secret from the given URI. Supported schemes: gcpsm://, awssm://, chachasm://, file://, vault://, keyring://
+// the fragment selects a key path from json or yaml secrets, i.e. awssm://db?region=us-east-1#credentials.password,
+// the env query parameter renames the selection to an environment variable and the template query parameter
+// renders the selection through a text/template file. see secrets.Select.
 func Read(ctx context.Context, uri string) io.Reader {
 	return secrets.Read(ctx, uri)
 }
 
-// Update a secret at the given URI. Supported schemes: gcpsm://, awssm://, chachasm://, file://
+// Update a secret at the given URI. Supported schemes: gcpsm://, awssm://, chachasm://, file://, vault://, keyring://
 func Update(ctx context.Context, uri string, r io.Reader) error {
 	return secrets.Update(ctx, uri, r)
 }
@@ -41,6 +44,8 @@ func CopyInto(ctx context.Context, w io.Writer, uris ...string) error {
 }
 
 // CopyIntoFile copies the secret content from the given URIs into a file at the provided path.
+// configuration files are rendered from structured secrets using the template query parameter,
+// i.e. file:///secrets/registry.json?template=/templates/npmrc.tmpl
 func CopyIntoFile(ctx context.Context, path string, uris ...string) (err error) {
 	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
 		return err
@@ -200,6 +200,19 @@ func TestCopyIntoFile(t *testing.T) {
 		require.Equal(t, "alpha\nbravo\n", string(result))
 	})
 
+	t.Run("rendered template", func(t *testing.T) {
+		tmp := t.TempDir()
+		uri := writeFileSecret(t, tmp, "secret.json", `{"registry": {"token": "abc123"}}`)
+		writeFileSecret(t, tmp, "npmrc.tmpl", "//registry.npmjs.org/:_authToken={{ .registry.token }}\n")
+		dst := filepath.Join(tmp, ".npmrc")
+
+		require.NoError(t, egsecrets.CopyIntoFile(t.Context(), dst, uri+"?template="+filepath.Join(tmp, "npmrc.tmpl")))
+
+		result, err := os.ReadFile(dst)
+		require.NoError(t, err)
+		require.Equal(t, "//registry.npmjs.org/:_authToken=abc123\n", string(result))
+	})
+
 	t.Run("invalid path", func(t *testing.T) {
 		tmp := t.TempDir()
 		uri := writeFileSecret(t, tmp, "secret.txt", "data")
@@ -0,0 +1,73 @@
+// Package egtests provides the functionality to report the results of individual tests,
+// allowing flaky and slow tests to be tracked over time.
+package egtests
+
+import (
+	"context"
+	"iter"
+	"path/filepath"
+
+	"github.com/egdaemon/eg/internal/testresults/gotest"
+	"github.com/egdaemon/eg/internal/testresults/junit"
+	"github.com/egdaemon/eg/internal/testresults/tap"
+	"github.com/egdaemon/eg/interp/events"
+	"github.com/egdaemon/eg/runtime/wasi/eg"
+	"github.com/egdaemon/eg/runtime/wasi/egenv"
+	"github.com/egdaemon/eg/runtime/wasi/egunsafe/ffitests"
+)
+
+// Directory for intermediate test reports.
+func Directory(rel ...string) string {
+	return egenv.EphemeralDirectory(".eg.tests", filepath.Join(rel...))
+}
+
+// Report the test results.
+func Report(ctx context.Context, results ...*events.TestResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	return ffitests.Report(ctx, results...)
+}
+
+// Record the test results in batches.
+func Record(ctx context.Context, results iter.Seq2[*events.TestResult, error]) error {
+	batch := make([]*events.TestResult, 0, 128)
+	for res, err := range results {
+		if err != nil {
+			return err
+		}
+
+		batch = append(batch, res)
+
+		if len(batch) == cap(batch) {
+			if err := Report(ctx, batch...); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+
+	return Report(ctx, batch...)
+}
+
+// report test results from go test -json output files (*.json) within a directory.
+func ReportGoTest(dir string) eg.OpFn {
+	return eg.OpFn(func(ctx context.Context, _ eg.Op) error {
+		return Record(ctx, gotest.Results(ctx, dir))
+	})
+}
+
+// report test results from junit xml files (*.xml) within a directory.
+func ReportJUnit(dir string) eg.OpFn {
+	return eg.OpFn(func(ctx context.Context, _ eg.Op) error {
+		return Record(ctx, junit.Results(ctx, dir))
+	})
+}
+
+// report test results from tap files (*.tap) within a directory.
+func ReportTAP(dir string) eg.OpFn {
+	return eg.OpFn(func(ctx context.Context, _ eg.Op) error {
+		return Record(ctx, tap.Results(ctx, dir))
+	})
+}
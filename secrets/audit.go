@@ -0,0 +1,289 @@
+package secrets
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/egdaemon/eg/internal/errorsx"
+	"golang.org/x/crypto/ssh"
+)
+
+// AuditSuffix appended to the path of a file backed secret to locate its audit log.
+const AuditSuffix = ".audit"
+
+// actions recorded by the audit log.
+const (
+	AuditActionUpdate = "update"
+	AuditActionRotate = "rotate"
+)
+
+// AuditEntry records a single change to a secret. values are never recorded, only the names of
+// the keys that changed and the digest of the encrypted secret after the change.
+type AuditEntry struct {
+	Timestamp time.Time `json:"ts"`
+	Actor     string    `json:"actor"`
+	Action    string    `json:"action"`
+	Keys      []string  `json:"keys,omitempty"`
+	Base      string    `json:"base,omitempty"` // digest of the encrypted secret before the change, empty when it didn't exist.
+	Digest    string    `json:"digest"`
+	Previous  string    `json:"previous"`
+	PublicKey string    `json:"public_key"`
+	Signature string    `json:"signature,omitempty"`
+}
+
+// secretPath resolves the file of a file backed secret.
+func secretPath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+
+	switch u.Scheme {
+	case "chachasm":
+		return chachaPath(u), nil
+	case "keyring":
+		return keyringPath(u), nil
+	case "file":
+		return filePath(u), nil
+	default:
+		return "", fmt.Errorf("audit logs are only supported by file backed schemes: %s", u.Scheme)
+	}
+}
+
+// AuditPath returns the location of the secret's audit log, it lives alongside the secret
+// allowing both to be committed to a repository.
+func AuditPath(uri string) (string, error) {
+	path, err := secretPath(uri)
+	if err != nil {
+		return "", err
+	}
+
+	return path + AuditSuffix, nil
+}
+
+func digest(b []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(b))
+}
+
+// the content signed for the entry, the entry encoded without its signature.
+func (t AuditEntry) signed() ([]byte, error) {
+	t.Signature = ""
+	return json.Marshal(t)
+}
+
+// Audit performs the change to the secret and appends a signed entry recording it to the secret's
+// audit log. the entries form a hash chain, each records the digest of the previous entry along with
+// the digests of the secret before and after the change. the log is validated before the change is
+// performed and the secret is restored when the entry can't be recorded.
+func Audit(uri string, s ssh.Signer, entry AuditEntry, change func() error) (err error) {
+	path, err := AuditPath(uri)
+	if err != nil {
+		return err
+	}
+	secret := strings.TrimSuffix(path, AuditSuffix)
+
+	lines, err := auditlines(path)
+	if err != nil {
+		return err
+	}
+
+	before, err := os.ReadFile(secret)
+	if err != nil && !os.IsNotExist(err) {
+		return errorsx.Wrap(err, "unable to read secret")
+	}
+	existed := err == nil
+
+	if existed {
+		entry.Base = digest(before)
+	}
+
+	if len(lines) > 0 {
+		var last AuditEntry
+		if err = json.Unmarshal(lines[len(lines)-1], &last); err != nil {
+			return errorsx.Wrap(err, "last audit entry is invalid")
+		}
+
+		if last.Digest != entry.Base {
+			return fmt.Errorf("secret was modified after the last audit entry")
+		}
+
+		entry.Previous = digest(lines[len(lines)-1])
+	}
+
+	if err = change(); err != nil {
+		return errors.Join(err, restore(secret, before, existed))
+	}
+
+	if err = appendentry(path, secret, s, entry); err != nil {
+		return errors.Join(err, restore(secret, before, existed))
+	}
+
+	return nil
+}
+
+// restore the content of the secret prior to a change.
+func restore(path string, content []byte, existed bool) error {
+	if !existed {
+		return errorsx.Wrap(errorsx.Ignore(os.Remove(path), os.ErrNotExist), "unable to restore secret")
+	}
+
+	return errorsx.Wrap(os.WriteFile(path, content, 0600), "unable to restore secret")
+}
+
+func appendentry(path string, secret string, s ssh.Signer, entry AuditEntry) (err error) {
+	current, err := os.ReadFile(secret)
+	if err != nil {
+		return errorsx.Wrap(err, "unable to read secret")
+	}
+
+	entry.Timestamp = entry.Timestamp.UTC()
+	entry.Digest = digest(current)
+	entry.PublicKey = strings.TrimSpace(string(ssh.MarshalAuthorizedKey(s.PublicKey())))
+
+	signed, err := entry.signed()
+	if err != nil {
+		return err
+	}
+
+	sig, err := s.Sign(rand.Reader, signed)
+	if err != nil {
+		return errorsx.Wrap(err, "unable to sign audit entry")
+	}
+	entry.Signature = base64.RawURLEncoding.EncodeToString(ssh.Marshal(sig))
+
+	encoded, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	dst, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return errorsx.Wrap(err, "unable to open audit log")
+	}
+	defer func() {
+		err = errorsx.Compact(err, dst.Close())
+	}()
+
+	_, err = dst.Write(append(encoded, '\n'))
+	return err
+}
+
+// AuditVerify returns the entries of the secret's audit log after verifying them. every entry must be
+// signed by one of the trusted keys, verification fails when a signature is invalid, the hash chain is
+// broken or the secret was modified outside of the recorded changes.
+func AuditVerify(uri string, keys ...ssh.PublicKey) (entries []AuditEntry, err error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("unable to verify the audit log without trusted keys")
+	}
+
+	path, err := AuditPath(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := auditlines(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("audit log %s: %w", path, os.ErrNotExist)
+	}
+
+	previous := ""
+	for idx, line := range lines {
+		var entry AuditEntry
+		if err = json.Unmarshal(line, &entry); err != nil {
+			return entries, errorsx.Wrapf(err, "audit entry %d is invalid", idx+1)
+		}
+
+		if entry.Previous != previous {
+			return entries, fmt.Errorf("audit entry %d doesn't follow the previous entry, the log was modified", idx+1)
+		}
+
+		if idx > 0 && entry.Base != entries[idx-1].Digest {
+			return entries, fmt.Errorf("audit entry %d doesn't follow the secret of the previous entry, the secret was modified without an entry", idx+1)
+		}
+
+		if err = entry.verify(keys...); err != nil {
+			return entries, errorsx.Wrapf(err, "audit entry %d", idx+1)
+		}
+
+		entries = append(entries, entry)
+		previous = digest(line)
+	}
+
+	current, err := os.ReadFile(strings.TrimSuffix(path, AuditSuffix))
+	if err != nil {
+		return entries, errorsx.Wrap(err, "unable to read secret")
+	}
+
+	if last := entries[len(entries)-1]; last.Digest != digest(current) {
+		return entries, fmt.Errorf("secret was modified after the last audit entry")
+	}
+
+	return entries, nil
+}
+
+func (t AuditEntry) verify(keys ...ssh.PublicKey) error {
+	var (
+		sig ssh.Signature
+	)
+
+	pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(t.PublicKey))
+	if err != nil {
+		return errorsx.Wrap(err, "invalid public key")
+	}
+
+	if !slices.ContainsFunc(keys, func(k ssh.PublicKey) bool { return bytes.Equal(k.Marshal(), pub.Marshal()) }) {
+		return fmt.Errorf("signed by an unauthorized key %s", ssh.FingerprintSHA256(pub))
+	}
+
+	encoded, err := base64.RawURLEncoding.DecodeString(t.Signature)
+	if err != nil {
+		return errorsx.Wrap(err, "invalid signature")
+	}
+
+	if err = ssh.Unmarshal(encoded, &sig); err != nil {
+		return errorsx.Wrap(err, "invalid signature")
+	}
+
+	signed, err := t.signed()
+	if err != nil {
+		return err
+	}
+
+	return errorsx.Wrap(pub.Verify(signed, &sig), "invalid signature")
+}
+
+// auditlines reads the entries of the log, a missing log has no entries.
+func auditlines(path string) (lines [][]byte, err error) {
+	src, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errorsx.Wrap(err, "unable to open audit log")
+	}
+	defer src.Close()
+
+	scanner := bufio.NewScanner(src)
+	for scanner.Scan() {
+		if line := bytes.TrimSpace(scanner.Bytes()); len(line) > 0 {
+			lines = append(lines, bytes.Clone(line))
+		}
+	}
+
+	return lines, scanner.Err()
+}
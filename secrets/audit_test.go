@@ -0,0 +1,174 @@
+package secrets_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/egdaemon/eg/internal/sshx"
+	"github.com/egdaemon/eg/secrets"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+// signer failing to sign, simulating a change whose entry can't be recorded.
+type brokensigner struct {
+	ssh.Signer
+}
+
+func (t brokensigner) Sign(io.Reader, []byte) (*ssh.Signature, error) {
+	return nil, errors.New("broken signer")
+}
+
+func entry() secrets.AuditEntry {
+	return secrets.AuditEntry{Timestamp: time.Now(), Actor: "tester", Action: secrets.AuditActionUpdate, Keys: []string{"A"}}
+}
+
+func update(t *testing.T, uri string, content string) func() error {
+	return func() error {
+		return secrets.Update(t.Context(), uri, strings.NewReader(content))
+	}
+}
+
+func TestAudit(t *testing.T) {
+	signer, err := sshx.SignerFromGenerator(sshx.NewKeyGenSeeded("audit"))
+	require.NoError(t, err)
+
+	setup := func(t *testing.T) (string, string) {
+		path := filepath.Join(t.TempDir(), "secret.chacha")
+		uri := "chachasm://p@" + path
+		for _, content := range []string{"A=1", "A=2"} {
+			require.NoError(t, secrets.Audit(uri, signer, entry(), update(t, uri, content)))
+		}
+
+		return uri, path
+	}
+
+	t.Run("verify chain of entries", func(t *testing.T) {
+		uri, _ := setup(t)
+		entries, err := secrets.AuditVerify(uri, signer.PublicKey())
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		require.Empty(t, entries[0].Previous)
+		require.NotEmpty(t, entries[1].Previous)
+		require.Empty(t, entries[0].Base)
+		require.Equal(t, entries[0].Digest, entries[1].Base)
+	})
+
+	t.Run("failure without trusted keys", func(t *testing.T) {
+		uri, _ := setup(t)
+		_, err := secrets.AuditVerify(uri)
+		require.ErrorContains(t, err, "without trusted keys")
+	})
+
+	t.Run("failure untrusted key", func(t *testing.T) {
+		uri, _ := setup(t)
+		other, err := sshx.SignerFromGenerator(sshx.NewKeyGenSeeded("other"))
+		require.NoError(t, err)
+		_, err = secrets.AuditVerify(uri, other.PublicKey())
+		require.ErrorContains(t, err, "unauthorized key")
+	})
+
+	t.Run("failure recording a change after the secret was modified without an entry", func(t *testing.T) {
+		uri, path := setup(t)
+		require.NoError(t, secrets.Update(t.Context(), uri, strings.NewReader("A=3")))
+		modified, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		require.ErrorContains(t, secrets.Audit(uri, signer, entry(), update(t, uri, "A=4")), "modified after the last audit entry")
+		current, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, modified, current)
+	})
+
+	t.Run("secret is restored when the entry can't be recorded", func(t *testing.T) {
+		uri, path := setup(t)
+		original, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		require.ErrorContains(t, secrets.Audit(uri, brokensigner{Signer: signer}, entry(), update(t, uri, "A=3")), "broken signer")
+		current, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, original, current)
+
+		_, err = secrets.AuditVerify(uri, signer.PublicKey())
+		require.NoError(t, err)
+	})
+
+	t.Run("new secret is removed when the entry can't be recorded", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "secret.chacha")
+		uri := "chachasm://p@" + path
+		require.Error(t, secrets.Audit(uri, brokensigner{Signer: signer}, entry(), update(t, uri, "A=1")))
+		require.NoFileExists(t, path)
+	})
+
+	t.Run("failure secret modified without an entry", func(t *testing.T) {
+		uri, _ := setup(t)
+		require.NoError(t, secrets.Update(t.Context(), uri, strings.NewReader("A=3")))
+		_, err := secrets.AuditVerify(uri, signer.PublicKey())
+		require.ErrorContains(t, err, "modified after the last audit entry")
+	})
+
+	t.Run("failure entry removed", func(t *testing.T) {
+		uri, path := setup(t)
+		encoded, err := os.ReadFile(path + secrets.AuditSuffix)
+		require.NoError(t, err)
+		lines := bytes.SplitAfter(encoded, []byte("\n"))
+		require.NoError(t, os.WriteFile(path+secrets.AuditSuffix, lines[1], 0600))
+
+		_, err = secrets.AuditVerify(uri, signer.PublicKey())
+		require.ErrorContains(t, err, "doesn't follow the previous entry")
+	})
+
+	t.Run("failure entry modified", func(t *testing.T) {
+		uri, path := setup(t)
+		encoded, err := os.ReadFile(path + secrets.AuditSuffix)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path+secrets.AuditSuffix, bytes.Replace(encoded, []byte(`"tester"`), []byte(`"mallory"`), 1), 0600))
+
+		_, err = secrets.AuditVerify(uri, signer.PublicKey())
+		require.ErrorContains(t, err, "invalid signature")
+	})
+
+	t.Run("failure unsupported scheme", func(t *testing.T) {
+		_, err := secrets.AuditPath("vault://secret/app")
+		require.Error(t, err)
+	})
+}
+
+func TestRotate(t *testing.T) {
+	t.Run("chachasm", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "secret.chacha")
+		require.NoError(t, secrets.Update(t.Context(), "chachasm://old@"+path, strings.NewReader("hello")))
+		require.NoError(t, secrets.Rotate(t.Context(), "chachasm://old@"+path, "new"))
+
+		result, err := io.ReadAll(secrets.Read(t.Context(), "chachasm://new@"+path))
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(result))
+	})
+
+	t.Run("keyring", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "keyring.age")
+		require.NoError(t, secrets.Update(t.Context(), "keyring://old@"+path+"#github", strings.NewReader("token")))
+		require.NoError(t, secrets.Rotate(t.Context(), "keyring://old@"+path, "new"))
+
+		result, err := io.ReadAll(secrets.Read(t.Context(), "keyring://new@"+path+"#github"))
+		require.NoError(t, err)
+		require.Equal(t, "token", string(result))
+	})
+
+	t.Run("failure incorrect passphrase", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "secret.chacha")
+		require.NoError(t, secrets.Update(t.Context(), "chachasm://old@"+path, strings.NewReader("hello")))
+		require.ErrorContains(t, secrets.Rotate(t.Context(), "chachasm://wrong@"+path, "new"), "decryption failed")
+	})
+
+	t.Run("failure unsupported scheme", func(t *testing.T) {
+		require.Error(t, secrets.Rotate(t.Context(), "vault://secret/app", "new"))
+	})
+}
@@ -0,0 +1,79 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/egdaemon/eg/internal/errorsx"
+)
+
+// jsonreader returns the secret's data as a json object, fields are selected
+// by the uri's fragment, see selection.
+func jsonreader(data map[string]any) io.Reader {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return errorsx.Reader(err)
+	}
+
+	return bytes.NewReader(encoded)
+}
+
+// mergefield updates the field at the dot separated key path of the secret's data, see Select.
+// when no field is provided the data is replaced by the encoded json object.
+func mergefield(current map[string]any, field string, encoded []byte) (_ map[string]any, err error) {
+	if field == "" {
+		var replaced map[string]any
+		if err = json.Unmarshal(encoded, &replaced); err != nil {
+			return nil, errorsx.Wrap(err, "secrets without a field must be a json object")
+		}
+		return replaced, nil
+	}
+
+	if current == nil {
+		current = make(map[string]any)
+	}
+
+	if err = setfield(current, field, keypath(field), string(encoded)); err != nil {
+		return nil, err
+	}
+
+	return current, nil
+}
+
+// setfield assigns the value at the key path creating missing objects along the way, objects
+// stored as strings are decoded, updated and stored as strings again.
+func setfield(current map[string]any, path string, keys []string, value string) error {
+	if len(keys) == 1 {
+		current[keys[0]] = value
+		return nil
+	}
+
+	switch next := current[keys[0]].(type) {
+	case nil:
+		nested := make(map[string]any)
+		current[keys[0]] = nested
+		return setfield(nested, path, keys[1:], value)
+	case map[string]any:
+		return setfield(next, path, keys[1:], value)
+	case string:
+		nested, ok := decode([]byte(next)).(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: unable to update a field of a scalar", path)
+		}
+
+		if err := setfield(nested, path, keys[1:], value); err != nil {
+			return err
+		}
+
+		encoded, err := json.Marshal(nested)
+		if err != nil {
+			return errorsx.WithStack(err)
+		}
+		current[keys[0]] = string(encoded)
+		return nil
+	default:
+		return fmt.Errorf("%s: unable to update a field of a %T", path, next)
+	}
+}
@@ -0,0 +1,98 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/egdaemon/eg/internal/agex"
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/userx"
+)
+
+// DefaultKeyring name of the keyring within the user's directory when the uri doesn't provide a path.
+const DefaultKeyring = "keyring.age"
+
+// keyringPath resolves the keyring from a keyring:// URI, defaulting to the user's keyring.
+// keyring://passphrase@/absolute/path#name, keyring://#name
+func keyringPath(u *url.URL) string {
+	path := u.Path
+	if u.Host != "" && !strings.Contains(u.Host, "@") {
+		path = u.Host + u.Path
+	}
+
+	if path == "" {
+		return userx.DefaultUserDirLocation(DefaultKeyring)
+	}
+
+	return path
+}
+
+// keyring stores named secrets as a json object within an age encrypted (passphrase) file.
+func readkeyring(u *url.URL, opts *readOptions) (secrets map[string]any, err error) {
+	if opts.passphrase == "" {
+		return nil, fmt.Errorf("passphrase not provided")
+	}
+
+	ciphertext, err := os.ReadFile(keyringPath(u))
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := agex.Decrypt(opts.passphrase, ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("decryption failed: %w", err)
+	}
+
+	if err = json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, errorsx.Wrap(err, "invalid keyring")
+	}
+
+	return secrets, nil
+}
+
+func downloadKeyring(ctx context.Context, u *url.URL, opts *readOptions) io.Reader {
+	secrets, err := readkeyring(u, opts)
+	if err != nil {
+		return errorsx.Reader(err)
+	}
+
+	return jsonreader(secrets)
+}
+
+func updateKeyring(u *url.URL, data []byte, opts *readOptions) error {
+	if opts.passphrase == "" {
+		return fmt.Errorf("passphrase required for encryption")
+	}
+
+	current, err := readkeyring(u, opts)
+	if errorsx.Ignore(err, os.ErrNotExist) != nil {
+		return err
+	}
+
+	if current, err = mergefield(current, u.Fragment, data); err != nil {
+		return err
+	}
+
+	plaintext, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+
+	ciphertext, err := agex.Encrypt(opts.passphrase, plaintext)
+	if err != nil {
+		return err
+	}
+
+	path := keyringPath(u)
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, ciphertext, 0600)
+}
@@ -0,0 +1,82 @@
+package secrets_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/egdaemon/eg/internal/agex"
+	"github.com/egdaemon/eg/secrets"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyring(t *testing.T) {
+	t.Run("round trip multiple secrets", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "keyring.age")
+		require.NoError(t, secrets.Update(t.Context(), "keyring://"+path+"#github", strings.NewReader("token-1"), secrets.WithPassphrase("passphrase")))
+		require.NoError(t, secrets.Update(t.Context(), "keyring://"+path+"#npm", strings.NewReader("token-2"), secrets.WithPassphrase("passphrase")))
+
+		result, err := io.ReadAll(secrets.Read(t.Context(), "keyring://"+path+"#github", secrets.WithPassphrase("passphrase")))
+		require.NoError(t, err)
+		require.Equal(t, "token-1", string(result))
+
+		result, err = io.ReadAll(secrets.Read(t.Context(), "keyring://passphrase@"+path+"#npm"))
+		require.NoError(t, err)
+		require.Equal(t, "token-2", string(result))
+
+		result, err = io.ReadAll(secrets.Read(t.Context(), "keyring://"+path, secrets.WithPassphrase("passphrase")))
+		require.NoError(t, err)
+		require.JSONEq(t, `{"github": "token-1", "npm": "token-2"}`, string(result))
+	})
+
+	t.Run("nested field read after write", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "keyring.age")
+		require.NoError(t, secrets.Update(t.Context(), "keyring://"+path+"#db", strings.NewReader(`{"username": "admin"}`), secrets.WithPassphrase("passphrase")))
+		require.NoError(t, secrets.Update(t.Context(), "keyring://"+path+"#db.password", strings.NewReader("hunter2"), secrets.WithPassphrase("passphrase")))
+
+		result, err := io.ReadAll(secrets.Read(t.Context(), "keyring://"+path+"#db.password", secrets.WithPassphrase("passphrase")))
+		require.NoError(t, err)
+		require.Equal(t, "hunter2", string(result))
+
+		result, err = io.ReadAll(secrets.Read(t.Context(), "keyring://"+path+"#db.username", secrets.WithPassphrase("passphrase")))
+		require.NoError(t, err)
+		require.Equal(t, "admin", string(result))
+
+		require.Error(t, secrets.Update(t.Context(), "keyring://"+path+"#db.password.value", strings.NewReader("hunter2"), secrets.WithPassphrase("passphrase")))
+	})
+
+	t.Run("stored as an age file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "keyring.age")
+		require.NoError(t, secrets.Update(t.Context(), "keyring://"+path+"#github", strings.NewReader("token-1"), secrets.WithPassphrase("passphrase")))
+
+		encrypted, err := os.ReadFile(path)
+		require.NoError(t, err)
+		decrypted, err := agex.Decrypt("passphrase", encrypted)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"github": "token-1"}`, string(decrypted))
+	})
+
+	t.Run("failure incorrect passphrase", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "keyring.age")
+		require.NoError(t, secrets.Update(t.Context(), "keyring://"+path+"#github", strings.NewReader("token-1"), secrets.WithPassphrase("passphrase")))
+
+		_, err := io.ReadAll(secrets.Read(t.Context(), "keyring://"+path+"#github", secrets.WithPassphrase("incorrect")))
+		require.ErrorContains(t, err, "decryption failed")
+		require.Error(t, secrets.Update(t.Context(), "keyring://"+path+"#npm", strings.NewReader("token-2"), secrets.WithPassphrase("incorrect")))
+	})
+
+	t.Run("failure missing secret", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "keyring.age")
+		require.NoError(t, secrets.Update(t.Context(), "keyring://"+path+"#github", strings.NewReader("token-1"), secrets.WithPassphrase("passphrase")))
+
+		_, err := io.ReadAll(secrets.Read(t.Context(), "keyring://"+path+"#npm", secrets.WithPassphrase("passphrase")))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("failure no passphrase provided", func(t *testing.T) {
+		_, err := io.ReadAll(secrets.Read(t.Context(), "keyring://"+filepath.Join(t.TempDir(), "keyring.age")+"#github"))
+		require.ErrorContains(t, err, "passphrase not provided")
+	})
+}
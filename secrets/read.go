@@ -91,6 +91,10 @@ func Read(ctx context.Context, uri string, options ...ReadOption) io.Reader {
 	// Extract passphrase from URI userinfo if not already set via options
 	opts.passphrase = langx.FirstNonZero(opts.passphrase, u.User.Username())
 
+	return selection(u, download(ctx, u, opts))
+}
+
+func download(ctx context.Context, u *url.URL, opts *readOptions) io.Reader {
 	switch u.Scheme {
 	case "gcpsm":
 		return downloadGCP(ctx, u, opts)
@@ -100,6 +104,10 @@ func Read(ctx context.Context, uri string, options ...ReadOption) io.Reader {
 		return downloadCHACHA(ctx, u, opts)
 	case "file":
 		return downloadFile(ctx, u)
+	case "vault":
+		return downloadVault(ctx, u)
+	case "keyring":
+		return downloadKeyring(ctx, u, opts)
 	default:
 		return errorsx.Reader(fmt.Errorf("unsupported scheme: %s", u.Scheme))
 	}
@@ -203,17 +211,22 @@ func downloadFile(ctx context.Context, u *url.URL) io.Reader {
 	return bytes.NewReader(data)
 }
 
+// chachaPath resolves the file path from a chachasm:// URI.
+// chachasm://passphrase@/absolute/path, chachasm://relative/path
+func chachaPath(u *url.URL) string {
+	if u.Host != "" && !strings.Contains(u.Host, "@") {
+		return u.Host + u.Path
+	}
+
+	return u.Path
+}
+
 func downloadCHACHA(ctx context.Context, u *url.URL, opts *readOptions) io.Reader {
 	if opts.passphrase == "" {
 		return errorsx.Reader(fmt.Errorf("passphrase not provided"))
 	}
 
-	filePath := u.Path
-	if u.Host != "" && !strings.Contains(u.Host, "@") {
-		filePath = u.Host + u.Path
-	}
-
-	ciphertext, err := os.ReadFile(filePath)
+	ciphertext, err := os.ReadFile(chachaPath(u))
 	if err != nil {
 		return errorsx.Reader(err)
 	}
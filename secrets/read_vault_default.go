@@ -0,0 +1,12 @@
+//go:build !(wasip1 && wasm)
+
+package secrets
+
+import (
+	"net/http"
+	"time"
+)
+
+func newvaulthttp() *http.Client {
+	return &http.Client{Timeout: 30 * time.Second}
+}
@@ -0,0 +1,23 @@
+//go:build wasip1 && wasm
+
+package secrets
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/egdaemon/wasinet/wasinet"
+)
+
+func newvaulthttp() *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				return wasinet.DialContext(ctx, network, addr)
+			},
+		},
+		Timeout: 30 * time.Second,
+	}
+}
@@ -0,0 +1,65 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+
+	"github.com/egdaemon/eg/internal/agex"
+	"github.com/egdaemon/eg/internal/langx"
+)
+
+// Rotate re-encrypts a passphrase protected secret (chachasm, keyring) with a new passphrase.
+// the current passphrase is provided by the uri or the options.
+func Rotate(ctx context.Context, uri string, passphrase string, options ...ReadOption) error {
+	opts := &readOptions{}
+	for _, o := range options {
+		o(opts)
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return err
+	}
+
+	opts.passphrase = langx.FirstNonZero(opts.passphrase, u.User.Username())
+
+	if passphrase == "" {
+		return fmt.Errorf("new passphrase not provided")
+	}
+
+	switch u.Scheme {
+	case "chachasm":
+		plaintext, err := io.ReadAll(downloadCHACHA(ctx, u, opts))
+		if err != nil {
+			return err
+		}
+
+		return updateCHACHA(u, plaintext, &readOptions{passphrase: passphrase})
+	case "keyring":
+		if opts.passphrase == "" {
+			return fmt.Errorf("passphrase not provided")
+		}
+
+		path := keyringPath(u)
+		ciphertext, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		plaintext, err := agex.Decrypt(opts.passphrase, ciphertext)
+		if err != nil {
+			return fmt.Errorf("decryption failed: %w", err)
+		}
+
+		if ciphertext, err = agex.Encrypt(passphrase, plaintext); err != nil {
+			return err
+		}
+
+		return os.WriteFile(path, ciphertext, 0600)
+	default:
+		return fmt.Errorf("unable to rotate the passphrase of scheme: %s", u.Scheme)
+	}
+}
@@ -0,0 +1,216 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/egdaemon/eg/internal/errorsx"
+	"gopkg.in/yaml.v3"
+)
+
+// query parameters reserved for rendering the selected value of a secret.
+const (
+	// renames the selected value to an environment variable (NAME=value), when empty
+	// every top level key of the selected object is rendered as an environment variable.
+	QueryEnv = "env"
+	// path to a text/template file rendered with the selected value.
+	QueryTemplate = "template"
+)
+
+// selection applies the structured selection of the uri to the secret's payload.
+// the fragment selects a dot separated key path from a json or yaml payload, i.e.
+// awssm://db?region=us-east-1#credentials.password, the selection is then rendered
+// as an environment variable or through a template when requested by the query.
+func selection(u *url.URL, r io.Reader) io.Reader {
+	q := u.Query()
+	if u.Fragment == "" && !q.Has(QueryEnv) && !q.Has(QueryTemplate) {
+		return r
+	}
+
+	if q.Has(QueryEnv) && q.Has(QueryTemplate) {
+		return errorsx.Reader(fmt.Errorf("%s and %s are mutually exclusive", QueryEnv, QueryTemplate))
+	}
+
+	payload, err := io.ReadAll(r)
+	if err != nil {
+		return errorsx.Reader(err)
+	}
+
+	v, err := Select(payload, u.Fragment)
+	if err != nil {
+		return errorsx.Reader(err)
+	}
+
+	var rendered []byte
+	switch {
+	case q.Has(QueryEnv):
+		rendered, err = renderenv(q.Get(QueryEnv), v)
+	case q.Has(QueryTemplate):
+		rendered, err = rendertemplate(q.Get(QueryTemplate), v)
+	default:
+		rendered, err = renderscalar(v)
+	}
+
+	if err != nil {
+		return errorsx.Reader(err)
+	}
+
+	return bytes.NewReader(rendered)
+}
+
+// Select the value at the dot separated key path from a json or yaml payload. array
+// elements are selected by their index. strings encountered along the path are decoded
+// allowing selection within structured values stored as a single field.
+func Select(payload []byte, path string) (v any, err error) {
+	v = decode(payload)
+	if path == "" {
+		return v, nil
+	}
+
+	for _, key := range keypath(path) {
+		if s, ok := v.(string); ok {
+			v = decode([]byte(s))
+		}
+
+		switch current := v.(type) {
+		case map[string]any:
+			var ok bool
+			if v, ok = current[key]; !ok {
+				return nil, fmt.Errorf("%s: %w", path, os.ErrNotExist)
+			}
+		case []any:
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx < 0 || idx >= len(current) {
+				return nil, fmt.Errorf("%s: %w", path, os.ErrNotExist)
+			}
+			v = current[idx]
+		default:
+			return nil, fmt.Errorf("%s: %w", path, os.ErrNotExist)
+		}
+	}
+
+	return v, nil
+}
+
+// keypath splits the dot separated key path of a uri fragment, shared by reads and
+// writes so both address the same field.
+func keypath(path string) []string {
+	return strings.Split(path, ".")
+}
+
+// decode the payload as json falling back to yaml, payloads that are neither
+// or decode to a scalar are returned as a string.
+func decode(payload []byte) any {
+	var v any
+
+	d := json.NewDecoder(bytes.NewReader(payload))
+	d.UseNumber()
+	if err := d.Decode(&v); err == nil && !d.More() {
+		if _, scalar := v.(string); !scalar {
+			return v
+		}
+	}
+
+	if err := yaml.Unmarshal(payload, &v); err == nil {
+		switch v.(type) {
+		case map[string]any, []any:
+			return v
+		}
+	}
+
+	return string(payload)
+}
+
+func renderscalar(v any) ([]byte, error) {
+	switch v := v.(type) {
+	case string:
+		return []byte(v), nil
+	case nil:
+		return nil, nil
+	case map[string]any, []any:
+		return json.Marshal(v)
+	default:
+		return []byte(fmt.Sprint(v)), nil
+	}
+}
+
+func renderenv(name string, v any) (_ []byte, err error) {
+	var (
+		buf bytes.Buffer
+	)
+
+	line := func(k string, v any) error {
+		encoded, err := renderscalar(v)
+		if err != nil {
+			return err
+		}
+
+		if bytes.ContainsAny(encoded, "\r\n") {
+			return fmt.Errorf("value of %s contains newlines and can't be used as an environment variable, use a %s instead", k, QueryTemplate)
+		}
+
+		if buf.Len() > 0 {
+			buf.WriteString("\n")
+		}
+
+		fmt.Fprintf(&buf, "%s=%s", k, encoded)
+		return nil
+	}
+
+	if name != "" {
+		if err = line(name, v); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	}
+
+	object, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s without a name requires the selection to be an object", QueryEnv)
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(object)) {
+		if err = line(k, object[k]); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+func rendertemplate(path string, v any) (_ []byte, err error) {
+	var (
+		buf bytes.Buffer
+	)
+
+	encoded, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errorsx.Wrapf(err, "unable to read template %s", path)
+	}
+
+	tmpl, err := template.New(path).Option("missingkey=error").Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			encoded, err := json.Marshal(v)
+			return string(encoded), err
+		},
+	}).Parse(string(encoded))
+	if err != nil {
+		return nil, errorsx.Wrapf(err, "unable to parse template %s", path)
+	}
+
+	if err = tmpl.Execute(&buf, v); err != nil {
+		return nil, errorsx.Wrapf(err, "unable to render template %s", path)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -50,6 +50,10 @@ func Update(ctx context.Context, uri string, r io.Reader, options ...ReadOption)
 		return updateCHACHA(u, data, opts)
 	case "file":
 		return os.WriteFile(filePath(u), data, 0600)
+	case "vault":
+		return updateVault(ctx, u, data)
+	case "keyring":
+		return updateKeyring(u, data, opts)
 	default:
 		return fmt.Errorf("unsupported scheme: %s", u.Scheme)
 	}
@@ -140,11 +144,6 @@ func updateCHACHA(u *url.URL, data []byte, opts *readOptions) error {
 		return fmt.Errorf("passphrase required for encryption")
 	}
 
-	filePath := u.Path
-	if u.Host != "" && !strings.Contains(u.Host, "@") {
-		filePath = u.Host + u.Path
-	}
-
 	salt := make([]byte, 16)
 	nonce := make([]byte, chacha20poly1305.NonceSize)
 	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
@@ -168,5 +167,5 @@ func updateCHACHA(u *url.URL, data []byte, opts *readOptions) error {
 	final.Write(nonce)
 	final.Write(ciphertext)
 
-	return os.WriteFile(filePath, final.Bytes(), 0600)
+	return os.WriteFile(chachaPath(u), final.Bytes(), 0600)
 }
@@ -0,0 +1,278 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/egdaemon/eg/internal/envx"
+	"github.com/egdaemon/eg/internal/errorsx"
+	"github.com/egdaemon/eg/internal/langx"
+)
+
+// environment variables used to authenticate with vault, they match the variables used by the vault cli.
+const (
+	EnvVaultAddr      = "VAULT_ADDR"
+	EnvVaultToken     = "VAULT_TOKEN"
+	EnvVaultNamespace = "VAULT_NAMESPACE"
+	EnvVaultRoleID    = "VAULT_ROLE_ID"
+	EnvVaultSecretID  = "VAULT_SECRET_ID"
+)
+
+// returned when the vault token lacks the policies required by the request.
+const errVaultPermissionDenied = errorsx.String("vault permission denied")
+
+// vault://mount/path#field
+//
+// the kv engine version is detected from the mount unless provided by the kv query parameter (kv=1, kv=2).
+// the version query parameter reads a specific version of a kv v2 secret. authenticates using VAULT_TOKEN
+// or an AppRole login using VAULT_ROLE_ID and VAULT_SECRET_ID, the approle query parameter overrides the
+// mount of the AppRole auth method.
+func downloadVault(ctx context.Context, u *url.URL) io.Reader {
+	v, err := newvault(ctx, u)
+	if err != nil {
+		return errorsx.Reader(err)
+	}
+
+	data, _, err := v.read(ctx, u)
+	if err != nil {
+		return errorsx.Reader(err)
+	}
+
+	return jsonreader(data)
+}
+
+func updateVault(ctx context.Context, u *url.URL, encoded []byte) error {
+	v, err := newvault(ctx, u)
+	if err != nil {
+		return err
+	}
+
+	// reads are always of the latest version, the check-and-set version prevents
+	// concurrent updates from being lost.
+	latest := *u
+	q := latest.Query()
+	q.Del("version")
+	latest.RawQuery = q.Encode()
+
+	current, cas, err := v.read(ctx, &latest)
+	if errorsx.Ignore(err, os.ErrNotExist) != nil {
+		return err
+	}
+
+	if current, err = mergefield(current, u.Fragment, encoded); err != nil {
+		return err
+	}
+
+	return v.write(ctx, u, current, cas)
+}
+
+type vaultclient struct {
+	c         *http.Client
+	addr      string
+	namespace string
+	token     string
+	kv        int
+}
+
+func newvault(ctx context.Context, u *url.URL) (v *vaultclient, err error) {
+	v = &vaultclient{
+		c:         newvaulthttp(),
+		addr:      strings.TrimSuffix(envx.String("https://127.0.0.1:8200", EnvVaultAddr), "/"),
+		namespace: envx.String("", EnvVaultNamespace),
+		token:     envx.String("", EnvVaultToken),
+	}
+
+	if u.Host == "" {
+		return nil, fmt.Errorf("missing vault mount")
+	}
+
+	if strings.Trim(u.Path, "/") == "" {
+		return nil, fmt.Errorf("missing secret path")
+	}
+
+	if v.token == "" {
+		if v.token, err = v.approle(ctx, u); err != nil {
+			return nil, err
+		}
+	}
+
+	if v.kv, err = v.version(ctx, u); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+func (t *vaultclient) approle(ctx context.Context, u *url.URL) (_ string, err error) {
+	var (
+		resp struct {
+			Auth struct {
+				ClientToken string `json:"client_token"`
+			} `json:"auth"`
+		}
+	)
+
+	roleid, secretid := envx.String("", EnvVaultRoleID), envx.String("", EnvVaultSecretID)
+	if roleid == "" || secretid == "" {
+		return "", fmt.Errorf("vault credentials not provided, set %s or %s and %s", EnvVaultToken, EnvVaultRoleID, EnvVaultSecretID)
+	}
+
+	mount := strings.Trim(langx.FirstNonZero(u.Query().Get("approle"), "approle"), "/")
+	req := map[string]string{"role_id": roleid, "secret_id": secretid}
+	if err = t.do(ctx, http.MethodPost, fmt.Sprintf("/v1/auth/%s/login", mount), req, &resp); err != nil {
+		return "", errorsx.Wrap(err, "vault approle login failed")
+	}
+
+	if resp.Auth.ClientToken == "" {
+		return "", fmt.Errorf("vault approle login returned no token")
+	}
+
+	return resp.Auth.ClientToken, nil
+}
+
+// version of the kv engine backing the mount.
+func (t *vaultclient) version(ctx context.Context, u *url.URL) (int, error) {
+	var (
+		resp struct {
+			Data struct {
+				Options struct {
+					Version string `json:"version"`
+				} `json:"options"`
+			} `json:"data"`
+		}
+	)
+
+	if v := u.Query().Get("kv"); v != "" {
+		kv, err := strconv.Atoi(v)
+		if err != nil || (kv != 1 && kv != 2) {
+			return 0, fmt.Errorf("unsupported kv version: %s", v)
+		}
+		return kv, nil
+	}
+
+	// tokens lacking access to the mount information are assumed to be using the default v2 engine.
+	err := t.do(ctx, http.MethodGet, fmt.Sprintf("/v1/sys/internal/ui/mounts/%s", u.Host), nil, &resp)
+	if errors.Is(err, os.ErrNotExist) || errors.Is(err, errVaultPermissionDenied) {
+		return 2, nil
+	} else if err != nil {
+		return 0, errorsx.Wrapf(err, "unable to detect the kv version of the mount: %s", u.Host)
+	}
+
+	if resp.Data.Options.Version == "2" {
+		return 2, nil
+	}
+
+	return 1, nil
+}
+
+// read the secret returning its data and the version to check and set against.
+func (t *vaultclient) read(ctx context.Context, u *url.URL) (_ map[string]any, cas int, err error) {
+	var (
+		v1 struct {
+			Data map[string]any `json:"data"`
+		}
+		v2 struct {
+			Data struct {
+				Data     map[string]any `json:"data"`
+				Metadata struct {
+					Version int `json:"version"`
+				} `json:"metadata"`
+			} `json:"data"`
+		}
+	)
+
+	path := strings.Trim(u.Path, "/")
+	if t.kv == 1 {
+		if err = t.do(ctx, http.MethodGet, fmt.Sprintf("/v1/%s/%s", u.Host, path), nil, &v1); err != nil {
+			return nil, 0, err
+		}
+
+		return v1.Data, 0, nil
+	}
+
+	endpoint := fmt.Sprintf("/v1/%s/data/%s", u.Host, path)
+	if version := u.Query().Get("version"); version != "" {
+		endpoint = fmt.Sprintf("%s?version=%s", endpoint, url.QueryEscape(version))
+	}
+
+	if err = t.do(ctx, http.MethodGet, endpoint, nil, &v2); err != nil {
+		return nil, 0, err
+	}
+
+	// deleted versions are returned without data.
+	if v2.Data.Data == nil {
+		return nil, v2.Data.Metadata.Version, os.ErrNotExist
+	}
+
+	return v2.Data.Data, v2.Data.Metadata.Version, nil
+}
+
+func (t *vaultclient) write(ctx context.Context, u *url.URL, data map[string]any, cas int) error {
+	path := strings.Trim(u.Path, "/")
+	if t.kv == 1 {
+		return t.do(ctx, http.MethodPost, fmt.Sprintf("/v1/%s/%s", u.Host, path), data, nil)
+	}
+
+	return t.do(ctx, http.MethodPost, fmt.Sprintf("/v1/%s/data/%s", u.Host, path), map[string]any{
+		"data":    data,
+		"options": map[string]any{"cas": cas},
+	}, nil)
+}
+
+func (t *vaultclient) do(ctx context.Context, method string, path string, body any, out any) (err error) {
+	var (
+		encoded []byte
+		failure struct {
+			Errors []string `json:"errors"`
+		}
+	)
+
+	if body != nil {
+		if encoded, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, t.addr+path, bytes.NewReader(encoded))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if t.token != "" {
+		req.Header.Set("X-Vault-Token", t.token)
+	}
+	if t.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", t.namespace)
+	}
+
+	resp, err := t.c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return os.ErrNotExist
+	case resp.StatusCode == http.StatusForbidden:
+		json.NewDecoder(resp.Body).Decode(&failure)
+		return fmt.Errorf("%w %s %s: %s", errVaultPermissionDenied, method, path, strings.Join(failure.Errors, ", "))
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		json.NewDecoder(resp.Body).Decode(&failure)
+		return fmt.Errorf("vault request failed %s %s: %d %s", method, path, resp.StatusCode, strings.Join(failure.Errors, ", "))
+	case out == nil || resp.StatusCode == http.StatusNoContent:
+		return nil
+	default:
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+}
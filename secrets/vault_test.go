@@ -0,0 +1,244 @@
+package secrets_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/egdaemon/eg/secrets"
+	"github.com/stretchr/testify/require"
+)
+
+// vaultstub implements the subset of the vault api used by the vault:// scheme.
+// the secret mount is a kv v2 engine, the kv mount a kv v1 engine.
+type vaultstub struct {
+	m        sync.Mutex
+	token    string
+	roleid   string
+	secretid string
+	v1       map[string]map[string]any
+	v2       map[string][]map[string]any
+}
+
+func newvaultstub(t *testing.T) *vaultstub {
+	stub := &vaultstub{
+		token:    "root-token",
+		roleid:   "role",
+		secretid: "secret",
+		v1:       make(map[string]map[string]any),
+		v2:       make(map[string][]map[string]any),
+	}
+
+	srv := httptest.NewServer(stub)
+	t.Cleanup(srv.Close)
+	t.Setenv(secrets.EnvVaultAddr, srv.URL)
+	t.Setenv(secrets.EnvVaultToken, stub.token)
+	t.Setenv(secrets.EnvVaultRoleID, "")
+	t.Setenv(secrets.EnvVaultSecretID, "")
+
+	return stub
+}
+
+func (t *vaultstub) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	reply := func(code int, v any) {
+		resp.WriteHeader(code)
+		json.NewEncoder(resp).Encode(v)
+	}
+
+	if req.URL.Path == "/v1/auth/approle/login" {
+		var login map[string]string
+		json.NewDecoder(req.Body).Decode(&login)
+		if login["role_id"] != t.roleid || login["secret_id"] != t.secretid {
+			reply(http.StatusBadRequest, map[string]any{"errors": []string{"invalid role or secret id"}})
+			return
+		}
+		reply(http.StatusOK, map[string]any{"auth": map[string]any{"client_token": t.token}})
+		return
+	}
+
+	if req.Header.Get("X-Vault-Token") != t.token {
+		reply(http.StatusForbidden, map[string]any{"errors": []string{"permission denied"}})
+		return
+	}
+
+	switch path := req.URL.Path; {
+	case path == "/v1/sys/internal/ui/mounts/secret":
+		reply(http.StatusOK, map[string]any{"data": map[string]any{"type": "kv", "options": map[string]any{"version": "2"}}})
+	case path == "/v1/sys/internal/ui/mounts/restricted":
+		reply(http.StatusForbidden, map[string]any{"errors": []string{"permission denied"}})
+	case path == "/v1/sys/internal/ui/mounts/unavailable":
+		reply(http.StatusServiceUnavailable, map[string]any{"errors": []string{"vault is sealed"}})
+	case path == "/v1/sys/internal/ui/mounts/kv":
+		reply(http.StatusOK, map[string]any{"data": map[string]any{"type": "kv", "options": nil}})
+	case strings.HasPrefix(path, "/v1/secret/data/"):
+		name := strings.TrimPrefix(path, "/v1/secret/data/")
+		versions := t.v2[name]
+		if req.Method == http.MethodGet {
+			version := len(versions)
+			if v := req.URL.Query().Get("version"); v != "" {
+				version, _ = strconv.Atoi(v)
+			}
+			if version == 0 || version > len(versions) {
+				reply(http.StatusNotFound, map[string]any{"errors": []string{}})
+				return
+			}
+			reply(http.StatusOK, map[string]any{"data": map[string]any{"data": versions[version-1], "metadata": map[string]any{"version": version}}})
+			return
+		}
+
+		var body struct {
+			Data    map[string]any `json:"data"`
+			Options struct {
+				CAS *int `json:"cas"`
+			} `json:"options"`
+		}
+		json.NewDecoder(req.Body).Decode(&body)
+		if body.Options.CAS != nil && *body.Options.CAS != len(versions) {
+			reply(http.StatusBadRequest, map[string]any{"errors": []string{"check-and-set parameter did not match the current version"}})
+			return
+		}
+		t.v2[name] = append(versions, body.Data)
+		reply(http.StatusOK, map[string]any{"data": map[string]any{"version": len(t.v2[name])}})
+	case strings.HasPrefix(path, "/v1/kv/"):
+		name := strings.TrimPrefix(path, "/v1/kv/")
+		if req.Method == http.MethodGet {
+			data, ok := t.v1[name]
+			if !ok {
+				reply(http.StatusNotFound, map[string]any{"errors": []string{}})
+				return
+			}
+			reply(http.StatusOK, map[string]any{"data": data})
+			return
+		}
+
+		var data map[string]any
+		json.NewDecoder(req.Body).Decode(&data)
+		t.v1[name] = data
+		resp.WriteHeader(http.StatusNoContent)
+	default:
+		reply(http.StatusNotFound, map[string]any{"errors": []string{}})
+	}
+}
+
+func TestVault(t *testing.T) {
+	t.Run("kv v2 field round trip", func(t *testing.T) {
+		newvaultstub(t)
+		uri := "vault://secret/app/config#password"
+		require.NoError(t, secrets.Update(t.Context(), uri, strings.NewReader("hunter2")))
+		require.NoError(t, secrets.Update(t.Context(), "vault://secret/app/config#username", strings.NewReader("admin")))
+
+		result, err := io.ReadAll(secrets.Read(t.Context(), uri))
+		require.NoError(t, err)
+		require.Equal(t, "hunter2", string(result))
+
+		result, err = io.ReadAll(secrets.Read(t.Context(), "vault://secret/app/config"))
+		require.NoError(t, err)
+		require.JSONEq(t, `{"password": "hunter2", "username": "admin"}`, string(result))
+	})
+
+	t.Run("nested field read after write", func(t *testing.T) {
+		stub := newvaultstub(t)
+		uri := "vault://kv/app/config#db.password"
+		require.NoError(t, secrets.Update(t.Context(), uri, strings.NewReader("hunter2")))
+		require.NoError(t, secrets.Update(t.Context(), "vault://kv/app/config#db.username", strings.NewReader("admin")))
+		require.Equal(t, map[string]any{"db": map[string]any{"password": "hunter2", "username": "admin"}}, stub.v1["app/config"])
+
+		result, err := io.ReadAll(secrets.Read(t.Context(), uri))
+		require.NoError(t, err)
+		require.Equal(t, "hunter2", string(result))
+	})
+
+	t.Run("kv v2 specific version", func(t *testing.T) {
+		newvaultstub(t)
+		uri := "vault://secret/app/config#password"
+		require.NoError(t, secrets.Update(t.Context(), uri, strings.NewReader("first")))
+		require.NoError(t, secrets.Update(t.Context(), uri, strings.NewReader("second")))
+
+		result, err := io.ReadAll(secrets.Read(t.Context(), "vault://secret/app/config?version=1#password"))
+		require.NoError(t, err)
+		require.Equal(t, "first", string(result))
+
+		result, err = io.ReadAll(secrets.Read(t.Context(), uri))
+		require.NoError(t, err)
+		require.Equal(t, "second", string(result))
+	})
+
+	t.Run("kv v1 detected from the mount", func(t *testing.T) {
+		stub := newvaultstub(t)
+		uri := "vault://kv/app/config#password"
+		require.NoError(t, secrets.Update(t.Context(), uri, strings.NewReader("hunter2")))
+		require.Equal(t, map[string]any{"password": "hunter2"}, stub.v1["app/config"])
+
+		result, err := io.ReadAll(secrets.Read(t.Context(), uri))
+		require.NoError(t, err)
+		require.Equal(t, "hunter2", string(result))
+	})
+
+	t.Run("kv v2 assumed when the mount is inaccessible", func(t *testing.T) {
+		newvaultstub(t)
+		_, err := io.ReadAll(secrets.Read(t.Context(), "vault://restricted/app/config#password"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("mount detection failures are returned", func(t *testing.T) {
+		newvaultstub(t)
+		_, err := io.ReadAll(secrets.Read(t.Context(), "vault://unavailable/app/config#password"))
+		require.ErrorContains(t, err, "vault is sealed")
+	})
+
+	t.Run("whole secret requires a json object", func(t *testing.T) {
+		newvaultstub(t)
+		require.Error(t, secrets.Update(t.Context(), "vault://secret/app/config", strings.NewReader("not json")))
+		require.NoError(t, secrets.Update(t.Context(), "vault://secret/app/config", strings.NewReader(`{"a": "b", "n": 1}`)))
+
+		result, err := io.ReadAll(secrets.Read(t.Context(), "vault://secret/app/config#n"))
+		require.NoError(t, err)
+		require.Equal(t, "1", string(result))
+	})
+
+	t.Run("approle authentication", func(t *testing.T) {
+		stub := newvaultstub(t)
+		t.Setenv(secrets.EnvVaultToken, "")
+		t.Setenv(secrets.EnvVaultRoleID, stub.roleid)
+		t.Setenv(secrets.EnvVaultSecretID, stub.secretid)
+
+		require.NoError(t, secrets.Update(t.Context(), "vault://secret/app/config#password", strings.NewReader("hunter2")))
+		result, err := io.ReadAll(secrets.Read(t.Context(), "vault://secret/app/config#password"))
+		require.NoError(t, err)
+		require.Equal(t, "hunter2", string(result))
+
+		t.Setenv(secrets.EnvVaultSecretID, "incorrect")
+		_, err = io.ReadAll(secrets.Read(t.Context(), "vault://secret/app/config#password"))
+		require.ErrorContains(t, err, "approle login failed")
+	})
+
+	t.Run("missing credentials", func(t *testing.T) {
+		newvaultstub(t)
+		t.Setenv(secrets.EnvVaultToken, "")
+		_, err := io.ReadAll(secrets.Read(t.Context(), "vault://secret/app/config#password"))
+		require.ErrorContains(t, err, "vault credentials not provided")
+	})
+
+	t.Run("missing secret", func(t *testing.T) {
+		newvaultstub(t)
+		_, err := io.ReadAll(secrets.Read(t.Context(), "vault://secret/app/missing#password"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("missing field", func(t *testing.T) {
+		newvaultstub(t)
+		require.NoError(t, secrets.Update(t.Context(), "vault://secret/app/config#password", bytes.NewReader([]byte("hunter2"))))
+		_, err := io.ReadAll(secrets.Read(t.Context(), "vault://secret/app/config#username"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
@@ -0,0 +1,46 @@
+package transpile
+
+import (
+	"strings"
+
+	"github.com/egdaemon/eg/cmd/cmdopts"
+	"github.com/egdaemon/eg/internal/md5x"
+	"github.com/egdaemon/eg/internal/modgraph"
+)
+
+// transpiled results of a single package, paths are relative so the results
+// can be replayed into the workspace of any cache id.
+type transpiled struct {
+	Files   []source `json:"files"`   // rewritten files; relative to the transpiled directory.
+	Modules []source `json:"modules"` // generated modules; relative to the generated module directory.
+	Main    []string `json:"main"`    // rewritten files declaring the main function of the workload.
+}
+
+type source struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// transpilekey identifies the results of transpiling a package. the transformations only
+// depend on the files of the package itself, so dependencies are deliberately excluded.
+func transpilekey(g modgraph.Graph, target string, dir string) string {
+	return md5x.FormatHex(md5x.Digest(cmdopts.BuildInfoSafe(), "transpile", target, dir, g.Digest(dir)))
+}
+
+// pattern for loading the package within the directory.
+func pattern(dir string) string {
+	if dir == "." {
+		return dir
+	}
+
+	return "./" + dir
+}
+
+// pkgdir returns the directory of the package relative to the module root.
+func pkgdir(module string, pkgpath string) string {
+	if pkgpath == module {
+		return "."
+	}
+
+	return strings.TrimPrefix(pkgpath, module+"/")
+}
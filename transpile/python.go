@@ -0,0 +1,35 @@
+package transpile
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/egdaemon/eg/internal/fsx"
+)
+
+const (
+	PythonEntrypoint = "main.py"
+)
+
+// Python reports if the module directory contains a python workload. a go.mod takes
+// precedence allowing python helpers to live alongside go modules.
+func Python(dir string) bool {
+	return fsx.FileExists(filepath.Join(dir, PythonEntrypoint)) && !fsx.FileExists(filepath.Join(dir, "go.mod"))
+}
+
+// python modules are interpreted, transpiling only stages the module for packaging.
+// see compile.Python.
+type python struct {
+	Context
+}
+
+func (t python) Run(ctx context.Context) (roots []Compiled, err error) {
+	transdir := filepath.Join(t.Context.Workspace.Root, t.Context.Workspace.TransDir)
+
+	if err = fsx.CloneTree(ctx, transdir, ".", os.DirFS(t.Context.root)); err != nil {
+		return roots, err
+	}
+
+	return append(roots, Compiled{Path: filepath.Join(t.Context.Workspace.TransDir, PythonEntrypoint)}), nil
+}
@@ -12,15 +12,19 @@ import (
 	"go/types"
 	"io"
 	"log"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/dave/jennifer/jen"
 	"github.com/egdaemon/eg/astbuild"
 	"github.com/egdaemon/eg/astcodec"
+	"github.com/egdaemon/eg/internal/errorsx"
 	"github.com/egdaemon/eg/internal/fsx"
 	"github.com/egdaemon/eg/internal/langx"
+	"github.com/egdaemon/eg/internal/modgraph"
 	"github.com/egdaemon/eg/internal/tracex"
 	"github.com/egdaemon/eg/workspaces"
 	"golang.org/x/tools/go/ast/astutil"
@@ -50,95 +54,172 @@ func New(root string, ws workspaces.Context) Context {
 
 // Autodetect the transpiler to use.
 func Autodetect(tctx Context) Transpiler {
+	if Python(tctx.root) {
+		return python{Context: tctx}
+	}
+
 	return golang{Context: tctx}
 }
 
 type module struct {
 	imported []*ast.ImportSpec
-	fname    string
+	fname    string // generated module; relative to the generated module directory.
 	main     *bytes.Buffer
-	pos      token.Position
+	pos      token.Position // position of the module; relative to the transpiled directory.
 }
 
 type golang struct {
 	Context
 }
 
+// Run transpiles the packages of the module. packages are content addressed by modgraph,
+// the results of unchanged packages are replayed from the build graph instead of being
+// loaded and rewritten again.
 func (t golang) Run(ctx context.Context) (roots []Compiled, err error) {
 	var (
-		dst  string
+		g    modgraph.Graph
 		pset []*packages.Package
 	)
 	transdir := filepath.Join(t.Context.Workspace.Root, t.Context.Workspace.TransDir)
+	store := modgraph.NewStore(workspaces.PathGraph(t.Context.Workspace))
 
 	err = fsx.CloneTree(ctx, transdir, ".", os.DirFS(t.Context.root))
 	if err != nil {
 		return roots, err
 	}
 
-	pkgc := astcodec.DefaultPkgLoad(
-		astcodec.LoadDir(transdir),
-		astcodec.AutoFileSet,
-		astcodec.DisableGowork, // dont want to do this but until I figure out the issue.
-	)
+	if g, err = modgraph.Scan(ctx, t.Context.root); err != nil {
+		return roots, err
+	}
+
+	target := filepath.Join(g.Module(), t.Workspace.Module)
+	results := make(map[string]transpiled, 32)
+	patterns := make([]string, 0, 32)
+	for _, dir := range g.Packages() {
+		var (
+			cached transpiled
+			found  bool
+		)
+
+		if found, err = store.Read(transpilekey(g, target, dir), &cached); err != nil {
+			return roots, err
+		}
+
+		if found {
+			tracex.Println("reusing transpiled package", dir)
+			results[dir] = cached
+			continue
+		}
 
-	generatedmodules := make([]*module, 0, 128)
-	if pset, err = packages.Load(pkgc, "./..."); err != nil {
-		return nil, err
+		patterns = append(patterns, pattern(dir))
+	}
+
+	if len(patterns) > 0 {
+		pkgc := astcodec.DefaultPkgLoad(
+			astcodec.LoadDir(transdir),
+			astcodec.AutoFileSet,
+			astcodec.DisableGowork, // dont want to do this but until I figure out the issue.
+		)
+
+		if pset, err = packages.Load(pkgc, patterns...); err != nil {
+			return nil, err
+		}
 	}
 
 	for _, pkg := range pset {
-		target := filepath.Join(langx.Autoderef(pkg.Module).Path, t.Workspace.Module)
-		for _, c := range pkg.Syntax {
-			var (
-				gendir string
-				genm   []*module
-			)
-			ftoken := pkg.Fset.File(c.Pos())
-
-			if dst, err = workspaces.PathTranspiled(t.Context.Workspace, transdir, ftoken.Name()); err != nil {
-				return roots, err
-			}
+		var (
+			r transpiled
+		)
 
-			if gendir, err = filepath.Rel(transdir, workspaces.ReplaceExt(ftoken.Name(), ".wasm.d")); err != nil {
-				return roots, err
-			}
+		dir := pkgdir(g.Module(), pkg.PkgPath)
+		if r, err = t.transpile(transdir, target, pkg); err != nil {
+			return roots, err
+		}
 
-			if genm, err = transform(t.Workspace, pkg.Fset, gendir, c); err != nil {
-				return roots, err
-			}
+		errorsx.Log(errorsx.Wrapf(store.Write(transpilekey(g, target, dir), r), "unable to retain transpiled package: %s", dir))
+		results[dir] = r
+	}
 
-			if err = rewrite(filepath.Join(t.Context.Workspace.Root, dst), pkg.Fset, c); err != nil {
+	generated := make([]Compiled, 0, 128)
+	for _, dir := range slices.Sorted(maps.Keys(results)) {
+		r := results[dir]
+
+		for _, src := range r.Files {
+			if err = write(filepath.Join(transdir, src.Path), src.Content); err != nil {
 				return roots, err
 			}
+		}
 
-			if target != pkg.ID {
-				tracex.Println("ignoring", target, pkg.ID)
-				continue
+		for _, src := range r.Modules {
+			if err = write(filepath.Join(t.Context.Workspace.Root, t.Context.Workspace.GenModDir, src.Path), src.Content); err != nil {
+				return roots, err
 			}
 
-			generatedmodules = append(generatedmodules, genm...)
+			generated = append(generated, Compiled{Path: filepath.Join(t.Context.Workspace.GenModDir, src.Path), Generated: true})
+		}
 
-			if mainfn := astcodec.FindFunctionDecl(pkg, astcodec.FindFunctionsByName("main")); mainfn != nil {
-				roots = append(roots, Compiled{Path: dst})
-			}
+		for _, path := range r.Main {
+			roots = append(roots, Compiled{Path: filepath.Join(t.Context.Workspace.TransDir, path)})
 		}
 	}
 
-	for _, m := range generatedmodules {
-		osrc, err := os.ReadFile(filepath.Join(t.Context.Workspace.Root, m.pos.Filename))
-		if err != nil {
-			return roots, err
+	return append(roots, generated...), nil
+}
+
+// transpile a single package, nothing is written to disk.
+func (t golang) transpile(transdir string, target string, pkg *packages.Package) (r transpiled, err error) {
+	generatedmodules := make([]*module, 0, 8)
+	rewritten := make(map[string]string, len(pkg.Syntax))
+
+	for _, c := range pkg.Syntax {
+		var (
+			path      string
+			gendir    string
+			formatted string
+			genm      []*module
+		)
+		ftoken := pkg.Fset.File(c.Pos())
+
+		if path, err = filepath.Rel(transdir, ftoken.Name()); err != nil {
+			return r, err
+		}
+
+		if gendir, err = filepath.Rel(transdir, workspaces.ReplaceExt(ftoken.Name(), ".wasm.d")); err != nil {
+			return r, err
+		}
+
+		if genm, err = transform(t.Workspace, pkg.Fset, gendir, c); err != nil {
+			return r, err
+		}
+
+		if formatted, err = rewrite(pkg.Fset, c); err != nil {
+			return r, err
 		}
 
-		o, err := parser.ParseFile(pkgc.Fset, m.fname, osrc, 0)
+		rewritten[path] = formatted
+		r.Files = append(r.Files, source{Path: path, Content: formatted})
+
+		if target != pkg.ID {
+			tracex.Println("ignoring", target, pkg.ID)
+			continue
+		}
+
+		generatedmodules = append(generatedmodules, genm...)
+
+		if mainfn := astcodec.FindFunctionDecl(pkg, astcodec.FindFunctionsByName("main")); mainfn != nil {
+			r.Main = append(r.Main, path)
+		}
+	}
+
+	for _, m := range generatedmodules {
+		o, err := parser.ParseFile(pkg.Fset, m.fname, rewritten[m.pos.Filename], 0)
 		if err != nil {
-			return roots, err
+			return r, err
 		}
 
 		mfn, err := parser.ParseFile(token.NewFileSet(), m.fname, m.main.String(), 0)
 		if err != nil {
-			return roots, err
+			return r, err
 		}
 
 		main := astcodec.FindFunctionDecl(&packages.Package{Syntax: []*ast.File{mfn}}, astcodec.FindFunctionsByName("main"))
@@ -150,21 +231,22 @@ func (t golang) Run(ctx context.Context) (roots []Compiled, err error) {
 		for _, i := range m.imported {
 			path := strings.Trim(i.Path.Value, "\"")
 			name := langx.Autoderef(i.Name).Name
-			if ok := astutil.AddNamedImport(pkgc.Fset, o, name, path); !ok {
+			if ok := astutil.AddNamedImport(pkg.Fset, o, name, path); !ok {
 				tracex.Printf("unable to readd import %s \"%s\"", name, path)
 			}
 		}
 		result := astcodec.ReplaceFunction(o, main, astcodec.FindFunctionsByName("main"))
 		tracex.Println("original", m.fname)
 
-		if err = rewrite(filepath.Join(t.Context.Workspace.Root, m.fname), token.NewFileSet(), result); err != nil {
-			return roots, err
+		formatted, err := rewrite(token.NewFileSet(), result)
+		if err != nil {
+			return r, err
 		}
 
-		roots = append(roots, Compiled{Path: m.fname, Generated: true})
+		r.Modules = append(r.Modules, source{Path: m.fname, Content: formatted})
 	}
 
-	return roots, nil
+	return r, nil
 }
 
 func transform(ws workspaces.Context, fset *token.FileSet, gendir string, c *ast.File) (generatedmodules []*module, err error) {
@@ -215,7 +297,7 @@ func transform(ws workspaces.Context, fset *token.FileSet, gendir string, c *ast
 		}
 
 		pos := fset.PositionFor(ce.Pos(), true)
-		pos.Filename = strings.TrimPrefix(pos.Filename, ws.Root+"/")
+		pos.Filename = strings.TrimPrefix(pos.Filename, filepath.Join(ws.Root, ws.TransDir)+"/")
 
 		main := jen.NewFile("main")
 		main.Commentf("automatically generated from: %s", pos)
@@ -250,7 +332,7 @@ func transform(ws workspaces.Context, fset *token.FileSet, gendir string, c *ast
 		genwasm := filepath.Join(gendir, fmt.Sprintf("module.%d.%d.wasm", pos.Line, pos.Column))
 		m := &module{
 			imported: imported,
-			fname:    filepath.Join(ws.GenModDir, gendir, fmt.Sprintf("module.%d.%d.go", pos.Line, pos.Column)),
+			fname:    filepath.Join(gendir, fmt.Sprintf("module.%d.%d.go", pos.Line, pos.Column)),
 			main:     mainbuf,
 			pos:      pos,
 		}
@@ -268,7 +350,6 @@ func transform(ws workspaces.Context, fset *token.FileSet, gendir string, c *ast
 		rarg := ce.Args[1]
 
 		pos := fset.PositionFor(ce.Pos(), true)
-		pos.Filename = strings.TrimPrefix(pos.Filename, ws.Root+"/")
 		genwasm := filepath.Join(gendir, fmt.Sprintf("module.%d.%d.wasm", pos.Line, pos.Column))
 
 		return astbuild.CallExpr(astbuild.SelExpr(egident, "UnsafeExec"), ctxarg, rarg, astbuild.StringLiteral(genwasm))
@@ -288,22 +369,22 @@ func transform(ws workspaces.Context, fset *token.FileSet, gendir string, c *ast
 	return generatedmodules, generr
 }
 
-func rewrite(dst string, fset *token.FileSet, c ast.Node) (err error) {
-	var (
-		iodst     *os.File
-		formatted string
-		buf       = bytes.NewBuffer(nil)
-	)
-
-	tracex.Println("writing transformed to", dst)
+// rewrite formats the node as go source.
+func rewrite(fset *token.FileSet, c ast.Node) (formatted string, err error) {
+	buf := bytes.NewBuffer(nil)
 	if err = (&printer.Config{Mode: printer.TabIndent | printer.UseSpaces}).Fprint(buf, fset, c); err != nil {
-		return err
+		return "", err
 	}
 
-	if formatted, err = astcodec.Format(buf.String()); err != nil {
-		return err
-	}
+	return astcodec.Format(buf.String())
+}
 
+func write(dst string, content string) (err error) {
+	var (
+		iodst *os.File
+	)
+
+	tracex.Println("writing transformed to", dst)
 	if err = os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
 		return err
 	}
@@ -313,7 +394,7 @@ func rewrite(dst string, fset *token.FileSet, c ast.Node) (err error) {
 	}
 	defer iodst.Close()
 
-	if _, err := io.Copy(iodst, bytes.NewBufferString(formatted)); err != nil {
+	if _, err := io.Copy(iodst, bytes.NewBufferString(content)); err != nil {
 		return err
 	}
 
@@ -15,6 +15,7 @@ import (
 //   - any folder with content older than 30 days is removed outright.
 //   - only the 3 most recent wazero compilation cache entries are kept.
 //   - only the 3 most recent .gen entries are kept.
+//   - build graph entries unused for 7 days are removed.
 func (c Context) Cleanup(ctx context.Context) {
 	for path := range fsx.Find(c.CacheDir, fsx.MaxAge(30*24*time.Hour), fsx.Levels(8)).Each(ctx) {
 		errorsx.Log(errorsx.Wrapf(os.RemoveAll(path), "cache cleanup: %s", path))
@@ -27,4 +28,8 @@ func (c Context) Cleanup(ctx context.Context) {
 	for path := range fsx.KeepNewestN(3, fsx.Find(filepath.Join(c.CacheDir, eg.DefaultModuleDirectory(), ".gen"), fsx.Levels(8))).Each(ctx) {
 		errorsx.Log(errorsx.Wrapf(os.RemoveAll(path), "gen cache cleanup: %s", path))
 	}
+
+	for path := range fsx.Find(filepath.Join(c.CacheDir, eg.DefaultModuleDirectory(), ".graph"), fsx.MaxAge(7*24*time.Hour), fsx.Levels(2)).Each(ctx) {
+		errorsx.Log(errorsx.Wrapf(os.RemoveAll(path), "graph cache cleanup: %s", path))
+	}
 }
@@ -108,6 +108,24 @@ func TestCleanup(t *testing.T) {
 		require.NoError(t, err, "e5 should be kept")
 	})
 
+	t.Run("graph_removes_entries_unused_for_7_days", func(t *testing.T) {
+		ctx, done := testx.Context(t)
+		defer done()
+
+		cacheDir := t.TempDir()
+		ws := workspaces.Context{CacheDir: cacheDir}
+		graphDir := filepath.Join(cacheDir, eg.DefaultModuleDirectory(), ".graph")
+		touch(t, filepath.Join(graphDir, "unused", "module.wasm"), 8*24*time.Hour)
+		touch(t, filepath.Join(graphDir, "recent", "module.wasm"), 6*24*time.Hour)
+
+		ws.Cleanup(ctx)
+
+		_, err := os.Stat(filepath.Join(graphDir, "unused"))
+		require.ErrorIs(t, err, os.ErrNotExist, "unused graph entry should be removed")
+		_, err = os.Stat(filepath.Join(graphDir, "recent"))
+		require.NoError(t, err, "recent graph entry should be kept")
+	})
+
 	t.Run("cache_levels_limits_depth_traversal", func(t *testing.T) {
 		ctx, done := testx.Context(t)
 		defer done()
@@ -62,6 +62,7 @@ type Context struct {
 	BuildDir       string // directory for built wasm modules; relative to the cache directory.
 	TransDir       string // root directory for the transpiled code; relative to the cache directory.
 	GenModDir      string // root directory for generated modules; relative to the cache directory.
+	GraphDir       string // persistent build graph shared between cache ids; relative to the cache directory.
 	Ignore         ignorable
 }
 
@@ -201,6 +202,7 @@ func OptionInvalidateModuleCache(ctx *Context) {
 	log.Println("resetting module cache", filepath.Join(ctx.Root, ctx.BuildDir))
 	os.RemoveAll(filepath.Join(ctx.Root, ctx.BuildDir))
 	os.RemoveAll(filepath.Join(ctx.Root, ctx.TransDir))
+	os.RemoveAll(filepath.Join(ctx.Root, ctx.GraphDir))
 }
 
 func OptionCompose(opts ...Option) Option {
@@ -268,6 +270,7 @@ func New(ctx context.Context, cid hash.Hash, cwd string, root string, name strin
 		BuildDir:       filepath.Join(eg.CacheDirectory, eg.DefaultModuleDirectory(), ".gen", _cid, "build"),
 		TransDir:       filepath.Join(eg.CacheDirectory, eg.DefaultModuleDirectory(), ".gen", _cid, "trans"),
 		GenModDir:      filepath.Join(eg.CacheDirectory, eg.DefaultModuleDirectory(), ".gen", _cid, "trans", ".genmod"),
+		GraphDir:       filepath.Join(eg.CacheDirectory, eg.DefaultModuleDirectory(), ".graph"),
 		Ignore:         ignore,
 	}, options...))
 }
@@ -377,6 +380,25 @@ func PathBuild(tctx Context, mdir string, current string) (path string, err erro
 	return filepath.Join(tctx.BuildDir, path), nil
 }
 
+// PathEntry returns the path of the compiled module relative to the build directory, i.e. the
+// entry point of the workload within the archive of the build directory.
+func PathEntry(tctx Context, compiled string) (path string, err error) {
+	if path, err = filepath.Rel(filepath.Join(tctx.Root, tctx.BuildDir), compiled); err != nil {
+		return "", errorsx.Wrap(err, "unable to determine entry relative path")
+	}
+
+	return path, nil
+}
+
+// PathGraph returns the absolute path to the build graph of the workspace, empty when the workspace has no build graph.
+func PathGraph(tctx Context) string {
+	if tctx.GraphDir == "" {
+		return ""
+	}
+
+	return filepath.Join(tctx.Root, tctx.GraphDir)
+}
+
 func ReplaceExt(path string, ext string) string {
 	return strings.TrimSuffix(path, filepath.Ext(path)) + ext
 }
@@ -1,6 +1,7 @@
 package workspaces_test
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"log"
 	"os"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/egdaemon/eg"
+	"github.com/egdaemon/eg/internal/tarx"
 	"github.com/egdaemon/eg/workspaces"
 	git "github.com/go-git/go-git/v6"
 	"github.com/gofrs/uuid/v5"
@@ -487,3 +489,37 @@ func TestNew(t *testing.T) {
 		require.Equal(t, filepath.Join(root, eg.CacheDirectory, eg.DefaultModuleDirectory(), "wazcache"), ws.CacheDirWazero)
 	})
 }
+
+func TestPathEntry(t *testing.T) {
+	// the entry is mounted from the directory the archive of the build directory is unpacked into.
+	roundtrip := func(t *testing.T, module string, compiled ...string) {
+		root := t.TempDir()
+		ws, err := workspaces.New(t.Context(), sha256.New(), root, root, module)
+		require.NoError(t, err)
+
+		path := filepath.Join(append([]string{root, ws.BuildDir}, compiled...)...)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("module"), 0644))
+
+		entry, err := workspaces.PathEntry(ws, path)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(compiled...), entry)
+
+		var archive bytes.Buffer
+		require.NoError(t, tarx.Pack(&archive, filepath.Join(root, ws.BuildDir)))
+
+		runtimedir := t.TempDir()
+		require.NoError(t, tarx.Unpack(runtimedir, &archive))
+		content, err := os.ReadFile(filepath.Join(runtimedir, entry))
+		require.NoError(t, err)
+		require.Equal(t, "module", string(content))
+	}
+
+	t.Run("go module", func(t *testing.T) {
+		roundtrip(t, "", "main.wasm")
+	})
+
+	t.Run("python module", func(t *testing.T) {
+		roundtrip(t, "", "main.wasm.d", eg.ModulePython)
+	})
+}